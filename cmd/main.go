@@ -1,34 +1,77 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/rs/zerolog/log"
 
 	"github.com/sgitwhyd/music-catalogue/internal/configs"
 	"github.com/sgitwhyd/music-catalogue/internal/handlers"
+	"github.com/sgitwhyd/music-catalogue/internal/handlers/export"
+	"github.com/sgitwhyd/music-catalogue/internal/handlers/importjob"
+	"github.com/sgitwhyd/music-catalogue/internal/handlers/play"
+	"github.com/sgitwhyd/music-catalogue/internal/handlers/playlist"
 	"github.com/sgitwhyd/music-catalogue/internal/handlers/spotify"
-	"github.com/sgitwhyd/music-catalogue/internal/models"
-	spotifyModel "github.com/sgitwhyd/music-catalogue/internal/models/spotify"
+	"github.com/sgitwhyd/music-catalogue/internal/handlers/spotifyaccount"
+	"github.com/sgitwhyd/music-catalogue/internal/handlers/spotifysync"
+	"github.com/sgitwhyd/music-catalogue/internal/handlers/tag"
+	"github.com/sgitwhyd/music-catalogue/internal/middleware"
+	"github.com/sgitwhyd/music-catalogue/internal/migrations"
 	"github.com/sgitwhyd/music-catalogue/internal/repositorys"
+	importRepo "github.com/sgitwhyd/music-catalogue/internal/repositorys/importjob"
+	playRepo "github.com/sgitwhyd/music-catalogue/internal/repositorys/play"
+	playlistRepo "github.com/sgitwhyd/music-catalogue/internal/repositorys/playlist"
 	spotifyRepo "github.com/sgitwhyd/music-catalogue/internal/repositorys/spotify"
+	spotifyAccountRepo "github.com/sgitwhyd/music-catalogue/internal/repositorys/spotifyaccount"
+	spotifySyncRepo "github.com/sgitwhyd/music-catalogue/internal/repositorys/spotifysync"
+	tagRepo "github.com/sgitwhyd/music-catalogue/internal/repositorys/tag"
 	"github.com/sgitwhyd/music-catalogue/internal/services"
+	exportSvc "github.com/sgitwhyd/music-catalogue/internal/services/export"
+	importSvc "github.com/sgitwhyd/music-catalogue/internal/services/importjob"
+	playSvc "github.com/sgitwhyd/music-catalogue/internal/services/play"
+	playlistSvc "github.com/sgitwhyd/music-catalogue/internal/services/playlist"
 	spotifySvc "github.com/sgitwhyd/music-catalogue/internal/services/spotify"
+	spotifyAccountSvc "github.com/sgitwhyd/music-catalogue/internal/services/spotifyaccount"
+	spotifySyncSvc "github.com/sgitwhyd/music-catalogue/internal/services/spotifysync"
+	tagSvc "github.com/sgitwhyd/music-catalogue/internal/services/tag"
 	"github.com/sgitwhyd/music-catalogue/pkg/httpclient"
 	"github.com/sgitwhyd/music-catalogue/pkg/internalsql"
+	"github.com/sgitwhyd/music-catalogue/pkg/secretbox"
+	"github.com/sgitwhyd/music-catalogue/pkg/spotifyfake"
 )
 
 func main() {
+	// deferred first so it runs after every other deferred close
+	exitCode := 0
+	defer func() {
+		if exitCode != 0 {
+			os.Exit(exitCode)
+		}
+	}()
+
 	config, err := configs.Init("./", "env", ".env")
 	if err != nil {
-		log.Error().Err(err).Msgf("load config error %v", err)
+		log.Fatal().Err(err).Msg("load config error")
 	}
 	
 	db, err := internalsql.Connect(config.DatabaseURL)
 	if err != nil {
-		log.Error().Err(err).Msgf("error db connection %v", err)
+		log.Fatal().Err(err).Msg("error db connection")
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		log.Fatal().Err(err).Msg("error get db connection pool")
 	}
+	defer sqlDB.Close()
 	log.Info().Msg("database connected")
 
 	if config.ENV == "release" {
@@ -37,37 +80,286 @@ func main() {
 
 
 	// migrate db
-	db.AutoMigrate(&models.User{})
-	db.AutoMigrate(&spotifyModel.TrackActivity{})
+	migrator, err := migrations.New(sqlDB)
+	if err != nil {
+		log.Fatal().Err(err).Msg("error load migrations")
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		err := runMigrateCommand(context.Background(), migrator, os.Args[2:])
+		if err != nil {
+			log.Fatal().Err(err).Msg("error migrate")
+		}
+		return
+	}
 
+	err = migrateOnBoot(context.Background(), migrator, config.MigrationMode)
+	if err != nil {
+		log.Fatal().Err(err).Msg("error migrate db")
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "role" {
+		err := runRoleCommand(repositorys.NewUserRepo(db), os.Args[2:])
+		if err != nil {
+			log.Fatal().Err(err).Msg("error set role")
+		}
+		return
+	}
+
+
+	// refuse to start on a typo rather than serve without limits
+	if _, err := middleware.RateLimitLimits(); err != nil {
+		log.Fatal().Err(err).Msg("error parse RATE_LIMITS")
+	}
 
 	r := gin.Default()
+
+	// requests are rate limited by client ip, only proxies we run may set it
+	var trustedProxies []string
+	if config.TrustedProxies != "" {
+		for _, proxy := range strings.Split(config.TrustedProxies, ",") {
+			trustedProxies = append(trustedProxies, strings.TrimSpace(proxy))
+		}
+	}
+	if err := r.SetTrustedProxies(trustedProxies); err != nil {
+		log.Fatal().Err(err).Msg("error parse TRUSTED_PROXIES")
+	}
+
 	route :=r.Group("/api/v1")
 
-	client := httpclient.NewClient(&http.Client{})
+	client := httpclient.NewClient(&http.Client{}, httpclient.WithRetry(httpclient.RetryPolicy{
+		MaxAttempts: orDefault(config.HTTPRetryMaxAttempts, 3),
+		BaseDelay: orDefault(config.HTTPRetryBaseDelay, 200*time.Millisecond),
+		MaxDelay: orDefault(config.HTTPRetryMaxDelay, 5*time.Second),
+		MaxRetryAfter: orDefault(config.HTTPRetryMaxRetryAfter, 30*time.Second),
+	}), httpclient.WithCircuitBreaker(httpclient.BreakerPolicy{
+		FailureThreshold: orDefault(config.BreakerFailureThreshold, 5),
+		OpenTimeout: orDefault(config.BreakerOpenTimeout, 30*time.Second),
+		HalfOpenMaxRequests: orDefault(config.BreakerHalfOpenMaxRequests, 1),
+	}))
 	if client == nil {
     log.Fatal().Msg("Failed to initialize http client")
 	}
 
 	log.Info().Msgf("client %v", client)
 
+	// run against the in-process spotify emulator instead of the real api
+	if config.SpotifyFake {
+		fake, err := spotifyfake.NewDefault()
+		if err != nil {
+			log.Fatal().Err(err).Msg("failed to load spotify fake catalogue")
+		}
+
+		fake.ClientID = config.SpotifyClientID
+		fake.ClientSecret = config.SpotifyClientSecret
+
+		fakeURL, fakeServer, err := fake.Start("127.0.0.1:0")
+		if err != nil {
+			log.Fatal().Err(err).Msg("failed to start spotify fake")
+		}
+		defer fakeServer.Close()
+
+		config.SpotifyAccountsBaseURL = fakeURL
+		config.SpotifyAPIBaseURL = fakeURL + "/v1"
+		log.Info().Msgf("using spotify fake at %s", fakeURL)
+	}
+
+	// linked spotify accounts need a key to store their tokens with
+	var tokenBox *secretbox.Box
+	if config.SpotifyTokenKey != "" {
+		tokenBox, err = secretbox.New(config.SpotifyTokenKey)
+		if err != nil {
+			log.Fatal().Err(err).Msg("invalid spotify token key")
+		}
+	} else {
+		log.Warn().Msg("SPOTIFY_TOKEN_KEY is not set, spotify account linking is disabled")
+	}
+
 	// repositorys
-	spotifyOutbond := spotifyRepo.NewSpotifyOutbond(config, client)
+	outbond := spotifyRepo.NewSpotifyOutbond(config, client)
+	spotifyOutbond := spotifyRepo.NewCachedSpotifyOutbond(
+		outbond,
+		orDefault(config.SearchCacheSize, 1000),
+		orDefault(config.SearchCacheTTL, 5*time.Minute),
+		orDefault(config.SearchCacheStaleWhileRevalidate, 10*time.Minute),
+		orDefault(config.SearchCacheStaleIfError, time.Hour),
+	)
 	userRepo := repositorys.NewUserRepo(db)
+	refreshTokenRepo := repositorys.NewRefreshTokenRepo(db)
 	spotifyRepository := spotifyRepo.NewSpotifyRepository(db)
+	playlistRepository := playlistRepo.NewPlaylistRepository(db)
+	playRepository := playRepo.NewPlayRepository(db)
+	tagRepository := tagRepo.NewTagRepository(db)
+	importRepository := importRepo.NewImportJobRepository(db)
+	spotifyAccountRepository := spotifyAccountRepo.NewSpotifyAccountRepository(db)
+	spotifySyncRepository := spotifySyncRepo.NewSpotifySyncRepository(db)
 
 
 	// services
-	userService := services.NewUserService(userRepo, config)
+	userService := services.NewUserService(userRepo, refreshTokenRepo, config)
 	spotifyService := spotifySvc.NewSpotifyServie(spotifyOutbond, spotifyRepository)
+	playlistService := playlistSvc.NewPlaylistService(playlistRepository, spotifyOutbond, spotifyRepository)
+	playService := playSvc.NewPlayService(playRepository, spotifyOutbond, spotifyRepository)
+	tagService := tagSvc.NewTagService(tagRepository)
+	exportService := exportSvc.NewExportService(spotifyOutbond, spotifyRepository, playlistRepository)
+	// imports search with queries nobody else sends, they would only evict
+	// cached searches
+	importService := importSvc.NewImportService(importRepository, outbond, spotifyRepository, playlistRepository)
+	spotifyAccountService := spotifyAccountSvc.NewSpotifyAccountService(spotifyAccountRepository, outbond, tokenBox, config)
+
+	// calls made with spotifyRepo.WithUserToken use the linked account's token
+	outbond.SetUserTokenSource(spotifyAccountService)
+	spotifySyncService := spotifySyncSvc.NewSpotifySyncService(spotifySyncRepository, spotifyAccountRepository, outbond, spotifyRepository)
+
+	// plays are only written to partitions created here, never on the request path
+	err = playService.EnsurePartitions(context.Background())
+	if err != nil {
+		log.Fatal().Err(err).Msg("error create play event partitions")
+	}
+
+	// imports run in the replica that accepted them, whatever a replica was
+	// running when it went away is lost
+	err = importService.FailInterrupted(context.Background())
+	if err != nil {
+		log.Error().Err(err).Msg("error fail interrupted imports")
+	}
+	importService.Start()
+
+	err = spotifySyncService.FailInterrupted(context.Background())
+	if err != nil {
+		log.Error().Err(err).Msg("error fail interrupted spotify syncs")
+	}
+
+	// without a token key nobody can link an account, there is nothing to sync
+	syncInterval := orDefault(config.SpotifySyncInterval, time.Hour)
+	if tokenBox != nil && syncInterval > 0 {
+		spotifySyncService.Start(syncInterval)
+	}
 
 	// // handlers
+	healthHandler := handlers.NewHealthHandler(r.Group(""),
+		handlers.ReadinessCheck{Name: "database", Check: sqlDB.PingContext},
+		handlers.ReadinessCheck{Name: "spotify", Check: func(ctx context.Context) error {
+			_, _, err := outbond.GetTokenDetails(ctx)
+			return err
+		}},
+	)
 	userHandler := handlers.NewUserHandler(userService, route)
 	spotifyHandler := spotify.NewSpotifyHandler(spotifyService, route)
+	playlistHandler := playlist.NewPlaylistHandler(playlistService, route)
+	playHandler := play.NewPlayHandler(playService, route)
+	tagHandler := tag.NewTagHandler(tagService, route)
+	exportHandler := export.NewExportHandler(exportService, route)
+	importHandler := importjob.NewImportHandler(importService, route)
+	spotifyAccountHandler := spotifyaccount.NewSpotifyAccountHandler(spotifyAccountService, route)
+	spotifySyncHandler := spotifysync.NewSpotifySyncHandler(spotifySyncService, route)
 
 	// // register route
+	healthHandler.RegisterRoute()
 	userHandler.RegisterRoute()
 	spotifyHandler.RegisterRoute()
+	playlistHandler.RegisterRoute()
+	playHandler.RegisterRoute()
+	tagHandler.RegisterRoute()
+	exportHandler.RegisterRoute()
+	importHandler.RegisterRoute()
+	spotifyAccountHandler.RegisterRoute()
+	spotifySyncHandler.RegisterRoute()
+
+	server := &http.Server{
+		Addr: config.PORT,
+		Handler: r,
+		ReadHeaderTimeout: orDefault(config.ServerReadHeaderTimeout, 5*time.Second),
+		ReadTimeout: orDefault(config.ServerReadTimeout, 15*time.Second),
+		WriteTimeout: orDefault(config.ServerWriteTimeout, 30*time.Second),
+		IdleTimeout: orDefault(config.ServerIdleTimeout, 2*time.Minute),
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	// keep next month's play partition ready before the month starts
+	go func() {
+		ticker := time.NewTicker(24 * time.Hour)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				err := playService.EnsurePartitions(ctx)
+				if err != nil {
+					log.Error().Err(err).Msg("error create play event partitions")
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	// a serve error goes back to main instead of log.Fatal, which would skip
+	// the deferred closes and the shutdown below
+	serveErr := make(chan error, 1)
+	go func() {
+		log.Info().Msgf("listening on %s", config.PORT)
+		err := server.ListenAndServe()
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serveErr <- err
+		}
+	}()
+
+	select {
+	case <-ctx.Done():
+		log.Info().Msg("shutting down, draining in-flight requests")
+
+		// fail readiness first and give load balancers time to notice before
+		// the listener stops accepting connections
+		healthHandler.Drain()
+		time.Sleep(config.ShutdownDrainDelay)
+	case err = <-serveErr:
+		log.Error().Err(err).Msg("error serve http, shutting down")
+		exitCode = 1
+	}
+	stop()
+
+	shutdownTimeout := orDefault(config.ShutdownTimeout, 20*time.Second)
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	err = server.Shutdown(shutdownCtx)
+	if err != nil {
+		log.Error().Err(err).Msg("error graceful shutdown")
+		exitCode = 1
+	}
+
+	// the background work is stopped even when requests didn't drain in
+	// time, each with a deadline of its own so neither eats the other's.
+	// imports and syncs that don't finish in time are stopped and marked as
+	// failed
+	importCtx, cancelImports := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancelImports()
+
+	err = importService.Shutdown(importCtx)
+	if err != nil {
+		log.Error().Err(err).Msg("error stop import jobs")
+	}
+
+	syncCtx, cancelSyncs := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancelSyncs()
+
+	err = spotifySyncService.Shutdown(syncCtx)
+	if err != nil {
+		log.Error().Err(err).Msg("error stop spotify syncs")
+	}
+
+	log.Info().Msg("server stopped")
+}
+
+func orDefault[T comparable](value, fallback T) T {
+	var zero T
+	if value == zero {
+		return fallback
+	}
 
-	r.Run(config.PORT)
-}
\ No newline at end of file
+	return value
+}
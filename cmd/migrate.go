@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/sgitwhyd/music-catalogue/internal/migrations"
+)
+
+const (
+	// migrationModeAuto applies pending migrations on boot.
+	migrationModeAuto = "auto"
+	// migrationModeVerify refuses to start while migrations are pending,
+	// for deployments that run `migrate up` as a separate step.
+	migrationModeVerify = "verify"
+)
+
+// runMigrateCommand handles `migrate up|down|status`.
+func runMigrateCommand(ctx context.Context, migrator *migrations.Migrator, args []string) error {
+	if len(args) != 1 {
+		return errors.New("usage: migrate up|down|status")
+	}
+
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		if err != nil {
+			return err
+		}
+
+		log.Info().Msgf("applied %d migrations", len(applied))
+		return nil
+	case "down":
+		migration, err := migrator.Down(ctx)
+		if err != nil {
+			return err
+		}
+
+		log.Info().Msgf("rolled back %04d_%s", migration.Version, migration.Name)
+		return nil
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT")
+		for _, status := range statuses {
+			appliedAt := "pending"
+			if status.AppliedAt != nil {
+				appliedAt = status.AppliedAt.Format("2006-01-02 15:04:05 MST")
+			}
+
+			fmt.Fprintf(w, "%04d\t%s\t%s\n", status.Version, status.Name, appliedAt)
+		}
+
+		return w.Flush()
+	}
+
+	return fmt.Errorf("unknown migrate command %q, use up, down or status", args[0])
+}
+
+func migrateOnBoot(ctx context.Context, migrator *migrations.Migrator, mode string) error {
+	switch mode {
+	case "", migrationModeAuto:
+		_, err := migrator.Up(ctx)
+		return err
+	case migrationModeVerify:
+		pending, err := migrator.Pending(ctx)
+		if err != nil {
+			return err
+		}
+
+		if len(pending) > 0 {
+			return fmt.Errorf("schema is out of date, %d migrations pending starting with %04d_%s, run `migrate up`",
+				len(pending), pending[0].Version, pending[0].Name)
+		}
+
+		return nil
+	}
+
+	return fmt.Errorf("unknown MIGRATION_MODE %q, use auto or verify", mode)
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/sgitwhyd/music-catalogue/internal/models"
+	"github.com/sgitwhyd/music-catalogue/internal/repositorys"
+)
+
+// runRoleCommand handles `role <email> <role>`. It is how the first admin
+// comes about, after that admins hand out roles through the api.
+func runRoleCommand(userRepo repositorys.UserRepository, args []string) error {
+	if len(args) != 2 {
+		return errors.New("usage: role <email> user|admin")
+	}
+
+	email, role := args[0], args[1]
+	if !slices.Contains([]string{models.RoleUser, models.RoleAdmin}, role) {
+		return fmt.Errorf("unknown role %q", role)
+	}
+
+	user, err := userRepo.Find(email, "", 0)
+	if err != nil {
+		return fmt.Errorf("find user %s: %w", email, err)
+	}
+
+	err = userRepo.UpdateRole(user.ID, role)
+	if err != nil {
+		return err
+	}
+
+	log.Info().Msgf("%s is now %s, it applies from their next token refresh", email, role)
+	return nil
+}
@@ -0,0 +1,110 @@
+// Package apperror defines the typed errors services return and how each
+// kind maps onto an HTTP status, so handlers never inspect error strings.
+package apperror
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/sgitwhyd/music-catalogue/pkg/httpclient"
+)
+
+type Kind int
+
+const (
+	KindInternal Kind = iota
+	KindInvalid
+	KindUnauthorized
+	KindForbidden
+	KindNotFound
+	KindConflict
+	KindRateLimited
+	KindUnavailable
+)
+
+// Error is a domain error with a stable, machine-readable code. Declare them
+// once as package level sentinels and compare with errors.Is.
+type Error struct {
+	Kind    Kind
+	Code    string
+	Message string
+}
+
+func New(kind Kind, code, message string) *Error {
+	return &Error{
+		Kind:    kind,
+		Code:    code,
+		Message: message,
+	}
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Internal is what clients see for errors that aren't an *Error, the
+// original message may leak implementation details.
+var Internal = New(KindInternal, "internal_error", "internal server error")
+
+var (
+	UpstreamInvalid     = New(KindInvalid, "upstream_invalid_request", "the music provider rejected the request")
+	UpstreamNotFound    = New(KindNotFound, "upstream_not_found", "the music provider has no such resource")
+	UpstreamRateLimited = New(KindRateLimited, "upstream_rate_limited", "the music provider is rate limiting requests, try again later")
+	// returned without calling the music provider while the circuit breaker
+	// is open
+	UpstreamUnavailable = New(KindUnavailable, "upstream_unavailable", "the music provider is unavailable, try again later")
+)
+
+// upstreamError is implemented by errors carrying the status an upstream API
+// answered with.
+type upstreamError interface {
+	UpstreamStatus() int
+}
+
+// From returns the *Error in err's chain, the upstream error matching the
+// status an upstream API answered with or an open circuit, or Internal.
+func From(err error) *Error {
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return appErr
+	}
+
+	if errors.Is(err, httpclient.ErrCircuitOpen) {
+		return UpstreamUnavailable
+	}
+
+	var upstream upstreamError
+	if errors.As(err, &upstream) {
+		switch upstream.UpstreamStatus() {
+		case http.StatusBadRequest:
+			return UpstreamInvalid
+		case http.StatusNotFound:
+			return UpstreamNotFound
+		case http.StatusTooManyRequests:
+			return UpstreamRateLimited
+		}
+	}
+
+	return Internal
+}
+
+func (k Kind) Status() int {
+	switch k {
+	case KindInvalid:
+		return http.StatusUnprocessableEntity
+	case KindUnauthorized:
+		return http.StatusUnauthorized
+	case KindForbidden:
+		return http.StatusForbidden
+	case KindNotFound:
+		return http.StatusNotFound
+	case KindConflict:
+		return http.StatusConflict
+	case KindRateLimited:
+		return http.StatusTooManyRequests
+	case KindUnavailable:
+		return http.StatusServiceUnavailable
+	}
+
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,89 @@
+package apperror
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/sgitwhyd/music-catalogue/pkg/httpclient"
+	"github.com/stretchr/testify/assert"
+)
+
+type upstreamStatus int
+
+func (s upstreamStatus) Error() string {
+	return fmt.Sprintf("upstream status %d", int(s))
+}
+
+func (s upstreamStatus) UpstreamStatus() int {
+	return int(s)
+}
+
+func TestFrom(t *testing.T) {
+	errNotFound := New(KindNotFound, "track_not_found", "track not found")
+
+	tests := []struct {
+		name       string
+		err        error
+		want       *Error
+		wantStatus int
+	}{
+		{
+			name:       "should return the error itself",
+			err:        errNotFound,
+			want:       errNotFound,
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "should unwrap",
+			err:        fmt.Errorf("get track: %w", errNotFound),
+			want:       errNotFound,
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "should map an upstream 400",
+			err:        fmt.Errorf("search: %w", upstreamStatus(http.StatusBadRequest)),
+			want:       UpstreamInvalid,
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "should map an upstream 404",
+			err:        upstreamStatus(http.StatusNotFound),
+			want:       UpstreamNotFound,
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "should map an upstream 429",
+			err:        upstreamStatus(http.StatusTooManyRequests),
+			want:       UpstreamRateLimited,
+			wantStatus: http.StatusTooManyRequests,
+		},
+		{
+			name:       "should map an open circuit",
+			err:        fmt.Errorf("get track: %w", httpclient.ErrCircuitOpen),
+			want:       UpstreamUnavailable,
+			wantStatus: http.StatusServiceUnavailable,
+		},
+		{
+			name:       "should hide upstream 5xx",
+			err:        upstreamStatus(http.StatusBadGateway),
+			want:       Internal,
+			wantStatus: http.StatusInternalServerError,
+		},
+		{
+			name:       "should hide unknown errors",
+			err:        errors.New("pq: connection refused"),
+			want:       Internal,
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := From(tt.err)
+
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantStatus, got.Kind.Status())
+		})
+	}
+}
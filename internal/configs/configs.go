@@ -2,6 +2,7 @@ package configs
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/spf13/viper"
 )
@@ -14,6 +15,34 @@ type (
 		ENV											string	`mapstructure:"ENV"`
 		SpotifyClientID					string	`mapstructure:"SPOTIFY_CLIENT_ID"`
 		SpotifyClientSecret			string	`mapstructure:"SPOTIFY_CLIENT_SECRET"`
+		SpotifyAPIBaseURL				string	`mapstructure:"SPOTIFY_API_BASE_URL"`
+		SpotifyAccountsBaseURL	string	`mapstructure:"SPOTIFY_ACCOUNTS_BASE_URL"`
+		SpotifyFake							bool		`mapstructure:"SPOTIFY_FAKE"`
+		SpotifyRedirectURL			string	`mapstructure:"SPOTIFY_REDIRECT_URL"`
+		SpotifyScopes						string	`mapstructure:"SPOTIFY_SCOPES"`
+		SpotifyTokenKey					string	`mapstructure:"SPOTIFY_TOKEN_KEY"`
+		SpotifySyncInterval			time.Duration	`mapstructure:"SPOTIFY_SYNC_INTERVAL"`
+		RefreshTokenTTL					time.Duration	`mapstructure:"REFRESH_TOKEN_TTL"`
+		SearchCacheSize					int						`mapstructure:"SEARCH_CACHE_SIZE"`
+		SearchCacheTTL					time.Duration	`mapstructure:"SEARCH_CACHE_TTL"`
+		SearchCacheStaleWhileRevalidate	time.Duration	`mapstructure:"SEARCH_CACHE_STALE_WHILE_REVALIDATE"`
+		SearchCacheStaleIfError	time.Duration	`mapstructure:"SEARCH_CACHE_STALE_IF_ERROR"`
+		HTTPRetryMaxAttempts		int						`mapstructure:"HTTP_RETRY_MAX_ATTEMPTS"`
+		HTTPRetryBaseDelay			time.Duration	`mapstructure:"HTTP_RETRY_BASE_DELAY"`
+		HTTPRetryMaxDelay				time.Duration	`mapstructure:"HTTP_RETRY_MAX_DELAY"`
+		HTTPRetryMaxRetryAfter	time.Duration	`mapstructure:"HTTP_RETRY_MAX_RETRY_AFTER"`
+		BreakerFailureThreshold	int						`mapstructure:"BREAKER_FAILURE_THRESHOLD"`
+		BreakerOpenTimeout			time.Duration	`mapstructure:"BREAKER_OPEN_TIMEOUT"`
+		BreakerHalfOpenMaxRequests	int				`mapstructure:"BREAKER_HALF_OPEN_MAX_REQUESTS"`
+		ServerReadHeaderTimeout	time.Duration	`mapstructure:"SERVER_READ_HEADER_TIMEOUT"`
+		ServerReadTimeout				time.Duration	`mapstructure:"SERVER_READ_TIMEOUT"`
+		ServerWriteTimeout			time.Duration	`mapstructure:"SERVER_WRITE_TIMEOUT"`
+		ServerIdleTimeout				time.Duration	`mapstructure:"SERVER_IDLE_TIMEOUT"`
+		ShutdownTimeout					time.Duration	`mapstructure:"SHUTDOWN_TIMEOUT"`
+		ShutdownDrainDelay			time.Duration	`mapstructure:"SHUTDOWN_DRAIN_DELAY"`
+		MigrationMode						string				`mapstructure:"MIGRATION_MODE"`
+		RateLimits							string				`mapstructure:"RATE_LIMITS"`
+		TrustedProxies					string				`mapstructure:"TRUSTED_PROXIES"`
 	}
 )
 
@@ -0,0 +1,69 @@
+package export
+
+import (
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+	"github.com/sgitwhyd/music-catalogue/internal/handlers/response"
+	"github.com/sgitwhyd/music-catalogue/internal/middleware"
+	"github.com/sgitwhyd/music-catalogue/internal/models/export"
+	exportService "github.com/sgitwhyd/music-catalogue/internal/services/export"
+	"github.com/sgitwhyd/music-catalogue/pkg/trackio"
+)
+
+type handler struct {
+	service exportService.ExportService
+	route   *gin.RouterGroup
+}
+
+func NewExportHandler(service exportService.ExportService, route *gin.RouterGroup) *handler {
+	return &handler{
+		service: service,
+		route:   route,
+	}
+}
+
+func (h *handler) Export(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var request export.ExportRequest
+	err := c.ShouldBindQuery(&request)
+	if err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	filename := "liked-tracks"
+	if request.PlaylistID != 0 {
+		filename = fmt.Sprintf("playlist-%d", request.PlaylistID)
+	}
+
+	c.Header("Content-Type", trackio.ContentType(request.Format))
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.%s"`, filename, request.Format))
+
+	userID := c.GetUint("userID")
+	err = h.service.Export(ctx, userID, request, c.Writer)
+	if err != nil {
+		log.Error().Err(err).Msg("error handler: Export")
+
+		// part of the listing went out already, all that's left is to cut
+		// the response short
+		if c.Writer.Written() {
+			c.Abort()
+			return
+		}
+
+		// gin keeps a content type that was already set
+		c.Writer.Header().Del("Content-Type")
+		c.Writer.Header().Del("Content-Disposition")
+		response.Error(c, err)
+	}
+}
+
+func (h *handler) RegisterRoute() {
+	route := h.route.Group("/export")
+	route.Use(middleware.AuthMiddleware(), middleware.RateLimitMiddleware("export"))
+
+	route.GET("", h.Export)
+}
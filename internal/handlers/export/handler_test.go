@@ -0,0 +1,106 @@
+package export
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sgitwhyd/music-catalogue/internal/configs"
+	"github.com/sgitwhyd/music-catalogue/internal/models"
+	"github.com/sgitwhyd/music-catalogue/internal/models/export"
+	playlistService "github.com/sgitwhyd/music-catalogue/internal/services/playlist"
+	"github.com/sgitwhyd/music-catalogue/pkg/jwt"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func Test_handler_Export(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockSvc := NewMockExportService(mockCtrl)
+
+	tests := []struct {
+		name                string
+		query               string
+		mockFn              func()
+		expectedStatusCode  int
+		expectedContentType string
+		expectedDisposition string
+		expectedBody        string
+	}{
+		{
+			name:  "success",
+			query: "?format=csv",
+			mockFn: func() {
+				mockSvc.EXPECT().Export(gomock.Any(), uint(1), export.ExportRequest{Format: "csv"}, gomock.Any()).
+					DoAndReturn(func(ctx context.Context, userID uint, request export.ExportRequest, w io.Writer) error {
+						_, err := io.WriteString(w, "name,artists,album,duration_ms,isrc,uri\n")
+						return err
+					})
+			},
+			expectedStatusCode:  200,
+			expectedContentType: "text/csv; charset=utf-8",
+			expectedDisposition: `attachment; filename="liked-tracks.csv"`,
+			expectedBody:        "name,artists,album,duration_ms,isrc,uri\n",
+		},
+		{
+			name:               "unknown format",
+			query:              "?format=wav",
+			mockFn:             func() {},
+			expectedStatusCode: 422,
+		},
+		{
+			name:  "playlist not found",
+			query: "?format=xspf&playlist_id=3",
+			mockFn: func() {
+				mockSvc.EXPECT().Export(gomock.Any(), uint(1), export.ExportRequest{Format: "xspf", PlaylistID: 3}, gomock.Any()).
+					Return(playlistService.ErrPlaylistNotFound)
+			},
+			expectedStatusCode:  404,
+			expectedContentType: "application/json; charset=utf-8",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFn()
+
+			config, err := configs.Init("../../configs", "env", "test.env")
+			assert.NoError(t, err)
+
+			gin.SetMode(gin.ReleaseMode)
+
+			r := gin.New()
+			route := r.Group("/api/v1")
+
+			h := NewExportHandler(mockSvc, route)
+			h.RegisterRoute()
+
+			req, err := http.NewRequest(http.MethodGet, "/api/v1/export"+tt.query, nil)
+			assert.NoError(t, err)
+
+			token, err := jwt.CreateToken(uint(1), "username", models.RoleUser, config.SecretJWT)
+			assert.NoError(t, err)
+
+			req.Header.Set("Authorization", token)
+
+			w := httptest.NewRecorder()
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+
+			if tt.expectedContentType != "" {
+				assert.Equal(t, tt.expectedContentType, w.Header().Get("Content-Type"))
+				assert.Equal(t, tt.expectedDisposition, w.Header().Get("Content-Disposition"))
+			}
+
+			if tt.expectedBody != "" {
+				assert.Equal(t, tt.expectedBody, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+)
+
+const readinessCheckTimeout = 2 * time.Second
+
+// ReadinessCheck is a dependency that must be reachable before the service
+// takes traffic.
+type ReadinessCheck struct {
+	Name  string
+	Check func(ctx context.Context) error
+}
+
+type healthHandler struct {
+	route    *gin.RouterGroup
+	checks   []ReadinessCheck
+	draining atomic.Bool
+}
+
+func NewHealthHandler(route *gin.RouterGroup, checks ...ReadinessCheck) *healthHandler {
+	return &healthHandler{
+		route:  route,
+		checks: checks,
+	}
+}
+
+// Drain makes readiness fail so the orchestrator stops routing new requests
+// while in-flight ones finish.
+func (h *healthHandler) Drain() {
+	h.draining.Store(true)
+}
+
+// Liveness only tells the process is serving, it never checks dependencies
+// so a database outage doesn't get every instance restarted.
+func (h *healthHandler) Liveness(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"status": "ok",
+	})
+}
+
+func (h *healthHandler) Readiness(c *gin.Context) {
+	if h.draining.Load() {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"status": "draining",
+		})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), readinessCheckTimeout)
+	defer cancel()
+
+	status := http.StatusOK
+	results := make(map[string]string, len(h.checks))
+	for _, check := range h.checks {
+		err := check.Check(ctx)
+		if err != nil {
+			log.Error().Err(err).Msgf("readiness check %s failed", check.Name)
+			status = http.StatusServiceUnavailable
+			results[check.Name] = err.Error()
+			continue
+		}
+
+		results[check.Name] = "ok"
+	}
+
+	body := gin.H{
+		"status": "ok",
+		"checks": results,
+	}
+	if status != http.StatusOK {
+		body["status"] = "unavailable"
+	}
+
+	c.JSON(status, body)
+}
+
+func (h *healthHandler) RegisterRoute() {
+	h.route.GET("/healthz", h.Liveness)
+	h.route.GET("/readyz", h.Readiness)
+}
@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_healthHandler_Readiness(t *testing.T) {
+	ok := func(ctx context.Context) error { return nil }
+	failing := func(ctx context.Context) error { return errors.New("connection refused") }
+
+	tests := []struct {
+		name               string
+		checks             []ReadinessCheck
+		drain              bool
+		expectedStatusCode int
+		expectedChecks     map[string]string
+	}{
+		{
+			name: "should be ready when every check passes",
+			checks: []ReadinessCheck{
+				{Name: "database", Check: ok},
+				{Name: "spotify", Check: ok},
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedChecks:     map[string]string{"database": "ok", "spotify": "ok"},
+		},
+		{
+			name: "should not be ready when a check fails",
+			checks: []ReadinessCheck{
+				{Name: "database", Check: ok},
+				{Name: "spotify", Check: failing},
+			},
+			expectedStatusCode: http.StatusServiceUnavailable,
+			expectedChecks:     map[string]string{"database": "ok", "spotify": "connection refused"},
+		},
+		{
+			name: "should not be ready while draining",
+			checks: []ReadinessCheck{
+				{Name: "database", Check: ok},
+			},
+			drain:              true,
+			expectedStatusCode: http.StatusServiceUnavailable,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := gin.New()
+
+			h := NewHealthHandler(r.Group(""), tt.checks...)
+			h.RegisterRoute()
+			if tt.drain {
+				h.Drain()
+			}
+
+			w := httptest.NewRecorder()
+			req, err := http.NewRequest(http.MethodGet, "/readyz", nil)
+			assert.NoError(t, err)
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+
+			response := struct {
+				Checks map[string]string `json:"checks"`
+			}{}
+			err = json.Unmarshal(w.Body.Bytes(), &response)
+			assert.NoError(t, err)
+
+			assert.Equal(t, tt.expectedChecks, response.Checks)
+
+			// liveness doesn't depend on the checks
+			w = httptest.NewRecorder()
+			req, err = http.NewRequest(http.MethodGet, "/healthz", nil)
+			assert.NoError(t, err)
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package importjob
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+	"github.com/sgitwhyd/music-catalogue/internal/apperror"
+	"github.com/sgitwhyd/music-catalogue/internal/handlers/response"
+	"github.com/sgitwhyd/music-catalogue/internal/middleware"
+	"github.com/sgitwhyd/music-catalogue/internal/models/importjob"
+	importService "github.com/sgitwhyd/music-catalogue/internal/services/importjob"
+)
+
+// uploads larger than this are cut off before they are parsed
+const maxImportFileSize = 10 << 20
+
+var (
+	ErrInvalidImportID    = apperror.New(apperror.KindInvalid, "invalid_import_id", "import id must be a positive number")
+	ErrMissingImportFile  = apperror.New(apperror.KindInvalid, "missing_import_file", "file is required")
+	ErrImportFileTooLarge = apperror.New(apperror.KindInvalid, "import_file_too_large", "file can't be larger than 10 MB")
+)
+
+type handler struct {
+	service importService.ImportService
+	route   *gin.RouterGroup
+}
+
+func NewImportHandler(service importService.ImportService, route *gin.RouterGroup) *handler {
+	return &handler{
+		service: service,
+		route:   route,
+	}
+}
+
+func (h *handler) Create(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// room for the other form fields on top of the file
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportFileSize+1<<20)
+
+	var request importjob.CreateImportRequest
+	err := c.ShouldBind(&request)
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			response.Error(c, ErrImportFileTooLarge)
+			return
+		}
+
+		response.BindError(c, err)
+		return
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		response.Error(c, ErrMissingImportFile)
+		return
+	}
+
+	if fileHeader.Size > maxImportFileSize {
+		response.Error(c, ErrImportFileTooLarge)
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		log.Error().Err(err).Msg("error handler: open import file")
+		response.Error(c, err)
+		return
+	}
+	defer file.Close()
+
+	userID := c.GetUint("userID")
+	job, err := h.service.Create(ctx, userID, request, fileHeader.Filename, file)
+	if err != nil {
+		log.Error().Err(err).Msg("error handler: Create import")
+		response.Error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, job)
+}
+
+func (h *handler) Get(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	jobID, ok := importIDParam(c)
+	if !ok {
+		return
+	}
+
+	userID := c.GetUint("userID")
+	job, err := h.service.Get(ctx, userID, jobID)
+	if err != nil {
+		log.Error().Err(err).Msg("error handler: Get import")
+		response.Error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, job)
+}
+
+func (h *handler) ListRows(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	jobID, ok := importIDParam(c)
+	if !ok {
+		return
+	}
+
+	var request importjob.ListImportRowsRequest
+	err := c.ShouldBindQuery(&request)
+	if err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	userID := c.GetUint("userID")
+	rows, err := h.service.ListRows(ctx, userID, jobID, request)
+	if err != nil {
+		log.Error().Err(err).Msg("error handler: ListRows import")
+		response.Error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, rows)
+}
+
+// importIDParam parses the :id path parameter, writing the error response
+// when it isn't a valid id.
+func importIDParam(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil || id == 0 {
+		response.Error(c, ErrInvalidImportID)
+		return 0, false
+	}
+
+	return uint(id), true
+}
+
+func (h *handler) RegisterRoute() {
+	route := h.route.Group("/imports")
+	route.Use(middleware.AuthMiddleware(), middleware.RateLimitMiddleware("imports"))
+
+	route.POST("", h.Create)
+	route.GET("/:id", h.Get)
+	route.GET("/:id/rows", h.ListRows)
+}
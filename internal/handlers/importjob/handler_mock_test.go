@@ -0,0 +1,88 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: service.go
+//
+// Generated by this command:
+//
+//	mockgen -source=service.go -destination=../../handlers/importjob/handler_mock_test.go -package=importjob
+//
+
+// Package importjob is a generated GoMock package.
+package importjob
+
+import (
+	context "context"
+	io "io"
+	reflect "reflect"
+
+	importjob "github.com/sgitwhyd/music-catalogue/internal/models/importjob"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockImportService is a mock of ImportService interface.
+type MockImportService struct {
+	ctrl     *gomock.Controller
+	recorder *MockImportServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockImportServiceMockRecorder is the mock recorder for MockImportService.
+type MockImportServiceMockRecorder struct {
+	mock *MockImportService
+}
+
+// NewMockImportService creates a new mock instance.
+func NewMockImportService(ctrl *gomock.Controller) *MockImportService {
+	mock := &MockImportService{ctrl: ctrl}
+	mock.recorder = &MockImportServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockImportService) EXPECT() *MockImportServiceMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockImportService) Create(ctx context.Context, userID uint, request importjob.CreateImportRequest, filename string, file io.Reader) (*importjob.ImportJobResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, userID, request, filename, file)
+	ret0, _ := ret[0].(*importjob.ImportJobResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockImportServiceMockRecorder) Create(ctx, userID, request, filename, file any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockImportService)(nil).Create), ctx, userID, request, filename, file)
+}
+
+// Get mocks base method.
+func (m *MockImportService) Get(ctx context.Context, userID, jobID uint) (*importjob.ImportJobResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, userID, jobID)
+	ret0, _ := ret[0].(*importjob.ImportJobResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockImportServiceMockRecorder) Get(ctx, userID, jobID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockImportService)(nil).Get), ctx, userID, jobID)
+}
+
+// ListRows mocks base method.
+func (m *MockImportService) ListRows(ctx context.Context, userID, jobID uint, request importjob.ListImportRowsRequest) (*importjob.ListImportRowsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRows", ctx, userID, jobID, request)
+	ret0, _ := ret[0].(*importjob.ListImportRowsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRows indicates an expected call of ListRows.
+func (mr *MockImportServiceMockRecorder) ListRows(ctx, userID, jobID, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRows", reflect.TypeOf((*MockImportService)(nil).ListRows), ctx, userID, jobID, request)
+}
@@ -0,0 +1,220 @@
+package importjob
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sgitwhyd/music-catalogue/internal/configs"
+	"github.com/sgitwhyd/music-catalogue/internal/handlers/response"
+	"github.com/sgitwhyd/music-catalogue/internal/models"
+	"github.com/sgitwhyd/music-catalogue/internal/models/importjob"
+	importService "github.com/sgitwhyd/music-catalogue/internal/services/importjob"
+	"github.com/sgitwhyd/music-catalogue/pkg/jwt"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func Test_handler_Create(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockSvc := NewMockImportService(mockCtrl)
+
+	tests := []struct {
+		name               string
+		fields             map[string]string
+		filename           string
+		content            string
+		mockFn             func()
+		expectedStatusCode int
+		expectedCode       string
+		expectedBody       importjob.ImportJobResponse
+	}{
+		{
+			name:     "success",
+			fields:   map[string]string{"target": "playlist", "name": "Road Trip"},
+			filename: "road-trip.m3u",
+			content:  "#EXTM3U\n01.mp3\n",
+			mockFn: func() {
+				mockSvc.EXPECT().Create(gomock.Any(), uint(1), importjob.CreateImportRequest{Target: "playlist", Name: "Road Trip"}, "road-trip.m3u", gomock.Any()).
+					Return(&importjob.ImportJobResponse{ID: 7, Status: importjob.StatusPending, Target: "playlist", Format: "m3u", Total: 1}, nil)
+			},
+			expectedStatusCode: 202,
+			expectedBody:       importjob.ImportJobResponse{ID: 7, Status: importjob.StatusPending, Target: "playlist", Format: "m3u", Total: 1},
+		},
+		{
+			name:               "unknown target",
+			fields:             map[string]string{"target": "albums"},
+			filename:           "liked.csv",
+			content:            "name\n",
+			mockFn:             func() {},
+			expectedStatusCode: 422,
+			expectedCode:       "invalid_request",
+		},
+		{
+			name:               "missing file",
+			fields:             map[string]string{"target": "likes"},
+			mockFn:             func() {},
+			expectedStatusCode: 422,
+			expectedCode:       "missing_import_file",
+		},
+		{
+			name:     "empty file",
+			fields:   map[string]string{"target": "likes"},
+			filename: "liked.csv",
+			content:  "name\n",
+			mockFn: func() {
+				mockSvc.EXPECT().Create(gomock.Any(), uint(1), gomock.Any(), "liked.csv", gomock.Any()).
+					Return(nil, importService.ErrEmptyImport)
+			},
+			expectedStatusCode: 422,
+			expectedCode:       "empty_import",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFn()
+
+			config, err := configs.Init("../../configs", "env", "test.env")
+			assert.NoError(t, err)
+
+			gin.SetMode(gin.ReleaseMode)
+
+			r := gin.New()
+			route := r.Group("/api/v1")
+
+			h := NewImportHandler(mockSvc, route)
+			h.RegisterRoute()
+
+			var body bytes.Buffer
+			form := multipart.NewWriter(&body)
+			for key, value := range tt.fields {
+				assert.NoError(t, form.WriteField(key, value))
+			}
+			if tt.filename != "" {
+				part, err := form.CreateFormFile("file", tt.filename)
+				assert.NoError(t, err)
+
+				_, err = part.Write([]byte(tt.content))
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, form.Close())
+
+			req, err := http.NewRequest(http.MethodPost, "/api/v1/imports", &body)
+			assert.NoError(t, err)
+
+			token, err := jwt.CreateToken(uint(1), "username", models.RoleUser, config.SecretJWT)
+			assert.NoError(t, err)
+
+			req.Header.Set("Authorization", token)
+			req.Header.Set("Content-Type", form.FormDataContentType())
+
+			w := httptest.NewRecorder()
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+
+			if tt.expectedCode != "" {
+				res := response.ErrorEnvelope{}
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+				assert.Equal(t, tt.expectedCode, res.Error.Code)
+				return
+			}
+
+			res := importjob.ImportJobResponse{}
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+			assert.Equal(t, tt.expectedBody, res)
+		})
+	}
+}
+
+func Test_handler_ListRows(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockSvc := NewMockImportService(mockCtrl)
+
+	tests := []struct {
+		name               string
+		path               string
+		mockFn             func()
+		expectedStatusCode int
+		expectedCode       string
+	}{
+		{
+			name: "success",
+			path: "/api/v1/imports/7/rows?status=ambiguous&pageSize=10",
+			mockFn: func() {
+				mockSvc.EXPECT().ListRows(gomock.Any(), uint(1), uint(7), importjob.ListImportRowsRequest{Status: "ambiguous", PageSize: 10}).
+					Return(&importjob.ListImportRowsResponse{Items: []importjob.ImportRowResponse{}, Limit: 10}, nil)
+			},
+			expectedStatusCode: 200,
+		},
+		{
+			name:               "unknown status",
+			path:               "/api/v1/imports/7/rows?status=skipped",
+			mockFn:             func() {},
+			expectedStatusCode: 422,
+			expectedCode:       "invalid_request",
+		},
+		{
+			name:               "invalid id",
+			path:               "/api/v1/imports/abc/rows",
+			mockFn:             func() {},
+			expectedStatusCode: 422,
+			expectedCode:       "invalid_import_id",
+		},
+		{
+			name: "not found",
+			path: "/api/v1/imports/7/rows",
+			mockFn: func() {
+				mockSvc.EXPECT().ListRows(gomock.Any(), uint(1), uint(7), gomock.Any()).
+					Return(nil, importService.ErrImportNotFound)
+			},
+			expectedStatusCode: 404,
+			expectedCode:       "import_not_found",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFn()
+
+			config, err := configs.Init("../../configs", "env", "test.env")
+			assert.NoError(t, err)
+
+			gin.SetMode(gin.ReleaseMode)
+
+			r := gin.New()
+			route := r.Group("/api/v1")
+
+			h := NewImportHandler(mockSvc, route)
+			h.RegisterRoute()
+
+			req, err := http.NewRequest(http.MethodGet, tt.path, nil)
+			assert.NoError(t, err)
+
+			token, err := jwt.CreateToken(uint(1), "username", models.RoleUser, config.SecretJWT)
+			assert.NoError(t, err)
+
+			req.Header.Set("Authorization", token)
+
+			w := httptest.NewRecorder()
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+
+			if tt.expectedCode != "" {
+				res := response.ErrorEnvelope{}
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+				assert.Equal(t, tt.expectedCode, res.Error.Code)
+			}
+		})
+	}
+}
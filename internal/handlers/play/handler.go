@@ -0,0 +1,74 @@
+package play
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+	"github.com/sgitwhyd/music-catalogue/internal/handlers/response"
+	"github.com/sgitwhyd/music-catalogue/internal/middleware"
+	"github.com/sgitwhyd/music-catalogue/internal/models/play"
+	playService "github.com/sgitwhyd/music-catalogue/internal/services/play"
+)
+
+type handler struct {
+	service playService.PlayService
+	route   *gin.RouterGroup
+}
+
+func NewPlayHandler(service playService.PlayService, route *gin.RouterGroup) *handler {
+	return &handler{
+		service: service,
+		route:   route,
+	}
+}
+
+func (h *handler) Record(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var request play.CreatePlayRequest
+	err := c.ShouldBindJSON(&request)
+	if err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	userID := c.GetUint("userID")
+	recorded, err := h.service.Record(ctx, userID, request)
+	if err != nil {
+		log.Error().Err(err).Msg("error handler: Record play")
+		response.Error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, recorded)
+}
+
+func (h *handler) ListRecent(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var request play.RecentPlaysRequest
+	err := c.ShouldBindQuery(&request)
+	if err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	userID := c.GetUint("userID")
+	plays, err := h.service.ListRecent(ctx, userID, request)
+	if err != nil {
+		log.Error().Err(err).Msg("error handler: ListRecent plays")
+		response.Error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, plays)
+}
+
+func (h *handler) RegisterRoute() {
+	route := h.route.Group("/plays")
+	route.Use(middleware.AuthMiddleware(), middleware.RateLimitMiddleware("plays"))
+
+	route.POST("", h.Record)
+	route.GET("/recent", h.ListRecent)
+}
@@ -0,0 +1,72 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: service.go
+//
+// Generated by this command:
+//
+//	mockgen -source=service.go -destination=../../handlers/play/handler_mock_test.go -package=play
+//
+
+// Package play is a generated GoMock package.
+package play
+
+import (
+	context "context"
+	reflect "reflect"
+
+	play "github.com/sgitwhyd/music-catalogue/internal/models/play"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockPlayService is a mock of PlayService interface.
+type MockPlayService struct {
+	ctrl     *gomock.Controller
+	recorder *MockPlayServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockPlayServiceMockRecorder is the mock recorder for MockPlayService.
+type MockPlayServiceMockRecorder struct {
+	mock *MockPlayService
+}
+
+// NewMockPlayService creates a new mock instance.
+func NewMockPlayService(ctrl *gomock.Controller) *MockPlayService {
+	mock := &MockPlayService{ctrl: ctrl}
+	mock.recorder = &MockPlayServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPlayService) EXPECT() *MockPlayServiceMockRecorder {
+	return m.recorder
+}
+
+// ListRecent mocks base method.
+func (m *MockPlayService) ListRecent(ctx context.Context, userID uint, request play.RecentPlaysRequest) (*play.RecentPlaysResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRecent", ctx, userID, request)
+	ret0, _ := ret[0].(*play.RecentPlaysResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRecent indicates an expected call of ListRecent.
+func (mr *MockPlayServiceMockRecorder) ListRecent(ctx, userID, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRecent", reflect.TypeOf((*MockPlayService)(nil).ListRecent), ctx, userID, request)
+}
+
+// Record mocks base method.
+func (m *MockPlayService) Record(ctx context.Context, userID uint, request play.CreatePlayRequest) (*play.PlayResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Record", ctx, userID, request)
+	ret0, _ := ret[0].(*play.PlayResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Record indicates an expected call of Record.
+func (mr *MockPlayServiceMockRecorder) Record(ctx, userID, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Record", reflect.TypeOf((*MockPlayService)(nil).Record), ctx, userID, request)
+}
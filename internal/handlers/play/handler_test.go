@@ -0,0 +1,177 @@
+package play
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sgitwhyd/music-catalogue/internal/configs"
+	"github.com/sgitwhyd/music-catalogue/internal/handlers/response"
+	"github.com/sgitwhyd/music-catalogue/internal/models"
+	"github.com/sgitwhyd/music-catalogue/internal/models/play"
+	playService "github.com/sgitwhyd/music-catalogue/internal/services/play"
+	"github.com/sgitwhyd/music-catalogue/pkg/jwt"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func Test_handler_Record(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockSvc := NewMockPlayService(mockCtrl)
+
+	tests := []struct {
+		name               string
+		requestBody        any
+		mockFn             func()
+		expectedStatusCode int
+		expectedCode       string
+	}{
+		{
+			name:        "success",
+			requestBody: play.CreatePlayRequest{SpotifyID: "a", DurationPlayedMs: 30000, Client: "web"},
+			mockFn: func() {
+				mockSvc.EXPECT().Record(gomock.Any(), uint(1), play.CreatePlayRequest{SpotifyID: "a", DurationPlayedMs: 30000, Client: "web"}).
+					Return(&play.PlayResponse{ID: 1, SpotifyID: "a", DurationPlayedMs: 30000, Client: "web"}, nil)
+			},
+			expectedStatusCode: 201,
+		},
+		{
+			name:               "negative duration",
+			requestBody:        play.CreatePlayRequest{SpotifyID: "a", DurationPlayedMs: -1},
+			mockFn:             func() {},
+			expectedStatusCode: 422,
+		},
+		{
+			name:        "played in the future",
+			requestBody: play.CreatePlayRequest{SpotifyID: "a"},
+			mockFn: func() {
+				mockSvc.EXPECT().Record(gomock.Any(), uint(1), play.CreatePlayRequest{SpotifyID: "a"}).Return(nil, playService.ErrInvalidPlayedAt)
+			},
+			expectedStatusCode: 422,
+			expectedCode:       "invalid_played_at",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFn()
+
+			config, err := configs.Init("../../configs", "env", "test.env")
+			assert.NoError(t, err)
+
+			gin.SetMode(gin.ReleaseMode)
+
+			r := gin.New()
+			route := r.Group("/api/v1")
+
+			h := NewPlayHandler(mockSvc, route)
+			h.RegisterRoute()
+
+			val, err := json.Marshal(tt.requestBody)
+			assert.NoError(t, err)
+
+			req, err := http.NewRequest(http.MethodPost, "/api/v1/plays", bytes.NewBuffer(val))
+			assert.NoError(t, err)
+
+			token, err := jwt.CreateToken(uint(1), "username", models.RoleUser, config.SecretJWT)
+			assert.NoError(t, err)
+
+			req.Header.Set("Authorization", token)
+
+			w := httptest.NewRecorder()
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+
+			if tt.expectedCode != "" {
+				envelope := response.ErrorEnvelope{}
+				err = json.Unmarshal(w.Body.Bytes(), &envelope)
+				assert.NoError(t, err)
+
+				assert.Equal(t, tt.expectedCode, envelope.Error.Code)
+			}
+		})
+	}
+}
+
+func Test_handler_ListRecent(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockSvc := NewMockPlayService(mockCtrl)
+
+	tests := []struct {
+		name               string
+		query              string
+		mockFn             func()
+		expectedStatusCode int
+		expectedResponse   *play.RecentPlaysResponse
+	}{
+		{
+			name:  "success",
+			query: "?limit=1&cursor=abc",
+			mockFn: func() {
+				mockSvc.EXPECT().ListRecent(gomock.Any(), uint(1), play.RecentPlaysRequest{Limit: 1, Cursor: "abc"}).Return(&play.RecentPlaysResponse{
+					Items:      []play.RecentPlayResponse{{PlayResponse: play.PlayResponse{ID: 1, SpotifyID: "a"}}},
+					NextCursor: "def",
+				}, nil)
+			},
+			expectedStatusCode: 200,
+			expectedResponse: &play.RecentPlaysResponse{
+				Items:      []play.RecentPlayResponse{{PlayResponse: play.PlayResponse{ID: 1, SpotifyID: "a"}}},
+				NextCursor: "def",
+			},
+		},
+		{
+			name:  "invalid cursor",
+			query: "?cursor=abc",
+			mockFn: func() {
+				mockSvc.EXPECT().ListRecent(gomock.Any(), uint(1), play.RecentPlaysRequest{Cursor: "abc"}).Return(nil, playService.ErrInvalidCursor)
+			},
+			expectedStatusCode: 422,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFn()
+
+			config, err := configs.Init("../../configs", "env", "test.env")
+			assert.NoError(t, err)
+
+			gin.SetMode(gin.ReleaseMode)
+
+			r := gin.New()
+			route := r.Group("/api/v1")
+
+			h := NewPlayHandler(mockSvc, route)
+			h.RegisterRoute()
+
+			req, err := http.NewRequest(http.MethodGet, "/api/v1/plays/recent"+tt.query, nil)
+			assert.NoError(t, err)
+
+			token, err := jwt.CreateToken(uint(1), "username", models.RoleUser, config.SecretJWT)
+			assert.NoError(t, err)
+
+			req.Header.Set("Authorization", token)
+
+			w := httptest.NewRecorder()
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+
+			if tt.expectedResponse != nil {
+				response := play.RecentPlaysResponse{}
+				err = json.Unmarshal(w.Body.Bytes(), &response)
+				assert.NoError(t, err)
+
+				assert.Equal(t, *tt.expectedResponse, response)
+			}
+		})
+	}
+}
@@ -0,0 +1,231 @@
+package playlist
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+	"github.com/sgitwhyd/music-catalogue/internal/apperror"
+	"github.com/sgitwhyd/music-catalogue/internal/handlers/response"
+	"github.com/sgitwhyd/music-catalogue/internal/middleware"
+	"github.com/sgitwhyd/music-catalogue/internal/models/playlist"
+	playlistService "github.com/sgitwhyd/music-catalogue/internal/services/playlist"
+)
+
+var ErrInvalidPlaylistID = apperror.New(apperror.KindInvalid, "invalid_playlist_id", "playlist id must be a positive number")
+
+type handler struct {
+	service playlistService.PlaylistService
+	route   *gin.RouterGroup
+}
+
+func NewPlaylistHandler(service playlistService.PlaylistService, route *gin.RouterGroup) *handler {
+	return &handler{
+		service: service,
+		route:   route,
+	}
+}
+
+func (h *handler) Create(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var request playlist.CreatePlaylistRequest
+	err := c.ShouldBindJSON(&request)
+	if err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	userID := c.GetUint("userID")
+	created, err := h.service.Create(ctx, userID, request)
+	if err != nil {
+		log.Error().Err(err).Msg("error handler: Create playlist")
+		response.Error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, created)
+}
+
+func (h *handler) List(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var request playlist.ListPlaylistRequest
+	err := c.ShouldBindQuery(&request)
+	if err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	userID := c.GetUint("userID")
+	playlists, err := h.service.List(ctx, userID, request)
+	if err != nil {
+		log.Error().Err(err).Msg("error handler: List playlists")
+		response.Error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, playlists)
+}
+
+func (h *handler) Get(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	playlistID, ok := playlistIDParam(c)
+	if !ok {
+		return
+	}
+
+	userID := c.GetUint("userID")
+	detail, err := h.service.Get(ctx, userID, playlistID)
+	if err != nil {
+		log.Error().Err(err).Msg("error handler: Get playlist")
+		response.Error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, detail)
+}
+
+func (h *handler) Update(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	playlistID, ok := playlistIDParam(c)
+	if !ok {
+		return
+	}
+
+	var request playlist.UpdatePlaylistRequest
+	err := c.ShouldBindJSON(&request)
+	if err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	userID := c.GetUint("userID")
+	updated, err := h.service.Update(ctx, userID, playlistID, request)
+	if err != nil {
+		log.Error().Err(err).Msg("error handler: Update playlist")
+		response.Error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, updated)
+}
+
+func (h *handler) Delete(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	playlistID, ok := playlistIDParam(c)
+	if !ok {
+		return
+	}
+
+	userID := c.GetUint("userID")
+	err := h.service.Delete(ctx, userID, playlistID)
+	if err != nil {
+		log.Error().Err(err).Msg("error handler: Delete playlist")
+		response.Error(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *handler) AddTracks(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	playlistID, ok := playlistIDParam(c)
+	if !ok {
+		return
+	}
+
+	var request playlist.AddTracksRequest
+	err := c.ShouldBindJSON(&request)
+	if err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	userID := c.GetUint("userID")
+	err = h.service.AddTracks(ctx, userID, playlistID, request)
+	if err != nil {
+		log.Error().Err(err).Msg("error handler: AddTracks")
+		response.Error(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *handler) RemoveTrack(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	playlistID, ok := playlistIDParam(c)
+	if !ok {
+		return
+	}
+
+	userID := c.GetUint("userID")
+	err := h.service.RemoveTrack(ctx, userID, playlistID, c.Param("spotifyID"))
+	if err != nil {
+		log.Error().Err(err).Msg("error handler: RemoveTrack")
+		response.Error(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *handler) ReorderTracks(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	playlistID, ok := playlistIDParam(c)
+	if !ok {
+		return
+	}
+
+	var request playlist.ReorderTracksRequest
+	err := c.ShouldBindJSON(&request)
+	if err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	userID := c.GetUint("userID")
+	err = h.service.ReorderTracks(ctx, userID, playlistID, request)
+	if err != nil {
+		log.Error().Err(err).Msg("error handler: ReorderTracks")
+		response.Error(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// playlistIDParam parses the :id path parameter, writing the error response
+// when it isn't a valid id.
+func playlistIDParam(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil || id == 0 {
+		response.Error(c, ErrInvalidPlaylistID)
+		return 0, false
+	}
+
+	return uint(id), true
+}
+
+func (h *handler) RegisterRoute() {
+	route := h.route.Group("/playlists")
+	route.Use(middleware.AuthMiddleware(), middleware.RateLimitMiddleware("playlists"))
+
+	route.POST("", h.Create)
+	route.GET("", h.List)
+	route.GET("/:id", h.Get)
+	route.PATCH("/:id", h.Update)
+	route.DELETE("/:id", h.Delete)
+	route.POST("/:id/tracks", h.AddTracks)
+	route.DELETE("/:id/tracks/:spotifyID", h.RemoveTrack)
+	route.PUT("/:id/tracks/order", h.ReorderTracks)
+}
@@ -0,0 +1,158 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: service.go
+//
+// Generated by this command:
+//
+//	mockgen -source=service.go -destination=../../handlers/playlist/handler_mock_test.go -package=playlist
+//
+
+// Package playlist is a generated GoMock package.
+package playlist
+
+import (
+	context "context"
+	reflect "reflect"
+
+	playlist "github.com/sgitwhyd/music-catalogue/internal/models/playlist"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockPlaylistService is a mock of PlaylistService interface.
+type MockPlaylistService struct {
+	ctrl     *gomock.Controller
+	recorder *MockPlaylistServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockPlaylistServiceMockRecorder is the mock recorder for MockPlaylistService.
+type MockPlaylistServiceMockRecorder struct {
+	mock *MockPlaylistService
+}
+
+// NewMockPlaylistService creates a new mock instance.
+func NewMockPlaylistService(ctrl *gomock.Controller) *MockPlaylistService {
+	mock := &MockPlaylistService{ctrl: ctrl}
+	mock.recorder = &MockPlaylistServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPlaylistService) EXPECT() *MockPlaylistServiceMockRecorder {
+	return m.recorder
+}
+
+// AddTracks mocks base method.
+func (m *MockPlaylistService) AddTracks(ctx context.Context, userID, playlistID uint, request playlist.AddTracksRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddTracks", ctx, userID, playlistID, request)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddTracks indicates an expected call of AddTracks.
+func (mr *MockPlaylistServiceMockRecorder) AddTracks(ctx, userID, playlistID, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddTracks", reflect.TypeOf((*MockPlaylistService)(nil).AddTracks), ctx, userID, playlistID, request)
+}
+
+// Create mocks base method.
+func (m *MockPlaylistService) Create(ctx context.Context, userID uint, request playlist.CreatePlaylistRequest) (*playlist.PlaylistResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, userID, request)
+	ret0, _ := ret[0].(*playlist.PlaylistResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockPlaylistServiceMockRecorder) Create(ctx, userID, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockPlaylistService)(nil).Create), ctx, userID, request)
+}
+
+// Delete mocks base method.
+func (m *MockPlaylistService) Delete(ctx context.Context, userID, playlistID uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, userID, playlistID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockPlaylistServiceMockRecorder) Delete(ctx, userID, playlistID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockPlaylistService)(nil).Delete), ctx, userID, playlistID)
+}
+
+// Get mocks base method.
+func (m *MockPlaylistService) Get(ctx context.Context, userID, playlistID uint) (*playlist.PlaylistDetailResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, userID, playlistID)
+	ret0, _ := ret[0].(*playlist.PlaylistDetailResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockPlaylistServiceMockRecorder) Get(ctx, userID, playlistID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockPlaylistService)(nil).Get), ctx, userID, playlistID)
+}
+
+// List mocks base method.
+func (m *MockPlaylistService) List(ctx context.Context, userID uint, request playlist.ListPlaylistRequest) (*playlist.ListPlaylistResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, userID, request)
+	ret0, _ := ret[0].(*playlist.ListPlaylistResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockPlaylistServiceMockRecorder) List(ctx, userID, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockPlaylistService)(nil).List), ctx, userID, request)
+}
+
+// RemoveTrack mocks base method.
+func (m *MockPlaylistService) RemoveTrack(ctx context.Context, userID, playlistID uint, spotifyID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveTrack", ctx, userID, playlistID, spotifyID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveTrack indicates an expected call of RemoveTrack.
+func (mr *MockPlaylistServiceMockRecorder) RemoveTrack(ctx, userID, playlistID, spotifyID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveTrack", reflect.TypeOf((*MockPlaylistService)(nil).RemoveTrack), ctx, userID, playlistID, spotifyID)
+}
+
+// ReorderTracks mocks base method.
+func (m *MockPlaylistService) ReorderTracks(ctx context.Context, userID, playlistID uint, request playlist.ReorderTracksRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReorderTracks", ctx, userID, playlistID, request)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReorderTracks indicates an expected call of ReorderTracks.
+func (mr *MockPlaylistServiceMockRecorder) ReorderTracks(ctx, userID, playlistID, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReorderTracks", reflect.TypeOf((*MockPlaylistService)(nil).ReorderTracks), ctx, userID, playlistID, request)
+}
+
+// Update mocks base method.
+func (m *MockPlaylistService) Update(ctx context.Context, userID, playlistID uint, request playlist.UpdatePlaylistRequest) (*playlist.PlaylistResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, userID, playlistID, request)
+	ret0, _ := ret[0].(*playlist.PlaylistResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockPlaylistServiceMockRecorder) Update(ctx, userID, playlistID, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockPlaylistService)(nil).Update), ctx, userID, playlistID, request)
+}
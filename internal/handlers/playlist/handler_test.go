@@ -0,0 +1,187 @@
+package playlist
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sgitwhyd/music-catalogue/internal/configs"
+	"github.com/sgitwhyd/music-catalogue/internal/handlers/response"
+	"github.com/sgitwhyd/music-catalogue/internal/models"
+	"github.com/sgitwhyd/music-catalogue/internal/models/playlist"
+	playlistService "github.com/sgitwhyd/music-catalogue/internal/services/playlist"
+	"github.com/sgitwhyd/music-catalogue/pkg/jwt"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func Test_handler_Create(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockSvc := NewMockPlaylistService(mockCtrl)
+
+	tests := []struct {
+		name               string
+		requestBody        any
+		mockFn             func()
+		expectedStatusCode int
+		expectedResponse   *playlist.PlaylistResponse
+	}{
+		{
+			name:        "success",
+			requestBody: playlist.CreatePlaylistRequest{Name: "Road Trip"},
+			mockFn: func() {
+				mockSvc.EXPECT().Create(gomock.Any(), uint(1), playlist.CreatePlaylistRequest{Name: "Road Trip"}).Return(&playlist.PlaylistResponse{
+					ID:         1,
+					OwnerID:    1,
+					Name:       "Road Trip",
+					Visibility: playlist.VisibilityPrivate,
+				}, nil)
+			},
+			expectedStatusCode: 201,
+			expectedResponse: &playlist.PlaylistResponse{
+				ID:         1,
+				OwnerID:    1,
+				Name:       "Road Trip",
+				Visibility: playlist.VisibilityPrivate,
+			},
+		},
+		{
+			name:               "invalid visibility",
+			requestBody:        playlist.CreatePlaylistRequest{Name: "Road Trip", Visibility: "friends"},
+			mockFn:             func() {},
+			expectedStatusCode: 422,
+		},
+		{
+			name:        "failed",
+			requestBody: playlist.CreatePlaylistRequest{Name: "Road Trip"},
+			mockFn: func() {
+				mockSvc.EXPECT().Create(gomock.Any(), uint(1), playlist.CreatePlaylistRequest{Name: "Road Trip"}).Return(nil, assert.AnError)
+			},
+			expectedStatusCode: 500,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFn()
+
+			config, err := configs.Init("../../configs", "env", "test.env")
+			assert.NoError(t, err)
+
+			gin.SetMode(gin.ReleaseMode)
+
+			r := gin.New()
+			route := r.Group("/api/v1")
+
+			h := NewPlaylistHandler(mockSvc, route)
+			h.RegisterRoute()
+
+			val, err := json.Marshal(tt.requestBody)
+			assert.NoError(t, err)
+
+			req, err := http.NewRequest(http.MethodPost, "/api/v1/playlists", bytes.NewBuffer(val))
+			assert.NoError(t, err)
+
+			token, err := jwt.CreateToken(uint(1), "username", models.RoleUser, config.SecretJWT)
+			assert.NoError(t, err)
+
+			req.Header.Set("Authorization", token)
+
+			w := httptest.NewRecorder()
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+
+			if tt.expectedResponse != nil {
+				response := playlist.PlaylistResponse{}
+				err = json.Unmarshal(w.Body.Bytes(), &response)
+				assert.NoError(t, err)
+
+				assert.Equal(t, *tt.expectedResponse, response)
+			}
+		})
+	}
+}
+
+func Test_handler_Get(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockSvc := NewMockPlaylistService(mockCtrl)
+
+	tests := []struct {
+		name               string
+		playlistID         string
+		mockFn             func()
+		expectedStatusCode int
+		expectedCode       string
+	}{
+		{
+			name:       "success",
+			playlistID: "1",
+			mockFn: func() {
+				mockSvc.EXPECT().Get(gomock.Any(), uint(1), uint(1)).Return(&playlist.PlaylistDetailResponse{}, nil)
+			},
+			expectedStatusCode: 200,
+		},
+		{
+			name:               "invalid id",
+			playlistID:         "abc",
+			mockFn:             func() {},
+			expectedStatusCode: 422,
+			expectedCode:       "invalid_playlist_id",
+		},
+		{
+			name:       "not found",
+			playlistID: "2",
+			mockFn: func() {
+				mockSvc.EXPECT().Get(gomock.Any(), uint(1), uint(2)).Return(nil, playlistService.ErrPlaylistNotFound)
+			},
+			expectedStatusCode: 404,
+			expectedCode:       "playlist_not_found",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFn()
+
+			config, err := configs.Init("../../configs", "env", "test.env")
+			assert.NoError(t, err)
+
+			gin.SetMode(gin.ReleaseMode)
+
+			r := gin.New()
+			route := r.Group("/api/v1")
+
+			h := NewPlaylistHandler(mockSvc, route)
+			h.RegisterRoute()
+
+			req, err := http.NewRequest(http.MethodGet, "/api/v1/playlists/"+tt.playlistID, nil)
+			assert.NoError(t, err)
+
+			token, err := jwt.CreateToken(uint(1), "username", models.RoleUser, config.SecretJWT)
+			assert.NoError(t, err)
+
+			req.Header.Set("Authorization", token)
+
+			w := httptest.NewRecorder()
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+
+			if tt.expectedCode != "" {
+				envelope := response.ErrorEnvelope{}
+				err = json.Unmarshal(w.Body.Bytes(), &envelope)
+				assert.NoError(t, err)
+
+				assert.Equal(t, tt.expectedCode, envelope.Error.Code)
+			}
+		})
+	}
+}
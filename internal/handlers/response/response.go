@@ -0,0 +1,39 @@
+// Package response writes the JSON error envelope every endpoint shares:
+//
+//	{"error": {"code": "track_not_found", "message": "track not found"}}
+package response
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/sgitwhyd/music-catalogue/internal/apperror"
+)
+
+type (
+	ErrorBody struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	}
+
+	ErrorEnvelope struct {
+		Error ErrorBody `json:"error"`
+	}
+)
+
+// Error aborts the request with the status and code of err's kind.
+func Error(c *gin.Context, err error) {
+	appErr := apperror.From(err)
+
+	c.AbortWithStatusJSON(appErr.Kind.Status(), ErrorEnvelope{
+		Error: ErrorBody{
+			Code:    appErr.Code,
+			Message: appErr.Message,
+		},
+	})
+}
+
+// BindError reports a request that failed binding or validation, keeping
+// the validator message since it tells the client which field is wrong.
+func BindError(c *gin.Context, err error) {
+	Error(c, apperror.New(apperror.KindInvalid, "invalid_request", err.Error()))
+}
@@ -3,9 +3,11 @@ package spotify
 import (
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/rs/zerolog/log"
+	"github.com/sgitwhyd/music-catalogue/internal/handlers/response"
 	"github.com/sgitwhyd/music-catalogue/internal/middleware"
 	"github.com/sgitwhyd/music-catalogue/internal/models/spotify"
 	spotifyService "github.com/sgitwhyd/music-catalogue/internal/services/spotify"
@@ -41,17 +43,67 @@ func (h *handler) Search(c *gin.Context){
 	}
 
 	userID := c.GetUint("userID")
+	types := listQueryParam(c, "type")
 
-	response, err := h.service.Search(ctx, query, pageSize, pageIndex, userID)
+	searchResponse, err := h.service.Search(ctx, spotify.SearchRequest{
+		Query: query,
+		PageSize: pageSize,
+		PageIndex: pageIndex,
+		Market: c.Query("market"),
+		Types: types,
+	}, userID)
 	if err != nil {
-		log.Error().Err(err).Msg("error count")
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": err.Error(),
-		})
+		log.Error().Err(err).Msg("error handler: Search")
+		response.Error(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK,response)
+	if searchResponse.CacheStatus != "" {
+		c.Header("X-Cache", searchResponse.CacheStatus)
+	}
+
+	// without type clients get the tracks page on its own, the shape search
+	// had before other types could be searched
+	if len(types) == 0 {
+		c.JSON(http.StatusOK, searchResponse.Tracks)
+		return
+	}
+
+	c.JSON(http.StatusOK, searchResponse)
+}
+
+// listQueryParam accepts comma separated values, key=a,b, as well as
+// repeated key=a&key=b params.
+func listQueryParam(c *gin.Context, key string) []string {
+	var values []string
+	for _, value := range c.QueryArray(key) {
+		for _, item := range strings.Split(value, ",") {
+			if item != "" {
+				values = append(values, item)
+			}
+		}
+	}
+
+	return values
+}
+
+// prefixedQueryParams collects the query params whose key starts with one of
+// prefixes, keeping the first value of each.
+func prefixedQueryParams(c *gin.Context, prefixes ...string) map[string]string {
+	var params map[string]string
+	for key, values := range c.Request.URL.Query() {
+		for _, prefix := range prefixes {
+			if strings.HasPrefix(strings.ToLower(key), prefix) && len(values) > 0 {
+				if params == nil {
+					params = make(map[string]string)
+				}
+				params[key] = values[0]
+				break
+			}
+		}
+	}
+
+	return params
 }
 
 func (h *handler) UpsertActivity(c *gin.Context){
@@ -60,19 +112,15 @@ func (h *handler) UpsertActivity(c *gin.Context){
 	var request spotify.TrackActivityRequest
 	err := c.ShouldBindJSON(&request)
 	if err != nil {
-		c.JSON(http.StatusUnprocessableEntity, gin.H{
-			"error": err.Error(),
-		})
+		response.BindError(c, err)
 		return
 	}
 
 	userID := c.GetUint("userID")
 	err = h.service.UpSertActivity(ctx, userID, request )
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-
-			"error": err.Error(),
-		})
+		log.Error().Err(err).Msg("error handler: UpsertActivity")
+		response.Error(c, err)
 		return
 	}
 
@@ -81,13 +129,220 @@ func (h *handler) UpsertActivity(c *gin.Context){
 	})
 }
 
+func (h *handler) UpdateActivity(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var request spotify.UpdateActivityRequest
+	err := c.ShouldBindJSON(&request)
+	if err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	spotifyID := c.Param("id")
+	userID := c.GetUint("userID")
+	activity, err := h.service.UpdateActivity(ctx, userID, spotifyID, request)
+	if err != nil {
+		log.Error().Err(err).Msg("error handler: UpdateActivity")
+		response.Error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, activity)
+}
+
+func (h *handler) GetTrack(c *gin.Context){
+	ctx := c.Request.Context()
+
+	spotifyID := c.Param("id")
+	userID := c.GetUint("userID")
+
+	track, err := h.service.GetTrack(ctx, spotifyID, userID)
+	if err != nil {
+		log.Error().Err(err).Msg("error handler: GetTrack")
+		response.Error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, track)
+}
+
+func (h *handler) LookupTracks(c *gin.Context){
+	ctx := c.Request.Context()
+
+	var request spotify.TrackLookupRequest
+	err := c.ShouldBindJSON(&request)
+	if err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	userID := c.GetUint("userID")
+	tracks, err := h.service.GetTracks(ctx, request.SpotifyIDs, userID)
+	if err != nil {
+		log.Error().Err(err).Msg("error handler: LookupTracks")
+		response.Error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, tracks)
+}
+
+func (h *handler) GetLikedLibrary(c *gin.Context){
+	h.getLibrary(c, true)
+}
+
+func (h *handler) GetDislikedLibrary(c *gin.Context){
+	h.getLibrary(c, false)
+}
+
+func (h *handler) getLibrary(c *gin.Context, isLiked bool){
+	ctx := c.Request.Context()
+
+	var request spotify.LibraryRequest
+	err := c.ShouldBindQuery(&request)
+	if err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	userID := c.GetUint("userID")
+	library, err := h.service.GetLibrary(ctx, userID, isLiked, request)
+	if err != nil {
+		log.Error().Err(err).Msg("error handler: GetLibrary")
+		response.Error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, library)
+}
+
+func (h *handler) GetArtist(c *gin.Context){
+	ctx := c.Request.Context()
+
+	artist, err := h.service.GetArtist(ctx, c.Param("id"))
+	if err != nil {
+		log.Error().Err(err).Msg("error handler: GetArtist")
+		response.Error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, artist)
+}
+
+func (h *handler) GetArtistTopTracks(c *gin.Context){
+	ctx := c.Request.Context()
+
+	userID := c.GetUint("userID")
+	tracks, err := h.service.GetArtistTopTracks(ctx, c.Param("id"), c.Query("market"), userID)
+	if err != nil {
+		log.Error().Err(err).Msg("error handler: GetArtistTopTracks")
+		response.Error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, tracks)
+}
+
+func (h *handler) GetArtistAlbums(c *gin.Context){
+	ctx := c.Request.Context()
+
+	var request spotify.ArtistAlbumsRequest
+	err := c.ShouldBindQuery(&request)
+	if err != nil {
+		response.BindError(c, err)
+		return
+	}
+	request.IncludeGroups = listQueryParam(c, "include_groups")
+
+	userID := c.GetUint("userID")
+	albums, err := h.service.GetArtistAlbums(ctx, c.Param("id"), request, userID)
+	if err != nil {
+		log.Error().Err(err).Msg("error handler: GetArtistAlbums")
+		response.Error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, albums)
+}
+
+func (h *handler) GetAlbum(c *gin.Context){
+	ctx := c.Request.Context()
+
+	userID := c.GetUint("userID")
+	album, err := h.service.GetAlbum(ctx, c.Param("id"), c.Query("market"), userID)
+	if err != nil {
+		log.Error().Err(err).Msg("error handler: GetAlbum")
+		response.Error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, album)
+}
+
+func (h *handler) GetAlbumTracks(c *gin.Context){
+	ctx := c.Request.Context()
+
+	var request spotify.AlbumTracksRequest
+	err := c.ShouldBindQuery(&request)
+	if err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	userID := c.GetUint("userID")
+	tracks, err := h.service.GetAlbumTracks(ctx, c.Param("id"), request, userID)
+	if err != nil {
+		log.Error().Err(err).Msg("error handler: GetAlbumTracks")
+		response.Error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, tracks)
+}
+
+func (h *handler) GetRecommendations(c *gin.Context){
+	ctx := c.Request.Context()
+
+	var request spotify.RecommendationsRequest
+	err := c.ShouldBindQuery(&request)
+	if err != nil {
+		response.BindError(c, err)
+		return
+	}
+	request.SeedTracks = listQueryParam(c, "seed_tracks")
+	request.SeedArtists = listQueryParam(c, "seed_artists")
+	request.SeedGenres = listQueryParam(c, "seed_genres")
+	request.Attributes = prefixedQueryParams(c, "min_", "max_", "target_")
+
+	userID := c.GetUint("userID")
+	recommendations, err := h.service.GetRecommendations(ctx, userID, request)
+	if err != nil {
+		log.Error().Err(err).Msg("error handler: GetRecommendations")
+		response.Error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, recommendations)
+}
 
 func (h *handler) RegisterRoute(){
 	route := h.route.Group("/spotify")
-	route.Use(middleware.AuthMiddleware())
+	route.Use(middleware.AuthMiddleware(), middleware.RateLimitMiddleware("spotify"))
 	
 	route.GET("/search", h.Search)
 	route.POST("/activity", h.UpsertActivity)
+	route.PATCH("/activity/:id", h.UpdateActivity)
+	route.GET("/activity/liked", h.GetLikedLibrary)
+	route.GET("/activity/disliked", h.GetDislikedLibrary)
+	route.GET("/tracks/:id", h.GetTrack)
+	route.POST("/tracks/lookup", h.LookupTracks)
+	route.GET("/artists/:id", h.GetArtist)
+	route.GET("/artists/:id/top-tracks", h.GetArtistTopTracks)
+	route.GET("/artists/:id/albums", h.GetArtistAlbums)
+	route.GET("/albums/:id", h.GetAlbum)
+	route.GET("/albums/:id/tracks", h.GetAlbumTracks)
+	route.GET("/recommendations", h.GetRecommendations)
 	
 
 }
\ No newline at end of file
@@ -41,19 +41,154 @@ func (m *MockSpotifyService) EXPECT() *MockSpotifyServiceMockRecorder {
 	return m.recorder
 }
 
+// GetAlbum mocks base method.
+func (m *MockSpotifyService) GetAlbum(ctx context.Context, albumID, market string, userID uint) (*spotify.AlbumResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAlbum", ctx, albumID, market, userID)
+	ret0, _ := ret[0].(*spotify.AlbumResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAlbum indicates an expected call of GetAlbum.
+func (mr *MockSpotifyServiceMockRecorder) GetAlbum(ctx, albumID, market, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAlbum", reflect.TypeOf((*MockSpotifyService)(nil).GetAlbum), ctx, albumID, market, userID)
+}
+
+// GetAlbumTracks mocks base method.
+func (m *MockSpotifyService) GetAlbumTracks(ctx context.Context, albumID string, request spotify.AlbumTracksRequest, userID uint) (*spotify.AlbumTracksResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAlbumTracks", ctx, albumID, request, userID)
+	ret0, _ := ret[0].(*spotify.AlbumTracksResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAlbumTracks indicates an expected call of GetAlbumTracks.
+func (mr *MockSpotifyServiceMockRecorder) GetAlbumTracks(ctx, albumID, request, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAlbumTracks", reflect.TypeOf((*MockSpotifyService)(nil).GetAlbumTracks), ctx, albumID, request, userID)
+}
+
+// GetArtist mocks base method.
+func (m *MockSpotifyService) GetArtist(ctx context.Context, artistID string) (*spotify.SpotifyArtistObjectResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetArtist", ctx, artistID)
+	ret0, _ := ret[0].(*spotify.SpotifyArtistObjectResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetArtist indicates an expected call of GetArtist.
+func (mr *MockSpotifyServiceMockRecorder) GetArtist(ctx, artistID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetArtist", reflect.TypeOf((*MockSpotifyService)(nil).GetArtist), ctx, artistID)
+}
+
+// GetArtistAlbums mocks base method.
+func (m *MockSpotifyService) GetArtistAlbums(ctx context.Context, artistID string, request spotify.ArtistAlbumsRequest, userID uint) (*spotify.Page[spotify.SpotifyAlbumObjectResponse], error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetArtistAlbums", ctx, artistID, request, userID)
+	ret0, _ := ret[0].(*spotify.Page[spotify.SpotifyAlbumObjectResponse])
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetArtistAlbums indicates an expected call of GetArtistAlbums.
+func (mr *MockSpotifyServiceMockRecorder) GetArtistAlbums(ctx, artistID, request, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetArtistAlbums", reflect.TypeOf((*MockSpotifyService)(nil).GetArtistAlbums), ctx, artistID, request, userID)
+}
+
+// GetArtistTopTracks mocks base method.
+func (m *MockSpotifyService) GetArtistTopTracks(ctx context.Context, artistID, market string, userID uint) (*spotify.ArtistTopTracksResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetArtistTopTracks", ctx, artistID, market, userID)
+	ret0, _ := ret[0].(*spotify.ArtistTopTracksResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetArtistTopTracks indicates an expected call of GetArtistTopTracks.
+func (mr *MockSpotifyServiceMockRecorder) GetArtistTopTracks(ctx, artistID, market, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetArtistTopTracks", reflect.TypeOf((*MockSpotifyService)(nil).GetArtistTopTracks), ctx, artistID, market, userID)
+}
+
+// GetLibrary mocks base method.
+func (m *MockSpotifyService) GetLibrary(ctx context.Context, userID uint, isLiked bool, request spotify.LibraryRequest) (*spotify.LibraryResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLibrary", ctx, userID, isLiked, request)
+	ret0, _ := ret[0].(*spotify.LibraryResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLibrary indicates an expected call of GetLibrary.
+func (mr *MockSpotifyServiceMockRecorder) GetLibrary(ctx, userID, isLiked, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLibrary", reflect.TypeOf((*MockSpotifyService)(nil).GetLibrary), ctx, userID, isLiked, request)
+}
+
+// GetRecommendations mocks base method.
+func (m *MockSpotifyService) GetRecommendations(ctx context.Context, userID uint, request spotify.RecommendationsRequest) (*spotify.RecommendationsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRecommendations", ctx, userID, request)
+	ret0, _ := ret[0].(*spotify.RecommendationsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRecommendations indicates an expected call of GetRecommendations.
+func (mr *MockSpotifyServiceMockRecorder) GetRecommendations(ctx, userID, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRecommendations", reflect.TypeOf((*MockSpotifyService)(nil).GetRecommendations), ctx, userID, request)
+}
+
+// GetTrack mocks base method.
+func (m *MockSpotifyService) GetTrack(ctx context.Context, spotifyID string, userID uint) (*spotify.SpotifyTrackObjectResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTrack", ctx, spotifyID, userID)
+	ret0, _ := ret[0].(*spotify.SpotifyTrackObjectResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTrack indicates an expected call of GetTrack.
+func (mr *MockSpotifyServiceMockRecorder) GetTrack(ctx, spotifyID, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTrack", reflect.TypeOf((*MockSpotifyService)(nil).GetTrack), ctx, spotifyID, userID)
+}
+
+// GetTracks mocks base method.
+func (m *MockSpotifyService) GetTracks(ctx context.Context, spotifyIDs []string, userID uint) (*spotify.TrackLookupResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTracks", ctx, spotifyIDs, userID)
+	ret0, _ := ret[0].(*spotify.TrackLookupResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTracks indicates an expected call of GetTracks.
+func (mr *MockSpotifyServiceMockRecorder) GetTracks(ctx, spotifyIDs, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTracks", reflect.TypeOf((*MockSpotifyService)(nil).GetTracks), ctx, spotifyIDs, userID)
+}
+
 // Search mocks base method.
-func (m *MockSpotifyService) Search(ctx context.Context, query string, pageSize, pageIndex int, userID uint) (*spotify.SearchResponse, error) {
+func (m *MockSpotifyService) Search(ctx context.Context, request spotify.SearchRequest, userID uint) (*spotify.SearchResponse, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Search", ctx, query, pageSize, pageIndex, userID)
+	ret := m.ctrl.Call(m, "Search", ctx, request, userID)
 	ret0, _ := ret[0].(*spotify.SearchResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Search indicates an expected call of Search.
-func (mr *MockSpotifyServiceMockRecorder) Search(ctx, query, pageSize, pageIndex, userID any) *gomock.Call {
+func (mr *MockSpotifyServiceMockRecorder) Search(ctx, request, userID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockSpotifyService)(nil).Search), ctx, query, pageSize, pageIndex, userID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockSpotifyService)(nil).Search), ctx, request, userID)
 }
 
 // UpSertActivity mocks base method.
@@ -69,3 +204,18 @@ func (mr *MockSpotifyServiceMockRecorder) UpSertActivity(ctx, userID, request an
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpSertActivity", reflect.TypeOf((*MockSpotifyService)(nil).UpSertActivity), ctx, userID, request)
 }
+
+// UpdateActivity mocks base method.
+func (m *MockSpotifyService) UpdateActivity(ctx context.Context, userID uint, spotifyID string, request spotify.UpdateActivityRequest) (*spotify.ActivityResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateActivity", ctx, userID, spotifyID, request)
+	ret0, _ := ret[0].(*spotify.ActivityResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateActivity indicates an expected call of UpdateActivity.
+func (mr *MockSpotifyServiceMockRecorder) UpdateActivity(ctx, userID, spotifyID, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateActivity", reflect.TypeOf((*MockSpotifyService)(nil).UpdateActivity), ctx, userID, spotifyID, request)
+}
@@ -7,10 +7,14 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/sgitwhyd/music-catalogue/internal/configs"
+	"github.com/sgitwhyd/music-catalogue/internal/models"
 	"github.com/sgitwhyd/music-catalogue/internal/models/spotify"
+	spotifyService "github.com/sgitwhyd/music-catalogue/internal/services/spotify"
+	"github.com/sgitwhyd/music-catalogue/pkg/httpclient"
 	"github.com/sgitwhyd/music-catalogue/pkg/jwt"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
@@ -24,17 +28,21 @@ func Test_handler_Search(t *testing.T) {
 
 	tests := []struct {
 		name             string
+		query            string
 		mockFn           func()
 		expectedCode     int
-		expectedResponse spotify.SearchResponse
+		expectedResponse any
+		expectedCache    string
 		wantErr          bool
 	}{
 		// TODO: Add test cases.
 		{
 			name:         "success",
 			expectedCode: 200,
+			expectedCache: "HIT",
 			wantErr:      false,
-			expectedResponse: spotify.SearchResponse{
+			// no type keeps the legacy top-level page
+			expectedResponse: spotify.Page[spotify.SpotifyTrackObjectResponse]{
 				Limit:  10,
 				Offset: 0,
 				Items: []spotify.SpotifyTrackObjectResponse{
@@ -52,32 +60,90 @@ func Test_handler_Search(t *testing.T) {
 				Total: 905,
 			},
 			mockFn: func() {
-				mockSvc.EXPECT().Search(gomock.Any(), "bohemian rhapsody", 10, 1, uint(1)).Return(&spotify.SearchResponse{
-					Limit:  10,
-					Offset: 0,
-					Items: []spotify.SpotifyTrackObjectResponse{
-						{
-							AlbumType:        "album",
-							AlbumTotalTracks: 22,
-							AlbumImagesURL:   []string{"https://i.scdn.co/image/ab67616d0000b273e8b066f70c206551210d902b"},
-							AlbumName:        "Bohemian Rhapsody (The Original Soundtrack)",
-							ArtistsName:      []string{"Queen"},
-							Explicit:         false,
-							ID:               "3z8h0TU7ReDPLIbEnYhWZb",
-							Name:             "Bohemian Rhapsody",
+				mockSvc.EXPECT().Search(gomock.Any(), spotify.SearchRequest{Query: "bohemian rhapsody", PageSize: 10, PageIndex: 1}, uint(1)).Return(&spotify.SearchResponse{
+					Tracks: &spotify.Page[spotify.SpotifyTrackObjectResponse]{
+						Limit:  10,
+						Offset: 0,
+						Items: []spotify.SpotifyTrackObjectResponse{
+							{
+								AlbumType:        "album",
+								AlbumTotalTracks: 22,
+								AlbumImagesURL:   []string{"https://i.scdn.co/image/ab67616d0000b273e8b066f70c206551210d902b"},
+								AlbumName:        "Bohemian Rhapsody (The Original Soundtrack)",
+								ArtistsName:      []string{"Queen"},
+								Explicit:         false,
+								ID:               "3z8h0TU7ReDPLIbEnYhWZb",
+								Name:             "Bohemian Rhapsody",
+							},
+						},
+						Total: 905,
+					},
+					CacheStatus: "HIT",
+				}, nil)
+			},
+		},
+		{
+			name:         "several types",
+			query:        "&type=album,artist&type=playlist",
+			expectedCode: 200,
+			wantErr:      false,
+			expectedResponse: spotify.SearchResponse{
+				Albums: &spotify.Page[spotify.SpotifyAlbumObjectResponse]{
+					Limit: 10,
+					Items: []spotify.SpotifyAlbumObjectResponse{
+						{ID: "6i6folBtxKV28WX3msQ4FE", Name: "Bohemian Rhapsody (The Original Soundtrack)"},
+					},
+					Total: 1,
+				},
+			},
+			mockFn: func() {
+				mockSvc.EXPECT().Search(gomock.Any(), spotify.SearchRequest{
+					Query:     "bohemian rhapsody",
+					PageSize:  10,
+					PageIndex: 1,
+					Types:     []string{"album", "artist", "playlist"},
+				}, uint(1)).Return(&spotify.SearchResponse{
+					Albums: &spotify.Page[spotify.SpotifyAlbumObjectResponse]{
+						Limit: 10,
+						Items: []spotify.SpotifyAlbumObjectResponse{
+							{ID: "6i6folBtxKV28WX3msQ4FE", Name: "Bohemian Rhapsody (The Original Soundtrack)"},
 						},
+						Total: 1,
 					},
-					Total: 905,
 				}, nil)
 			},
 		},
+		{
+			name:             "invalid type",
+			query:            "&type=podcast",
+			expectedCode:     422,
+			wantErr:          true,
+			expectedResponse: spotify.SearchResponse{},
+			mockFn: func() {
+				mockSvc.EXPECT().Search(gomock.Any(), spotify.SearchRequest{
+					Query:     "bohemian rhapsody",
+					PageSize:  10,
+					PageIndex: 1,
+					Types:     []string{"podcast"},
+				}, uint(1)).Return(nil, spotifyService.ErrInvalidSearchType)
+			},
+		},
 		{
 			name:             "failed",
-			expectedCode:     400,
+			expectedCode:     500,
 			wantErr:          true,
 			expectedResponse: spotify.SearchResponse{},
 			mockFn: func() {
-				mockSvc.EXPECT().Search(gomock.Any(), "bohemian rhapsody", 10, 1, uint(1)).Return(nil, assert.AnError).Times(1)
+				mockSvc.EXPECT().Search(gomock.Any(), spotify.SearchRequest{Query: "bohemian rhapsody", PageSize: 10, PageIndex: 1}, uint(1)).Return(nil, assert.AnError).Times(1)
+			},
+		},
+		{
+			name:             "upstream unavailable",
+			expectedCode:     503,
+			wantErr:          true,
+			expectedResponse: spotify.SearchResponse{},
+			mockFn: func() {
+				mockSvc.EXPECT().Search(gomock.Any(), spotify.SearchRequest{Query: "bohemian rhapsody", PageSize: 10, PageIndex: 1}, uint(1)).Return(nil, httpclient.ErrCircuitOpen).Times(1)
 			},
 		},
 	}
@@ -99,11 +165,11 @@ func Test_handler_Search(t *testing.T) {
 			}
 			h.RegisterRoute()
 
-			endpoint := "/api/v1/spotify/search?query=bohemian+rhapsody&pageIndex=1&pageSize=10"
+			endpoint := "/api/v1/spotify/search?query=bohemian+rhapsody&pageIndex=1&pageSize=10" + tt.query
 			req, err := http.NewRequest(http.MethodGet, endpoint, nil)
 			assert.NoError(t, err)
 
-			token, err := jwt.CreateToken(uint(1), "username", config.SecretJWT)
+			token, err := jwt.CreateToken(uint(1), "username", models.RoleUser, config.SecretJWT)
 			assert.NoError(t, err)
 
 			req.Header.Set("Authorization", token)
@@ -113,16 +179,16 @@ func Test_handler_Search(t *testing.T) {
 			r.ServeHTTP(w, req)
 
 			assert.Equal(t, tt.expectedCode, w.Code)
+			assert.Equal(t, tt.expectedCache, w.Header().Get("X-Cache"))
 
 			if !tt.wantErr {
 				res := w.Result()
 				defer res.Body.Close()
 
-				response := spotify.SearchResponse{}
-				err = json.Unmarshal(w.Body.Bytes(), &response)
+				expected, err := json.Marshal(tt.expectedResponse)
 				assert.NoError(t, err)
 
-				assert.Equal(t, tt.expectedResponse, response)
+				assert.JSONEq(t, string(expected), w.Body.String())
 			}
 		})
 	}
@@ -163,7 +229,7 @@ func Test_handler_UpsertActivity(t *testing.T) {
 				SpotifyID: "SpotifyID",
 				IsLiked: &isLiked,
 			},
-			expectedStatusCode: 400,
+			expectedStatusCode: 500,
 			mockFn: func() {
 				mockSvc.EXPECT().UpSertActivity(gomock.Any(), uint(1), spotify.TrackActivityRequest{
 					SpotifyID: "SpotifyID",
@@ -200,7 +266,7 @@ func Test_handler_UpsertActivity(t *testing.T) {
 			httpReq, err := http.NewRequest(http.MethodPost, endpoint, io.NopCloser(bytes.NewBuffer(bodyBytes)))
 			assert.NoError(t, err)
 
-			token, err := jwt.CreateToken(uint(1), "username", config.SecretJWT)
+			token, err := jwt.CreateToken(uint(1), "username", models.RoleUser, config.SecretJWT)
 			assert.NoError(t, err)
 
 			httpReq.Header.Set("Authorization", token)
@@ -211,3 +277,648 @@ func Test_handler_UpsertActivity(t *testing.T) {
 		})
 	}
 }
+
+func Test_handler_GetTrack(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockSvc := NewMockSpotifyService(mockCtrl)
+
+	tests := []struct {
+		name             string
+		mockFn           func()
+		expectedCode     int
+		expectedResponse spotify.SpotifyTrackObjectResponse
+		wantErr          bool
+	}{
+		{
+			name:         "success",
+			expectedCode: 200,
+			wantErr:      false,
+			expectedResponse: spotify.SpotifyTrackObjectResponse{
+				ID:          "3z8h0TU7ReDPLIbEnYhWZb",
+				Name:        "Bohemian Rhapsody",
+				ArtistsName: []string{"Queen"},
+			},
+			mockFn: func() {
+				mockSvc.EXPECT().GetTrack(gomock.Any(), "3z8h0TU7ReDPLIbEnYhWZb", uint(1)).Return(&spotify.SpotifyTrackObjectResponse{
+					ID:          "3z8h0TU7ReDPLIbEnYhWZb",
+					Name:        "Bohemian Rhapsody",
+					ArtistsName: []string{"Queen"},
+				}, nil)
+			},
+		},
+		{
+			name:         "not found",
+			expectedCode: 404,
+			wantErr:      true,
+			mockFn: func() {
+				mockSvc.EXPECT().GetTrack(gomock.Any(), "3z8h0TU7ReDPLIbEnYhWZb", uint(1)).Return(nil, spotifyService.ErrTrackNotFound)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFn()
+
+			config, err := configs.Init("../../configs", "env", "test.env")
+			assert.NoError(t, err)
+
+			gin.SetMode(gin.ReleaseMode)
+
+			r := gin.New()
+			route := r.Group("/api/v1")
+
+			h := &handler{
+				route:   route,
+				service: mockSvc,
+			}
+			h.RegisterRoute()
+
+			req, err := http.NewRequest(http.MethodGet, "/api/v1/spotify/tracks/3z8h0TU7ReDPLIbEnYhWZb", nil)
+			assert.NoError(t, err)
+
+			token, err := jwt.CreateToken(uint(1), "username", models.RoleUser, config.SecretJWT)
+			assert.NoError(t, err)
+
+			req.Header.Set("Authorization", token)
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+
+			if !tt.wantErr {
+				response := spotify.SpotifyTrackObjectResponse{}
+				err = json.Unmarshal(w.Body.Bytes(), &response)
+				assert.NoError(t, err)
+
+				assert.Equal(t, tt.expectedResponse, response)
+			}
+		})
+	}
+}
+
+func Test_handler_LookupTracks(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockSvc := NewMockSpotifyService(mockCtrl)
+
+	tests := []struct {
+		name               string
+		requestBody        spotify.TrackLookupRequest
+		expectedStatusCode int
+		mockFn             func()
+	}{
+		{
+			name: "success",
+			requestBody: spotify.TrackLookupRequest{
+				SpotifyIDs: []string{"first", "second"},
+			},
+			expectedStatusCode: 200,
+			mockFn: func() {
+				mockSvc.EXPECT().GetTracks(gomock.Any(), []string{"first", "second"}, uint(1)).Return(&spotify.TrackLookupResponse{
+					Items: []spotify.SpotifyTrackObjectResponse{
+						{ID: "first"},
+						{ID: "second"},
+					},
+				}, nil)
+			},
+		},
+		{
+			name:               "should fail when spotify ids empty",
+			requestBody:        spotify.TrackLookupRequest{},
+			expectedStatusCode: 422,
+			mockFn: func() {
+				mockSvc.EXPECT().GetTracks(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+		{
+			name: "failed",
+			requestBody: spotify.TrackLookupRequest{
+				SpotifyIDs: []string{"first"},
+			},
+			expectedStatusCode: 500,
+			mockFn: func() {
+				mockSvc.EXPECT().GetTracks(gomock.Any(), []string{"first"}, uint(1)).Return(nil, assert.AnError)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFn()
+
+			config, err := configs.Init("../../configs", "env", "test.env")
+			assert.NoError(t, err)
+
+			gin.SetMode(gin.ReleaseMode)
+
+			r := gin.New()
+			route := r.Group("/api/v1")
+
+			h := &handler{
+				route:   route,
+				service: mockSvc,
+			}
+			h.RegisterRoute()
+
+			bodyBytes, err := json.Marshal(tt.requestBody)
+			assert.NoError(t, err)
+
+			req, err := http.NewRequest(http.MethodPost, "/api/v1/spotify/tracks/lookup", bytes.NewReader(bodyBytes))
+			assert.NoError(t, err)
+
+			token, err := jwt.CreateToken(uint(1), "username", models.RoleUser, config.SecretJWT)
+			assert.NoError(t, err)
+
+			req.Header.Set("Authorization", token)
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+		})
+	}
+}
+
+func Test_handler_UpdateActivity(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockSvc := NewMockSpotifyService(mockCtrl)
+
+	rating := 4
+
+	tests := []struct {
+		name               string
+		requestBody        string
+		mockFn             func()
+		expectedStatusCode int
+		expectedResponse   *spotify.ActivityResponse
+	}{
+		{
+			name:        "should tell missing fields from cleared ones",
+			requestBody: `{"rating": 4, "note": null}`,
+			mockFn: func() {
+				mockSvc.EXPECT().UpdateActivity(gomock.Any(), uint(1), "SpotifyID", spotify.UpdateActivityRequest{
+					Rating: spotify.Optional[int]{Set: true, Value: &rating},
+					Note:   spotify.Optional[string]{Set: true},
+				}).Return(&spotify.ActivityResponse{SpotifyID: "SpotifyID", Rating: &rating}, nil)
+			},
+			expectedStatusCode: 200,
+			expectedResponse:   &spotify.ActivityResponse{SpotifyID: "SpotifyID", Rating: &rating},
+		},
+		{
+			name:               "should fail on malformed field",
+			requestBody:        `{"rating": "four"}`,
+			mockFn:             func() {},
+			expectedStatusCode: 422,
+		},
+		{
+			name:        "should fail on out of range rating",
+			requestBody: `{"rating": 6}`,
+			mockFn: func() {
+				mockSvc.EXPECT().UpdateActivity(gomock.Any(), uint(1), "SpotifyID", gomock.Any()).
+					Return(nil, spotifyService.ErrInvalidRating)
+			},
+			expectedStatusCode: 422,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFn()
+
+			config, err := configs.Init("../../configs", "env", "test.env")
+			assert.NoError(t, err)
+
+			gin.SetMode(gin.ReleaseMode)
+
+			r := gin.New()
+			route := r.Group("/api/v1")
+
+			h := &handler{
+				route:   route,
+				service: mockSvc,
+			}
+			h.RegisterRoute()
+
+			req, err := http.NewRequest(http.MethodPatch, "/api/v1/spotify/activity/SpotifyID", bytes.NewBufferString(tt.requestBody))
+			assert.NoError(t, err)
+
+			token, err := jwt.CreateToken(uint(1), "username", models.RoleUser, config.SecretJWT)
+			assert.NoError(t, err)
+
+			req.Header.Set("Authorization", token)
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+
+			if tt.expectedResponse != nil {
+				response := spotify.ActivityResponse{}
+				err = json.Unmarshal(w.Body.Bytes(), &response)
+				assert.NoError(t, err)
+
+				assert.Equal(t, *tt.expectedResponse, response)
+			}
+		})
+	}
+}
+
+func Test_handler_GetLibrary(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockSvc := NewMockSpotifyService(mockCtrl)
+
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name               string
+		endpoint           string
+		expectedStatusCode int
+		mockFn             func()
+	}{
+		{
+			name:               "should list liked tracks",
+			endpoint:           "/api/v1/spotify/activity/liked?pageIndex=2&pageSize=5&sort=oldest&from=2024-01-01T00:00:00Z",
+			expectedStatusCode: 200,
+			mockFn: func() {
+				mockSvc.EXPECT().GetLibrary(gomock.Any(), uint(1), true, spotify.LibraryRequest{
+					PageIndex: 2,
+					PageSize:  5,
+					Sort:      "oldest",
+					From:      &from,
+				}).Return(&spotify.LibraryResponse{}, nil)
+			},
+		},
+		{
+			name:               "should list disliked tracks",
+			endpoint:           "/api/v1/spotify/activity/disliked",
+			expectedStatusCode: 200,
+			mockFn: func() {
+				mockSvc.EXPECT().GetLibrary(gomock.Any(), uint(1), false, spotify.LibraryRequest{}).
+					Return(&spotify.LibraryResponse{}, nil)
+			},
+		},
+		{
+			name:               "should filter by minimum rating and tag",
+			endpoint:           "/api/v1/spotify/activity/liked?minRating=4&tag=workout",
+			expectedStatusCode: 200,
+			mockFn: func() {
+				mockSvc.EXPECT().GetLibrary(gomock.Any(), uint(1), true, spotify.LibraryRequest{MinRating: 4, Tag: "workout"}).
+					Return(&spotify.LibraryResponse{}, nil)
+			},
+		},
+		{
+			name:               "should fail on out of range minimum rating",
+			endpoint:           "/api/v1/spotify/activity/liked?minRating=6",
+			expectedStatusCode: 422,
+			mockFn: func() {
+				mockSvc.EXPECT().GetLibrary(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+		{
+			name:               "should fail on unknown sort",
+			endpoint:           "/api/v1/spotify/activity/liked?sort=random",
+			expectedStatusCode: 422,
+			mockFn: func() {
+				mockSvc.EXPECT().GetLibrary(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFn()
+
+			config, err := configs.Init("../../configs", "env", "test.env")
+			assert.NoError(t, err)
+
+			gin.SetMode(gin.ReleaseMode)
+
+			r := gin.New()
+			route := r.Group("/api/v1")
+
+			h := &handler{
+				route:   route,
+				service: mockSvc,
+			}
+			h.RegisterRoute()
+
+			req, err := http.NewRequest(http.MethodGet, tt.endpoint, nil)
+			assert.NoError(t, err)
+
+			token, err := jwt.CreateToken(uint(1), "username", models.RoleUser, config.SecretJWT)
+			assert.NoError(t, err)
+
+			req.Header.Set("Authorization", token)
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+		})
+	}
+}
+
+func Test_handler_GetArtistAlbums(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockSvc := NewMockSpotifyService(mockCtrl)
+
+	tests := []struct {
+		name             string
+		query            string
+		mockFn           func()
+		expectedCode     int
+		expectedResponse spotify.Page[spotify.SpotifyAlbumObjectResponse]
+		wantErr          bool
+	}{
+		{
+			name:         "success",
+			query:        "?pageIndex=1&pageSize=5&market=ID&include_groups=album,single",
+			expectedCode: 200,
+			wantErr:      false,
+			expectedResponse: spotify.Page[spotify.SpotifyAlbumObjectResponse]{
+				Items: []spotify.SpotifyAlbumObjectResponse{
+					{ID: "2noRn2Aes5aoNVsU6iWThc", Name: "Discovery", AlbumGroup: "album"},
+				},
+				Limit: 5,
+				Total: 1,
+			},
+			mockFn: func() {
+				mockSvc.EXPECT().GetArtistAlbums(gomock.Any(), "4tZwfgrHOc3mvqYlEYSvVi", spotify.ArtistAlbumsRequest{
+					PageIndex:     1,
+					PageSize:      5,
+					Market:        "ID",
+					IncludeGroups: []string{"album", "single"},
+				}, uint(1)).Return(&spotify.Page[spotify.SpotifyAlbumObjectResponse]{
+					Items: []spotify.SpotifyAlbumObjectResponse{
+						{ID: "2noRn2Aes5aoNVsU6iWThc", Name: "Discovery", AlbumGroup: "album"},
+					},
+					Limit: 5,
+					Total: 1,
+				}, nil)
+			},
+		},
+		{
+			name:         "invalid album group",
+			query:        "?include_groups=ep",
+			expectedCode: 422,
+			wantErr:      true,
+			mockFn: func() {
+				mockSvc.EXPECT().GetArtistAlbums(gomock.Any(), "4tZwfgrHOc3mvqYlEYSvVi", spotify.ArtistAlbumsRequest{
+					IncludeGroups: []string{"ep"},
+				}, uint(1)).Return(nil, spotifyService.ErrInvalidAlbumGroup)
+			},
+		},
+		{
+			name:         "not found",
+			expectedCode: 404,
+			wantErr:      true,
+			mockFn: func() {
+				mockSvc.EXPECT().GetArtistAlbums(gomock.Any(), "4tZwfgrHOc3mvqYlEYSvVi", spotify.ArtistAlbumsRequest{}, uint(1)).
+					Return(nil, spotifyService.ErrArtistNotFound)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFn()
+
+			config, err := configs.Init("../../configs", "env", "test.env")
+			assert.NoError(t, err)
+
+			gin.SetMode(gin.ReleaseMode)
+
+			r := gin.New()
+			route := r.Group("/api/v1")
+
+			h := &handler{
+				route:   route,
+				service: mockSvc,
+			}
+			h.RegisterRoute()
+
+			req, err := http.NewRequest(http.MethodGet, "/api/v1/spotify/artists/4tZwfgrHOc3mvqYlEYSvVi/albums"+tt.query, nil)
+			assert.NoError(t, err)
+
+			token, err := jwt.CreateToken(uint(1), "username", models.RoleUser, config.SecretJWT)
+			assert.NoError(t, err)
+
+			req.Header.Set("Authorization", token)
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+
+			if !tt.wantErr {
+				response := spotify.Page[spotify.SpotifyAlbumObjectResponse]{}
+				err = json.Unmarshal(w.Body.Bytes(), &response)
+				assert.NoError(t, err)
+
+				assert.Equal(t, tt.expectedResponse, response)
+			}
+		})
+	}
+}
+
+func Test_handler_GetAlbumTracks(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockSvc := NewMockSpotifyService(mockCtrl)
+
+	tests := []struct {
+		name             string
+		query            string
+		mockFn           func()
+		expectedCode     int
+		expectedResponse spotify.AlbumTracksResponse
+		wantErr          bool
+	}{
+		{
+			name:         "success",
+			query:        "?pageIndex=2&pageSize=1&market=ID",
+			expectedCode: 200,
+			wantErr:      false,
+			expectedResponse: spotify.AlbumTracksResponse{
+				Page: spotify.Page[spotify.SpotifyTrackObjectResponse]{
+					Items: []spotify.SpotifyTrackObjectResponse{
+						{ID: "2VxeLyX666F8uXCJ0dZF8B", AlbumID: "2noRn2Aes5aoNVsU6iWThc", TrackNumber: 4},
+					},
+					Limit:  1,
+					Offset: 1,
+					Total:  2,
+				},
+				Completion: spotify.AlbumCompletionResponse{LikedTracks: 1, TotalTracks: 2},
+			},
+			mockFn: func() {
+				mockSvc.EXPECT().GetAlbumTracks(gomock.Any(), "2noRn2Aes5aoNVsU6iWThc", spotify.AlbumTracksRequest{
+					PageIndex: 2,
+					PageSize:  1,
+					Market:    "ID",
+				}, uint(1)).Return(&spotify.AlbumTracksResponse{
+					Page: spotify.Page[spotify.SpotifyTrackObjectResponse]{
+						Items: []spotify.SpotifyTrackObjectResponse{
+							{ID: "2VxeLyX666F8uXCJ0dZF8B", AlbumID: "2noRn2Aes5aoNVsU6iWThc", TrackNumber: 4},
+						},
+						Limit:  1,
+						Offset: 1,
+						Total:  2,
+					},
+					Completion: spotify.AlbumCompletionResponse{LikedTracks: 1, TotalTracks: 2},
+				}, nil)
+			},
+		},
+		{
+			name:         "invalid page size",
+			query:        "?pageSize=abc",
+			expectedCode: 422,
+			wantErr:      true,
+			mockFn:       func() {},
+		},
+		{
+			name:         "not found",
+			expectedCode: 404,
+			wantErr:      true,
+			mockFn: func() {
+				mockSvc.EXPECT().GetAlbumTracks(gomock.Any(), "2noRn2Aes5aoNVsU6iWThc", spotify.AlbumTracksRequest{}, uint(1)).
+					Return(nil, spotifyService.ErrAlbumNotFound)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFn()
+
+			config, err := configs.Init("../../configs", "env", "test.env")
+			assert.NoError(t, err)
+
+			gin.SetMode(gin.ReleaseMode)
+
+			r := gin.New()
+			route := r.Group("/api/v1")
+
+			h := &handler{
+				route:   route,
+				service: mockSvc,
+			}
+			h.RegisterRoute()
+
+			req, err := http.NewRequest(http.MethodGet, "/api/v1/spotify/albums/2noRn2Aes5aoNVsU6iWThc/tracks"+tt.query, nil)
+			assert.NoError(t, err)
+
+			token, err := jwt.CreateToken(uint(1), "username", models.RoleUser, config.SecretJWT)
+			assert.NoError(t, err)
+
+			req.Header.Set("Authorization", token)
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+
+			if !tt.wantErr {
+				response := spotify.AlbumTracksResponse{}
+				err = json.Unmarshal(w.Body.Bytes(), &response)
+				assert.NoError(t, err)
+
+				assert.Equal(t, tt.expectedResponse, response)
+			}
+		})
+	}
+}
+
+func Test_handler_GetRecommendations(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockSvc := NewMockSpotifyService(mockCtrl)
+
+	tests := []struct {
+		name             string
+		query            string
+		mockFn           func()
+		expectedCode     int
+		expectedResponse spotify.RecommendationsResponse
+		wantErr          bool
+	}{
+		{
+			name:         "success",
+			query:        "?limit=10&seed_tracks=a,b&seed_genres=jazz&target_energy=0.8&min_tempo=100&foo=bar",
+			expectedCode: 200,
+			wantErr:      false,
+			expectedResponse: spotify.RecommendationsResponse{
+				Seeds: []spotify.RecommendationSeedResponse{{ID: "a", Type: "track"}},
+				Items: []spotify.SpotifyTrackObjectResponse{{ID: "c"}},
+			},
+			mockFn: func() {
+				mockSvc.EXPECT().GetRecommendations(gomock.Any(), uint(1), spotify.RecommendationsRequest{
+					Limit:      10,
+					SeedTracks: []string{"a", "b"},
+					SeedGenres: []string{"jazz"},
+					Attributes: map[string]string{"target_energy": "0.8", "min_tempo": "100"},
+				}).Return(&spotify.RecommendationsResponse{
+					Seeds: []spotify.RecommendationSeedResponse{{ID: "a", Type: "track"}},
+					Items: []spotify.SpotifyTrackObjectResponse{{ID: "c"}},
+				}, nil)
+			},
+		},
+		{
+			name:         "too many seeds",
+			query:        "?seed_genres=a,b,c,d,e,f",
+			expectedCode: 422,
+			wantErr:      true,
+			mockFn: func() {
+				mockSvc.EXPECT().GetRecommendations(gomock.Any(), uint(1), spotify.RecommendationsRequest{
+					SeedGenres: []string{"a", "b", "c", "d", "e", "f"},
+				}).Return(nil, spotifyService.ErrTooManySeeds)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFn()
+
+			config, err := configs.Init("../../configs", "env", "test.env")
+			assert.NoError(t, err)
+
+			gin.SetMode(gin.ReleaseMode)
+
+			r := gin.New()
+			route := r.Group("/api/v1")
+
+			h := &handler{
+				route:   route,
+				service: mockSvc,
+			}
+			h.RegisterRoute()
+
+			req, err := http.NewRequest(http.MethodGet, "/api/v1/spotify/recommendations"+tt.query, nil)
+			assert.NoError(t, err)
+
+			token, err := jwt.CreateToken(uint(1), "username", models.RoleUser, config.SecretJWT)
+			assert.NoError(t, err)
+
+			req.Header.Set("Authorization", token)
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+
+			if !tt.wantErr {
+				response := spotify.RecommendationsResponse{}
+				err = json.Unmarshal(w.Body.Bytes(), &response)
+				assert.NoError(t, err)
+
+				assert.Equal(t, tt.expectedResponse, response)
+			}
+		})
+	}
+}
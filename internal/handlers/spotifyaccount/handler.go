@@ -0,0 +1,101 @@
+package spotifyaccount
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+	"github.com/sgitwhyd/music-catalogue/internal/handlers/response"
+	"github.com/sgitwhyd/music-catalogue/internal/middleware"
+	"github.com/sgitwhyd/music-catalogue/internal/models/spotifyaccount"
+	accountService "github.com/sgitwhyd/music-catalogue/internal/services/spotifyaccount"
+)
+
+type handler struct {
+	service accountService.SpotifyAccountService
+	route   *gin.RouterGroup
+}
+
+func NewSpotifyAccountHandler(service accountService.SpotifyAccountService, route *gin.RouterGroup) *handler {
+	return &handler{
+		service: service,
+		route:   route,
+	}
+}
+
+func (h *handler) Connect(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	userID := c.GetUint("userID")
+	connect, err := h.service.Connect(ctx, userID)
+	if err != nil {
+		log.Error().Err(err).Msg("error handler: Connect spotify")
+		response.Error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, connect)
+}
+
+// Callback takes what spotify appended to the redirect url. The client posts
+// it with the jwt, the state only counts for the user that started it.
+func (h *handler) Callback(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var request spotifyaccount.CallbackRequest
+	err := c.ShouldBindJSON(&request)
+	if err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	userID := c.GetUint("userID")
+	connection, err := h.service.Callback(ctx, userID, request)
+	if err != nil {
+		log.Error().Err(err).Msg("error handler: Callback spotify")
+		response.Error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, connection)
+}
+
+func (h *handler) GetConnection(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	userID := c.GetUint("userID")
+	connection, err := h.service.GetConnection(ctx, userID)
+	if err != nil {
+		log.Error().Err(err).Msg("error handler: GetConnection spotify")
+		response.Error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, connection)
+}
+
+func (h *handler) Disconnect(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	userID := c.GetUint("userID")
+	err := h.service.Disconnect(ctx, userID)
+	if err != nil {
+		log.Error().Err(err).Msg("error handler: Disconnect spotify")
+		response.Error(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *handler) RegisterRoute() {
+	rateLimit := middleware.RateLimitMiddleware("spotify_account")
+
+	authorized := h.route.Group("/spotify")
+	authorized.Use(middleware.AuthMiddleware(), rateLimit)
+
+	authorized.GET("/connect", h.Connect)
+	authorized.POST("/callback", h.Callback)
+	authorized.GET("/connection", h.GetConnection)
+	authorized.DELETE("/connection", h.Disconnect)
+}
@@ -0,0 +1,101 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: service.go
+//
+// Generated by this command:
+//
+//	mockgen -source=service.go -destination=../../handlers/spotifyaccount/handler_mock_test.go -package=spotifyaccount
+//
+
+// Package spotifyaccount is a generated GoMock package.
+package spotifyaccount
+
+import (
+	context "context"
+	reflect "reflect"
+
+	spotifyaccount "github.com/sgitwhyd/music-catalogue/internal/models/spotifyaccount"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockSpotifyAccountService is a mock of SpotifyAccountService interface.
+type MockSpotifyAccountService struct {
+	ctrl     *gomock.Controller
+	recorder *MockSpotifyAccountServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockSpotifyAccountServiceMockRecorder is the mock recorder for MockSpotifyAccountService.
+type MockSpotifyAccountServiceMockRecorder struct {
+	mock *MockSpotifyAccountService
+}
+
+// NewMockSpotifyAccountService creates a new mock instance.
+func NewMockSpotifyAccountService(ctrl *gomock.Controller) *MockSpotifyAccountService {
+	mock := &MockSpotifyAccountService{ctrl: ctrl}
+	mock.recorder = &MockSpotifyAccountServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSpotifyAccountService) EXPECT() *MockSpotifyAccountServiceMockRecorder {
+	return m.recorder
+}
+
+// Callback mocks base method.
+func (m *MockSpotifyAccountService) Callback(ctx context.Context, userID uint, request spotifyaccount.CallbackRequest) (*spotifyaccount.ConnectionResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Callback", ctx, userID, request)
+	ret0, _ := ret[0].(*spotifyaccount.ConnectionResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Callback indicates an expected call of Callback.
+func (mr *MockSpotifyAccountServiceMockRecorder) Callback(ctx, userID, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Callback", reflect.TypeOf((*MockSpotifyAccountService)(nil).Callback), ctx, userID, request)
+}
+
+// Connect mocks base method.
+func (m *MockSpotifyAccountService) Connect(ctx context.Context, userID uint) (*spotifyaccount.ConnectResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Connect", ctx, userID)
+	ret0, _ := ret[0].(*spotifyaccount.ConnectResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Connect indicates an expected call of Connect.
+func (mr *MockSpotifyAccountServiceMockRecorder) Connect(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Connect", reflect.TypeOf((*MockSpotifyAccountService)(nil).Connect), ctx, userID)
+}
+
+// Disconnect mocks base method.
+func (m *MockSpotifyAccountService) Disconnect(ctx context.Context, userID uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Disconnect", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Disconnect indicates an expected call of Disconnect.
+func (mr *MockSpotifyAccountServiceMockRecorder) Disconnect(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Disconnect", reflect.TypeOf((*MockSpotifyAccountService)(nil).Disconnect), ctx, userID)
+}
+
+// GetConnection mocks base method.
+func (m *MockSpotifyAccountService) GetConnection(ctx context.Context, userID uint) (*spotifyaccount.ConnectionResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetConnection", ctx, userID)
+	ret0, _ := ret[0].(*spotifyaccount.ConnectionResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetConnection indicates an expected call of GetConnection.
+func (mr *MockSpotifyAccountServiceMockRecorder) GetConnection(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConnection", reflect.TypeOf((*MockSpotifyAccountService)(nil).GetConnection), ctx, userID)
+}
@@ -0,0 +1,210 @@
+package spotifyaccount
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sgitwhyd/music-catalogue/internal/configs"
+	"github.com/sgitwhyd/music-catalogue/internal/handlers/response"
+	"github.com/sgitwhyd/music-catalogue/internal/models"
+	"github.com/sgitwhyd/music-catalogue/internal/models/spotifyaccount"
+	accountService "github.com/sgitwhyd/music-catalogue/internal/services/spotifyaccount"
+	"github.com/sgitwhyd/music-catalogue/pkg/jwt"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func Test_handler_Connect(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockSvc := NewMockSpotifyAccountService(mockCtrl)
+
+	expiresAt := time.Date(2026, 1, 2, 15, 14, 5, 0, time.UTC)
+
+	tests := []struct {
+		name               string
+		withToken          bool
+		mockFn             func()
+		expectedStatusCode int
+		expectedCode       string
+		expectedBody       spotifyaccount.ConnectResponse
+	}{
+		{
+			name:      "success",
+			withToken: true,
+			mockFn: func() {
+				mockSvc.EXPECT().Connect(gomock.Any(), uint(1)).
+					Return(&spotifyaccount.ConnectResponse{AuthorizeURL: "https://accounts.spotify.com/authorize?state=abc", ExpiresAt: expiresAt}, nil)
+			},
+			expectedStatusCode: 200,
+			expectedBody:       spotifyaccount.ConnectResponse{AuthorizeURL: "https://accounts.spotify.com/authorize?state=abc", ExpiresAt: expiresAt},
+		},
+		{
+			name:               "unauthenticated",
+			mockFn:             func() {},
+			expectedStatusCode: 401,
+			expectedCode:       "token_not_provided",
+		},
+		{
+			name:      "linking disabled",
+			withToken: true,
+			mockFn: func() {
+				mockSvc.EXPECT().Connect(gomock.Any(), uint(1)).Return(nil, accountService.ErrSpotifyLinkingDisabled)
+			},
+			expectedStatusCode: 503,
+			expectedCode:       "spotify_linking_disabled",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFn()
+
+			config, err := configs.Init("../../configs", "env", "test.env")
+			assert.NoError(t, err)
+
+			gin.SetMode(gin.ReleaseMode)
+
+			r := gin.New()
+			route := r.Group("/api/v1")
+
+			h := NewSpotifyAccountHandler(mockSvc, route)
+			h.RegisterRoute()
+
+			req, err := http.NewRequest(http.MethodGet, "/api/v1/spotify/connect", nil)
+			assert.NoError(t, err)
+
+			if tt.withToken {
+				token, err := jwt.CreateToken(uint(1), "username", models.RoleUser, config.SecretJWT)
+				assert.NoError(t, err)
+
+				req.Header.Set("Authorization", token)
+			}
+
+			w := httptest.NewRecorder()
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+
+			if tt.expectedCode != "" {
+				res := response.ErrorEnvelope{}
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+				assert.Equal(t, tt.expectedCode, res.Error.Code)
+				return
+			}
+
+			res := spotifyaccount.ConnectResponse{}
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+			assert.Equal(t, tt.expectedBody, res)
+		})
+	}
+}
+
+func Test_handler_Callback(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockSvc := NewMockSpotifyAccountService(mockCtrl)
+
+	tests := []struct {
+		name               string
+		withToken          bool
+		body               string
+		mockFn             func()
+		expectedStatusCode int
+		expectedCode       string
+		expectedBody       spotifyaccount.ConnectionResponse
+	}{
+		{
+			name:      "success",
+			withToken: true,
+			body:      `{"code":"abc","state":"xyz"}`,
+			mockFn: func() {
+				mockSvc.EXPECT().Callback(gomock.Any(), uint(1), spotifyaccount.CallbackRequest{Code: "abc", State: "xyz"}).
+					Return(&spotifyaccount.ConnectionResponse{Connected: true, SpotifyUserID: "listener"}, nil)
+			},
+			expectedStatusCode: 200,
+			expectedBody:       spotifyaccount.ConnectionResponse{Connected: true, SpotifyUserID: "listener"},
+		},
+		{
+			name:               "unauthenticated",
+			body:               `{"code":"abc","state":"xyz"}`,
+			mockFn:             func() {},
+			expectedStatusCode: 401,
+			expectedCode:       "token_not_provided",
+		},
+		{
+			name:      "state of another user",
+			withToken: true,
+			body:      `{"code":"abc","state":"forged"}`,
+			mockFn: func() {
+				mockSvc.EXPECT().Callback(gomock.Any(), uint(1), spotifyaccount.CallbackRequest{Code: "abc", State: "forged"}).
+					Return(nil, accountService.ErrInvalidOAuthState)
+			},
+			expectedStatusCode: 422,
+			expectedCode:       "invalid_oauth_state",
+		},
+		{
+			name:      "access denied",
+			withToken: true,
+			body:      `{"error":"access_denied","state":"xyz"}`,
+			mockFn: func() {
+				mockSvc.EXPECT().Callback(gomock.Any(), uint(1), spotifyaccount.CallbackRequest{Error: "access_denied", State: "xyz"}).
+					Return(nil, accountService.ErrSpotifyAuthDenied)
+			},
+			expectedStatusCode: 403,
+			expectedCode:       "spotify_auth_denied",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFn()
+
+			config, err := configs.Init("../../configs", "env", "test.env")
+			assert.NoError(t, err)
+
+			gin.SetMode(gin.ReleaseMode)
+
+			r := gin.New()
+			route := r.Group("/api/v1")
+
+			h := NewSpotifyAccountHandler(mockSvc, route)
+			h.RegisterRoute()
+
+			req, err := http.NewRequest(http.MethodPost, "/api/v1/spotify/callback", strings.NewReader(tt.body))
+			assert.NoError(t, err)
+
+			req.Header.Set("Content-Type", "application/json")
+
+			if tt.withToken {
+				token, err := jwt.CreateToken(uint(1), "username", models.RoleUser, config.SecretJWT)
+				assert.NoError(t, err)
+
+				req.Header.Set("Authorization", token)
+			}
+
+			w := httptest.NewRecorder()
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+
+			if tt.expectedCode != "" {
+				res := response.ErrorEnvelope{}
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+				assert.Equal(t, tt.expectedCode, res.Error.Code)
+				return
+			}
+
+			res := spotifyaccount.ConnectionResponse{}
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+			assert.Equal(t, tt.expectedBody, res)
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package spotifysync
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+	"github.com/sgitwhyd/music-catalogue/internal/apperror"
+	"github.com/sgitwhyd/music-catalogue/internal/handlers/response"
+	"github.com/sgitwhyd/music-catalogue/internal/middleware"
+	"github.com/sgitwhyd/music-catalogue/internal/models/spotifysync"
+	syncService "github.com/sgitwhyd/music-catalogue/internal/services/spotifysync"
+)
+
+var ErrInvalidSyncRunID = apperror.New(apperror.KindInvalid, "invalid_sync_run_id", "sync run id must be a positive number")
+
+type handler struct {
+	service syncService.SpotifySyncService
+	route   *gin.RouterGroup
+}
+
+func NewSpotifySyncHandler(service syncService.SpotifySyncService, route *gin.RouterGroup) *handler {
+	return &handler{
+		service: service,
+		route:   route,
+	}
+}
+
+func (h *handler) Sync(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	userID := c.GetUint("userID")
+	run, err := h.service.Sync(ctx, userID)
+	if err != nil {
+		log.Error().Err(err).Msg("error handler: Sync spotify")
+		response.Error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, run)
+}
+
+func (h *handler) ListRuns(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var request spotifysync.ListSyncRunsRequest
+	err := c.ShouldBindQuery(&request)
+	if err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	userID := c.GetUint("userID")
+	runs, err := h.service.ListRuns(ctx, userID, request)
+	if err != nil {
+		log.Error().Err(err).Msg("error handler: ListRuns spotify sync")
+		response.Error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, runs)
+}
+
+func (h *handler) GetRun(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	runID, ok := syncRunIDParam(c)
+	if !ok {
+		return
+	}
+
+	userID := c.GetUint("userID")
+	run, err := h.service.GetRun(ctx, userID, runID)
+	if err != nil {
+		log.Error().Err(err).Msg("error handler: GetRun spotify sync")
+		response.Error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, run)
+}
+
+func (h *handler) RegisterRoute() {
+	route := h.route.Group("/spotify/sync")
+	route.Use(middleware.AuthMiddleware(), middleware.RateLimitMiddleware("spotify_sync"))
+
+	route.POST("", h.Sync)
+	route.GET("/runs", h.ListRuns)
+	route.GET("/runs/:id", h.GetRun)
+}
+
+// syncRunIDParam parses the :id path parameter, writing the error response
+// when it isn't a valid id.
+func syncRunIDParam(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil || id == 0 {
+		response.Error(c, ErrInvalidSyncRunID)
+		return 0, false
+	}
+
+	return uint(id), true
+}
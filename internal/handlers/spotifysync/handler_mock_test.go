@@ -0,0 +1,87 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: service.go
+//
+// Generated by this command:
+//
+//	mockgen -source=service.go -destination=../../handlers/spotifysync/handler_mock_test.go -package=spotifysync
+//
+
+// Package spotifysync is a generated GoMock package.
+package spotifysync
+
+import (
+	context "context"
+	reflect "reflect"
+
+	spotifysync "github.com/sgitwhyd/music-catalogue/internal/models/spotifysync"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockSpotifySyncService is a mock of SpotifySyncService interface.
+type MockSpotifySyncService struct {
+	ctrl     *gomock.Controller
+	recorder *MockSpotifySyncServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockSpotifySyncServiceMockRecorder is the mock recorder for MockSpotifySyncService.
+type MockSpotifySyncServiceMockRecorder struct {
+	mock *MockSpotifySyncService
+}
+
+// NewMockSpotifySyncService creates a new mock instance.
+func NewMockSpotifySyncService(ctrl *gomock.Controller) *MockSpotifySyncService {
+	mock := &MockSpotifySyncService{ctrl: ctrl}
+	mock.recorder = &MockSpotifySyncServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSpotifySyncService) EXPECT() *MockSpotifySyncServiceMockRecorder {
+	return m.recorder
+}
+
+// GetRun mocks base method.
+func (m *MockSpotifySyncService) GetRun(ctx context.Context, userID, runID uint) (*spotifysync.SyncRunResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRun", ctx, userID, runID)
+	ret0, _ := ret[0].(*spotifysync.SyncRunResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRun indicates an expected call of GetRun.
+func (mr *MockSpotifySyncServiceMockRecorder) GetRun(ctx, userID, runID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRun", reflect.TypeOf((*MockSpotifySyncService)(nil).GetRun), ctx, userID, runID)
+}
+
+// ListRuns mocks base method.
+func (m *MockSpotifySyncService) ListRuns(ctx context.Context, userID uint, request spotifysync.ListSyncRunsRequest) (*spotifysync.ListSyncRunsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRuns", ctx, userID, request)
+	ret0, _ := ret[0].(*spotifysync.ListSyncRunsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRuns indicates an expected call of ListRuns.
+func (mr *MockSpotifySyncServiceMockRecorder) ListRuns(ctx, userID, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRuns", reflect.TypeOf((*MockSpotifySyncService)(nil).ListRuns), ctx, userID, request)
+}
+
+// Sync mocks base method.
+func (m *MockSpotifySyncService) Sync(ctx context.Context, userID uint) (*spotifysync.SyncRunResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Sync", ctx, userID)
+	ret0, _ := ret[0].(*spotifysync.SyncRunResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Sync indicates an expected call of Sync.
+func (mr *MockSpotifySyncServiceMockRecorder) Sync(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Sync", reflect.TypeOf((*MockSpotifySyncService)(nil).Sync), ctx, userID)
+}
@@ -0,0 +1,184 @@
+package spotifysync
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sgitwhyd/music-catalogue/internal/configs"
+	"github.com/sgitwhyd/music-catalogue/internal/handlers/response"
+	"github.com/sgitwhyd/music-catalogue/internal/models"
+	"github.com/sgitwhyd/music-catalogue/internal/models/spotifysync"
+	syncService "github.com/sgitwhyd/music-catalogue/internal/services/spotifysync"
+	"github.com/sgitwhyd/music-catalogue/pkg/jwt"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func Test_handler_Sync(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockSvc := NewMockSpotifySyncService(mockCtrl)
+
+	tests := []struct {
+		name               string
+		mockFn             func()
+		expectedStatusCode int
+		expectedCode       string
+		expectedBody       spotifysync.SyncRunResponse
+	}{
+		{
+			name: "success",
+			mockFn: func() {
+				mockSvc.EXPECT().Sync(gomock.Any(), uint(1)).
+					Return(&spotifysync.SyncRunResponse{ID: 4, Trigger: spotifysync.TriggerManual, Status: spotifysync.StatusRunning}, nil)
+			},
+			expectedStatusCode: 202,
+			expectedBody:       spotifysync.SyncRunResponse{ID: 4, Trigger: spotifysync.TriggerManual, Status: spotifysync.StatusRunning},
+		},
+		{
+			name: "not connected",
+			mockFn: func() {
+				mockSvc.EXPECT().Sync(gomock.Any(), uint(1)).Return(nil, syncService.ErrSpotifyNotConnected)
+			},
+			expectedStatusCode: 403,
+			expectedCode:       "spotify_not_connected",
+		},
+		{
+			name: "already syncing",
+			mockFn: func() {
+				mockSvc.EXPECT().Sync(gomock.Any(), uint(1)).Return(nil, syncService.ErrSyncInProgress)
+			},
+			expectedStatusCode: 409,
+			expectedCode:       "sync_in_progress",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFn()
+
+			config, err := configs.Init("../../configs", "env", "test.env")
+			assert.NoError(t, err)
+
+			gin.SetMode(gin.ReleaseMode)
+
+			r := gin.New()
+			route := r.Group("/api/v1")
+
+			h := NewSpotifySyncHandler(mockSvc, route)
+			h.RegisterRoute()
+
+			req, err := http.NewRequest(http.MethodPost, "/api/v1/spotify/sync", nil)
+			assert.NoError(t, err)
+
+			token, err := jwt.CreateToken(uint(1), "username", models.RoleUser, config.SecretJWT)
+			assert.NoError(t, err)
+
+			req.Header.Set("Authorization", token)
+
+			w := httptest.NewRecorder()
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+
+			if tt.expectedCode != "" {
+				res := response.ErrorEnvelope{}
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+				assert.Equal(t, tt.expectedCode, res.Error.Code)
+				return
+			}
+
+			res := spotifysync.SyncRunResponse{}
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+			assert.Equal(t, tt.expectedBody, res)
+		})
+	}
+}
+
+func Test_handler_GetRun(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockSvc := NewMockSpotifySyncService(mockCtrl)
+
+	tests := []struct {
+		name               string
+		runID              string
+		mockFn             func()
+		expectedStatusCode int
+		expectedCode       string
+		expectedBody       spotifysync.SyncRunResponse
+	}{
+		{
+			name:  "success",
+			runID: "4",
+			mockFn: func() {
+				mockSvc.EXPECT().GetRun(gomock.Any(), uint(1), uint(4)).
+					Return(&spotifysync.SyncRunResponse{ID: 4, Status: spotifysync.StatusCompleted, SavedRemote: 3}, nil)
+			},
+			expectedStatusCode: 200,
+			expectedBody:       spotifysync.SyncRunResponse{ID: 4, Status: spotifysync.StatusCompleted, SavedRemote: 3},
+		},
+		{
+			name:               "invalid id",
+			runID:              "abc",
+			mockFn:             func() {},
+			expectedStatusCode: 422,
+			expectedCode:       "invalid_sync_run_id",
+		},
+		{
+			name:  "not found",
+			runID: "5",
+			mockFn: func() {
+				mockSvc.EXPECT().GetRun(gomock.Any(), uint(1), uint(5)).Return(nil, syncService.ErrSyncRunNotFound)
+			},
+			expectedStatusCode: 404,
+			expectedCode:       "sync_run_not_found",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFn()
+
+			config, err := configs.Init("../../configs", "env", "test.env")
+			assert.NoError(t, err)
+
+			gin.SetMode(gin.ReleaseMode)
+
+			r := gin.New()
+			route := r.Group("/api/v1")
+
+			h := NewSpotifySyncHandler(mockSvc, route)
+			h.RegisterRoute()
+
+			req, err := http.NewRequest(http.MethodGet, "/api/v1/spotify/sync/runs/"+tt.runID, nil)
+			assert.NoError(t, err)
+
+			token, err := jwt.CreateToken(uint(1), "username", models.RoleUser, config.SecretJWT)
+			assert.NoError(t, err)
+
+			req.Header.Set("Authorization", token)
+
+			w := httptest.NewRecorder()
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+
+			if tt.expectedCode != "" {
+				res := response.ErrorEnvelope{}
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+				assert.Equal(t, tt.expectedCode, res.Error.Code)
+				return
+			}
+
+			res := spotifysync.SyncRunResponse{}
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+			assert.Equal(t, tt.expectedBody, res)
+		})
+	}
+}
@@ -0,0 +1,177 @@
+package tag
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+	"github.com/sgitwhyd/music-catalogue/internal/apperror"
+	"github.com/sgitwhyd/music-catalogue/internal/handlers/response"
+	"github.com/sgitwhyd/music-catalogue/internal/middleware"
+	"github.com/sgitwhyd/music-catalogue/internal/models/tag"
+	tagService "github.com/sgitwhyd/music-catalogue/internal/services/tag"
+)
+
+var ErrInvalidTagID = apperror.New(apperror.KindInvalid, "invalid_tag_id", "tag id must be a positive number")
+
+type handler struct {
+	service tagService.TagService
+	route   *gin.RouterGroup
+}
+
+func NewTagHandler(service tagService.TagService, route *gin.RouterGroup) *handler {
+	return &handler{
+		service: service,
+		route:   route,
+	}
+}
+
+func (h *handler) Create(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var request tag.CreateTagRequest
+	err := c.ShouldBindJSON(&request)
+	if err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	userID := c.GetUint("userID")
+	created, err := h.service.Create(ctx, userID, request)
+	if err != nil {
+		log.Error().Err(err).Msg("error handler: Create tag")
+		response.Error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, created)
+}
+
+func (h *handler) List(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	userID := c.GetUint("userID")
+	tags, err := h.service.List(ctx, userID)
+	if err != nil {
+		log.Error().Err(err).Msg("error handler: List tags")
+		response.Error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, tags)
+}
+
+func (h *handler) Update(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	tagID, ok := tagIDParam(c)
+	if !ok {
+		return
+	}
+
+	var request tag.UpdateTagRequest
+	err := c.ShouldBindJSON(&request)
+	if err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	userID := c.GetUint("userID")
+	updated, err := h.service.Update(ctx, userID, tagID, request)
+	if err != nil {
+		log.Error().Err(err).Msg("error handler: Update tag")
+		response.Error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, updated)
+}
+
+func (h *handler) Delete(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	tagID, ok := tagIDParam(c)
+	if !ok {
+		return
+	}
+
+	userID := c.GetUint("userID")
+	err := h.service.Delete(ctx, userID, tagID)
+	if err != nil {
+		log.Error().Err(err).Msg("error handler: Delete tag")
+		response.Error(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *handler) TagTracks(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	tagID, ok := tagIDParam(c)
+	if !ok {
+		return
+	}
+
+	var request tag.TagTracksRequest
+	err := c.ShouldBindJSON(&request)
+	if err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	userID := c.GetUint("userID")
+	err = h.service.TagTracks(ctx, userID, tagID, request)
+	if err != nil {
+		log.Error().Err(err).Msg("error handler: TagTracks")
+		response.Error(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *handler) UntagTrack(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	tagID, ok := tagIDParam(c)
+	if !ok {
+		return
+	}
+
+	userID := c.GetUint("userID")
+	err := h.service.UntagTrack(ctx, userID, tagID, c.Param("spotifyID"))
+	if err != nil {
+		log.Error().Err(err).Msg("error handler: UntagTrack")
+		response.Error(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// tagIDParam parses the :id path parameter, writing the error response when
+// it isn't a valid id.
+func tagIDParam(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil || id == 0 {
+		response.Error(c, ErrInvalidTagID)
+		return 0, false
+	}
+
+	return uint(id), true
+}
+
+func (h *handler) RegisterRoute() {
+	route := h.route.Group("/tags")
+	route.Use(middleware.AuthMiddleware(), middleware.RateLimitMiddleware("tags"))
+
+	route.POST("", h.Create)
+	route.GET("", h.List)
+	route.PATCH("/:id", h.Update)
+	route.DELETE("/:id", h.Delete)
+	route.POST("/:id/tracks", h.TagTracks)
+	route.DELETE("/:id/tracks/:spotifyID", h.UntagTrack)
+}
@@ -0,0 +1,129 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: service.go
+//
+// Generated by this command:
+//
+//	mockgen -source=service.go -destination=../../handlers/tag/handler_mock_test.go -package=tag
+//
+
+// Package tag is a generated GoMock package.
+package tag
+
+import (
+	context "context"
+	reflect "reflect"
+
+	tag "github.com/sgitwhyd/music-catalogue/internal/models/tag"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockTagService is a mock of TagService interface.
+type MockTagService struct {
+	ctrl     *gomock.Controller
+	recorder *MockTagServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockTagServiceMockRecorder is the mock recorder for MockTagService.
+type MockTagServiceMockRecorder struct {
+	mock *MockTagService
+}
+
+// NewMockTagService creates a new mock instance.
+func NewMockTagService(ctrl *gomock.Controller) *MockTagService {
+	mock := &MockTagService{ctrl: ctrl}
+	mock.recorder = &MockTagServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTagService) EXPECT() *MockTagServiceMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockTagService) Create(ctx context.Context, userID uint, request tag.CreateTagRequest) (*tag.TagResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, userID, request)
+	ret0, _ := ret[0].(*tag.TagResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockTagServiceMockRecorder) Create(ctx, userID, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockTagService)(nil).Create), ctx, userID, request)
+}
+
+// Delete mocks base method.
+func (m *MockTagService) Delete(ctx context.Context, userID, tagID uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, userID, tagID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockTagServiceMockRecorder) Delete(ctx, userID, tagID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockTagService)(nil).Delete), ctx, userID, tagID)
+}
+
+// List mocks base method.
+func (m *MockTagService) List(ctx context.Context, userID uint) (*tag.ListTagResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, userID)
+	ret0, _ := ret[0].(*tag.ListTagResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockTagServiceMockRecorder) List(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockTagService)(nil).List), ctx, userID)
+}
+
+// TagTracks mocks base method.
+func (m *MockTagService) TagTracks(ctx context.Context, userID, tagID uint, request tag.TagTracksRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TagTracks", ctx, userID, tagID, request)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// TagTracks indicates an expected call of TagTracks.
+func (mr *MockTagServiceMockRecorder) TagTracks(ctx, userID, tagID, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TagTracks", reflect.TypeOf((*MockTagService)(nil).TagTracks), ctx, userID, tagID, request)
+}
+
+// UntagTrack mocks base method.
+func (m *MockTagService) UntagTrack(ctx context.Context, userID, tagID uint, spotifyID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UntagTrack", ctx, userID, tagID, spotifyID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UntagTrack indicates an expected call of UntagTrack.
+func (mr *MockTagServiceMockRecorder) UntagTrack(ctx, userID, tagID, spotifyID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UntagTrack", reflect.TypeOf((*MockTagService)(nil).UntagTrack), ctx, userID, tagID, spotifyID)
+}
+
+// Update mocks base method.
+func (m *MockTagService) Update(ctx context.Context, userID, tagID uint, request tag.UpdateTagRequest) (*tag.TagResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, userID, tagID, request)
+	ret0, _ := ret[0].(*tag.TagResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockTagServiceMockRecorder) Update(ctx, userID, tagID, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockTagService)(nil).Update), ctx, userID, tagID, request)
+}
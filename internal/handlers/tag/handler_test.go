@@ -0,0 +1,180 @@
+package tag
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sgitwhyd/music-catalogue/internal/configs"
+	"github.com/sgitwhyd/music-catalogue/internal/handlers/response"
+	"github.com/sgitwhyd/music-catalogue/internal/models"
+	"github.com/sgitwhyd/music-catalogue/internal/models/tag"
+	tagService "github.com/sgitwhyd/music-catalogue/internal/services/tag"
+	"github.com/sgitwhyd/music-catalogue/pkg/jwt"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func Test_handler_Create(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockSvc := NewMockTagService(mockCtrl)
+
+	tests := []struct {
+		name               string
+		requestBody        any
+		mockFn             func()
+		expectedStatusCode int
+		expectedCode       string
+	}{
+		{
+			name:        "success",
+			requestBody: tag.CreateTagRequest{Name: "workout"},
+			mockFn: func() {
+				mockSvc.EXPECT().Create(gomock.Any(), uint(1), tag.CreateTagRequest{Name: "workout"}).Return(&tag.TagResponse{ID: 1, Name: "workout"}, nil)
+			},
+			expectedStatusCode: 201,
+		},
+		{
+			name:               "missing name",
+			requestBody:        tag.CreateTagRequest{},
+			mockFn:             func() {},
+			expectedStatusCode: 422,
+		},
+		{
+			name:        "name taken",
+			requestBody: tag.CreateTagRequest{Name: "workout"},
+			mockFn: func() {
+				mockSvc.EXPECT().Create(gomock.Any(), uint(1), tag.CreateTagRequest{Name: "workout"}).Return(nil, tagService.ErrTagExists)
+			},
+			expectedStatusCode: 409,
+			expectedCode:       "tag_exists",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFn()
+
+			config, err := configs.Init("../../configs", "env", "test.env")
+			assert.NoError(t, err)
+
+			gin.SetMode(gin.ReleaseMode)
+
+			r := gin.New()
+			route := r.Group("/api/v1")
+
+			h := NewTagHandler(mockSvc, route)
+			h.RegisterRoute()
+
+			val, err := json.Marshal(tt.requestBody)
+			assert.NoError(t, err)
+
+			req, err := http.NewRequest(http.MethodPost, "/api/v1/tags", bytes.NewBuffer(val))
+			assert.NoError(t, err)
+
+			token, err := jwt.CreateToken(uint(1), "username", models.RoleUser, config.SecretJWT)
+			assert.NoError(t, err)
+
+			req.Header.Set("Authorization", token)
+
+			w := httptest.NewRecorder()
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+
+			if tt.expectedCode != "" {
+				envelope := response.ErrorEnvelope{}
+				err = json.Unmarshal(w.Body.Bytes(), &envelope)
+				assert.NoError(t, err)
+
+				assert.Equal(t, tt.expectedCode, envelope.Error.Code)
+			}
+		})
+	}
+}
+
+func Test_handler_TagTracks(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockSvc := NewMockTagService(mockCtrl)
+
+	tests := []struct {
+		name               string
+		endpoint           string
+		requestBody        any
+		mockFn             func()
+		expectedStatusCode int
+	}{
+		{
+			name:        "success",
+			endpoint:    "/api/v1/tags/3/tracks",
+			requestBody: tag.TagTracksRequest{SpotifyIDs: []string{"a", "b"}},
+			mockFn: func() {
+				mockSvc.EXPECT().TagTracks(gomock.Any(), uint(1), uint(3), tag.TagTracksRequest{SpotifyIDs: []string{"a", "b"}}).Return(nil)
+			},
+			expectedStatusCode: 204,
+		},
+		{
+			name:               "invalid tag id",
+			endpoint:           "/api/v1/tags/abc/tracks",
+			requestBody:        tag.TagTracksRequest{SpotifyIDs: []string{"a"}},
+			mockFn:             func() {},
+			expectedStatusCode: 422,
+		},
+		{
+			name:               "empty spotify id",
+			endpoint:           "/api/v1/tags/3/tracks",
+			requestBody:        tag.TagTracksRequest{SpotifyIDs: []string{"a", ""}},
+			mockFn:             func() {},
+			expectedStatusCode: 422,
+		},
+		{
+			name:        "tag not found",
+			endpoint:    "/api/v1/tags/3/tracks",
+			requestBody: tag.TagTracksRequest{SpotifyIDs: []string{"a"}},
+			mockFn: func() {
+				mockSvc.EXPECT().TagTracks(gomock.Any(), uint(1), uint(3), tag.TagTracksRequest{SpotifyIDs: []string{"a"}}).Return(tagService.ErrTagNotFound)
+			},
+			expectedStatusCode: 404,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFn()
+
+			config, err := configs.Init("../../configs", "env", "test.env")
+			assert.NoError(t, err)
+
+			gin.SetMode(gin.ReleaseMode)
+
+			r := gin.New()
+			route := r.Group("/api/v1")
+
+			h := NewTagHandler(mockSvc, route)
+			h.RegisterRoute()
+
+			val, err := json.Marshal(tt.requestBody)
+			assert.NoError(t, err)
+
+			req, err := http.NewRequest(http.MethodPost, tt.endpoint, bytes.NewBuffer(val))
+			assert.NoError(t, err)
+
+			token, err := jwt.CreateToken(uint(1), "username", models.RoleUser, config.SecretJWT)
+			assert.NoError(t, err)
+
+			req.Header.Set("Authorization", token)
+
+			w := httptest.NewRecorder()
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+		})
+	}
+}
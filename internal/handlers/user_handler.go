@@ -2,9 +2,13 @@ package handlers
 
 import (
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/rs/zerolog/log"
+	"github.com/sgitwhyd/music-catalogue/internal/apperror"
+	"github.com/sgitwhyd/music-catalogue/internal/handlers/response"
+	"github.com/sgitwhyd/music-catalogue/internal/middleware"
 	"github.com/sgitwhyd/music-catalogue/internal/models"
 	"github.com/sgitwhyd/music-catalogue/internal/services"
 )
@@ -14,6 +18,8 @@ type UserService interface {
 	services.UserService
 }
 
+var ErrInvalidUserID = apperror.New(apperror.KindInvalid, "invalid_user_id", "user id must be a positive number")
+
 type userHandler struct {
 	userService services.UserService
 	route *gin.RouterGroup
@@ -31,26 +37,14 @@ func (h *userHandler) SignUp(c *gin.Context) {
 
 	err := c.ShouldBindJSON(&request)
 	if err != nil {
-		log.Error().Err(err).Msgf("error hhhh %v", err)
-		c.JSON(http.StatusBadRequest, gin.H{
-			"errors": err.Error(),
-		})
+		response.BindError(c, err)
 		return
 	}
 
 	err = h.userService.Register(request)
 	if err != nil {
-		log.Error().Err(err).Msgf("error %v", err)
-		if err.Error() == "username or email already registered" {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"errors": err.Error(),
-			})
-			return
-		}
-
-		c.JSON(http.StatusInternalServerError, gin.H{
-				"errors": err.Error(),
-			})
+		log.Error().Err(err).Msg("error handler: SignUp")
+		response.Error(c, err)
 		return
 	}
 
@@ -64,30 +58,78 @@ func (h *userHandler) SignIn(c *gin.Context) {
 
 	err := c.ShouldBindJSON(&request)
 	if err != nil {
-		c.JSON(http.StatusUnprocessableEntity, gin.H{
-			"error": err.Error(),
-		})
+		response.BindError(c, err)
 		return
 	}
 
-	token, err := h.userService.Login(request)
+	loginResponse, err := h.userService.Login(request)
 	if err != nil {
 		log.Error().Err(err).Msg("error handler: Login")
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": err.Error(),
-		})
+		response.Error(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, models.LoginResponse{
-		AccessToken: token,
-	})
+	c.JSON(http.StatusOK, loginResponse)
+
+}
+
+func (h *userHandler) Refresh(c *gin.Context) {
+	var request models.RefreshTokenRequest
+
+	err := c.ShouldBindJSON(&request)
+	if err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	userID := c.GetUint("userID")
+	refreshResponse, err := h.userService.Refresh(userID, request)
+	if err != nil {
+		log.Error().Err(err).Msg("error handler: Refresh")
+		response.Error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, refreshResponse)
+}
+
+func (h *userHandler) UpdateRole(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil || userID == 0 {
+		response.Error(c, ErrInvalidUserID)
+		return
+	}
 
+	var request models.UpdateRoleRequest
+
+	err = c.ShouldBindJSON(&request)
+	if err != nil {
+		response.BindError(c, err)
+		return
+	}
+
+	adminID := c.GetUint("userID")
+	userResponse, err := h.userService.UpdateRole(adminID, uint(userID), request)
+	if err != nil {
+		log.Error().Err(err).Msg("error handler: UpdateRole")
+		response.Error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, userResponse)
 }
 
 func (h *userHandler) RegisterRoute(){
 	route := h.route.Group("/auth")
+	// keyed by ip, nobody is signed in yet
+	route.Use(middleware.RateLimitMiddleware("auth"))
 
 	route.POST("/signup", h.SignUp)
 	route.POST("/signin", h.SignIn)
+	route.POST("/refresh", middleware.AuthRefreshMiddleware(), h.Refresh)
+
+	admin := h.route.Group("/admin")
+	admin.Use(middleware.AuthMiddleware(), middleware.RateLimitMiddleware("admin"), middleware.RequireRole(models.RoleAdmin))
+
+	admin.PUT("/users/:id/role", h.UpdateRole)
 }
\ No newline at end of file
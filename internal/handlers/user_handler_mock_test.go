@@ -41,10 +41,10 @@ func (m *MockUserService) EXPECT() *MockUserServiceMockRecorder {
 }
 
 // Login mocks base method.
-func (m *MockUserService) Login(request models.SignInRequest) (string, error) {
+func (m *MockUserService) Login(request models.SignInRequest) (*models.LoginResponse, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Login", request)
-	ret0, _ := ret[0].(string)
+	ret0, _ := ret[0].(*models.LoginResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -55,6 +55,21 @@ func (mr *MockUserServiceMockRecorder) Login(request any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Login", reflect.TypeOf((*MockUserService)(nil).Login), request)
 }
 
+// Refresh mocks base method.
+func (m *MockUserService) Refresh(userID uint, request models.RefreshTokenRequest) (*models.LoginResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Refresh", userID, request)
+	ret0, _ := ret[0].(*models.LoginResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Refresh indicates an expected call of Refresh.
+func (mr *MockUserServiceMockRecorder) Refresh(userID, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Refresh", reflect.TypeOf((*MockUserService)(nil).Refresh), userID, request)
+}
+
 // Register mocks base method.
 func (m *MockUserService) Register(request models.SignUpRequest) error {
 	m.ctrl.T.Helper()
@@ -68,3 +83,18 @@ func (mr *MockUserServiceMockRecorder) Register(request any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Register", reflect.TypeOf((*MockUserService)(nil).Register), request)
 }
+
+// UpdateRole mocks base method.
+func (m *MockUserService) UpdateRole(adminID, userID uint, request models.UpdateRoleRequest) (*models.UserResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateRole", adminID, userID, request)
+	ret0, _ := ret[0].(*models.UserResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateRole indicates an expected call of UpdateRole.
+func (mr *MockUserServiceMockRecorder) UpdateRole(adminID, userID, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRole", reflect.TypeOf((*MockUserService)(nil).UpdateRole), adminID, userID, request)
+}
@@ -4,13 +4,16 @@ import (
 	"bytes"
 	"encoding/json"
 	"errors"
-	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
 	"github.com/gin-gonic/gin"
+	"github.com/sgitwhyd/music-catalogue/internal/configs"
+	"github.com/sgitwhyd/music-catalogue/internal/handlers/response"
 	"github.com/sgitwhyd/music-catalogue/internal/models"
+	"github.com/sgitwhyd/music-catalogue/internal/services"
+	"github.com/sgitwhyd/music-catalogue/pkg/jwt"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
 )
@@ -26,6 +29,7 @@ func Test_userHandler_SignUp(t *testing.T) {
 		mockFn             func()
 		requestBody        models.SignUpRequest
 		expectedStatusCode int
+		expectedErrorCode  string
 	}{
 		{
 			name: "success register",
@@ -54,7 +58,7 @@ func Test_userHandler_SignUp(t *testing.T) {
 				Email:    "",
 				Password: "",
 			},
-			expectedStatusCode: 400,
+			expectedStatusCode: 422,
 		},
 		{
 			name: "should fail when username or email already registered",
@@ -66,14 +70,28 @@ func Test_userHandler_SignUp(t *testing.T) {
 						Email:    "developer@testing.com",
 						Password: "password",
 					},
-				).Return(errors.New("username or email already registered"))
+				).Return(services.ErrUserAlreadyRegistered)
 			},
 			requestBody: models.SignUpRequest{
 				Username: "developer",
 				Email:    "developer@testing.com",
 				Password: "password",
 			},
-			expectedStatusCode: 400,
+			expectedStatusCode: 409,
+			expectedErrorCode:  "user_already_registered",
+		},
+		{
+			name: "should hide unexpected errors",
+			mockFn: func() {
+				mockSvc.EXPECT().Register(gomock.Any()).Return(errors.New("pq: connection refused"))
+			},
+			requestBody: models.SignUpRequest{
+				Username: "developer",
+				Email:    "developer@testing.com",
+				Password: "password",
+			},
+			expectedStatusCode: 500,
+			expectedErrorCode:  "internal_error",
 		},
 	}
 
@@ -104,6 +122,14 @@ func Test_userHandler_SignUp(t *testing.T) {
 			r.ServeHTTP(w, httpReq)
 
 			assert.Equal(t, tt.expectedStatusCode, w.Code)
+
+			if tt.expectedErrorCode != "" {
+				envelope := response.ErrorEnvelope{}
+				err = json.Unmarshal(w.Body.Bytes(), &envelope)
+				assert.NoError(t, err)
+
+				assert.Equal(t, tt.expectedErrorCode, envelope.Error.Code)
+			}
 		})
 	}
 }
@@ -142,14 +168,14 @@ func Test_userHandler_SignIn(t *testing.T) {
 				Email: "developer@gmail.com",
 				Password: "password",
 			},
-			expectedStatusCode: 500,
+			expectedStatusCode: 401,
 			wantErr: true,
 			expectedBody: models.LoginResponse{},
 			mockFn: func() {
 				mockSvc.EXPECT().Login(models.SignInRequest{
 					Email: "developer@gmail.com",
 					Password: "password",
-				}).Return("", fmt.Errorf("email not registered"))
+				}).Return(nil, services.ErrInvalidCredentials)
 			},
 		},
 		{	
@@ -158,14 +184,14 @@ func Test_userHandler_SignIn(t *testing.T) {
 				Email: "developer@gmail.com",
 				Password: "password",
 			},
-			expectedStatusCode: 500,
+			expectedStatusCode: 401,
 			wantErr: true,
 			expectedBody: models.LoginResponse{},
 			mockFn: func() {
 				mockSvc.EXPECT().Login(models.SignInRequest{
 					Email: "developer@gmail.com",
 					Password: "password",
-				}).Return("", fmt.Errorf("password doesn't match"))
+				}).Return(nil, services.ErrInvalidCredentials)
 			},
 		},
 		{	
@@ -177,13 +203,17 @@ func Test_userHandler_SignIn(t *testing.T) {
 			expectedStatusCode: 200,
 			expectedBody: models.LoginResponse{
 				AccessToken: "valid token",
+				RefreshToken: "valid refresh token",
 			},
 			wantErr: false,
 			mockFn: func() {
 				mockSvc.EXPECT().Login(models.SignInRequest{
 					Email: "developer@gmail.com",
 					Password: "password",
-				}).Return("valid token", nil)
+				}).Return(&models.LoginResponse{
+					AccessToken: "valid token",
+					RefreshToken: "valid refresh token",
+				}, nil)
 			},
 		},
 	}
@@ -228,3 +258,262 @@ func Test_userHandler_SignIn(t *testing.T) {
 		})
 	}
 }
+
+func Test_userHandler_Refresh(t *testing.T) {
+	ctrlMock := gomock.NewController(t)
+	defer ctrlMock.Finish()
+
+	mockSvc := NewMockUserService(ctrlMock)
+
+	config, err := configs.Init("../configs", "env", "test.env")
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name               string
+		requestBody        models.RefreshTokenRequest
+		withToken          bool
+		expectedStatusCode int
+		expectedBody       models.LoginResponse
+		mockFn             func()
+		wantErr            bool
+	}{
+		{
+			name:               "should fail without access token",
+			requestBody:        models.RefreshTokenRequest{RefreshToken: "refresh token"},
+			withToken:          false,
+			expectedStatusCode: 401,
+			wantErr:            true,
+			mockFn: func() {
+				mockSvc.EXPECT().Refresh(gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+		{
+			name:               "should fail when refresh token not filled",
+			requestBody:        models.RefreshTokenRequest{},
+			withToken:          true,
+			expectedStatusCode: 422,
+			wantErr:            true,
+			mockFn: func() {
+				mockSvc.EXPECT().Refresh(gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+		{
+			name:               "should fail when refresh token reused",
+			requestBody:        models.RefreshTokenRequest{RefreshToken: "refresh token"},
+			withToken:          true,
+			expectedStatusCode: 401,
+			wantErr:            true,
+			mockFn: func() {
+				mockSvc.EXPECT().Refresh(uint(1), models.RefreshTokenRequest{RefreshToken: "refresh token"}).
+					Return(nil, services.ErrRefreshTokenReused)
+			},
+		},
+		{
+			name:               "should fail on internal error",
+			requestBody:        models.RefreshTokenRequest{RefreshToken: "refresh token"},
+			withToken:          true,
+			expectedStatusCode: 500,
+			wantErr:            true,
+			mockFn: func() {
+				mockSvc.EXPECT().Refresh(uint(1), models.RefreshTokenRequest{RefreshToken: "refresh token"}).
+					Return(nil, assert.AnError)
+			},
+		},
+		{
+			name:               "should return rotated tokens",
+			requestBody:        models.RefreshTokenRequest{RefreshToken: "refresh token"},
+			withToken:          true,
+			expectedStatusCode: 200,
+			expectedBody: models.LoginResponse{
+				AccessToken:  "new access token",
+				RefreshToken: "new refresh token",
+			},
+			wantErr: false,
+			mockFn: func() {
+				mockSvc.EXPECT().Refresh(uint(1), models.RefreshTokenRequest{RefreshToken: "refresh token"}).
+					Return(&models.LoginResponse{
+						AccessToken:  "new access token",
+						RefreshToken: "new refresh token",
+					}, nil)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFn()
+
+			r := gin.New()
+			route := r.Group("/api/v1")
+
+			h := &userHandler{
+				route:       route,
+				userService: mockSvc,
+			}
+
+			h.RegisterRoute()
+
+			w := httptest.NewRecorder()
+
+			endpoint := "/api/v1/auth/refresh"
+			bodyBytes, err := json.Marshal(tt.requestBody)
+			assert.NoError(t, err)
+
+			httpReq, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewReader(bodyBytes))
+			assert.NoError(t, err)
+
+			if tt.withToken {
+				token, err := jwt.CreateToken(uint(1), "developer", models.RoleUser, config.SecretJWT)
+				assert.NoError(t, err)
+				httpReq.Header.Set("Authorization", token)
+			}
+
+			r.ServeHTTP(w, httpReq)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+
+			if !tt.wantErr {
+				response := models.LoginResponse{}
+				err := json.Unmarshal(w.Body.Bytes(), &response)
+				assert.NoError(t, err)
+
+				assert.Equal(t, tt.expectedBody, response)
+			}
+		})
+	}
+}
+
+func Test_userHandler_UpdateRole(t *testing.T) {
+	ctrlMock := gomock.NewController(t)
+	defer ctrlMock.Finish()
+
+	mockSvc := NewMockUserService(ctrlMock)
+
+	config, err := configs.Init("../configs", "env", "test.env")
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name               string
+		userID             string
+		role               string
+		requestBody        models.UpdateRoleRequest
+		expectedStatusCode int
+		expectedCode       string
+		expectedBody       models.UserResponse
+		mockFn             func()
+	}{
+		{
+			name:               "should update role",
+			userID:             "2",
+			role:               models.RoleAdmin,
+			requestBody:        models.UpdateRoleRequest{Role: models.RoleAdmin},
+			expectedStatusCode: 200,
+			expectedBody:       models.UserResponse{ID: 2, Email: "developer@gmail.com", Username: "developer", Role: models.RoleAdmin},
+			mockFn: func() {
+				mockSvc.EXPECT().UpdateRole(uint(1), uint(2), models.UpdateRoleRequest{Role: models.RoleAdmin}).
+					Return(&models.UserResponse{ID: 2, Email: "developer@gmail.com", Username: "developer", Role: models.RoleAdmin}, nil)
+			},
+		},
+		{
+			name:               "should forbid non admins",
+			userID:             "2",
+			role:               models.RoleUser,
+			requestBody:        models.UpdateRoleRequest{Role: models.RoleAdmin},
+			expectedStatusCode: 403,
+			expectedCode:       "insufficient_role",
+			mockFn:             func() {},
+		},
+		{
+			name:               "should forbid tokens without role",
+			userID:             "2",
+			role:               "",
+			requestBody:        models.UpdateRoleRequest{Role: models.RoleAdmin},
+			expectedStatusCode: 403,
+			expectedCode:       "insufficient_role",
+			mockFn:             func() {},
+		},
+		{
+			name:               "should fail on invalid user id",
+			userID:             "abc",
+			role:               models.RoleAdmin,
+			requestBody:        models.UpdateRoleRequest{Role: models.RoleAdmin},
+			expectedStatusCode: 422,
+			expectedCode:       "invalid_user_id",
+			mockFn:             func() {},
+		},
+		{
+			name:               "should fail on unknown role",
+			userID:             "2",
+			role:               models.RoleAdmin,
+			requestBody:        models.UpdateRoleRequest{Role: "owner"},
+			expectedStatusCode: 422,
+			mockFn:             func() {},
+		},
+		{
+			name:               "should fail on the dropped curator role",
+			userID:             "2",
+			role:               models.RoleAdmin,
+			requestBody:        models.UpdateRoleRequest{Role: "curator"},
+			expectedStatusCode: 422,
+			mockFn:             func() {},
+		},
+		{
+			name:               "should fail when user not found",
+			userID:             "2",
+			role:               models.RoleAdmin,
+			requestBody:        models.UpdateRoleRequest{Role: models.RoleAdmin},
+			expectedStatusCode: 404,
+			expectedCode:       "user_not_found",
+			mockFn: func() {
+				mockSvc.EXPECT().UpdateRole(uint(1), uint(2), models.UpdateRoleRequest{Role: models.RoleAdmin}).
+					Return(nil, services.ErrUserNotFound)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFn()
+
+			gin.SetMode(gin.ReleaseMode)
+			r := gin.New()
+			route := r.Group("/api/v1")
+
+			h := &userHandler{
+				route:       route,
+				userService: mockSvc,
+			}
+
+			h.RegisterRoute()
+
+			w := httptest.NewRecorder()
+
+			endpoint := "/api/v1/admin/users/" + tt.userID + "/role"
+			bodyBytes, err := json.Marshal(tt.requestBody)
+			assert.NoError(t, err)
+
+			httpReq, err := http.NewRequest(http.MethodPut, endpoint, bytes.NewReader(bodyBytes))
+			assert.NoError(t, err)
+
+			token, err := jwt.CreateToken(uint(1), "admin", tt.role, config.SecretJWT)
+			assert.NoError(t, err)
+			httpReq.Header.Set("Authorization", token)
+
+			r.ServeHTTP(w, httpReq)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+
+			if tt.expectedCode != "" {
+				envelope := response.ErrorEnvelope{}
+				err := json.Unmarshal(w.Body.Bytes(), &envelope)
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedCode, envelope.Error.Code)
+			}
+
+			if tt.expectedStatusCode == 200 {
+				body := models.UserResponse{}
+				err := json.Unmarshal(w.Body.Bytes(), &body)
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedBody, body)
+			}
+		})
+	}
+}
@@ -1,15 +1,23 @@
 package middleware
 
 import (
-	"net/http"
+	"slices"
 	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/rs/zerolog/log"
+	"github.com/sgitwhyd/music-catalogue/internal/apperror"
 	"github.com/sgitwhyd/music-catalogue/internal/configs"
+	"github.com/sgitwhyd/music-catalogue/internal/handlers/response"
 	"github.com/sgitwhyd/music-catalogue/pkg/jwt"
 )
 
+var (
+	ErrTokenNotProvided = apperror.New(apperror.KindUnauthorized, "token_not_provided", "token not provided")
+	ErrInvalidToken     = apperror.New(apperror.KindUnauthorized, "invalid_token", "invalid token")
+	ErrInsufficientRole = apperror.New(apperror.KindForbidden, "insufficient_role", "your role is not allowed to do this")
+)
+
 func AuthMiddleware() gin.HandlerFunc {
 	secretKey := configs.Get().SecretJWT
 
@@ -18,23 +26,20 @@ func AuthMiddleware() gin.HandlerFunc {
 		header = strings.TrimSpace(header)
 		if header == "" {
 			log.Error().Msg("Unauthorize request")
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
-				"error": "token not provided",
-			})
+			response.Error(ctx, ErrTokenNotProvided)
 			return 
 		}
 
-		userID, username, err := jwt.ValidateToken(header, secretKey)
+		userID, username, role, err := jwt.ValidateToken(header, secretKey)
 		if err != nil {
 			log.Error().Msg("Token Invalid")
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
-				"error": "invalid token",
-			})
+			response.Error(ctx, ErrInvalidToken)
 			return
 		}
 
 		ctx.Set("userID", userID)
 		ctx.Set("username", username)
+		ctx.Set("role", role)
 		ctx.Next()
 	}
 }
@@ -47,23 +52,35 @@ func AuthRefreshMiddleware() gin.HandlerFunc {
 		header = strings.TrimSpace(header)
 		if header == "" {
 			log.Error().Msg("Unauthorize request")
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
-				"error": "token not provided",
-			})
+			response.Error(ctx, ErrTokenNotProvided)
 			return 
 		}
 
-		userID, username, err := jwt.ValidateTokenWithoutExpiry(header, secretKey)
+		// the role may be stale, refresh reads the current one from the db
+		userID, username, _, err := jwt.ValidateTokenWithoutExpiry(header, secretKey)
 		if err != nil {
 			log.Error().Msg("Token Invalid")
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
-				"error": "invalid token",
-			})
+			response.Error(ctx, ErrInvalidToken)
 			return
 		}
 
 		ctx.Set("userID", userID)
 		ctx.Set("username", username)
+		ctx.Next()
+	}
+}
+
+// RequireRole lets through users having one of roles. It reads the role
+// AuthMiddleware took from the token, so it has to come after it.
+func RequireRole(roles ...string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		role := ctx.GetString("role")
+		if !slices.Contains(roles, role) {
+			log.Error().Msgf("role %q not allowed, user id: %d", role, ctx.GetUint("userID"))
+			response.Error(ctx, ErrInsufficientRole)
+			return
+		}
+
 		ctx.Next()
 	}
 }
\ No newline at end of file
@@ -0,0 +1,79 @@
+package middleware
+
+import (
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+	"github.com/sgitwhyd/music-catalogue/internal/apperror"
+	"github.com/sgitwhyd/music-catalogue/internal/configs"
+	"github.com/sgitwhyd/music-catalogue/internal/handlers/response"
+	"github.com/sgitwhyd/music-catalogue/pkg/ratelimit"
+)
+
+// DefaultRateLimits apply when RATE_LIMITS is not set. Route groups without
+// a limit of their own share the default limit, each with its own buckets.
+const DefaultRateLimits = "default=300/m,auth=10/m,spotify=60/m:20"
+
+var ErrRateLimited = apperror.New(apperror.KindRateLimited, "rate_limited", "too many requests, try again later")
+
+// RateLimitLimits parses RATE_LIMITS, or DefaultRateLimits when it is empty.
+func RateLimitLimits() (map[string]ratelimit.Limit, error) {
+	spec := configs.Get().RateLimits
+	if spec == "" {
+		spec = DefaultRateLimits
+	}
+
+	return ratelimit.ParseLimits(spec)
+}
+
+// RateLimitMiddleware limits the requests to a route group by the limit named
+// group, or the default one. Signed in users are limited by user id, so it
+// has to come after AuthMiddleware, anybody else by client ip.
+func RateLimitMiddleware(group string) gin.HandlerFunc {
+	limits, err := RateLimitLimits()
+	if err != nil {
+		// main refuses to start with an invalid spec, this is a test setup
+		log.Error().Err(err).Msg("invalid rate limits, requests are not limited")
+		return func(ctx *gin.Context) { ctx.Next() }
+	}
+
+	limit, ok := limits[group]
+	if !ok {
+		limit, ok = limits["default"]
+	}
+	if !ok {
+		return func(ctx *gin.Context) { ctx.Next() }
+	}
+
+	limiter := ratelimit.New(limit)
+
+	return func(ctx *gin.Context) {
+		key := "ip:" + ctx.ClientIP()
+		if userID := ctx.GetUint("userID"); userID != 0 {
+			key = "user:" + strconv.FormatUint(uint64(userID), 10)
+		}
+
+		decision := limiter.Allow(key)
+
+		header := ctx.Writer.Header()
+		header.Set("RateLimit-Limit", strconv.Itoa(decision.Limit))
+		header.Set("RateLimit-Remaining", strconv.Itoa(decision.Remaining))
+		header.Set("RateLimit-Reset", seconds(decision.Reset))
+
+		if !decision.Allowed {
+			header.Set("Retry-After", seconds(decision.RetryAfter))
+			response.Error(ctx, ErrRateLimited)
+			return
+		}
+
+		ctx.Next()
+	}
+}
+
+// seconds rounds up, a client waiting the rounded down time would be early.
+func seconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
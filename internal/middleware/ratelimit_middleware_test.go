@@ -0,0 +1,116 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sgitwhyd/music-catalogue/internal/configs"
+	"github.com/sgitwhyd/music-catalogue/internal/handlers/response"
+	"github.com/sgitwhyd/music-catalogue/internal/models"
+	"github.com/sgitwhyd/music-catalogue/pkg/jwt"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimitMiddleware(t *testing.T) {
+	config, err := configs.Init("../configs", "env", "test.env")
+	assert.NoError(t, err)
+
+	previous := config.RateLimits
+	config.RateLimits = "default=100/m,tags=1/m:2"
+	defer func() { config.RateLimits = previous }()
+
+	gin.SetMode(gin.ReleaseMode)
+
+	newRouter := func(group string, auth bool) *gin.Engine {
+		r := gin.New()
+		route := r.Group("/api/v1")
+		if auth {
+			route.Use(AuthMiddleware())
+		}
+		route.Use(RateLimitMiddleware(group))
+		route.GET("/ping", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+		return r
+	}
+
+	send := func(r *gin.Engine, userID uint, remoteAddr string) *httptest.ResponseRecorder {
+		req, err := http.NewRequest(http.MethodGet, "/api/v1/ping", nil)
+		assert.NoError(t, err)
+		req.RemoteAddr = remoteAddr + ":1234"
+
+		if userID != 0 {
+			token, err := jwt.CreateToken(userID, "username", models.RoleUser, config.SecretJWT)
+			assert.NoError(t, err)
+			req.Header.Set("Authorization", token)
+		}
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		return w
+	}
+
+	t.Run("rejects past the burst", func(t *testing.T) {
+		r := newRouter("tags", true)
+
+		w := send(r, 1, "10.0.0.1")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "2", w.Header().Get("RateLimit-Limit"))
+		assert.Equal(t, "1", w.Header().Get("RateLimit-Remaining"))
+		assert.Equal(t, "60", w.Header().Get("RateLimit-Reset"))
+		assert.Empty(t, w.Header().Get("Retry-After"))
+
+		w = send(r, 1, "10.0.0.1")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "0", w.Header().Get("RateLimit-Remaining"))
+
+		w = send(r, 1, "10.0.0.1")
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+		assert.Equal(t, "0", w.Header().Get("RateLimit-Remaining"))
+		assert.Equal(t, "60", w.Header().Get("Retry-After"))
+
+		var body response.ErrorEnvelope
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		assert.Equal(t, "rate_limited", body.Error.Code)
+	})
+
+	t.Run("keys signed in users by user id", func(t *testing.T) {
+		r := newRouter("tags", true)
+
+		send(r, 1, "10.0.0.1")
+		send(r, 1, "10.0.0.1")
+
+		// same user from another address is still limited
+		w := send(r, 1, "10.0.0.2")
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+
+		// another user behind the same address is not
+		w = send(r, 2, "10.0.0.1")
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("keys anonymous requests by client ip", func(t *testing.T) {
+		r := newRouter("tags", false)
+
+		send(r, 0, "10.0.0.1")
+		send(r, 0, "10.0.0.1")
+
+		w := send(r, 0, "10.0.0.1")
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+
+		w = send(r, 0, "10.0.0.2")
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("falls back to the default limit", func(t *testing.T) {
+		r := newRouter("plays", false)
+
+		w := send(r, 0, "10.0.0.1")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "100", w.Header().Get("RateLimit-Limit"))
+		assert.Equal(t, "99", w.Header().Get("RateLimit-Remaining"))
+	})
+}
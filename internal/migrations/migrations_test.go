@@ -0,0 +1,187 @@
+package migrations
+
+import (
+	"context"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name         string
+		fsys         fstest.MapFS
+		wantVersions []int
+		wantErr      bool
+	}{
+		{
+			name: "should order migrations by version",
+			fsys: fstest.MapFS{
+				"0002_add_index.up.sql":      {Data: []byte("CREATE INDEX ...")},
+				"0002_add_index.down.sql":    {Data: []byte("DROP INDEX ...")},
+				"0001_create_users.up.sql":   {Data: []byte("CREATE TABLE ...")},
+				"0001_create_users.down.sql": {Data: []byte("DROP TABLE ...")},
+			},
+			wantVersions: []int{1, 2},
+		},
+		{
+			name: "should fail without a down file",
+			fsys: fstest.MapFS{
+				"0001_create_users.up.sql": {Data: []byte("CREATE TABLE ...")},
+			},
+			wantErr: true,
+		},
+		{
+			name: "should fail on a version used twice",
+			fsys: fstest.MapFS{
+				"0001_create_users.up.sql":   {Data: []byte("CREATE TABLE ...")},
+				"0001_create_users.down.sql": {Data: []byte("DROP TABLE ...")},
+				"0001_create_tags.up.sql":    {Data: []byte("CREATE TABLE ...")},
+				"0001_create_tags.down.sql":  {Data: []byte("DROP TABLE ...")},
+			},
+			wantErr: true,
+		},
+		{
+			name: "should fail on unexpected files",
+			fsys: fstest.MapFS{
+				"create_users.sql": {Data: []byte("CREATE TABLE ...")},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Load(tt.fsys)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+
+			versions := make([]int, 0, len(got))
+			for _, migration := range got {
+				versions = append(versions, migration.Version)
+			}
+			assert.Equal(t, tt.wantVersions, versions)
+		})
+	}
+}
+
+func TestNew_EmbeddedMigrations(t *testing.T) {
+	m, err := New(nil)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, m.migrations)
+
+	for i, migration := range m.migrations {
+		assert.Equal(t, i+1, migration.Version, "versions must be contiguous")
+	}
+}
+
+func testMigrations() []Migration {
+	return []Migration{
+		{Version: 1, Name: "create_users", Up: "CREATE TABLE users ()", Down: "DROP TABLE users"},
+		{Version: 2, Name: "create_tags", Up: "CREATE TABLE tags ()", Down: "DROP TABLE tags"},
+	}
+}
+
+func expectApplied(mock sqlmock.Sqlmock, versions ...int) {
+	mock.ExpectExec(`CREATE TABLE IF NOT EXISTS schema_migrations`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	rows := sqlmock.NewRows([]string{"version", "applied_at"})
+	for _, version := range versions {
+		rows.AddRow(version, time.Now())
+	}
+	mock.ExpectQuery(`SELECT version, applied_at FROM schema_migrations`).WillReturnRows(rows)
+}
+
+func TestMigrator_Up(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	m := &Migrator{db: db, migrations: testMigrations()}
+
+	mock.ExpectExec(`SELECT pg_advisory_lock`).WithArgs(lockKey).WillReturnResult(sqlmock.NewResult(0, 0))
+	expectApplied(mock, 1)
+	mock.ExpectBegin()
+	mock.ExpectExec(`CREATE TABLE tags`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`INSERT INTO schema_migrations`).
+		WithArgs(2, "create_tags", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectExec(`SELECT pg_advisory_unlock`).WithArgs(lockKey).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	applied, err := m.Up(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, applied, 1)
+	assert.Equal(t, 2, applied[0].Version)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigrator_Up_RollsBackFailedMigration(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	m := &Migrator{db: db, migrations: testMigrations()}
+
+	mock.ExpectExec(`SELECT pg_advisory_lock`).WithArgs(lockKey).WillReturnResult(sqlmock.NewResult(0, 0))
+	expectApplied(mock)
+	mock.ExpectBegin()
+	mock.ExpectExec(`CREATE TABLE users`).WillReturnError(assert.AnError)
+	mock.ExpectRollback()
+	mock.ExpectExec(`SELECT pg_advisory_unlock`).WithArgs(lockKey).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	applied, err := m.Up(context.Background())
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.Empty(t, applied)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigrator_Down(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	m := &Migrator{db: db, migrations: testMigrations()}
+
+	mock.ExpectExec(`SELECT pg_advisory_lock`).WithArgs(lockKey).WillReturnResult(sqlmock.NewResult(0, 0))
+	expectApplied(mock, 1, 2)
+	mock.ExpectBegin()
+	mock.ExpectExec(`DROP TABLE tags`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`DELETE FROM schema_migrations WHERE version = \$1`).
+		WithArgs(2).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectExec(`SELECT pg_advisory_unlock`).WithArgs(lockKey).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	rolledBack, err := m.Down(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 2, rolledBack.Version)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigrator_Pending(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	m := &Migrator{db: db, migrations: testMigrations()}
+
+	expectApplied(mock, 1)
+
+	pending, err := m.Pending(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, pending, 1)
+	assert.Equal(t, "create_tags", pending[0].Name)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,13 @@
+-- baseline taken from the schema AutoMigrate created, IF NOT EXISTS lets
+-- databases it already created adopt the migration history as is
+CREATE TABLE IF NOT EXISTS users (
+    id         BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    deleted_at TIMESTAMPTZ,
+    email      TEXT NOT NULL UNIQUE,
+    username   TEXT NOT NULL UNIQUE,
+    password   TEXT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_users_deleted_at ON users (deleted_at);
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS track_activities (
+    id         BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    deleted_at TIMESTAMPTZ,
+    user_id    BIGINT NOT NULL,
+    spotify_id TEXT NOT NULL,
+    is_liked   BOOLEAN
+);
+
+CREATE INDEX IF NOT EXISTS idx_track_activities_deleted_at ON track_activities (deleted_at);
@@ -0,0 +1,24 @@
+CREATE TABLE playlists (
+    id          BIGSERIAL PRIMARY KEY,
+    created_at  TIMESTAMPTZ,
+    updated_at  TIMESTAMPTZ,
+    deleted_at  TIMESTAMPTZ,
+    user_id     BIGINT NOT NULL,
+    name        TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    visibility  TEXT NOT NULL DEFAULT 'private' CHECK (visibility IN ('private', 'public'))
+);
+
+CREATE INDEX idx_playlists_deleted_at ON playlists (deleted_at);
+CREATE INDEX idx_playlists_user_id ON playlists (user_id);
+
+CREATE TABLE playlist_tracks (
+    id          BIGSERIAL PRIMARY KEY,
+    playlist_id BIGINT NOT NULL REFERENCES playlists (id) ON DELETE CASCADE,
+    spotify_id  TEXT NOT NULL,
+    position    INTEGER NOT NULL,
+    created_at  TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX idx_playlist_tracks_playlist_spotify ON playlist_tracks (playlist_id, spotify_id);
+CREATE INDEX idx_playlist_tracks_playlist_position ON playlist_tracks (playlist_id, position);
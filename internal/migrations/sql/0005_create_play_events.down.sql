@@ -0,0 +1,2 @@
+DROP FUNCTION IF EXISTS ensure_play_events_partition(TIMESTAMPTZ);
+DROP TABLE IF EXISTS play_events;
@@ -0,0 +1,31 @@
+-- play events are partitioned by month of played_at, the partitions are
+-- created on demand through ensure_play_events_partition
+CREATE TABLE play_events (
+    id                 BIGSERIAL,
+    user_id            BIGINT NOT NULL,
+    spotify_id         TEXT NOT NULL,
+    played_at          TIMESTAMPTZ NOT NULL,
+    duration_played_ms INTEGER NOT NULL DEFAULT 0 CHECK (duration_played_ms >= 0),
+    client             TEXT NOT NULL DEFAULT '',
+    device             TEXT NOT NULL DEFAULT '',
+    created_at         TIMESTAMPTZ,
+    PRIMARY KEY (id, played_at)
+) PARTITION BY RANGE (played_at);
+
+CREATE INDEX idx_play_events_user_played_at ON play_events (user_id, played_at DESC, id DESC);
+
+CREATE FUNCTION ensure_play_events_partition(event_at TIMESTAMPTZ) RETURNS void AS $$
+DECLARE
+    month_start TIMESTAMPTZ := date_trunc('month', event_at, 'UTC');
+BEGIN
+    EXECUTE format(
+        'CREATE TABLE IF NOT EXISTS %I PARTITION OF play_events FOR VALUES FROM (%L) TO (%L)',
+        'play_events_' || to_char(month_start AT TIME ZONE 'UTC', 'YYYY_MM'),
+        month_start,
+        month_start + INTERVAL '1 month'
+    );
+EXCEPTION
+    -- another session created the partition first
+    WHEN duplicate_table OR unique_violation THEN NULL;
+END;
+$$ LANGUAGE plpgsql;
@@ -0,0 +1,3 @@
+ALTER TABLE track_activities
+    DROP COLUMN IF EXISTS note,
+    DROP COLUMN IF EXISTS rating;
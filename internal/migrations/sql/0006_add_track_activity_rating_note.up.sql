@@ -0,0 +1,3 @@
+ALTER TABLE track_activities
+    ADD COLUMN IF NOT EXISTS rating SMALLINT CHECK (rating BETWEEN 1 AND 5),
+    ADD COLUMN IF NOT EXISTS note   TEXT;
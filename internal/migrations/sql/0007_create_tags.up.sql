@@ -0,0 +1,18 @@
+CREATE TABLE tags (
+    id         BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    user_id    BIGINT NOT NULL,
+    name       TEXT NOT NULL
+);
+
+CREATE UNIQUE INDEX idx_tags_user_name ON tags (user_id, name);
+
+CREATE TABLE track_tags (
+    tag_id     BIGINT NOT NULL REFERENCES tags (id) ON DELETE CASCADE,
+    spotify_id TEXT NOT NULL,
+    created_at TIMESTAMPTZ,
+    PRIMARY KEY (tag_id, spotify_id)
+);
+
+CREATE INDEX idx_track_tags_spotify_id ON track_tags (spotify_id);
@@ -0,0 +1,37 @@
+CREATE TABLE import_jobs (
+    id          BIGSERIAL PRIMARY KEY,
+    created_at  TIMESTAMPTZ,
+    updated_at  TIMESTAMPTZ,
+    finished_at TIMESTAMPTZ,
+    user_id     BIGINT NOT NULL,
+    status      TEXT NOT NULL DEFAULT 'pending',
+    target      TEXT NOT NULL,
+    format      TEXT NOT NULL,
+    filename    TEXT NOT NULL DEFAULT '',
+    playlist_id BIGINT,
+    total       INTEGER NOT NULL DEFAULT 0,
+    processed   INTEGER NOT NULL DEFAULT 0,
+    matched     INTEGER NOT NULL DEFAULT 0,
+    ambiguous   INTEGER NOT NULL DEFAULT 0,
+    unmatched   INTEGER NOT NULL DEFAULT 0,
+    error       TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX idx_import_jobs_user_id ON import_jobs (user_id);
+CREATE INDEX idx_import_jobs_status ON import_jobs (status);
+
+CREATE TABLE import_job_rows (
+    id         BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ,
+    job_id     BIGINT NOT NULL REFERENCES import_jobs (id) ON DELETE CASCADE,
+    row_number INTEGER NOT NULL,
+    name       TEXT NOT NULL DEFAULT '',
+    artists    TEXT NOT NULL DEFAULT '',
+    album      TEXT NOT NULL DEFAULT '',
+    isrc       TEXT NOT NULL DEFAULT '',
+    status     TEXT NOT NULL,
+    spotify_id TEXT NOT NULL DEFAULT '',
+    candidates TEXT NOT NULL DEFAULT ''
+);
+
+CREATE UNIQUE INDEX idx_import_job_rows_job_row ON import_job_rows (job_id, row_number);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS spotify_auth_states;
+DROP TABLE IF EXISTS spotify_accounts;
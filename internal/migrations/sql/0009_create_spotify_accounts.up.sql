@@ -0,0 +1,25 @@
+CREATE TABLE spotify_accounts (
+    id              BIGSERIAL PRIMARY KEY,
+    created_at      TIMESTAMPTZ,
+    updated_at      TIMESTAMPTZ,
+    user_id         BIGINT NOT NULL,
+    spotify_user_id TEXT NOT NULL,
+    display_name    TEXT NOT NULL DEFAULT '',
+    access_token    TEXT NOT NULL,
+    refresh_token   TEXT NOT NULL,
+    token_type      TEXT NOT NULL,
+    scope           TEXT NOT NULL DEFAULT '',
+    expires_at      TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX idx_spotify_accounts_user_id ON spotify_accounts (user_id);
+
+CREATE TABLE spotify_auth_states (
+    state         TEXT PRIMARY KEY,
+    created_at    TIMESTAMPTZ,
+    user_id       BIGINT NOT NULL,
+    code_verifier TEXT NOT NULL,
+    expires_at    TIMESTAMPTZ
+);
+
+CREATE INDEX idx_spotify_auth_states_expires_at ON spotify_auth_states (expires_at);
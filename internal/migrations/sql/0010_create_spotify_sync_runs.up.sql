@@ -0,0 +1,19 @@
+CREATE TABLE spotify_sync_runs (
+    id             BIGSERIAL PRIMARY KEY,
+    created_at     TIMESTAMPTZ,
+    updated_at     TIMESTAMPTZ,
+    finished_at    TIMESTAMPTZ,
+    user_id        BIGINT NOT NULL,
+    trigger        TEXT NOT NULL,
+    status         TEXT NOT NULL DEFAULT 'running',
+    remote_tracks  INTEGER NOT NULL DEFAULT 0,
+    local_tracks   INTEGER NOT NULL DEFAULT 0,
+    liked_local    INTEGER NOT NULL DEFAULT 0,
+    unliked_local  INTEGER NOT NULL DEFAULT 0,
+    saved_remote   INTEGER NOT NULL DEFAULT 0,
+    removed_remote INTEGER NOT NULL DEFAULT 0,
+    error          TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX idx_spotify_sync_runs_user_id ON spotify_sync_runs (user_id, finished_at);
+CREATE INDEX idx_spotify_sync_runs_status ON spotify_sync_runs (status);
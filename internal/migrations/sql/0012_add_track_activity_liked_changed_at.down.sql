@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_track_activities_user_liked;
+
+ALTER TABLE track_activities
+    DROP COLUMN IF EXISTS liked_changed_at;
@@ -0,0 +1,10 @@
+ALTER TABLE track_activities
+    ADD COLUMN IF NOT EXISTS liked_changed_at TIMESTAMPTZ;
+
+-- updated_at is the best guess there is for likes set before the column
+UPDATE track_activities
+SET liked_changed_at = updated_at
+WHERE is_liked IS NOT NULL AND liked_changed_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_track_activities_user_liked
+    ON track_activities (user_id, is_liked, liked_changed_at);
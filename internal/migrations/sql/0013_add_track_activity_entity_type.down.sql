@@ -0,0 +1,2 @@
+ALTER TABLE track_activities
+    DROP COLUMN IF EXISTS entity_type;
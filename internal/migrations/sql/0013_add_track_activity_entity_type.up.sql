@@ -0,0 +1,5 @@
+-- albums can be liked as well, rows written before the column are assumed
+-- to be tracks
+ALTER TABLE track_activities
+    ADD COLUMN IF NOT EXISTS entity_type TEXT NOT NULL DEFAULT 'track'
+        CHECK (entity_type IN ('track', 'album'));
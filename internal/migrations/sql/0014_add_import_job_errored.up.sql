@@ -0,0 +1,3 @@
+-- rows spotify couldn't be searched for are reported instead of failing the job
+ALTER TABLE import_jobs
+    ADD COLUMN IF NOT EXISTS errored INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,4 @@
+-- jobs run inside an api replica, a job whose heartbeat stopped was lost
+-- with its replica and is failed by the others
+ALTER TABLE import_jobs
+    ADD COLUMN IF NOT EXISTS heartbeat_at TIMESTAMPTZ;
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_spotify_sync_runs_user_running;
+
+ALTER TABLE spotify_sync_runs
+    DROP COLUMN IF EXISTS heartbeat_at;
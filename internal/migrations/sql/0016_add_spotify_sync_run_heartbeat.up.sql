@@ -0,0 +1,17 @@
+-- syncs run inside an api replica, a run whose heartbeat stopped was lost
+-- with its replica and is failed by the others
+ALTER TABLE spotify_sync_runs
+    ADD COLUMN IF NOT EXISTS heartbeat_at TIMESTAMPTZ;
+
+-- a user is synced by one replica at a time, only the latest of runs left
+-- running side by side is kept for the index
+UPDATE spotify_sync_runs
+SET status = 'failed',
+    error = 'the sync was interrupted, it runs again with the next scheduled sync',
+    finished_at = NOW(),
+    updated_at = NOW()
+WHERE status = 'running'
+  AND id NOT IN (SELECT MAX(id) FROM spotify_sync_runs WHERE status = 'running' GROUP BY user_id);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_spotify_sync_runs_user_running
+    ON spotify_sync_runs (user_id) WHERE status = 'running';
@@ -0,0 +1,2 @@
+ALTER TABLE spotify_sync_runs
+    DROP COLUMN IF EXISTS snapshot_at;
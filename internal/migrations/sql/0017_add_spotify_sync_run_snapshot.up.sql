@@ -0,0 +1,8 @@
+-- later runs date changes against the moment a run read both libraries.
+-- runs recorded before the column read them right after they were created
+ALTER TABLE spotify_sync_runs
+    ADD COLUMN IF NOT EXISTS snapshot_at TIMESTAMPTZ;
+
+UPDATE spotify_sync_runs
+SET snapshot_at = created_at
+WHERE snapshot_at IS NULL;
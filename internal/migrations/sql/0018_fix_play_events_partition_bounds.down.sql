@@ -0,0 +1,15 @@
+CREATE OR REPLACE FUNCTION ensure_play_events_partition(event_at TIMESTAMPTZ) RETURNS void AS $$
+DECLARE
+    month_start TIMESTAMPTZ := date_trunc('month', event_at, 'UTC');
+BEGIN
+    EXECUTE format(
+        'CREATE TABLE IF NOT EXISTS %I PARTITION OF play_events FOR VALUES FROM (%L) TO (%L)',
+        'play_events_' || to_char(month_start AT TIME ZONE 'UTC', 'YYYY_MM'),
+        month_start,
+        month_start + INTERVAL '1 month'
+    );
+EXCEPTION
+    -- another session created the partition first
+    WHEN duplicate_table OR unique_violation THEN NULL;
+END;
+$$ LANGUAGE plpgsql;
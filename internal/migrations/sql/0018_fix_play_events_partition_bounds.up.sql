@@ -0,0 +1,18 @@
+-- the bounds are computed in utc, adding a month in the session time zone
+-- drifted by an hour across daylight saving time and left gaps or overlaps
+-- between the partitions
+CREATE OR REPLACE FUNCTION ensure_play_events_partition(event_at TIMESTAMPTZ) RETURNS void AS $$
+DECLARE
+    month_start TIMESTAMP := date_trunc('month', event_at AT TIME ZONE 'UTC');
+BEGIN
+    EXECUTE format(
+        'CREATE TABLE IF NOT EXISTS %I PARTITION OF play_events FOR VALUES FROM (%L) TO (%L)',
+        'play_events_' || to_char(month_start, 'YYYY_MM'),
+        month_start AT TIME ZONE 'UTC',
+        (month_start + INTERVAL '1 month') AT TIME ZONE 'UTC'
+    );
+EXCEPTION
+    -- another session created the partition first
+    WHEN duplicate_table OR unique_violation THEN NULL;
+END;
+$$ LANGUAGE plpgsql;
@@ -0,0 +1,10 @@
+-- no route was ever guarded for curators, the role granted nothing
+UPDATE users
+SET role = 'user'
+WHERE role = 'curator';
+
+ALTER TABLE users
+    DROP CONSTRAINT IF EXISTS users_role_check;
+
+ALTER TABLE users
+    ADD CONSTRAINT users_role_check CHECK (role IN ('user', 'admin'));
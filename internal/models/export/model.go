@@ -0,0 +1,10 @@
+package export
+
+type (
+	// ExportRequest exports the liked tracks, or the playlist when
+	// PlaylistID is set.
+	ExportRequest struct {
+		Format     string `form:"format" binding:"required,oneof=csv json m3u8 xspf"`
+		PlaylistID uint   `form:"playlist_id"`
+	}
+)
@@ -0,0 +1,120 @@
+package importjob
+
+import "time"
+
+const (
+	StatusPending   = "pending"
+	StatusRunning   = "running"
+	StatusCompleted = "completed"
+	StatusFailed    = "failed"
+
+	TargetLikes    = "likes"
+	TargetPlaylist = "playlist"
+
+	RowMatched   = "matched"
+	RowAmbiguous = "ambiguous"
+	RowUnmatched = "unmatched"
+	// spotify couldn't be searched for the entry, it was rate limiting or down
+	RowError = "error"
+)
+
+type (
+	// ImportJob recreates the likes or a playlist of a user from an uploaded
+	// file. The counters grow while the job runs so clients can poll it.
+	ImportJob struct {
+		ID         uint   `gorm:"primarykey"`
+		UserID     uint   `gorm:"not null;index"`
+		Status     string `gorm:"not null;default:pending"`
+		Target     string `gorm:"not null"`
+		Format     string `gorm:"not null"`
+		Filename   string `gorm:"not null;default:''"`
+		PlaylistID *uint
+		Total      int    `gorm:"not null;default:0"`
+		Processed  int    `gorm:"not null;default:0"`
+		Matched    int    `gorm:"not null;default:0"`
+		Ambiguous  int    `gorm:"not null;default:0"`
+		Unmatched  int    `gorm:"not null;default:0"`
+		Errored    int    `gorm:"not null;default:0"`
+		Error      string `gorm:"not null;default:''"`
+		// refreshed while a process works on the job, a stale one was lost
+		// with its replica
+		HeartbeatAt *time.Time
+		CreatedAt   time.Time
+		UpdatedAt   time.Time
+		FinishedAt  *time.Time
+	}
+
+	// ImportJobRow is the outcome for one entry of the file. Artists are
+	// joined with "; ", Candidates holds comma separated spotify ids of an
+	// ambiguous entry.
+	ImportJobRow struct {
+		ID         uint   `gorm:"primarykey"`
+		JobID      uint   `gorm:"not null"`
+		RowNumber  int    `gorm:"not null"`
+		Name       string `gorm:"not null;default:''"`
+		Artists    string `gorm:"not null;default:''"`
+		Album      string `gorm:"not null;default:''"`
+		ISRC       string `gorm:"column:isrc;not null;default:''"`
+		Status     string `gorm:"not null"`
+		SpotifyID  string `gorm:"not null;default:''"`
+		Candidates string `gorm:"not null;default:''"`
+		CreatedAt  time.Time
+	}
+)
+
+// requests
+type (
+	// CreateImportRequest comes with the file as multipart form. Format is
+	// taken from the file extension when it is not sent, Name names the
+	// playlist and defaults to the title in the file.
+	CreateImportRequest struct {
+		Target string `form:"target" binding:"required,oneof=likes playlist"`
+		Format string `form:"format" binding:"omitempty,oneof=csv json m3u m3u8 xspf"`
+		Name   string `form:"name" binding:"max=100"`
+	}
+
+	ListImportRowsRequest struct {
+		Status    string `form:"status" binding:"omitempty,oneof=matched ambiguous unmatched error"`
+		PageIndex int    `form:"pageIndex"`
+		PageSize  int    `form:"pageSize"`
+	}
+)
+
+// responses
+type (
+	ImportJobResponse struct {
+		ID         uint       `json:"id"`
+		Status     string     `json:"status"`
+		Target     string     `json:"target"`
+		Format     string     `json:"format"`
+		Filename   string     `json:"filename"`
+		PlaylistID *uint      `json:"playlist_id,omitempty"`
+		Total      int        `json:"total"`
+		Processed  int        `json:"processed"`
+		Matched    int        `json:"matched"`
+		Ambiguous  int        `json:"ambiguous"`
+		Unmatched  int        `json:"unmatched"`
+		Errored    int        `json:"errored"`
+		Error      string     `json:"error,omitempty"`
+		CreatedAt  time.Time  `json:"created_at"`
+		FinishedAt *time.Time `json:"finished_at,omitempty"`
+	}
+
+	ImportRowResponse struct {
+		RowNumber  int      `json:"row_number"`
+		Name       string   `json:"name"`
+		Artists    []string `json:"artists"`
+		Album      string   `json:"album,omitempty"`
+		ISRC       string   `json:"isrc,omitempty"`
+		Status     string   `json:"status"`
+		SpotifyID  string   `json:"spotify_id,omitempty"`
+		Candidates []string `json:"candidates,omitempty"`
+	}
+
+	ListImportRowsResponse struct {
+		Items  []ImportRowResponse `json:"items"`
+		Limit  int                 `json:"limit"`
+		Offset int                 `json:"offset"`
+		Total  int                 `json:"total"`
+	}
+)
@@ -0,0 +1,70 @@
+package play
+
+import (
+	"time"
+
+	"github.com/sgitwhyd/music-catalogue/internal/models/spotify"
+)
+
+type (
+	// PlayEvent is one listen of a spotify track. The table is partitioned
+	// by month of PlayedAt, which is why it is part of the primary key.
+	PlayEvent struct {
+		ID               uint      `gorm:"primaryKey;autoIncrement"`
+		UserID           uint      `gorm:"not null"`
+		SpotifyID        string    `gorm:"not null"`
+		PlayedAt         time.Time `gorm:"primaryKey"`
+		DurationPlayedMs int       `gorm:"not null;default:0"`
+		Client           string    `gorm:"not null;default:''"`
+		Device           string    `gorm:"not null;default:''"`
+		CreatedAt        time.Time
+	}
+
+	// Cursor points at the last play of a page, the next page starts right
+	// after it.
+	Cursor struct {
+		PlayedAt time.Time
+		ID       uint
+	}
+)
+
+// requests
+type (
+	// CreatePlayRequest records a play, PlayedAt defaults to now.
+	CreatePlayRequest struct {
+		SpotifyID        string     `json:"spotify_id" binding:"required"`
+		PlayedAt         *time.Time `json:"played_at"`
+		DurationPlayedMs int        `json:"duration_played_ms" binding:"min=0"`
+		Client           string     `json:"client" binding:"max=100"`
+		Device           string     `json:"device" binding:"max=100"`
+	}
+
+	RecentPlaysRequest struct {
+		Limit  int    `form:"limit"`
+		Cursor string `form:"cursor"`
+	}
+)
+
+// responses
+type (
+	PlayResponse struct {
+		ID               uint      `json:"id"`
+		SpotifyID        string    `json:"spotify_id"`
+		PlayedAt         time.Time `json:"played_at"`
+		DurationPlayedMs int       `json:"duration_played_ms"`
+		Client           string    `json:"client"`
+		Device           string    `json:"device"`
+	}
+
+	RecentPlayResponse struct {
+		PlayResponse
+		// nil when spotify no longer has the track
+		Track *spotify.SpotifyTrackObjectResponse `json:"track"`
+	}
+
+	RecentPlaysResponse struct {
+		Items []RecentPlayResponse `json:"items"`
+		// empty on the last page
+		NextCursor string `json:"next_cursor,omitempty"`
+	}
+)
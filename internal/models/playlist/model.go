@@ -0,0 +1,102 @@
+package playlist
+
+import (
+	"time"
+
+	"github.com/sgitwhyd/music-catalogue/internal/models/spotify"
+	"gorm.io/gorm"
+)
+
+const (
+	VisibilityPrivate = "private"
+	VisibilityPublic  = "public"
+)
+
+type (
+	Playlist struct {
+		gorm.Model
+		UserID      uint   `gorm:"not null;index"`
+		Name        string `gorm:"not null"`
+		Description string `gorm:"not null;default:''"`
+		Visibility  string `gorm:"not null;default:private"`
+	}
+
+	// PlaylistTrack places a spotify track in a playlist. Positions are
+	// zero-based and contiguous, a track appears at most once per playlist.
+	PlaylistTrack struct {
+		ID         uint      `gorm:"primarykey"`
+		PlaylistID uint      `gorm:"not null;uniqueIndex:idx_playlist_tracks_playlist_spotify"`
+		SpotifyID  string    `gorm:"not null;uniqueIndex:idx_playlist_tracks_playlist_spotify"`
+		Position   int       `gorm:"not null"`
+		CreatedAt  time.Time
+	}
+)
+
+// requests
+type (
+	CreatePlaylistRequest struct {
+		Name        string `json:"name" binding:"required,max=100"`
+		Description string `json:"description" binding:"max=300"`
+		Visibility  string `json:"visibility" binding:"omitempty,oneof=private public"`
+	}
+
+	// UpdatePlaylistRequest only changes the fields that are sent.
+	UpdatePlaylistRequest struct {
+		Name        *string `json:"name" binding:"omitempty,min=1,max=100"`
+		Description *string `json:"description" binding:"omitempty,max=300"`
+		Visibility  *string `json:"visibility" binding:"omitempty,oneof=private public"`
+	}
+
+	ListPlaylistRequest struct {
+		PageIndex int `form:"pageIndex"`
+		PageSize  int `form:"pageSize"`
+	}
+
+	// AddTracksRequest inserts the tracks at Position, or appends them when
+	// it is not sent.
+	AddTracksRequest struct {
+		SpotifyIDs []string `json:"spotify_ids" binding:"required,min=1,max=100,dive,required"`
+		Position   *int     `json:"position" binding:"omitempty,min=0"`
+	}
+
+	// ReorderTracksRequest moves RangeLength tracks starting at RangeStart
+	// so they are placed before the track at InsertBefore, the same way the
+	// spotify playlist api does.
+	ReorderTracksRequest struct {
+		RangeStart   *int `json:"range_start" binding:"required,min=0"`
+		InsertBefore *int `json:"insert_before" binding:"required,min=0"`
+		RangeLength  int  `json:"range_length" binding:"omitempty,min=1"`
+	}
+)
+
+// responses
+type (
+	PlaylistResponse struct {
+		ID          uint      `json:"id"`
+		OwnerID     uint      `json:"owner_id"`
+		Name        string    `json:"name"`
+		Description string    `json:"description"`
+		Visibility  string    `json:"visibility"`
+		TrackCount  int       `json:"track_count"`
+		CreatedAt   time.Time `json:"created_at"`
+		UpdatedAt   time.Time `json:"updated_at"`
+	}
+
+	PlaylistDetailResponse struct {
+		PlaylistResponse
+		Tracks []PlaylistTrackResponse `json:"tracks"`
+	}
+
+	PlaylistTrackResponse struct {
+		spotify.SpotifyTrackObjectResponse
+		Position int       `json:"position"`
+		AddedAt  time.Time `json:"added_at"`
+	}
+
+	ListPlaylistResponse struct {
+		Items  []PlaylistResponse `json:"items"`
+		Limit  int                `json:"limit"`
+		Offset int                `json:"offset"`
+		Total  int                `json:"total"`
+	}
+)
@@ -1,13 +1,39 @@
 package spotify
 
-import "gorm.io/gorm"
+import (
+	"encoding/json"
+	"time"
+
+	"gorm.io/gorm"
+)
 
 type (
+	SearchRequest struct {
+		Query     string
+		PageSize  int
+		PageIndex int
+		Market    string
+		// track, album, artist or playlist, tracks only when empty
+		Types []string
+	}
+
+	// SearchResponse has a section for every searched type, the others are
+	// left out.
 	SearchResponse struct {
-		Items  []SpotifyTrackObjectResponse `json:"items"`
-		Limit  int                          `json:"limit"`
-		Offset int                          `json:"offset"`
-		Total  int                          `json:"total"`
+		Tracks    *Page[SpotifyTrackObjectResponse]    `json:"tracks,omitempty"`
+		Albums    *Page[SpotifyAlbumObjectResponse]    `json:"albums,omitempty"`
+		Artists   *Page[SpotifyArtistObjectResponse]   `json:"artists,omitempty"`
+		Playlists *Page[SpotifyPlaylistObjectResponse] `json:"playlists,omitempty"`
+
+		CacheStatus string `json:"-"`
+	}
+
+	// Page is one page of a spotify paging object.
+	Page[T any] struct {
+		Items  []T `json:"items"`
+		Limit  int `json:"limit"`
+		Offset int `json:"offset"`
+		Total  int `json:"total"`
 	}
 
 	SpotifyTrackObjectResponse struct {
@@ -16,9 +42,11 @@ type (
 		AlbumTotalTracks int      `json:"album_total_tracks"`
 		AlbumImagesURL   []string `json:"album_image_url"`
 		AlbumName        string   `json:"album_name"`
+		AlbumID          string   `json:"album_id"`
 
 		// artis related fields
 		ArtistsName []string `json:"artists_name"`
+		ArtistsID   []string `json:"artists_id"`
 
 		// track related field
 		Explicit bool   `json:"explicit"`
@@ -26,21 +54,257 @@ type (
 		ID       string `json:"id"`
 		Name     string `json:"name"`
 		IsLiked  *bool	`json:"is_liked"`
+		Rating   *int    `json:"rating"`
+		Note     *string `json:"note"`
+		// the user's tags, only loaded for search and library results
+		Tags []string `json:"tags,omitempty"`
+
+		DiscNumber  int `json:"disc_number"`
+		TrackNumber int `json:"track_number"`
+		DurationMs  int `json:"duration_ms"`
+	}
+
+	SpotifyAlbumObjectResponse struct {
+		AlbumType   string   `json:"album_type"`
+		TotalTracks int      `json:"total_tracks"`
+		ImagesURL   []string `json:"image_url"`
+		ArtistsName []string `json:"artists_name"`
+		ArtistsID   []string `json:"artists_id"`
+		ReleaseDate string   `json:"release_date"`
+		// year, month or day
+		ReleaseDatePrecision string `json:"release_date_precision"`
+		AlbumGroup           string `json:"album_group,omitempty"`
+		Href                 string `json:"href"`
+		ID                   string `json:"id"`
+		Name                 string `json:"name"`
+		IsLiked              *bool   `json:"is_liked"`
+		Rating               *int    `json:"rating"`
+		Note                 *string `json:"note"`
+	}
+
+	// AlbumResponse is an album with the first page of its tracks.
+	AlbumResponse struct {
+		SpotifyAlbumObjectResponse
+		Label      string                           `json:"label"`
+		Popularity int                              `json:"popularity"`
+		Copyrights []AlbumCopyrightResponse         `json:"copyrights"`
+		Tracks     Page[SpotifyTrackObjectResponse] `json:"tracks"`
+		Completion AlbumCompletionResponse          `json:"completion"`
+	}
+
+	AlbumCopyrightResponse struct {
+		Text string `json:"text"`
+		Type string `json:"type"`
+	}
+
+	// AlbumCompletionResponse counts how many of the album's tracks the user
+	// has liked.
+	AlbumCompletionResponse struct {
+		LikedTracks int `json:"liked_tracks"`
+		TotalTracks int `json:"total_tracks"`
+	}
+
+	AlbumTracksRequest struct {
+		PageIndex int    `form:"pageIndex"`
+		PageSize  int    `form:"pageSize"`
+		Market    string `form:"market"`
+	}
+
+	AlbumTracksResponse struct {
+		Page[SpotifyTrackObjectResponse]
+		Completion AlbumCompletionResponse `json:"completion"`
+	}
+
+	SpotifyArtistObjectResponse struct {
+		Genres     []string `json:"genres"`
+		ImagesURL  []string `json:"image_url"`
+		Followers  int      `json:"followers"`
+		Popularity int      `json:"popularity"`
+		Href       string   `json:"href"`
+		ID         string   `json:"id"`
+		Name       string   `json:"name"`
+	}
+
+	SpotifyPlaylistObjectResponse struct {
+		Description string   `json:"description"`
+		ImagesURL   []string `json:"image_url"`
+		OwnerName   string   `json:"owner_name"`
+		TotalTracks int      `json:"total_tracks"`
+		Href        string   `json:"href"`
+		ID          string   `json:"id"`
+		Name        string   `json:"name"`
+	}
+
+	ArtistTopTracksResponse struct {
+		Items []SpotifyTrackObjectResponse `json:"items"`
+	}
+
+	ArtistAlbumsRequest struct {
+		PageIndex int    `form:"pageIndex"`
+		PageSize  int    `form:"pageSize"`
+		Market    string `form:"market"`
+		// album, single, appears_on or compilation, every group when empty
+		IncludeGroups []string `form:"-"`
+	}
+
+	RecommendationsRequest struct {
+		Limit  int    `form:"limit"`
+		Market string `form:"market"`
+		// explicit seeds, the remaining slots are filled from the user's
+		// latest likes
+		SeedTracks  []string `form:"-"`
+		SeedArtists []string `form:"-"`
+		SeedGenres  []string `form:"-"`
+		// min_, max_ and target_ prefixed track attributes, e.g. target_energy
+		Attributes map[string]string `form:"-"`
+	}
+
+	RecommendationsResponse struct {
+		Seeds []RecommendationSeedResponse `json:"seeds"`
+		Items []SpotifyTrackObjectResponse `json:"items"`
+	}
+
+	RecommendationSeedResponse struct {
+		ID string `json:"id"`
+		// track, artist or genre
+		Type string `json:"type"`
+	}
+
+	TrackLookupRequest struct {
+		SpotifyIDs []string `json:"spotify_ids" binding:"required,min=1,max=500,dive,required"`
+	}
+
+	TrackLookupResponse struct {
+		Items []SpotifyTrackObjectResponse `json:"items"`
 	}
 )
 
 // track activities
 
+// what a track activity's SpotifyID refers to
+const (
+	EntityTypeTrack = "track"
+	EntityTypeAlbum = "album"
+)
+
 type (
 	TrackActivity struct {
 		gorm.Model
 		UserID 		uint `gorm:"not null"`
 		SpotifyID string `gorm:"not null"`
+		// EntityTypeTrack or EntityTypeAlbum, the library, recommendation
+		// seeds, export and sync only look at tracks
+		EntityType string `gorm:"not null;default:track"`
 		IsLiked 	*bool
+		// when IsLiked last changed, rating and note edits leave it alone
+		LikedChangedAt *time.Time
+		// 1 to 5, nil when unrated
+		Rating *int
+		Note   *string
 	}
 
 	TrackActivityRequest struct {
 		SpotifyID string `json:"spotify_id" binding:"required"`
+		// track or album, track when empty
+		Type string `json:"type" binding:"omitempty,oneof=track album"`
 		IsLiked *bool `json:"is_liked"`
 	}
-)
\ No newline at end of file
+
+	// UpdateActivityRequest changes only the fields present in the body, a
+	// field sent as null is cleared.
+	UpdateActivityRequest struct {
+		// track or album, only used when the activity doesn't exist yet
+		Type    string           `json:"type" binding:"omitempty,oneof=track album"`
+		IsLiked Optional[bool]   `json:"is_liked"`
+		Rating  Optional[int]    `json:"rating"`
+		Note    Optional[string] `json:"note"`
+	}
+
+	ActivityResponse struct {
+		SpotifyID string  `json:"spotify_id"`
+		IsLiked   *bool   `json:"is_liked"`
+		Rating    *int    `json:"rating"`
+		Note      *string `json:"note"`
+	}
+)
+
+// SetLiked changes the like state and dates the change. Setting the state
+// it already has keeps the old date.
+func (a *TrackActivity) SetLiked(isLiked *bool, at time.Time) {
+	if a.IsLiked == nil && isLiked == nil {
+		return
+	}
+	if a.IsLiked != nil && isLiked != nil && *a.IsLiked == *isLiked {
+		return
+	}
+
+	a.IsLiked = isLiked
+	a.LikedChangedAt = &at
+}
+
+// Optional tells a JSON field that was left out apart from one sent as null:
+// Set is false when the field was left out, Value is nil when it was null.
+type Optional[T any] struct {
+	Set   bool
+	Value *T
+}
+
+func (o *Optional[T]) UnmarshalJSON(data []byte) error {
+	o.Set = true
+	if string(data) == "null" {
+		o.Value = nil
+		return nil
+	}
+
+	var value T
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	o.Value = &value
+	return nil
+}
+
+func (o Optional[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(o.Value)
+}
+
+// library
+type (
+	LibraryRequest struct {
+		PageIndex int        `form:"pageIndex"`
+		PageSize  int        `form:"pageSize"`
+		Sort      string     `form:"sort" binding:"omitempty,oneof=newest oldest"`
+		From      *time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+		To        *time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+		MinRating int        `form:"minRating" binding:"omitempty,min=1,max=5"`
+		Tag       string     `form:"tag"`
+	}
+
+	// ActivityFilter narrows the track activities a user's library is built from.
+	ActivityFilter struct {
+		IsLiked bool
+		From    *time.Time
+		To      *time.Time
+		// only activities rated at least this much, 0 for any
+		MinRating int
+		// only tracks carrying the tag with this name, empty for any
+		Tag     string
+		SortAsc bool
+		Limit   int
+		Offset  int
+	}
+
+	LibraryResponse struct {
+		Items  []LibraryItemResponse `json:"items"`
+		Limit  int                   `json:"limit"`
+		Offset int                   `json:"offset"`
+		Total  int                   `json:"total"`
+	}
+
+	LibraryItemResponse struct {
+		SpotifyTrackObjectResponse
+		// when the track was liked or disliked
+		ActivityAt time.Time `json:"activity_at"`
+	}
+)
@@ -0,0 +1,60 @@
+package spotifyaccount
+
+import "time"
+
+type (
+	// SpotifyAccount is the spotify account a user linked. AccessToken and
+	// RefreshToken are stored sealed with secretbox, never in the clear.
+	SpotifyAccount struct {
+		ID            uint   `gorm:"primarykey"`
+		UserID        uint   `gorm:"not null;uniqueIndex"`
+		SpotifyUserID string `gorm:"not null"`
+		DisplayName   string `gorm:"not null;default:''"`
+		AccessToken   string `gorm:"not null"`
+		RefreshToken  string `gorm:"not null"`
+		TokenType     string `gorm:"not null"`
+		Scope         string `gorm:"not null;default:''"`
+		ExpiresAt     time.Time
+		CreatedAt     time.Time
+		UpdatedAt     time.Time
+	}
+
+	// SpotifyAuthState is an authorization request waiting for its callback.
+	// The state is used once and only by the user that started the request,
+	// a state leaked to someone else can't link their account to the user.
+	SpotifyAuthState struct {
+		State        string `gorm:"primaryKey"`
+		UserID       uint   `gorm:"not null"`
+		CodeVerifier string `gorm:"not null"`
+		ExpiresAt    time.Time
+		CreatedAt    time.Time
+	}
+)
+
+// requests
+type (
+	// CallbackRequest is what spotify appends to the redirect url, the
+	// client posts it on with the jwt of the user. Error is set instead of
+	// Code when the user didn't grant access.
+	CallbackRequest struct {
+		Code  string `json:"code"`
+		State string `json:"state"`
+		Error string `json:"error"`
+	}
+)
+
+// responses
+type (
+	ConnectResponse struct {
+		AuthorizeURL string    `json:"authorize_url"`
+		ExpiresAt    time.Time `json:"expires_at"`
+	}
+
+	ConnectionResponse struct {
+		Connected     bool       `json:"connected"`
+		SpotifyUserID string     `json:"spotify_user_id,omitempty"`
+		DisplayName   string     `json:"display_name,omitempty"`
+		Scopes        []string   `json:"scopes,omitempty"`
+		ConnectedAt   *time.Time `json:"connected_at,omitempty"`
+	}
+)
@@ -0,0 +1,72 @@
+package spotifysync
+
+import "time"
+
+const (
+	StatusRunning   = "running"
+	StatusCompleted = "completed"
+	StatusFailed    = "failed"
+
+	TriggerManual    = "manual"
+	TriggerScheduled = "scheduled"
+)
+
+type (
+	// SpotifySyncRun is the report of one sync between the likes of a user and the
+	// "Liked Songs" of their linked spotify account. When the last completed
+	// run read both libraries is what later runs date changes against.
+	SpotifySyncRun struct {
+		ID            uint   `gorm:"primarykey"`
+		UserID        uint   `gorm:"not null;index"`
+		Trigger       string `gorm:"not null"`
+		Status        string `gorm:"not null;default:running"`
+		RemoteTracks  int    `gorm:"not null;default:0"`
+		LocalTracks   int    `gorm:"not null;default:0"`
+		LikedLocal    int    `gorm:"not null;default:0"`
+		UnlikedLocal  int    `gorm:"not null;default:0"`
+		SavedRemote   int    `gorm:"not null;default:0"`
+		RemovedRemote int    `gorm:"not null;default:0"`
+		Error         string `gorm:"not null;default:''"`
+		// refreshed while a replica works on the run, a stale one was lost
+		// with its replica
+		HeartbeatAt *time.Time
+		SnapshotAt  *time.Time
+		CreatedAt   time.Time
+		UpdatedAt   time.Time
+		FinishedAt  *time.Time
+	}
+)
+
+// requests
+type (
+	ListSyncRunsRequest struct {
+		PageIndex int `form:"pageIndex"`
+		PageSize  int `form:"pageSize"`
+	}
+)
+
+// responses
+type (
+	SyncRunResponse struct {
+		ID      uint   `json:"id"`
+		Trigger string `json:"trigger"`
+		Status  string `json:"status"`
+		// liked tracks seen on each side before the sync
+		RemoteTracks  int        `json:"remote_tracks"`
+		LocalTracks   int        `json:"local_tracks"`
+		LikedLocal    int        `json:"liked_local"`
+		UnlikedLocal  int        `json:"unliked_local"`
+		SavedRemote   int        `json:"saved_remote"`
+		RemovedRemote int        `json:"removed_remote"`
+		Error         string     `json:"error,omitempty"`
+		CreatedAt     time.Time  `json:"created_at"`
+		FinishedAt    *time.Time `json:"finished_at,omitempty"`
+	}
+
+	ListSyncRunsResponse struct {
+		Items  []SyncRunResponse `json:"items"`
+		Limit  int               `json:"limit"`
+		Offset int               `json:"offset"`
+		Total  int               `json:"total"`
+	}
+)
@@ -0,0 +1,60 @@
+package tag
+
+import (
+	"strings"
+	"time"
+)
+
+// NormalizeName is how tag names are stored and looked up, so "Workout" and
+// " workout" are the same tag.
+func NormalizeName(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+type (
+	// Tag is a user's own label for tracks, names are unique per user and
+	// stored lower-cased.
+	Tag struct {
+		ID        uint   `gorm:"primarykey"`
+		UserID    uint   `gorm:"not null;uniqueIndex:idx_tags_user_name"`
+		Name      string `gorm:"not null;uniqueIndex:idx_tags_user_name"`
+		CreatedAt time.Time
+		UpdatedAt time.Time
+	}
+
+	TrackTag struct {
+		TagID     uint   `gorm:"primaryKey"`
+		SpotifyID string `gorm:"primaryKey"`
+		CreatedAt time.Time
+	}
+)
+
+// requests
+type (
+	CreateTagRequest struct {
+		Name string `json:"name" binding:"required,max=50"`
+	}
+
+	UpdateTagRequest struct {
+		Name string `json:"name" binding:"required,max=50"`
+	}
+
+	TagTracksRequest struct {
+		SpotifyIDs []string `json:"spotify_ids" binding:"required,min=1,max=100,dive,required"`
+	}
+)
+
+// responses
+type (
+	TagResponse struct {
+		ID         uint      `json:"id"`
+		Name       string    `json:"name"`
+		TrackCount int       `json:"track_count"`
+		CreatedAt  time.Time `json:"created_at"`
+		UpdatedAt  time.Time `json:"updated_at"`
+	}
+
+	ListTagResponse struct {
+		Items []TagResponse `json:"items"`
+	}
+)
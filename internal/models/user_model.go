@@ -1,6 +1,17 @@
 package models
 
-import "gorm.io/gorm"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// roles, a user's role is carried in the access token and picked up again on
+// every refresh
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
 
 type (
 	User struct {
@@ -8,6 +19,7 @@ type (
 		Email string 		`db:"email" gorm:"unique;not null"` 
 		Username string `db:"username" gorm:"unique;not null"`
 		Password string	`db:"password" gorm:"not null"`
+		Role string			`db:"role" gorm:"not null;default:user"`
 	}
 
 	SignUpRequest struct {
@@ -22,5 +34,36 @@ type (
 
 	LoginResponse struct {
 		AccessToken string `json:"access_token"`
+		RefreshToken string `json:"refresh_token"`
+	}
+
+	RefreshTokenRequest struct {
+		RefreshToken string `json:"refresh_token" binding:"required"`
+	}
+
+	UpdateRoleRequest struct {
+		Role string `json:"role" binding:"required,oneof=user admin"`
+	}
+
+	UserResponse struct {
+		ID       uint   `json:"id"`
+		Email    string `json:"email"`
+		Username string `json:"username"`
+		Role     string `json:"role"`
+	}
+)
+
+// refresh tokens
+type (
+	// RefreshToken is a persisted, single-use refresh token. Every rotation
+	// keeps the FamilyID of the token it replaces, so a reused token can
+	// revoke the whole chain.
+	RefreshToken struct {
+		gorm.Model
+		UserID    uint      `gorm:"not null;index"`
+		FamilyID  string    `gorm:"not null;index"`
+		TokenHash string    `gorm:"not null;uniqueIndex"`
+		ExpiresAt time.Time `gorm:"not null"`
+		RevokedAt *time.Time
 	}
-)
\ No newline at end of file
+)
@@ -0,0 +1,165 @@
+package importjob
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/sgitwhyd/music-catalogue/internal/models/importjob"
+	"gorm.io/gorm"
+)
+
+// ErrJobNotRunning is returned when a job can't be stored because it was
+// finished meanwhile, usually failed as stale by another replica.
+var ErrJobNotRunning = errors.New("import job is no longer running")
+
+// jobs in these states are still owned by the process running them
+var unfinished = []string{importjob.StatusPending, importjob.StatusRunning}
+
+type importJobRepository struct {
+	db *gorm.DB
+}
+
+func NewImportJobRepository(db *gorm.DB) *importJobRepository {
+	return &importJobRepository{
+		db: db,
+	}
+}
+
+//go:generate mockgen -source=repository.go -destination=../../services/importjob/service_mock_test.go -package=importjob
+type ImportJobRepository interface {
+	Create(ctx context.Context, model *importjob.ImportJob) error
+	Update(ctx context.Context, model *importjob.ImportJob) error
+	Get(ctx context.Context, id uint) (*importjob.ImportJob, error)
+	SaveProgress(ctx context.Context, model *importjob.ImportJob, rows []importjob.ImportJobRow) error
+	Heartbeat(ctx context.Context, id uint) error
+	ListRows(ctx context.Context, jobID uint, status string, limit, offset int) ([]importjob.ImportJobRow, int64, error)
+	FailUnfinished(ctx context.Context, message string, staleBefore time.Time) (int64, error)
+}
+
+func (r *importJobRepository) Create(ctx context.Context, model *importjob.ImportJob) error {
+	return r.db.Create(model).Error
+}
+
+// Update stores the whole job as long as it is unfinished, a job that was
+// failed meanwhile is never flipped back, ErrJobNotRunning then.
+func (r *importJobRepository) Update(ctx context.Context, model *importjob.ImportJob) error {
+	return updateUnfinished(r.db, model)
+}
+
+func (r *importJobRepository) Get(ctx context.Context, id uint) (*importjob.ImportJob, error) {
+	model := importjob.ImportJob{}
+
+	response := r.db.First(&model, id)
+	if response.Error != nil {
+		return nil, response.Error
+	}
+
+	return &model, nil
+}
+
+// SaveProgress stores a batch of resolved rows together with the job
+// counters, a poll never sees counters that are ahead of the report. The
+// rows are rolled back with ErrJobNotRunning when the job was finished
+// meanwhile.
+func (r *importJobRepository) SaveProgress(ctx context.Context, model *importjob.ImportJob, rows []importjob.ImportJobRow) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if len(rows) > 0 {
+			err := tx.Create(&rows).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		return updateUnfinished(tx, model)
+	})
+}
+
+// Heartbeat records that the job is still being worked on, ErrJobNotRunning
+// when it was finished meanwhile.
+func (r *importJobRepository) Heartbeat(ctx context.Context, id uint) error {
+	response := r.db.Model(&importjob.ImportJob{}).
+		Where("id = ?", id).
+		Where("status IN ?", unfinished).
+		Update("heartbeat_at", time.Now())
+	if response.Error != nil {
+		return response.Error
+	}
+
+	if response.RowsAffected == 0 {
+		return ErrJobNotRunning
+	}
+
+	return nil
+}
+
+// updateUnfinished doubles as a heartbeat, storing the job proves it is
+// worked on.
+func updateUnfinished(db *gorm.DB, model *importjob.ImportJob) error {
+	now := time.Now()
+	model.HeartbeatAt = &now
+
+	response := db.Model(model).
+		Where("status IN ?", unfinished).
+		Select("*").
+		Omit("id", "created_at").
+		Updates(model)
+	if response.Error != nil {
+		return response.Error
+	}
+
+	if response.RowsAffected == 0 {
+		return ErrJobNotRunning
+	}
+
+	return nil
+}
+
+// ListRows pages through the report of a job in file order, status narrows
+// it to one outcome when it is not empty.
+func (r *importJobRepository) ListRows(ctx context.Context, jobID uint, status string, limit, offset int) ([]importjob.ImportJobRow, int64, error) {
+	filter := func(db *gorm.DB) *gorm.DB {
+		db = db.Where("job_id = ?", jobID)
+		if status != "" {
+			db = db.Where("status = ?", status)
+		}
+
+		return db
+	}
+
+	var total int64
+	response := r.db.Model(&importjob.ImportJobRow{}).Scopes(filter).Count(&total)
+	if response.Error != nil {
+		return nil, 0, response.Error
+	}
+
+	rows := []importjob.ImportJobRow{}
+	response = r.db.Scopes(filter).Order("row_number ASC").Limit(limit).Offset(offset).Find(&rows)
+	if response.Error != nil {
+		return nil, 0, response.Error
+	}
+
+	return rows, total, nil
+}
+
+// FailUnfinished marks jobs that were pending or running as failed when their
+// heartbeat is older than staleBefore. Jobs run inside an api process, one
+// that stopped beating was lost with a replica that went away.
+func (r *importJobRepository) FailUnfinished(ctx context.Context, message string, staleBefore time.Time) (int64, error) {
+	now := time.Now()
+
+	response := r.db.Model(&importjob.ImportJob{}).
+		Where("status IN ?", unfinished).
+		Where("COALESCE(heartbeat_at, created_at) < ?", staleBefore).
+		Updates(map[string]interface{}{
+			"status":      importjob.StatusFailed,
+			"error":       message,
+			"finished_at": now,
+			"updated_at":  now,
+		})
+	if response.Error != nil {
+		return 0, response.Error
+	}
+
+	return response.RowsAffected, nil
+}
@@ -0,0 +1,146 @@
+package importjob
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/sgitwhyd/music-catalogue/internal/models/importjob"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func Test_importJobRepository_SaveProgress(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	gormDB, err := gorm.Open(postgres.New(postgres.Config{Conn: db}))
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		mockFn  func()
+		wantErr error
+	}{
+		{
+			name: "should store the rows and the counters together",
+			mockFn: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`INSERT INTO "import_job_rows" .* VALUES \(.*\),\(.*\) RETURNING "id"`).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(2))
+				mock.ExpectExec(`UPDATE "import_jobs" SET .*"matched"=\$\d+.* WHERE status IN \(\$\d+,\$\d+\) AND "id" = \$\d+`).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "should roll back the rows of a job that was failed meanwhile",
+			mockFn: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`INSERT INTO "import_job_rows"`).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(2))
+				mock.ExpectExec(`UPDATE "import_jobs" SET`).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectRollback()
+			},
+			wantErr: ErrJobNotRunning,
+		},
+		{
+			name: "should roll back when the rows can't be stored",
+			mockFn: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`INSERT INTO "import_job_rows"`).
+					WillReturnError(assert.AnError)
+				mock.ExpectRollback()
+			},
+			wantErr: assert.AnError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFn()
+
+			r := &importJobRepository{
+				db: gormDB,
+			}
+
+			err := r.SaveProgress(context.Background(), &importjob.ImportJob{ID: 7, UserID: 1, Matched: 1, Unmatched: 1}, []importjob.ImportJobRow{
+				{JobID: 7, RowNumber: 1, Status: importjob.RowMatched, SpotifyID: "a"},
+				{JobID: 7, RowNumber: 2, Status: importjob.RowUnmatched},
+			})
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func Test_importJobRepository_FailUnfinished(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	gormDB, err := gorm.Open(postgres.New(postgres.Config{Conn: db}))
+	assert.NoError(t, err)
+
+	staleBefore := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "import_jobs" SET "error"=\$1,"finished_at"=\$2,"status"=\$3,"updated_at"=\$4 WHERE status IN \(\$5,\$6\) AND COALESCE\(heartbeat_at, created_at\) < \$7`).
+		WithArgs("interrupted", sqlmock.AnyArg(), importjob.StatusFailed, sqlmock.AnyArg(), importjob.StatusPending, importjob.StatusRunning, staleBefore).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	r := &importJobRepository{
+		db: gormDB,
+	}
+
+	count, err := r.FailUnfinished(context.Background(), "interrupted", staleBefore)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func Test_importJobRepository_Heartbeat(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	gormDB, err := gorm.Open(postgres.New(postgres.Config{Conn: db}))
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		affected int64
+		wantErr  error
+	}{
+		{
+			name:     "should beat for an unfinished job",
+			affected: 1,
+		},
+		{
+			name:     "should tell a job was finished meanwhile",
+			affected: 0,
+			wantErr:  ErrJobNotRunning,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock.ExpectBegin()
+			mock.ExpectExec(`UPDATE "import_jobs" SET "heartbeat_at"=\$1,"updated_at"=\$2 WHERE id = \$3 AND status IN \(\$4,\$5\)`).
+				WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), 7, importjob.StatusPending, importjob.StatusRunning).
+				WillReturnResult(sqlmock.NewResult(0, tt.affected))
+			mock.ExpectCommit()
+
+			r := &importJobRepository{
+				db: gormDB,
+			}
+
+			err := r.Heartbeat(context.Background(), 7)
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
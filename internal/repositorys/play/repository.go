@@ -0,0 +1,66 @@
+package play
+
+import (
+	"context"
+	"time"
+
+	"github.com/sgitwhyd/music-catalogue/internal/models/play"
+	"gorm.io/gorm"
+)
+
+type playRepository struct {
+	db *gorm.DB
+}
+
+func NewPlayRepository(db *gorm.DB) *playRepository {
+	return &playRepository{
+		db: db,
+	}
+}
+
+//go:generate mockgen -source=repository.go -destination=../../services/play/service_mock_test.go -package=play
+type PlayRepository interface {
+	Create(ctx context.Context, model *play.PlayEvent) error
+	ListRecent(ctx context.Context, UserID uint, after *play.Cursor, limit int) ([]play.PlayEvent, error)
+	EnsurePartitions(ctx context.Context, from, to time.Time) error
+}
+
+// Create expects the partition for the play's month to exist already, see
+// EnsurePartitions.
+func (r *playRepository) Create(ctx context.Context, model *play.PlayEvent) error {
+	return r.db.Create(model).Error
+}
+
+// EnsurePartitions creates the monthly play_events partitions from the month
+// of from through the month of to.
+func (r *playRepository) EnsurePartitions(ctx context.Context, from, to time.Time) error {
+	from, to = from.UTC(), to.UTC()
+	month := time.Date(from.Year(), from.Month(), 1, 0, 0, 0, 0, time.UTC)
+	for !month.After(to) {
+		err := r.db.Exec("SELECT ensure_play_events_partition(?)", month).Error
+		if err != nil {
+			return err
+		}
+
+		month = month.AddDate(0, 1, 0)
+	}
+
+	return nil
+}
+
+// ListRecent returns the user's plays newest first, starting after the
+// cursor when one is given.
+func (r *playRepository) ListRecent(ctx context.Context, UserID uint, after *play.Cursor, limit int) ([]play.PlayEvent, error) {
+	db := r.db.Where("user_id = ?", UserID)
+	if after != nil {
+		db = db.Where("(played_at, id) < (?, ?)", after.PlayedAt, after.ID)
+	}
+
+	events := []play.PlayEvent{}
+	response := db.Order("played_at DESC, id DESC").Limit(limit).Find(&events)
+	if response.Error != nil {
+		return nil, response.Error
+	}
+
+	return events, nil
+}
@@ -0,0 +1,122 @@
+package play
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/sgitwhyd/music-catalogue/internal/models/play"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func Test_playRepository_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	gormDB, err := gorm.Open(postgres.New(postgres.Config{Conn: db}))
+	assert.NoError(t, err)
+
+	playedAt := time.Date(2026, 10, 18, 7, 0, 0, 0, time.UTC)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO "play_events"`).
+		WithArgs(uint(1), "spotifyID", playedAt, 30000, "web", "", sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectCommit()
+
+	r := NewPlayRepository(gormDB)
+	model := &play.PlayEvent{
+		UserID:           1,
+		SpotifyID:        "spotifyID",
+		PlayedAt:         playedAt,
+		DurationPlayedMs: 30000,
+		Client:           "web",
+	}
+	err = r.Create(context.Background(), model)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(1), model.ID)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func Test_playRepository_EnsurePartitions(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	gormDB, err := gorm.Open(postgres.New(postgres.Config{Conn: db}))
+	assert.NoError(t, err)
+
+	// one call per month, the year boundary included
+	for _, month := range []time.Time{
+		time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2026, 12, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC),
+	} {
+		mock.ExpectExec(`SELECT ensure_play_events_partition\(\$1\)`).
+			WithArgs(month).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+	}
+
+	r := NewPlayRepository(gormDB)
+	err = r.EnsurePartitions(context.Background(), time.Date(2026, 11, 20, 7, 0, 0, 0, time.UTC), time.Date(2027, 1, 3, 0, 0, 0, 0, time.UTC))
+	assert.NoError(t, err)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func Test_playRepository_ListRecent(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	gormDB, err := gorm.Open(postgres.New(postgres.Config{Conn: db}))
+	assert.NoError(t, err)
+
+	playedAt := time.Date(2026, 10, 18, 7, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		after  *play.Cursor
+		mockFn func()
+	}{
+		{
+			name: "first page",
+			mockFn: func() {
+				mock.ExpectQuery(`SELECT \* FROM "play_events" WHERE user_id = \$1 ORDER BY played_at DESC, id DESC LIMIT \$2`).
+					WithArgs(1, 2).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "spotify_id", "played_at"}).
+						AddRow(2, 1, "b", playedAt).
+						AddRow(1, 1, "a", playedAt))
+			},
+		},
+		{
+			name:  "after cursor",
+			after: &play.Cursor{PlayedAt: playedAt, ID: 3},
+			mockFn: func() {
+				mock.ExpectQuery(`SELECT \* FROM "play_events" WHERE user_id = \$1 AND \(played_at, id\) < \(\$2, \$3\) ORDER BY played_at DESC, id DESC LIMIT \$4`).
+					WithArgs(1, playedAt, 3, 2).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "spotify_id", "played_at"}).
+						AddRow(2, 1, "b", playedAt).
+						AddRow(1, 1, "a", playedAt))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFn()
+
+			r := NewPlayRepository(gormDB)
+			got, err := r.ListRecent(context.Background(), 1, tt.after, 2)
+			assert.NoError(t, err)
+			assert.Len(t, got, 2)
+			assert.Equal(t, "b", got[0].SpotifyID)
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
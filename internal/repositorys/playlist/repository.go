@@ -0,0 +1,186 @@
+package playlist
+
+import (
+	"context"
+	"time"
+
+	"github.com/sgitwhyd/music-catalogue/internal/models/playlist"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type playlistRepository struct {
+	db *gorm.DB
+}
+
+func NewPlaylistRepository(db *gorm.DB) *playlistRepository {
+	return &playlistRepository{
+		db: db,
+	}
+}
+
+//go:generate mockgen -source=repository.go -destination=../../services/playlist/service_mock_test.go -package=playlist
+type PlaylistRepository interface {
+	Create(ctx context.Context, model *playlist.Playlist) error
+	Update(ctx context.Context, model *playlist.Playlist) error
+	Delete(ctx context.Context, id uint) error
+	Get(ctx context.Context, id uint) (*playlist.Playlist, error)
+	List(ctx context.Context, UserID uint, limit, offset int) ([]playlist.Playlist, int64, error)
+	CountTracks(ctx context.Context, playlistIDs []uint) (map[uint]int, error)
+	ListTracks(ctx context.Context, playlistID uint) ([]playlist.PlaylistTrack, error)
+	EditTracks(ctx context.Context, playlistID uint, edit func(tracks []playlist.PlaylistTrack) ([]playlist.PlaylistTrack, error)) error
+}
+
+func (r *playlistRepository) Create(ctx context.Context, model *playlist.Playlist) error {
+	return r.db.Create(model).Error
+}
+
+func (r *playlistRepository) Update(ctx context.Context, model *playlist.Playlist) error {
+	return r.db.Save(model).Error
+}
+
+func (r *playlistRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("playlist_id = ?", id).Delete(&playlist.PlaylistTrack{}).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Delete(&playlist.Playlist{}, id).Error
+	})
+}
+
+func (r *playlistRepository) Get(ctx context.Context, id uint) (*playlist.Playlist, error) {
+	model := playlist.Playlist{}
+
+	response := r.db.First(&model, id)
+	if response.Error != nil {
+		return nil, response.Error
+	}
+
+	return &model, nil
+}
+
+func (r *playlistRepository) List(ctx context.Context, UserID uint, limit, offset int) ([]playlist.Playlist, int64, error) {
+	var total int64
+	response := r.db.Model(&playlist.Playlist{}).Where("user_id = ?", UserID).Count(&total)
+	if response.Error != nil {
+		return nil, 0, response.Error
+	}
+
+	playlists := []playlist.Playlist{}
+	response = r.db.Where("user_id = ?", UserID).Order("updated_at DESC, id DESC").Limit(limit).Offset(offset).Find(&playlists)
+	if response.Error != nil {
+		return nil, 0, response.Error
+	}
+
+	return playlists, total, nil
+}
+
+func (r *playlistRepository) CountTracks(ctx context.Context, playlistIDs []uint) (map[uint]int, error) {
+	var rows []struct {
+		PlaylistID uint
+		Count      int
+	}
+
+	response := r.db.Model(&playlist.PlaylistTrack{}).
+		Select("playlist_id, COUNT(*) AS count").
+		Where("playlist_id IN ?", playlistIDs).
+		Group("playlist_id").
+		Scan(&rows)
+	if response.Error != nil {
+		return nil, response.Error
+	}
+
+	result := make(map[uint]int, len(rows))
+	for _, row := range rows {
+		result[row.PlaylistID] = row.Count
+	}
+
+	return result, nil
+}
+
+func (r *playlistRepository) ListTracks(ctx context.Context, playlistID uint) ([]playlist.PlaylistTrack, error) {
+	tracks := []playlist.PlaylistTrack{}
+
+	response := r.db.Where("playlist_id = ?", playlistID).Order("position ASC").Find(&tracks)
+	if response.Error != nil {
+		return nil, response.Error
+	}
+
+	return tracks, nil
+}
+
+// EditTracks hands the playlist's tracks in order to edit and stores the
+// list it returns: tracks it dropped are removed, tracks without an ID are
+// inserted, and everything is renumbered by its index. The playlist row is
+// locked for the duration so concurrent edits can't interleave.
+func (r *playlistRepository) EditTracks(ctx context.Context, playlistID uint, edit func(tracks []playlist.PlaylistTrack) ([]playlist.PlaylistTrack, error)) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		locked := playlist.Playlist{}
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&locked, playlistID).Error
+		if err != nil {
+			return err
+		}
+
+		current := []playlist.PlaylistTrack{}
+		err = tx.Where("playlist_id = ?", playlistID).Order("position ASC").Find(&current).Error
+		if err != nil {
+			return err
+		}
+
+		positions := make(map[uint]int, len(current))
+		for _, track := range current {
+			positions[track.ID] = track.Position
+		}
+
+		next, err := edit(current)
+		if err != nil {
+			return err
+		}
+
+		kept := make(map[uint]struct{}, len(next))
+		for _, track := range next {
+			if track.ID != 0 {
+				kept[track.ID] = struct{}{}
+			}
+		}
+
+		removed := make([]uint, 0)
+		for _, track := range current {
+			if _, ok := kept[track.ID]; !ok {
+				removed = append(removed, track.ID)
+			}
+		}
+
+		if len(removed) > 0 {
+			err = tx.Delete(&playlist.PlaylistTrack{}, removed).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		for idx, track := range next {
+			if track.ID == 0 {
+				track.PlaylistID = playlistID
+				track.Position = idx
+				err = tx.Create(&track).Error
+				if err != nil {
+					return err
+				}
+				continue
+			}
+
+			if positions[track.ID] == idx {
+				continue
+			}
+
+			err = tx.Model(&playlist.PlaylistTrack{}).Where("id = ?", track.ID).Update("position", idx).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		return tx.Model(&locked).Update("updated_at", time.Now()).Error
+	})
+}
@@ -0,0 +1,110 @@
+package playlist
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/sgitwhyd/music-catalogue/internal/models/playlist"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func Test_playlistRepository_EditTracks(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	gormDB, err := gorm.Open(postgres.New(postgres.Config{Conn: db}))
+	assert.NoError(t, err)
+
+	now := time.Now()
+
+	tests := []struct {
+		name    string
+		edit    func(tracks []playlist.PlaylistTrack) ([]playlist.PlaylistTrack, error)
+		mockFn  func()
+		wantErr error
+	}{
+		{
+			name: "should delete dropped tracks, insert new ones and renumber",
+			edit: func(tracks []playlist.PlaylistTrack) ([]playlist.PlaylistTrack, error) {
+				// drop "a", keep "b" and "c" swapped, append "d"
+				return []playlist.PlaylistTrack{tracks[2], tracks[1], {SpotifyID: "d"}}, nil
+			},
+			mockFn: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`SELECT \* FROM "playlists" WHERE "playlists"."id" = \$1 .* FOR UPDATE`).
+					WithArgs(1, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "user_id"}).AddRow(1, 1))
+				mock.ExpectQuery(`SELECT \* FROM "playlist_tracks" WHERE playlist_id = \$1 ORDER BY position ASC`).
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "playlist_id", "spotify_id", "position", "created_at"}).
+						AddRow(1, 1, "a", 0, now).
+						AddRow(2, 1, "b", 1, now).
+						AddRow(3, 1, "c", 2, now))
+				mock.ExpectExec(`DELETE FROM "playlist_tracks" WHERE "playlist_tracks"."id" = \$1`).
+					WithArgs(1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(`UPDATE "playlist_tracks" SET "position"=\$1 WHERE id = \$2`).
+					WithArgs(0, 3).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery(`INSERT INTO "playlist_tracks"`).
+					WithArgs(1, "d", 2, sqlmock.AnyArg()).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(4))
+				mock.ExpectExec(`UPDATE "playlists" SET "updated_at"=\$1`).
+					WithArgs(sqlmock.AnyArg(), 1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "should roll back when edit fails",
+			edit: func(tracks []playlist.PlaylistTrack) ([]playlist.PlaylistTrack, error) {
+				return nil, assert.AnError
+			},
+			mockFn: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`SELECT \* FROM "playlists"`).
+					WithArgs(1, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "user_id"}).AddRow(1, 1))
+				mock.ExpectQuery(`SELECT \* FROM "playlist_tracks"`).
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "playlist_id", "spotify_id", "position", "created_at"}))
+				mock.ExpectRollback()
+			},
+			wantErr: assert.AnError,
+		},
+		{
+			name: "should fail when the playlist is missing",
+			edit: func(tracks []playlist.PlaylistTrack) ([]playlist.PlaylistTrack, error) {
+				return tracks, nil
+			},
+			mockFn: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`SELECT \* FROM "playlists"`).
+					WithArgs(1, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "user_id"}))
+				mock.ExpectRollback()
+			},
+			wantErr: gorm.ErrRecordNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFn()
+
+			r := NewPlaylistRepository(gormDB)
+			err := r.EditTracks(context.Background(), 1, tt.edit)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
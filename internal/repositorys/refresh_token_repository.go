@@ -0,0 +1,72 @@
+package repositorys
+
+import (
+	"errors"
+	"time"
+
+	"github.com/sgitwhyd/music-catalogue/internal/models"
+	"gorm.io/gorm"
+)
+
+// ErrRefreshTokenRevoked is returned by Rotate when the token was already
+// rotated or revoked by a concurrent request.
+var ErrRefreshTokenRevoked = errors.New("refresh token already revoked")
+
+type RefreshTokenRepository interface {
+	Create(model models.RefreshToken) error
+	FindByHash(tokenHash string) (*models.RefreshToken, error)
+	Rotate(oldID uint, next models.RefreshToken) error
+	RevokeFamily(familyID string) error
+}
+
+type refreshTokenRepository struct {
+	db *gorm.DB
+}
+
+func NewRefreshTokenRepo(db *gorm.DB) *refreshTokenRepository {
+	return &refreshTokenRepository{
+		db: db,
+	}
+}
+
+func (r *refreshTokenRepository) Create(model models.RefreshToken) error {
+	return r.db.Create(&model).Error
+}
+
+func (r *refreshTokenRepository) FindByHash(tokenHash string) (*models.RefreshToken, error) {
+	token := models.RefreshToken{}
+	err := r.db.Where("token_hash = ?", tokenHash).First(&token).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+// Rotate revokes the old token and stores its replacement in one
+// transaction. Only one caller can win the revoke, so a token presented
+// twice concurrently is still detected as reused.
+func (r *refreshTokenRepository) Rotate(oldID uint, next models.RefreshToken) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.RefreshToken{}).
+			Where("id = ?", oldID).
+			Where("revoked_at IS NULL").
+			Update("revoked_at", time.Now())
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return ErrRefreshTokenRevoked
+		}
+
+		return tx.Create(&next).Error
+	})
+}
+
+func (r *refreshTokenRepository) RevokeFamily(familyID string) error {
+	return r.db.Model(&models.RefreshToken{}).
+		Where("family_id = ?", familyID).
+		Where("revoked_at IS NULL").
+		Update("revoked_at", time.Now()).Error
+}
@@ -0,0 +1,162 @@
+package repositorys
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/sgitwhyd/music-catalogue/internal/models"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func Test_refreshTokenRepository_Rotate(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	gormDB, err := gorm.Open(postgres.New(postgres.Config{Conn: db}))
+	assert.NoError(t, err)
+
+	expiresAt := time.Now().Add(time.Hour)
+
+	type args struct {
+		oldID uint
+		next  models.RefreshToken
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		wantErr error
+		mockFn  func(args args)
+	}{
+		{
+			name: "should revoke old token and store next token",
+			args: args{
+				oldID: 10,
+				next: models.RefreshToken{
+					UserID:    1,
+					FamilyID:  "family",
+					TokenHash: "hash",
+					ExpiresAt: expiresAt,
+				},
+			},
+			wantErr: nil,
+			mockFn: func(args args) {
+				mock.ExpectBegin()
+				mock.ExpectExec(`UPDATE "refresh_tokens" SET "revoked_at"=.+ WHERE .+revoked_at IS NULL`).
+					WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), args.oldID).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery(`INSERT INTO "refresh_tokens" (.+) VALUES (.+)`).
+					WithArgs(
+						sqlmock.AnyArg(),
+						sqlmock.AnyArg(),
+						sqlmock.AnyArg(),
+						args.next.UserID,
+						args.next.FamilyID,
+						args.next.TokenHash,
+						args.next.ExpiresAt,
+						nil,
+					).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(11))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "should fail when old token already revoked",
+			args: args{
+				oldID: 10,
+				next: models.RefreshToken{
+					UserID:    1,
+					FamilyID:  "family",
+					TokenHash: "hash",
+					ExpiresAt: expiresAt,
+				},
+			},
+			wantErr: ErrRefreshTokenRevoked,
+			mockFn: func(args args) {
+				mock.ExpectBegin()
+				mock.ExpectExec(`UPDATE "refresh_tokens" SET "revoked_at"=.+ WHERE .+revoked_at IS NULL`).
+					WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), args.oldID).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectRollback()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFn(tt.args)
+
+			r := &refreshTokenRepository{
+				db: gormDB,
+			}
+			err := r.Rotate(tt.args.oldID, tt.args.next)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func Test_refreshTokenRepository_RevokeFamily(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	gormDB, err := gorm.Open(postgres.New(postgres.Config{Conn: db}))
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		familyID string
+		wantErr  bool
+		mockFn   func(familyID string)
+	}{
+		{
+			name:     "should revoke every active token in family",
+			familyID: "family",
+			wantErr:  false,
+			mockFn: func(familyID string) {
+				mock.ExpectBegin()
+				mock.ExpectExec(`UPDATE "refresh_tokens" SET "revoked_at"=.+ WHERE family_id.+revoked_at IS NULL`).
+					WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), familyID).
+					WillReturnResult(sqlmock.NewResult(0, 3))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name:     "failed",
+			familyID: "family",
+			wantErr:  true,
+			mockFn: func(familyID string) {
+				mock.ExpectBegin()
+				mock.ExpectExec(`UPDATE "refresh_tokens" SET (.+) WHERE (.+)`).
+					WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), familyID).
+					WillReturnError(assert.AnError)
+				mock.ExpectRollback()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFn(tt.familyID)
+
+			r := &refreshTokenRepository{
+				db: gormDB,
+			}
+			if err := r.RevokeFamily(tt.familyID); (err != nil) != tt.wantErr {
+				t.Errorf("refreshTokenRepository.RevokeFamily() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,194 @@
+package spotify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+)
+
+var (
+	// ErrInvalidGrant is spotify refusing an authorization code or refresh
+	// token, the user has to connect their account again.
+	ErrInvalidGrant = errors.New("spotify: invalid grant")
+
+	ErrNoUserTokenSource = errors.New("spotify: user tokens are not configured")
+)
+
+type (
+	// AuthorizeParams start the authorization code flow with PKCE.
+	AuthorizeParams struct {
+		State         string
+		CodeChallenge string
+		RedirectURI   string
+		Scopes        []string
+	}
+
+	SpotifyUserProfile struct {
+		ID          string `json:"id"`
+		DisplayName string `json:"display_name"`
+	}
+)
+
+//go:generate mockgen -source=account.go -destination=../../services/spotifyaccount/outbond_mock_test.go -package=spotifyaccount
+type SpotifyAuthOutbond interface {
+	AuthorizeURL(params AuthorizeParams) string
+	ExchangeCode(ctx context.Context, code, codeVerifier, redirectURI string) (*SpotifyTokenResponse, error)
+	RefreshUserToken(ctx context.Context, refreshToken string) (*SpotifyTokenResponse, error)
+	GetCurrentUser(ctx context.Context, accessToken string) (*SpotifyUserProfile, error)
+}
+
+// UserTokenSource hands out a usable access token of the spotify account a
+// user linked.
+type UserTokenSource interface {
+	UserToken(ctx context.Context, userID uint) (accessToken, tokenType string, err error)
+}
+
+type userTokenKey struct{}
+
+// WithUserToken makes the outbond calls made with the returned context act
+// on behalf of userID instead of the app. Search results fetched this way
+// bypass the cache, they may be personalised.
+func WithUserToken(ctx context.Context, userID uint) context.Context {
+	return context.WithValue(ctx, userTokenKey{}, userID)
+}
+
+func userTokenFromContext(ctx context.Context) (uint, bool) {
+	userID, ok := ctx.Value(userTokenKey{}).(uint)
+	return userID, ok
+}
+
+// SetUserTokenSource wires in where user tokens come from. The source itself
+// refreshes tokens through the outbond, so it can't be a constructor
+// argument.
+func (o *outbond) SetUserTokenSource(source UserTokenSource) {
+	o.userTokens = source
+}
+
+// authorization returns the Authorization header for a call, the user's
+// token when ctx asks for it and the app token otherwise.
+func (o *outbond) authorization(ctx context.Context) (string, error) {
+	var (
+		accessToken, tokenType string
+		err                    error
+	)
+
+	if userID, ok := userTokenFromContext(ctx); ok {
+		if o.userTokens == nil {
+			return "", ErrNoUserTokenSource
+		}
+
+		accessToken, tokenType, err = o.userTokens.UserToken(ctx, userID)
+	} else {
+		accessToken, tokenType, err = o.GetTokenDetails(ctx)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%s %s", tokenType, accessToken), nil
+}
+
+func (o *outbond) AuthorizeURL(authorizeParams AuthorizeParams) string {
+	params := url.Values{}
+	params.Set("client_id", o.cfg.SpotifyClientID)
+	params.Set("response_type", "code")
+	params.Set("redirect_uri", authorizeParams.RedirectURI)
+	params.Set("state", authorizeParams.State)
+	params.Set("code_challenge_method", "S256")
+	params.Set("code_challenge", authorizeParams.CodeChallenge)
+	if len(authorizeParams.Scopes) > 0 {
+		params.Set("scope", strings.Join(authorizeParams.Scopes, " "))
+	}
+
+	return fmt.Sprintf(`%s?%s`, o.accountsURL("/authorize"), params.Encode())
+}
+
+// ExchangeCode redeems the code of the callback. Being a PKCE exchange the
+// verifier stands in for the client secret.
+func (o *outbond) ExchangeCode(ctx context.Context, code, codeVerifier, redirectURI string) (*SpotifyTokenResponse, error) {
+	formData := url.Values{}
+	formData.Set("grant_type", "authorization_code")
+	formData.Set("code", code)
+	formData.Set("redirect_uri", redirectURI)
+	formData.Set("client_id", o.cfg.SpotifyClientID)
+	formData.Set("code_verifier", codeVerifier)
+
+	return o.postToken(ctx, formData)
+}
+
+// RefreshUserToken trades a refresh token for a new access token. Spotify
+// may rotate the refresh token as well, RefreshToken is empty when it
+// didn't.
+func (o *outbond) RefreshUserToken(ctx context.Context, refreshToken string) (*SpotifyTokenResponse, error) {
+	formData := url.Values{}
+	formData.Set("grant_type", "refresh_token")
+	formData.Set("refresh_token", refreshToken)
+	formData.Set("client_id", o.cfg.SpotifyClientID)
+
+	return o.postToken(ctx, formData)
+}
+
+func (o *outbond) GetCurrentUser(ctx context.Context, accessToken string) (*SpotifyUserProfile, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, o.apiURL("/me"), nil)
+	if err != nil {
+		log.Error().Err(err).Msg("error create request spotify")
+		return nil, err
+	}
+
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+
+	var response SpotifyUserProfile
+	err = o.do(req, &response)
+	if err != nil {
+		log.Error().Err(err).Msg("error get spotify current user")
+		return nil, err
+	}
+
+	return &response, nil
+}
+
+func (o *outbond) postToken(ctx context.Context, formData url.Values) (*SpotifyTokenResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, o.accountsURL("/api/token"), strings.NewReader(formData.Encode()))
+	if err != nil {
+		log.Error().Err(err).Msg("error create request spotify token")
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := o.client.Do(req)
+	if err != nil {
+		log.Error().Err(err).Msg("error execute spotify token")
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusBadRequest {
+		var oauthError struct {
+			Error string `json:"error"`
+		}
+		if json.NewDecoder(resp.Body).Decode(&oauthError) == nil && oauthError.Error == "invalid_grant" {
+			return nil, ErrInvalidGrant
+		}
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("spotify token: unexpected status code %d", resp.StatusCode)
+	}
+
+	var response SpotifyTokenResponse
+	err = json.NewDecoder(resp.Body).Decode(&response)
+	if err != nil {
+		log.Error().Err(err).Msg("error decoded spotify token response")
+		return nil, err
+	}
+
+	return &response, nil
+}
@@ -0,0 +1,138 @@
+package spotify
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"golang.org/x/sync/singleflight"
+
+	"github.com/sgitwhyd/music-catalogue/pkg/lrucache"
+)
+
+const (
+	CacheHit   = "HIT"
+	CacheMiss  = "MISS"
+	CacheStale = "STALE"
+
+	// bounds a search that is shared by every caller waiting on the same key
+	searchFetchTimeout = 10 * time.Second
+)
+
+// cachedOutbond decorates a SpotifyOutbond with an in-memory search cache.
+// Fresh entries are served for ttl. After that an entry is still served for
+// staleWhileRevalidate while a background search refreshes it, and for
+// staleIfError when spotify cannot be reached.
+type cachedOutbond struct {
+	SpotifyOutbond
+
+	cache *lrucache.Cache[string, searchCacheEntry]
+	group singleflight.Group
+	now   func() time.Time
+
+	ttl                  time.Duration
+	staleWhileRevalidate time.Duration
+	staleIfError         time.Duration
+}
+
+type searchCacheEntry struct {
+	response  *SpotifySearchResponse
+	fetchedAt time.Time
+}
+
+func NewCachedSpotifyOutbond(next SpotifyOutbond, size int, ttl, staleWhileRevalidate, staleIfError time.Duration) SpotifyOutbond {
+	return &cachedOutbond{
+		SpotifyOutbond:       next,
+		cache:                lrucache.New[string, searchCacheEntry](size),
+		now:                  time.Now,
+		ttl:                  ttl,
+		staleWhileRevalidate: staleWhileRevalidate,
+		staleIfError:         staleIfError,
+	}
+}
+
+func (c *cachedOutbond) Search(ctx context.Context, params SearchParams) (*SpotifySearchResponse, error) {
+	// results for a user are neither served from nor put into the cache
+	if _, ok := userTokenFromContext(ctx); ok {
+		return c.SpotifyOutbond.Search(ctx, params)
+	}
+
+	key := searchCacheKey(params)
+
+	cached, ok := c.cache.Get(key)
+	age := c.now().Sub(cached.fetchedAt)
+
+	if ok && age < c.ttl {
+		return withCacheStatus(cached.response, CacheHit), nil
+	}
+
+	if ok && age < c.ttl+c.staleWhileRevalidate {
+		c.group.DoChan(key, func() (any, error) {
+			return c.load(ctx, key, params)
+		})
+
+		return withCacheStatus(cached.response, CacheStale), nil
+	}
+
+	result := c.group.DoChan(key, func() (any, error) {
+		return c.load(ctx, key, params)
+	})
+
+	select {
+	case res := <-result:
+		if res.Err != nil {
+			if ok && age < c.ttl+c.staleIfError {
+				log.Error().Err(res.Err).Msg("error search spotify, serving stale result")
+				return withCacheStatus(cached.response, CacheStale), nil
+			}
+
+			return nil, res.Err
+		}
+
+		return withCacheStatus(res.Val.(*SpotifySearchResponse), CacheMiss), nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (c *cachedOutbond) load(ctx context.Context, key string, params SearchParams) (*SpotifySearchResponse, error) {
+	// detached from the caller, a cancelled request must not fail everybody
+	// else waiting on the same search
+	fetchCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), searchFetchTimeout)
+	defer cancel()
+
+	response, err := c.SpotifyOutbond.Search(fetchCtx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	c.cache.Add(key, searchCacheEntry{
+		response:  response,
+		fetchedAt: c.now(),
+	})
+
+	return response, nil
+}
+
+func searchCacheKey(params SearchParams) string {
+	query := strings.Join(strings.Fields(strings.ToLower(params.Query)), " ")
+
+	types := slices.Clone(params.Types)
+	if len(types) == 0 {
+		types = []string{SearchTypeTrack}
+	}
+	slices.Sort(types)
+
+	return fmt.Sprintf("%s|%d|%d|%s|%s", query, params.Limit, params.Offset, strings.ToUpper(params.Market), strings.Join(types, ","))
+}
+
+// withCacheStatus returns a copy so the cached response is never mutated.
+func withCacheStatus(response *SpotifySearchResponse, status string) *SpotifySearchResponse {
+	copied := *response
+	copied.CacheStatus = status
+
+	return &copied
+}
@@ -0,0 +1,158 @@
+package spotify
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// stubSearchOutbond answers searches with the total set in next, counting calls.
+type stubSearchOutbond struct {
+	SpotifyOutbond
+
+	calls atomic.Int32
+	next  atomic.Int32
+	err   atomic.Pointer[error]
+	wait  chan struct{}
+}
+
+func (s *stubSearchOutbond) Search(ctx context.Context, params SearchParams) (*SpotifySearchResponse, error) {
+	s.calls.Add(1)
+
+	if s.wait != nil {
+		<-s.wait
+	}
+
+	if err := s.err.Load(); err != nil {
+		return nil, *err
+	}
+
+	return &SpotifySearchResponse{
+		Tracks: SpotifyTrack{Total: int(s.next.Load())},
+	}, nil
+}
+
+func newTestCachedOutbond(stub *stubSearchOutbond, now *time.Time) *cachedOutbond {
+	c := NewCachedSpotifyOutbond(stub, 10, time.Minute, time.Minute, time.Hour).(*cachedOutbond)
+	c.now = func() time.Time { return *now }
+
+	return c
+}
+
+func Test_cachedOutbond_Search(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	stub := &stubSearchOutbond{}
+	stub.next.Store(1)
+	c := newTestCachedOutbond(stub, &now)
+
+	params := SearchParams{Query: "Bohemian  Rhapsody", Limit: 10, Market: "id"}
+
+	got, err := c.Search(context.Background(), params)
+	assert.NoError(t, err)
+	assert.Equal(t, CacheMiss, got.CacheStatus)
+	assert.Equal(t, 1, got.Tracks.Total)
+
+	// same search after normalizing case and whitespace
+	got, err = c.Search(context.Background(), SearchParams{Query: "bohemian rhapsody", Limit: 10, Market: "ID"})
+	assert.NoError(t, err)
+	assert.Equal(t, CacheHit, got.CacheStatus)
+	assert.Equal(t, int32(1), stub.calls.Load())
+
+	// other page is a different key
+	got, err = c.Search(context.Background(), SearchParams{Query: "bohemian rhapsody", Limit: 10, Offset: 10, Market: "ID"})
+	assert.NoError(t, err)
+	assert.Equal(t, CacheMiss, got.CacheStatus)
+	assert.Equal(t, int32(2), stub.calls.Load())
+
+	// past ttl the stale entry is served while it is revalidated
+	now = now.Add(90 * time.Second)
+	stub.next.Store(2)
+
+	got, err = c.Search(context.Background(), params)
+	assert.NoError(t, err)
+	assert.Equal(t, CacheStale, got.CacheStatus)
+	assert.Equal(t, 1, got.Tracks.Total)
+
+	assert.Eventually(t, func() bool {
+		got, err := c.Search(context.Background(), params)
+		return err == nil && got.CacheStatus == CacheHit && got.Tracks.Total == 2
+	}, time.Second, 10*time.Millisecond)
+
+	// past stale-while-revalidate, a failing spotify still gets the stale entry
+	now = now.Add(5 * time.Minute)
+	searchErr := errors.New("spotify down")
+	stub.err.Store(&searchErr)
+
+	got, err = c.Search(context.Background(), params)
+	assert.NoError(t, err)
+	assert.Equal(t, CacheStale, got.CacheStatus)
+	assert.Equal(t, 2, got.Tracks.Total)
+
+	// past stale-if-error the failure is returned
+	now = now.Add(2 * time.Hour)
+
+	_, err = c.Search(context.Background(), params)
+	assert.ErrorIs(t, err, searchErr)
+}
+
+func Test_cachedOutbond_Search_UserToken(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	stub := &stubSearchOutbond{}
+	c := newTestCachedOutbond(stub, &now)
+
+	params := SearchParams{Query: "creep", Limit: 10}
+	ctx := WithUserToken(context.Background(), 1)
+
+	for range 2 {
+		got, err := c.Search(ctx, params)
+		assert.NoError(t, err)
+		assert.Empty(t, got.CacheStatus)
+	}
+	assert.Equal(t, int32(2), stub.calls.Load())
+
+	// nor did they end up in the cache for app token searches
+	got, err := c.Search(context.Background(), params)
+	assert.NoError(t, err)
+	assert.Equal(t, CacheMiss, got.CacheStatus)
+}
+
+func Test_cachedOutbond_Search_SingleFlight(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	stub := &stubSearchOutbond{wait: make(chan struct{})}
+	c := newTestCachedOutbond(stub, &now)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			_, err := c.Search(context.Background(), SearchParams{Query: "queen", Limit: 10})
+			assert.NoError(t, err)
+		}()
+	}
+
+	assert.Eventually(t, func() bool {
+		return stub.calls.Load() == 1
+	}, time.Second, 10*time.Millisecond)
+	close(stub.wait)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), stub.calls.Load())
+}
+
+func Test_searchCacheKey(t *testing.T) {
+	base := SearchParams{Query: "Bohemian  Rhapsody", Limit: 10, Market: "id"}
+
+	assert.Equal(t, searchCacheKey(base), searchCacheKey(SearchParams{Query: "bohemian rhapsody", Limit: 10, Market: "ID", Types: []string{SearchTypeTrack}}))
+	assert.Equal(t,
+		searchCacheKey(SearchParams{Query: "queen", Types: []string{SearchTypeArtist, SearchTypeAlbum}}),
+		searchCacheKey(SearchParams{Query: "queen", Types: []string{SearchTypeAlbum, SearchTypeArtist}}),
+	)
+	assert.NotEqual(t, searchCacheKey(base), searchCacheKey(SearchParams{Query: "bohemian rhapsody", Limit: 10, Market: "ID", Types: []string{SearchTypeAlbum}}))
+}
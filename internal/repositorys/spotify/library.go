@@ -0,0 +1,114 @@
+package spotify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// spotify pages saved tracks with at most this many items
+const maxSavedTracksPageSize = 50
+
+type (
+	SpotifySavedTracks struct {
+		Href     string                    `json:"href"`
+		Limit    int                       `json:"limit"`
+		Next     *string                   `json:"next"`
+		Offset   int                       `json:"offset"`
+		Previous *string                   `json:"previous"`
+		Total    int                       `json:"total"`
+		Items    []SpotifySavedTrackObject `json:"items"`
+	}
+
+	SpotifySavedTrackObject struct {
+		AddedAt time.Time          `json:"added_at"`
+		Track   SpotifyTrackObject `json:"track"`
+	}
+)
+
+// SpotifyLibraryOutbond reads and changes the "Liked Songs" of a linked
+// account. The calls need a ctx from WithUserToken, the app token has no
+// library.
+//
+//go:generate mockgen -source=library.go -destination=../../services/spotifysync/library_mock_test.go -package=spotifysync
+type SpotifyLibraryOutbond interface {
+	GetSavedTracks(ctx context.Context, limit, offset int) (*SpotifySavedTracks, error)
+	SaveTracks(ctx context.Context, spotifyIDs []string) error
+	RemoveSavedTracks(ctx context.Context, spotifyIDs []string) error
+}
+
+// GetSavedTracks returns a page of the saved tracks, the most recently
+// saved first.
+func (o *outbond) GetSavedTracks(ctx context.Context, limit, offset int) (*SpotifySavedTracks, error) {
+	params := url.Values{}
+	params.Set("limit", strconv.Itoa(min(limit, maxSavedTracksPageSize)))
+	params.Set("offset", strconv.Itoa(offset))
+
+	SAVED_TRACKS_ENDPOINT := fmt.Sprintf(`%s?%s`, o.apiURL("/me/tracks"), params.Encode())
+
+	var response SpotifySavedTracks
+	err := o.get(ctx, SAVED_TRACKS_ENDPOINT, &response)
+	if err != nil {
+		log.Error().Err(err).Msg("error get spotify saved tracks")
+		return nil, err
+	}
+
+	return &response, nil
+}
+
+// SaveTracks adds the tracks to the saved tracks in batches of
+// maxTracksPerRequest. Saving a track twice keeps it once.
+func (o *outbond) SaveTracks(ctx context.Context, spotifyIDs []string) error {
+	return o.editSavedTracks(ctx, http.MethodPut, spotifyIDs)
+}
+
+// RemoveSavedTracks removes the tracks from the saved tracks in batches of
+// maxTracksPerRequest. Tracks that aren't saved are ignored.
+func (o *outbond) RemoveSavedTracks(ctx context.Context, spotifyIDs []string) error {
+	return o.editSavedTracks(ctx, http.MethodDelete, spotifyIDs)
+}
+
+func (o *outbond) editSavedTracks(ctx context.Context, method string, spotifyIDs []string) error {
+	for start := 0; start < len(spotifyIDs); start += maxTracksPerRequest {
+		end := min(start+maxTracksPerRequest, len(spotifyIDs))
+
+		body, err := json.Marshal(map[string][]string{"ids": spotifyIDs[start:end]})
+		if err != nil {
+			return err
+		}
+
+		err = o.send(ctx, method, o.apiURL("/me/tracks"), body)
+		if err != nil {
+			log.Error().Err(err).Msgf("error %s spotify saved tracks", method)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// send is get for requests with a JSON body and no response body.
+func (o *outbond) send(ctx context.Context, method, endpoint string, body []byte) error {
+	BEARER_TOKEN, err := o.authorization(ctx)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, bytes.NewReader(body))
+	if err != nil {
+		log.Error().Err(err).Msg("error create request spotify")
+		return err
+	}
+
+	req.Header.Set("Authorization", BEARER_TOKEN)
+	req.Header.Set("Content-Type", "application/json")
+
+	return o.do(req, nil)
+}
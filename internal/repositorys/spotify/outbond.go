@@ -3,76 +3,328 @@ package spotify
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
 	"strconv"
-	"time"
+	"strings"
 
 	"github.com/rs/zerolog/log"
 	"github.com/sgitwhyd/music-catalogue/internal/configs"
 	"github.com/sgitwhyd/music-catalogue/pkg/httpclient"
 )
 
+const (
+	defaultSpotifyAPIBaseURL      = "https://api.spotify.com/v1"
+	defaultSpotifyAccountsBaseURL = "https://accounts.spotify.com"
+
+	// spotify rejects several-tracks requests with more ids than this
+	maxTracksPerRequest = 50
+)
+
+// ErrNotFound matches the *StatusError spotify answers with a 404 for the
+// requested resource.
+var ErrNotFound = errors.New("spotify: resource not found")
+
+// StatusError is returned when spotify answers with a non 2xx status, so
+// callers can tell a bad request or a rate limit apart from an outage.
+type StatusError struct {
+	StatusCode int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("spotify: unexpected status code %d", e.StatusCode)
+}
+
+// UpstreamStatus lets apperror.From map the status without importing this package.
+func (e *StatusError) UpstreamStatus() int {
+	return e.StatusCode
+}
+
+func (e *StatusError) Is(target error) bool {
+	return target == ErrNotFound && e.StatusCode == http.StatusNotFound
+}
+
 type outbond struct {
 	cfg *configs.Config
 	client httpclient.HTTPClient
-	AccessToken string
-	TokenType string
-	ExpiredAt time.Time
+	tokens *tokenSource
+	userTokens UserTokenSource
 }
 
-
-
 func NewSpotifyOutbond(cfg *configs.Config, client httpclient.HTTPClient) *outbond {
-		return &outbond{
+		o := &outbond{
 			cfg: cfg,
 			client: client,
 		}
+		o.tokens = newTokenSource(o.generateToken)
+
+		return o
 	}
 
+func (o *outbond) Search(ctx context.Context, searchParams SearchParams) (*SpotifySearchResponse, error) {
+	types := searchParams.Types
+	if len(types) == 0 {
+		types = []string{SearchTypeTrack}
+	}
 
-func (o *outbond) Search(ctx context.Context, query string, limit, offset int) (*SpotifySearchResponse, error) {
 	// set url params
 	params := url.Values{}
-	params.Set("q", query)
-	params.Set("type", "track")
-	params.Set("limit", strconv.Itoa(limit))
-	params.Set("offset", strconv.Itoa(offset))
+	params.Set("q", searchParams.Query)
+	params.Set("type", strings.Join(types, ","))
+	params.Set("limit", strconv.Itoa(searchParams.Limit))
+	params.Set("offset", strconv.Itoa(searchParams.Offset))
+	if searchParams.Market != "" {
+		params.Set("market", searchParams.Market)
+	}
 
-	BASE_URL := "https://api.spotify.com/v1/search"
-	SEARCH_ENDPOINT := fmt.Sprintf(`%s?%s`, BASE_URL, params.Encode())
+	SEARCH_ENDPOINT := fmt.Sprintf(`%s?%s`, o.apiURL("/search"), params.Encode())
 
-	// get token GetTokenDetails
-	accessToken, tokenType, err := o.GetTokenDetails()
+	var response SpotifySearchResponse
+	err := o.get(ctx, SEARCH_ENDPOINT, &response)
 	if err != nil {
+		log.Error().Err(err).Msg("error execute search spotify")
 		return nil, err
 	}
 
-	BEARER_TOKEN := fmt.Sprintf("%s %s", tokenType, accessToken)
+	return &response, nil
+}
+
+func (o *outbond) GetTrack(ctx context.Context, spotifyID string) (*SpotifyTrackObject, error) {
+	TRACK_ENDPOINT := o.apiURL("/tracks/" + url.PathEscape(spotifyID))
 
-	req, err := http.NewRequest(http.MethodGet, SEARCH_ENDPOINT, nil)
+	var response SpotifyTrackObject
+	err := o.get(ctx, TRACK_ENDPOINT, &response)
 	if err != nil {
-		log.Error().Err(err).Msg("error create request spotify search")
+		log.Error().Err(err).Msgf("error get spotify track %s", spotifyID)
 		return nil, err
 	}
 
-	req.Header.Set("Authorization", BEARER_TOKEN)
-	resp, err := o.client.Do(req)
+	return &response, nil
+}
+
+// GetSeveralTracks looks tracks up in batches of maxTracksPerRequest. The
+// result keeps the order of spotifyIDs; ids spotify doesn't know are skipped.
+func (o *outbond) GetSeveralTracks(ctx context.Context, spotifyIDs []string) ([]SpotifyTrackObject, error) {
+	tracks := make([]SpotifyTrackObject, 0, len(spotifyIDs))
+
+	for start := 0; start < len(spotifyIDs); start += maxTracksPerRequest {
+		end := min(start+maxTracksPerRequest, len(spotifyIDs))
+
+		params := url.Values{}
+		params.Set("ids", strings.Join(spotifyIDs[start:end], ","))
+
+		TRACKS_ENDPOINT := fmt.Sprintf(`%s?%s`, o.apiURL("/tracks"), params.Encode())
+
+		var response SpotifySeveralTracksResponse
+		err := o.get(ctx, TRACKS_ENDPOINT, &response)
+		if err != nil {
+			log.Error().Err(err).Msg("error get several spotify tracks")
+			return nil, err
+		}
+
+		for _, track := range response.Tracks {
+			if track != nil {
+				tracks = append(tracks, *track)
+			}
+		}
+	}
+
+	return tracks, nil
+}
+
+func (o *outbond) GetArtist(ctx context.Context, artistID string) (*SpotifyFullArtistObject, error) {
+	ARTIST_ENDPOINT := o.apiURL("/artists/" + url.PathEscape(artistID))
+
+	var response SpotifyFullArtistObject
+	err := o.get(ctx, ARTIST_ENDPOINT, &response)
 	if err != nil {
-		log.Error().Err(err).Msg("error execute search spotify")
+		log.Error().Err(err).Msgf("error get spotify artist %s", artistID)
 		return nil, err
 	}
 
-	defer resp.Body.Close()
+	return &response, nil
+}
 
-	var response SpotifySearchResponse
-	err = json.NewDecoder(resp.Body).Decode(&response)
+func (o *outbond) GetArtistTopTracks(ctx context.Context, artistID, market string) ([]SpotifyTrackObject, error) {
+	params := url.Values{}
+	if market != "" {
+		params.Set("market", market)
+	}
+
+	TOP_TRACKS_ENDPOINT := o.apiURL("/artists/" + url.PathEscape(artistID) + "/top-tracks")
+	if len(params) > 0 {
+		TOP_TRACKS_ENDPOINT = fmt.Sprintf(`%s?%s`, TOP_TRACKS_ENDPOINT, params.Encode())
+	}
+
+	var response SpotifyArtistTopTracksResponse
+	err := o.get(ctx, TOP_TRACKS_ENDPOINT, &response)
+	if err != nil {
+		log.Error().Err(err).Msgf("error get spotify artist %s top tracks", artistID)
+		return nil, err
+	}
+
+	return response.Tracks, nil
+}
+
+func (o *outbond) GetArtistAlbums(ctx context.Context, artistParams ArtistAlbumsParams) (*SpotifyAlbums, error) {
+	params := url.Values{}
+	if len(artistParams.IncludeGroups) > 0 {
+		params.Set("include_groups", strings.Join(artistParams.IncludeGroups, ","))
+	}
+	if artistParams.Market != "" {
+		params.Set("market", artistParams.Market)
+	}
+	params.Set("limit", strconv.Itoa(artistParams.Limit))
+	params.Set("offset", strconv.Itoa(artistParams.Offset))
+
+	ALBUMS_ENDPOINT := fmt.Sprintf(`%s?%s`, o.apiURL("/artists/"+url.PathEscape(artistParams.ArtistID)+"/albums"), params.Encode())
+
+	var response SpotifyAlbums
+	err := o.get(ctx, ALBUMS_ENDPOINT, &response)
+	if err != nil {
+		log.Error().Err(err).Msgf("error get spotify artist %s albums", artistParams.ArtistID)
+		return nil, err
+	}
+
+	return &response, nil
+}
+
+func (o *outbond) GetAlbum(ctx context.Context, albumID, market string) (*SpotifyFullAlbumObject, error) {
+	params := url.Values{}
+	if market != "" {
+		params.Set("market", market)
+	}
+
+	ALBUM_ENDPOINT := o.apiURL("/albums/" + url.PathEscape(albumID))
+	if len(params) > 0 {
+		ALBUM_ENDPOINT = fmt.Sprintf(`%s?%s`, ALBUM_ENDPOINT, params.Encode())
+	}
+
+	var response SpotifyFullAlbumObject
+	err := o.get(ctx, ALBUM_ENDPOINT, &response)
+	if err != nil {
+		log.Error().Err(err).Msgf("error get spotify album %s", albumID)
+		return nil, err
+	}
+
+	return &response, nil
+}
+
+func (o *outbond) GetAlbumTracks(ctx context.Context, albumParams AlbumTracksParams) (*SpotifyTrack, error) {
+	params := url.Values{}
+	if albumParams.Market != "" {
+		params.Set("market", albumParams.Market)
+	}
+	params.Set("limit", strconv.Itoa(albumParams.Limit))
+	params.Set("offset", strconv.Itoa(albumParams.Offset))
+
+	ALBUM_TRACKS_ENDPOINT := fmt.Sprintf(`%s?%s`, o.apiURL("/albums/"+url.PathEscape(albumParams.AlbumID)+"/tracks"), params.Encode())
+
+	var response SpotifyTrack
+	err := o.get(ctx, ALBUM_TRACKS_ENDPOINT, &response)
+	if err != nil {
+		log.Error().Err(err).Msgf("error get spotify album %s tracks", albumParams.AlbumID)
+		return nil, err
+	}
+
+	return &response, nil
+}
+
+func (o *outbond) GetRecommendations(ctx context.Context, recommendationsParams RecommendationsParams) (*SpotifyRecommendationsResponse, error) {
+	params := url.Values{}
+	if len(recommendationsParams.SeedTracks) > 0 {
+		params.Set("seed_tracks", strings.Join(recommendationsParams.SeedTracks, ","))
+	}
+	if len(recommendationsParams.SeedArtists) > 0 {
+		params.Set("seed_artists", strings.Join(recommendationsParams.SeedArtists, ","))
+	}
+	if len(recommendationsParams.SeedGenres) > 0 {
+		params.Set("seed_genres", strings.Join(recommendationsParams.SeedGenres, ","))
+	}
+	if recommendationsParams.Market != "" {
+		params.Set("market", recommendationsParams.Market)
+	}
+	for attribute, value := range recommendationsParams.Attributes {
+		params.Set(attribute, strconv.FormatFloat(value, 'f', -1, 64))
+	}
+	params.Set("limit", strconv.Itoa(recommendationsParams.Limit))
+
+	RECOMMENDATIONS_ENDPOINT := fmt.Sprintf(`%s?%s`, o.apiURL("/recommendations"), params.Encode())
+
+	var response SpotifyRecommendationsResponse
+	err := o.get(ctx, RECOMMENDATIONS_ENDPOINT, &response)
 	if err != nil {
-		log.Error().Err(err).Msg("error decoded spotify search response")
+		log.Error().Err(err).Msg("error get spotify recommendations")
 		return nil, err
 	}
 
 	return &response, nil
+}
+
+// apiURL resolves path against the configured web API base url.
+func (o *outbond) apiURL(path string) string {
+	baseURL := o.cfg.SpotifyAPIBaseURL
+	if baseURL == "" {
+		baseURL = defaultSpotifyAPIBaseURL
+	}
+
+	return strings.TrimRight(baseURL, "/") + path
+}
+
+// accountsURL resolves path against the configured accounts service base url.
+func (o *outbond) accountsURL(path string) string {
+	baseURL := o.cfg.SpotifyAccountsBaseURL
+	if baseURL == "" {
+		baseURL = defaultSpotifyAccountsBaseURL
+	}
+
+	return strings.TrimRight(baseURL, "/") + path
+}
+
+// get sends an authorized GET to spotify and decodes the JSON body into
+// response. It goes out with the app token unless ctx carries WithUserToken.
+func (o *outbond) get(ctx context.Context, endpoint string, response any) error {
+	BEARER_TOKEN, err := o.authorization(ctx)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		log.Error().Err(err).Msg("error create request spotify")
+		return err
+	}
+
+	req.Header.Set("Authorization", BEARER_TOKEN)
+
+	return o.do(req, response)
+}
+
+// do sends req and decodes the JSON body into response, the body is ignored
+// when response is nil.
+func (o *outbond) do(req *http.Request, response any) error {
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return &StatusError{StatusCode: resp.StatusCode}
+	}
+
+	if response == nil {
+		return nil
+	}
+
+	err = json.NewDecoder(resp.Body).Decode(response)
+	if err != nil {
+		log.Error().Err(err).Msg("error decoded spotify response")
+		return err
+	}
 
+	return nil
 }
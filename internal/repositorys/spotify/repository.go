@@ -8,8 +8,25 @@ import (
 )
 
 type (
+	SearchParams struct {
+		Query  string
+		Limit  int
+		Offset int
+		Market string
+		// any of the Search* types, tracks only when empty
+		Types []string
+	}
+
+	// SpotifySearchResponse holds a paging object for every type that was
+	// searched, the sections of the other types stay empty.
 	SpotifySearchResponse struct {
-		Tracks SpotifyTrack `json:"tracks"`
+		Tracks    SpotifyTrack      `json:"tracks"`
+		Albums    *SpotifyAlbums    `json:"albums"`
+		Artists   *SpotifyArtists   `json:"artists"`
+		Playlists *SpotifyPlaylists `json:"playlists"`
+
+		// set by the search cache, HIT, STALE or MISS
+		CacheStatus string `json:"-"`
 	}
 
 	SpotifyTrack struct {
@@ -22,6 +39,79 @@ type (
 		Items 		[]SpotifyTrackObject 	`json:"items"`
 	}
 
+	SpotifyAlbums struct {
+		Href     string                         `json:"href"`
+		Limit    int                            `json:"limit"`
+		Next     *string                        `json:"next"`
+		Offset   int                            `json:"offset"`
+		Previous *string                        `json:"previous"`
+		Total    int                            `json:"total"`
+		Items    []SpotifySimplifiedAlbumObject `json:"items"`
+	}
+
+	SpotifyArtists struct {
+		Href     string                    `json:"href"`
+		Limit    int                       `json:"limit"`
+		Next     *string                   `json:"next"`
+		Offset   int                       `json:"offset"`
+		Previous *string                   `json:"previous"`
+		Total    int                       `json:"total"`
+		Items    []SpotifyFullArtistObject `json:"items"`
+	}
+
+	SpotifyPlaylists struct {
+		Href     string  `json:"href"`
+		Limit    int     `json:"limit"`
+		Next     *string `json:"next"`
+		Offset   int     `json:"offset"`
+		Previous *string `json:"previous"`
+		Total    int     `json:"total"`
+		// spotify sends null for playlists it can no longer show
+		Items []*SpotifyPlaylistObject `json:"items"`
+	}
+
+	ArtistAlbumsParams struct {
+		ArtistID string
+		// any of the AlbumGroup* values, every group when empty
+		IncludeGroups []string
+		Market        string
+		Limit         int
+		Offset        int
+	}
+
+	SpotifyArtistTopTracksResponse struct {
+		Tracks []SpotifyTrackObject `json:"tracks"`
+	}
+
+	// RecommendationsParams takes up to five seeds across the three kinds.
+	RecommendationsParams struct {
+		SeedTracks  []string
+		SeedArtists []string
+		SeedGenres  []string
+		Market      string
+		Limit       int
+		// min_, max_ or target_ prefixed track attributes, e.g. target_energy
+		Attributes map[string]float64
+	}
+
+	SpotifyRecommendationsResponse struct {
+		Seeds  []SpotifyRecommendationSeedObject `json:"seeds"`
+		Tracks []SpotifyTrackObject              `json:"tracks"`
+	}
+
+	SpotifyRecommendationSeedObject struct {
+		ID   string `json:"id"`
+		// TRACK, ARTIST or GENRE
+		Type               string `json:"type"`
+		Href               string `json:"href"`
+		InitialPoolSize    int    `json:"initialPoolSize"`
+		AfterFilteringSize int    `json:"afterFilteringSize"`
+	}
+
+	SpotifySeveralTracksResponse struct {
+		Tracks 		[]*SpotifyTrackObject 	`json:"tracks"`
+	}
+
 	SpotifyTrackObject struct {
 		Album 		SpotifyAlbumObject 			`json:"album"`
 		Artists 	[]SpotifyArtisObject 		`json:"artists"`
@@ -29,9 +119,19 @@ type (
 		Href 			string 									`json:"href"`
 		ID 				string 									`json:"id"`
 		Name 			string 									`json:"name"`
+		DiscNumber 	int 										`json:"disc_number"`
+		TrackNumber int 										`json:"track_number"`
+		DurationMs 	int 										`json:"duration_ms"`
+		URI 				string 									`json:"uri"`
+		ExternalIDs SpotifyExternalIDsObject 	`json:"external_ids"`
+	}
+
+	SpotifyExternalIDsObject struct {
+		ISRC string `json:"isrc"`
 	}
 
 	SpotifyArtisObject struct {
+		ID 						string 								`json:"id"`
 		Name 					string 								`json:"name"`
 		Href 					string 								`json:"href"`
 	}
@@ -41,6 +141,65 @@ type (
 		TotalTracks 	int 								`json:"total_tracks"`
 		Images 				[]SpotifyImagesObject `json:"images"`
 		Name 					string 								`json:"name"`
+		ID 						string 								`json:"id"`
+		Href 					string 								`json:"href"`
+		ReleaseDate 	string 								`json:"release_date"`
+		// year, month or day, how much of ReleaseDate is known
+		ReleaseDatePrecision string 				`json:"release_date_precision"`
+		Artists 			[]SpotifyArtisObject 	`json:"artists"`
+	}
+
+	SpotifySimplifiedAlbumObject struct {
+		SpotifyAlbumObject
+		// only set on an artist's albums, how the album relates to the artist
+		AlbumGroup string `json:"album_group"`
+	}
+
+	// SpotifyFullAlbumObject carries the first page of the album's tracks,
+	// the rest is paged through GetAlbumTracks.
+	SpotifyFullAlbumObject struct {
+		SpotifyAlbumObject
+		Label      string                   `json:"label"`
+		Popularity int                      `json:"popularity"`
+		Copyrights []SpotifyCopyrightObject `json:"copyrights"`
+		Tracks     SpotifyTrack             `json:"tracks"`
+	}
+
+	SpotifyCopyrightObject struct {
+		Text string `json:"text"`
+		// C for the copyright, P for the sound recording copyright
+		Type string `json:"type"`
+	}
+
+	AlbumTracksParams struct {
+		AlbumID string
+		Market  string
+		Limit   int
+		Offset  int
+	}
+
+	SpotifyFullArtistObject struct {
+		SpotifyArtisObject
+		Genres     []string              `json:"genres"`
+		Images     []SpotifyImagesObject `json:"images"`
+		Popularity int                   `json:"popularity"`
+		Followers  struct {
+			Total int `json:"total"`
+		} `json:"followers"`
+	}
+
+	SpotifyPlaylistObject struct {
+		ID          string                `json:"id"`
+		Name        string                `json:"name"`
+		Description string                `json:"description"`
+		Href        string                `json:"href"`
+		Images      []SpotifyImagesObject `json:"images"`
+		Owner       struct {
+			DisplayName string `json:"display_name"`
+		} `json:"owner"`
+		Tracks struct {
+			Total int `json:"total"`
+		} `json:"tracks"`
 	}
 
 	SpotifyImagesObject struct {
@@ -48,6 +207,25 @@ type (
 	}
 )
 
+// album groups of an artist's albums
+const (
+	AlbumGroupAlbum       = "album"
+	AlbumGroupSingle      = "single"
+	AlbumGroupAppearsOn   = "appears_on"
+	AlbumGroupCompilation = "compilation"
+)
+
+// MaxRecommendationSeeds is how many seeds spotify accepts in total.
+const MaxRecommendationSeeds = 5
+
+// search types accepted by spotify
+const (
+	SearchTypeTrack    = "track"
+	SearchTypeAlbum    = "album"
+	SearchTypeArtist   = "artist"
+	SearchTypePlaylist = "playlist"
+)
+
 
 type spotifyRepository struct {
 	db *gorm.DB
@@ -61,13 +239,25 @@ func NewSpotifyRepository (db *gorm.DB) *spotifyRepository {
 
 //go:generate mockgen -source=repository.go -destination=../../services/spotify/service_mock_test.go -package=spotify
 type SpotifyOutbond interface {
-	Search(ctx context.Context, query string, limit, offset int) (*SpotifySearchResponse, error)
+	Search(ctx context.Context, params SearchParams) (*SpotifySearchResponse, error)
+	GetTrack(ctx context.Context, spotifyID string) (*SpotifyTrackObject, error)
+	GetSeveralTracks(ctx context.Context, spotifyIDs []string) ([]SpotifyTrackObject, error)
+	GetArtist(ctx context.Context, artistID string) (*SpotifyFullArtistObject, error)
+	GetArtistTopTracks(ctx context.Context, artistID, market string) ([]SpotifyTrackObject, error)
+	GetArtistAlbums(ctx context.Context, params ArtistAlbumsParams) (*SpotifyAlbums, error)
+	GetAlbum(ctx context.Context, albumID, market string) (*SpotifyFullAlbumObject, error)
+	GetAlbumTracks(ctx context.Context, params AlbumTracksParams) (*SpotifyTrack, error)
+	GetRecommendations(ctx context.Context, params RecommendationsParams) (*SpotifyRecommendationsResponse, error)
 }
 type SpotifyRepository interface {
 	Create(ctx context.Context, model spotify.TrackActivity) error
 	Update(ctx context.Context, model spotify.TrackActivity) error
 	Get(ctx context.Context, UserID uint, spotifyID string) (*spotify.TrackActivity, error)
 	GetBulkSpotifyIDs(ctx context.Context, UserID uint, spotifyIDs []string) (map[string]spotify.TrackActivity, error)
+	ListActivities(ctx context.Context, UserID uint, filter spotify.ActivityFilter) ([]spotify.TrackActivity, int64, error)
+	GetBulkTags(ctx context.Context, UserID uint, spotifyIDs []string) (map[string][]string, error)
+	ListAllActivities(ctx context.Context, UserID uint) ([]spotify.TrackActivity, error)
+	ListLikedBefore(ctx context.Context, UserID uint, beforeID uint, limit int) ([]spotify.TrackActivity, error)
 }
 
 func (r *spotifyRepository) Create(ctx context.Context, model spotify.TrackActivity) error {
@@ -104,3 +294,107 @@ func (r *spotifyRepository) GetBulkSpotifyIDs(ctx context.Context, UserID uint,
 
 	return result, nil
 }
+
+// GetBulkTags returns the names of the user's tags on each of the spotify
+// ids, sorted by name. Ids without tags are left out.
+func (r *spotifyRepository) GetBulkTags(ctx context.Context, UserID uint, spotifyIDs []string) (map[string][]string, error) {
+	var rows []struct {
+		SpotifyID string
+		Name      string
+	}
+
+	response := r.db.Table("track_tags").
+		Select("track_tags.spotify_id, tags.name").
+		Joins("JOIN tags ON tags.id = track_tags.tag_id").
+		Where("tags.user_id = ?", UserID).
+		Where("track_tags.spotify_id IN ?", spotifyIDs).
+		Order("tags.name ASC").
+		Scan(&rows)
+	if response.Error != nil {
+		return nil, response.Error
+	}
+
+	result := make(map[string][]string)
+	for _, row := range rows {
+		result[row.SpotifyID] = append(result[row.SpotifyID], row.Name)
+	}
+
+	return result, nil
+}
+
+func (r *spotifyRepository) ListActivities(ctx context.Context, UserID uint, filter spotify.ActivityFilter) ([]spotify.TrackActivity, int64, error) {
+	scope := func(db *gorm.DB) *gorm.DB {
+		// liked albums aren't part of the library
+		db = db.Where("user_id = ?", UserID).Where("entity_type = ?", spotify.EntityTypeTrack).Where("is_liked = ?", filter.IsLiked)
+		if filter.From != nil {
+			db = db.Where("liked_changed_at >= ?", *filter.From)
+		}
+		if filter.To != nil {
+			db = db.Where("liked_changed_at <= ?", *filter.To)
+		}
+		if filter.MinRating > 0 {
+			db = db.Where("rating >= ?", filter.MinRating)
+		}
+		if filter.Tag != "" {
+			db = db.Where("spotify_id IN (?)", r.db.Table("track_tags").
+				Select("track_tags.spotify_id").
+				Joins("JOIN tags ON tags.id = track_tags.tag_id").
+				Where("tags.user_id = ?", UserID).
+				Where("tags.name = ?", filter.Tag))
+		}
+
+		return db
+	}
+
+	var total int64
+	response := r.db.Model(&spotify.TrackActivity{}).Scopes(scope).Count(&total)
+	if response.Error != nil {
+		return nil, 0, response.Error
+	}
+
+	// ordered by when the like was set, a rating or note edit doesn't move it
+	order := "liked_changed_at DESC, id DESC"
+	if filter.SortAsc {
+		order = "liked_changed_at ASC, id ASC"
+	}
+
+	activities := []spotify.TrackActivity{}
+	response = r.db.Scopes(scope).Order(order).Limit(filter.Limit).Offset(filter.Offset).Find(&activities)
+	if response.Error != nil {
+		return nil, 0, response.Error
+	}
+
+	return activities, total, nil
+}
+
+// ListAllActivities returns every track activity of the user, oldest change
+// first.
+func (r *spotifyRepository) ListAllActivities(ctx context.Context, UserID uint) ([]spotify.TrackActivity, error) {
+	activities := []spotify.TrackActivity{}
+
+	response := r.db.Where("user_id = ?", UserID).Where("entity_type = ?", spotify.EntityTypeTrack).Order("updated_at ASC, id ASC").Find(&activities)
+	if response.Error != nil {
+		return nil, response.Error
+	}
+
+	return activities, nil
+}
+
+// ListLikedBefore pages through the user's liked tracks by descending id,
+// starting after beforeID, or from the newest when it is 0. Unlike
+// ListActivities it needs neither an offset nor a count, so rows liked or
+// unliked while paging don't shift later pages.
+func (r *spotifyRepository) ListLikedBefore(ctx context.Context, UserID uint, beforeID uint, limit int) ([]spotify.TrackActivity, error) {
+	db := r.db.Where("user_id = ?", UserID).Where("entity_type = ?", spotify.EntityTypeTrack).Where("is_liked = ?", true)
+	if beforeID > 0 {
+		db = db.Where("id < ?", beforeID)
+	}
+
+	activities := []spotify.TrackActivity{}
+	response := db.Order("id DESC").Limit(limit).Find(&activities)
+	if response.Error != nil {
+		return nil, response.Error
+	}
+
+	return activities, nil
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"reflect"
 	"strconv"
@@ -17,6 +18,7 @@ import (
 	"github.com/sgitwhyd/music-catalogue/internal/configs"
 	"github.com/sgitwhyd/music-catalogue/internal/models/spotify"
 	"github.com/sgitwhyd/music-catalogue/pkg/httpclient"
+	"github.com/sgitwhyd/music-catalogue/pkg/spotifyfake"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
 	"gorm.io/driver/postgres"
@@ -74,18 +76,35 @@ func Test_outbond_Search(t *testing.T) {
 										URL: "https://i.scdn.co/image/ab67616d00004851e8b066f70c206551210d902b",
 									},
 								},
-								Name: "Bohemian Rhapsody (The Original Soundtrack)",
+								Name:                 "Bohemian Rhapsody (The Original Soundtrack)",
+								ID:                   "6i6folBtxKV28WX3msQ4FE",
+								Href:                 "https://api.spotify.com/v1/albums/6i6folBtxKV28WX3msQ4FE",
+								ReleaseDate:          "2018-10-19",
+								ReleaseDatePrecision: "day",
+								Artists: []SpotifyArtisObject{
+									{
+										ID:   "1dfeR4HaWDbWqFHLkxsg1d",
+										Href: "https://api.spotify.com/v1/artists/1dfeR4HaWDbWqFHLkxsg1d",
+										Name: "Queen",
+									},
+								},
 							},
 							Artists: []SpotifyArtisObject{
 								{
+									ID:   "1dfeR4HaWDbWqFHLkxsg1d",
 									Href: "https://api.spotify.com/v1/artists/1dfeR4HaWDbWqFHLkxsg1d",
 									Name: "Queen",
 								},
 							},
-							Explicit: false,
-							Href:     "https://api.spotify.com/v1/tracks/3z8h0TU7ReDPLIbEnYhWZb",
-							ID:       "3z8h0TU7ReDPLIbEnYhWZb",
-							Name:     "Bohemian Rhapsody",
+							Explicit:    false,
+							Href:        "https://api.spotify.com/v1/tracks/3z8h0TU7ReDPLIbEnYhWZb",
+							ID:          "3z8h0TU7ReDPLIbEnYhWZb",
+							Name:        "Bohemian Rhapsody",
+							DiscNumber:  1,
+							TrackNumber: 7,
+							DurationMs:  354947,
+							URI:         "spotify:track:3z8h0TU7ReDPLIbEnYhWZb",
+							ExternalIDs: SpotifyExternalIDsObject{ISRC: "GBUM71029604"},
 						},
 						{
 							Album: SpotifyAlbumObject{
@@ -102,18 +121,35 @@ func Test_outbond_Search(t *testing.T) {
 										URL: "https://i.scdn.co/image/ab67616d00004851e319baafd16e84f0408af2a0",
 									},
 								},
-								Name: "A Night At The Opera (2011 Remaster)",
+								Name:                 "A Night At The Opera (2011 Remaster)",
+								ID:                   "1GbtB4zTqAsyfZEsm1RZfx",
+								Href:                 "https://api.spotify.com/v1/albums/1GbtB4zTqAsyfZEsm1RZfx",
+								ReleaseDate:          "1975-11-21",
+								ReleaseDatePrecision: "day",
+								Artists: []SpotifyArtisObject{
+									{
+										ID:   "1dfeR4HaWDbWqFHLkxsg1d",
+										Href: "https://api.spotify.com/v1/artists/1dfeR4HaWDbWqFHLkxsg1d",
+										Name: "Queen",
+									},
+								},
 							},
 							Artists: []SpotifyArtisObject{
 								{
+									ID:   "1dfeR4HaWDbWqFHLkxsg1d",
 									Href: "https://api.spotify.com/v1/artists/1dfeR4HaWDbWqFHLkxsg1d",
 									Name: "Queen",
 								},
 							},
-							Explicit: false,
-							Href:     "https://api.spotify.com/v1/tracks/4u7EnebtmKWzUH433cf5Qv",
-							ID:       "4u7EnebtmKWzUH433cf5Qv",
-							Name:     "Bohemian Rhapsody - Remastered 2011",
+							Explicit:    false,
+							Href:        "https://api.spotify.com/v1/tracks/4u7EnebtmKWzUH433cf5Qv",
+							ID:          "4u7EnebtmKWzUH433cf5Qv",
+							Name:        "Bohemian Rhapsody - Remastered 2011",
+							DiscNumber:  1,
+							TrackNumber: 11,
+							DurationMs:  354320,
+							URI:         "spotify:track:4u7EnebtmKWzUH433cf5Qv",
+							ExternalIDs: SpotifyExternalIDsObject{ISRC: "GBUM71029604"},
 						},
 					},
 				},
@@ -173,12 +209,14 @@ func Test_outbond_Search(t *testing.T) {
 			o := &outbond{
 				cfg:         &configs.Config{},
 				client:      mockHttpClient,
-				AccessToken: "accessToken",
-				TokenType:   "Bearer",
-				ExpiredAt:   time.Now().Add(1 * time.Hour),
+				tokens:      newStaticTokenSource("accessToken", "Bearer", time.Now().Add(1*time.Hour)),
 			}
 
-			got, err := o.Search(context.Background(), tt.args.query, tt.args.limit, tt.args.offset)
+			got, err := o.Search(context.Background(), SearchParams{
+				Query:  tt.args.query,
+				Limit:  tt.args.limit,
+				Offset: tt.args.offset,
+			})
 			if (err != nil) != tt.wantErr {
 				t.Errorf("outbond.Search() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -190,6 +228,435 @@ func Test_outbond_Search(t *testing.T) {
 	}
 }
 
+func Test_outbond_GetTrack(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockHttpClient := httpclient.NewMockHTTPClient(mockCtrl)
+
+	tests := []struct {
+		name    string
+		id      string
+		want    *SpotifyTrackObject
+		wantErr error
+		mockFn  func(id string)
+	}{
+		{
+			name: "success",
+			id:   "3z8h0TU7ReDPLIbEnYhWZb",
+			want: &SpotifyTrackObject{
+				Album: SpotifyAlbumObject{
+					AlbumType:   "album",
+					TotalTracks: 22,
+					Images: []SpotifyImagesObject{
+						{
+							URL: "https://i.scdn.co/image/ab67616d0000b273e8b066f70c206551210d902b",
+						},
+					},
+					Name:                 "Bohemian Rhapsody (The Original Soundtrack)",
+					ID:                   "6i6folBtxKV28WX3msQ4FE",
+					Href:                 "https://api.spotify.com/v1/albums/6i6folBtxKV28WX3msQ4FE",
+					ReleaseDate:          "2018-10-19",
+					ReleaseDatePrecision: "day",
+				},
+				Artists: []SpotifyArtisObject{
+					{
+						ID:   "1dfeR4HaWDbWqFHLkxsg1d",
+						Href: "https://api.spotify.com/v1/artists/1dfeR4HaWDbWqFHLkxsg1d",
+						Name: "Queen",
+					},
+				},
+				Explicit:    false,
+				Href:        "https://api.spotify.com/v1/tracks/3z8h0TU7ReDPLIbEnYhWZb",
+				ID:          "3z8h0TU7ReDPLIbEnYhWZb",
+				Name:        "Bohemian Rhapsody",
+				DiscNumber:  1,
+				TrackNumber: 7,
+				DurationMs:  354947,
+				URI:         "spotify:track:3z8h0TU7ReDPLIbEnYhWZb",
+				ExternalIDs: SpotifyExternalIDsObject{ISRC: "GBUM71029604"},
+			},
+			wantErr: nil,
+			mockFn: func(id string) {
+				req, err := http.NewRequest(http.MethodGet, "https://api.spotify.com/v1/tracks/"+id, nil)
+				assert.NoError(t, err)
+				req.Header.Set("Authorization", "Bearer accessToken")
+
+				mockHttpClient.EXPECT().Do(req).Return(&http.Response{
+					StatusCode: 200,
+					Body:       io.NopCloser(bytes.NewBufferString(trackResponse)),
+				}, nil)
+			},
+		},
+		{
+			name:    "not found",
+			id:      "unknown",
+			want:    nil,
+			wantErr: ErrNotFound,
+			mockFn: func(id string) {
+				req, err := http.NewRequest(http.MethodGet, "https://api.spotify.com/v1/tracks/"+id, nil)
+				assert.NoError(t, err)
+				req.Header.Set("Authorization", "Bearer accessToken")
+
+				mockHttpClient.EXPECT().Do(req).Return(&http.Response{
+					StatusCode: 404,
+					Body:       io.NopCloser(bytes.NewBufferString(`{"error":{"status":404,"message":"Not found."}}`)),
+				}, nil)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFn(tt.id)
+			o := &outbond{
+				cfg:         &configs.Config{},
+				client:      mockHttpClient,
+				tokens:      newStaticTokenSource("accessToken", "Bearer", time.Now().Add(1*time.Hour)),
+			}
+			got, err := o.GetTrack(context.Background(), tt.id)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_outbond_GetTrack_statusError(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockHttpClient := httpclient.NewMockHTTPClient(mockCtrl)
+	mockHttpClient.EXPECT().Do(gomock.Any()).Return(&http.Response{
+		StatusCode: http.StatusTooManyRequests,
+		Body:       io.NopCloser(bytes.NewBufferString(`{"error":{"status":429,"message":"API rate limit exceeded"}}`)),
+	}, nil)
+
+	o := &outbond{
+		cfg:    &configs.Config{},
+		client: mockHttpClient,
+		tokens: newStaticTokenSource("accessToken", "Bearer", time.Now().Add(1*time.Hour)),
+	}
+	_, err := o.GetTrack(context.Background(), "70LcF31zb1H0PyJoS1Sx1r")
+
+	var statusErr *StatusError
+	assert.ErrorAs(t, err, &statusErr)
+	assert.Equal(t, http.StatusTooManyRequests, statusErr.StatusCode)
+	assert.NotErrorIs(t, err, ErrNotFound)
+}
+
+func Test_outbond_GetSeveralTracks(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockHttpClient := httpclient.NewMockHTTPClient(mockCtrl)
+
+	tests := []struct {
+		name    string
+		ids     []string
+		wantIDs []string
+		wantErr bool
+		mockFn  func(ids []string)
+	}{
+		{
+			name:    "should chunk ids by 50 and skip unknown tracks",
+			ids:     makeIDs(51),
+			wantIDs: append(makeIDs(51)[:49], "id-50"),
+			wantErr: false,
+			mockFn: func(ids []string) {
+				gomock.InOrder(
+					mockHttpClient.EXPECT().Do(gomock.Any()).DoAndReturn(func(req *http.Request) (*http.Response, error) {
+						requested := strings.Split(req.URL.Query().Get("ids"), ",")
+						assert.Equal(t, ids[:50], requested)
+
+						// spotify answers null for ids it doesn't know
+						body := make([]string, len(requested))
+						for i, id := range requested {
+							body[i] = fmt.Sprintf(`{"id":%q}`, id)
+						}
+						body[49] = "null"
+
+						return &http.Response{
+							StatusCode: 200,
+							Body:       io.NopCloser(bytes.NewBufferString(`{"tracks":[` + strings.Join(body, ",") + `]}`)),
+						}, nil
+					}),
+					mockHttpClient.EXPECT().Do(gomock.Any()).DoAndReturn(func(req *http.Request) (*http.Response, error) {
+						assert.Equal(t, "id-50", req.URL.Query().Get("ids"))
+
+						return &http.Response{
+							StatusCode: 200,
+							Body:       io.NopCloser(bytes.NewBufferString(`{"tracks":[{"id":"id-50"}]}`)),
+						}, nil
+					}),
+				)
+			},
+		},
+		{
+			name:    "failed",
+			ids:     makeIDs(2),
+			wantErr: true,
+			mockFn: func(ids []string) {
+				mockHttpClient.EXPECT().Do(gomock.Any()).Return(&http.Response{
+					StatusCode: 500,
+					Body:       io.NopCloser(bytes.NewBufferString(`internal server error`)),
+				}, nil)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFn(tt.ids)
+			o := &outbond{
+				cfg:         &configs.Config{},
+				client:      mockHttpClient,
+				tokens:      newStaticTokenSource("accessToken", "Bearer", time.Now().Add(1*time.Hour)),
+			}
+			got, err := o.GetSeveralTracks(context.Background(), tt.ids)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("outbond.GetSeveralTracks() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			gotIDs := make([]string, 0, len(got))
+			for _, track := range got {
+				gotIDs = append(gotIDs, track.ID)
+			}
+			if !tt.wantErr {
+				assert.Equal(t, tt.wantIDs, gotIDs)
+			}
+		})
+	}
+}
+
+func Test_outbond_SpotifyFake(t *testing.T) {
+	fake, err := spotifyfake.NewDefault()
+	assert.NoError(t, err)
+
+	fake.ClientID = "client"
+	fake.ClientSecret = "secret"
+
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	o := NewSpotifyOutbond(&configs.Config{
+		SpotifyClientID:        "client",
+		SpotifyClientSecret:    "secret",
+		SpotifyAPIBaseURL:      server.URL + "/v1",
+		SpotifyAccountsBaseURL: server.URL,
+	}, httpclient.NewClient(server.Client()))
+
+	search, err := o.Search(context.Background(), SearchParams{Query: "bohemian rhapsody", Limit: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, search.Tracks.Total)
+	assert.Len(t, search.Tracks.Items, 1)
+	assert.Equal(t, "3z8h0TU7ReDPLIbEnYhWZb", search.Tracks.Items[0].ID)
+	assert.NotNil(t, search.Tracks.Next)
+	assert.Nil(t, search.Albums)
+
+	search, err = o.Search(context.Background(), SearchParams{Query: "daft punk", Limit: 10, Types: []string{SearchTypeAlbum, SearchTypeArtist}})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, search.Tracks.Total)
+	assert.Equal(t, 2, search.Albums.Total)
+	assert.Equal(t, "Daft Punk", search.Albums.Items[0].Artists[0].Name)
+	assert.Equal(t, 1, search.Artists.Total)
+	assert.Equal(t, "4tZwfgrHOc3mvqYlEYSvVi", search.Artists.Items[0].ID)
+
+	track, err := o.GetTrack(context.Background(), "70LcF31zb1H0PyJoS1Sx1r")
+	assert.NoError(t, err)
+	assert.Equal(t, "Creep", track.Name)
+
+	_, err = o.GetTrack(context.Background(), "unknown")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	tracks, err := o.GetSeveralTracks(context.Background(), []string{"0DiWol3AO6WpXZgp0goxAV", "unknown", "70LcF31zb1H0PyJoS1Sx1r"})
+	assert.NoError(t, err)
+	assert.Len(t, tracks, 2)
+
+	artist, err := o.GetArtist(context.Background(), "4tZwfgrHOc3mvqYlEYSvVi")
+	assert.NoError(t, err)
+	assert.Equal(t, "Daft Punk", artist.Name)
+
+	_, err = o.GetArtist(context.Background(), "unknown")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	topTracks, err := o.GetArtistTopTracks(context.Background(), "4tZwfgrHOc3mvqYlEYSvVi", "ID")
+	assert.NoError(t, err)
+	assert.Len(t, topTracks, 3)
+
+	albums, err := o.GetArtistAlbums(context.Background(), ArtistAlbumsParams{
+		ArtistID:      "4tZwfgrHOc3mvqYlEYSvVi",
+		IncludeGroups: []string{AlbumGroupAlbum},
+		Limit:         1,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, albums.Total)
+	assert.Len(t, albums.Items, 1)
+	assert.Equal(t, AlbumGroupAlbum, albums.Items[0].AlbumGroup)
+
+	album, err := o.GetAlbum(context.Background(), "2noRn2Aes5aoNVsU6iWThc", "ID")
+	assert.NoError(t, err)
+	assert.Equal(t, "Discovery", album.Name)
+	assert.Equal(t, "day", album.ReleaseDatePrecision)
+	assert.Equal(t, 2, album.Tracks.Total)
+	assert.Equal(t, "0DiWol3AO6WpXZgp0goxAV", album.Tracks.Items[0].ID)
+
+	_, err = o.GetAlbum(context.Background(), "unknown", "")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	albumTracks, err := o.GetAlbumTracks(context.Background(), AlbumTracksParams{
+		AlbumID: "2noRn2Aes5aoNVsU6iWThc",
+		Limit:   1,
+		Offset:  1,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, albumTracks.Total)
+	assert.Len(t, albumTracks.Items, 1)
+	assert.Equal(t, 4, albumTracks.Items[0].TrackNumber)
+
+	recommendations, err := o.GetRecommendations(context.Background(), RecommendationsParams{
+		SeedTracks: []string{"0DiWol3AO6WpXZgp0goxAV"},
+		Limit:      2,
+		Attributes: map[string]float64{"min_popularity": 78, "target_danceability": 0.8},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "TRACK", recommendations.Seeds[0].Type)
+	assert.Len(t, recommendations.Tracks, 1)
+	assert.Equal(t, "69kOkLUCkxIZYexIgSG8rq", recommendations.Tracks[0].ID)
+}
+
+type userTokenSourceFunc func(ctx context.Context, userID uint) (string, string, error)
+
+func (f userTokenSourceFunc) UserToken(ctx context.Context, userID uint) (string, string, error) {
+	return f(ctx, userID)
+}
+
+func Test_outbond_SpotifyFake_UserToken(t *testing.T) {
+	fake, err := spotifyfake.NewDefault()
+	assert.NoError(t, err)
+
+	fake.ClientID = "client"
+	fake.ClientSecret = "secret"
+
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	o := NewSpotifyOutbond(&configs.Config{
+		SpotifyClientID:        "client",
+		SpotifyClientSecret:    "secret",
+		SpotifyAPIBaseURL:      server.URL + "/v1",
+		SpotifyAccountsBaseURL: server.URL,
+	}, httpclient.NewClient(server.Client()))
+
+	redirectURI := "http://localhost:8080/api/v1/spotify/callback"
+	verifier := "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+
+	authorizeURL := o.AuthorizeURL(AuthorizeParams{
+		State:         "state",
+		CodeChallenge: "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM",
+		RedirectURI:   redirectURI,
+		Scopes:        []string{"user-library-read"},
+	})
+
+	// stop at the redirect back to the app, that's where the code is
+	client := server.Client()
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+
+	resp, err := client.Get(authorizeURL)
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusFound, resp.StatusCode)
+
+	callback, err := url.Parse(resp.Header.Get("Location"))
+	assert.NoError(t, err)
+	assert.Equal(t, "state", callback.Query().Get("state"))
+
+	code := callback.Query().Get("code")
+	assert.NotEmpty(t, code)
+
+	_, err = o.ExchangeCode(context.Background(), code, "wrong-verifier", redirectURI)
+	assert.ErrorIs(t, err, ErrInvalidGrant)
+
+	// a failed exchange burns the code, start over
+	resp, err = client.Get(authorizeURL)
+	assert.NoError(t, err)
+	resp.Body.Close()
+
+	callback, err = url.Parse(resp.Header.Get("Location"))
+	assert.NoError(t, err)
+
+	token, err := o.ExchangeCode(context.Background(), callback.Query().Get("code"), verifier, redirectURI)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, token.AccessToken)
+	assert.NotEmpty(t, token.RefreshToken)
+	assert.Equal(t, "user-library-read", token.Scope)
+
+	profile, err := o.GetCurrentUser(context.Background(), token.AccessToken)
+	assert.NoError(t, err)
+	assert.Equal(t, spotifyfake.UserID, profile.ID)
+
+	// without a source the outbond can't act for a user
+	_, err = o.Search(WithUserToken(context.Background(), 1), SearchParams{Query: "creep", Limit: 1})
+	assert.ErrorIs(t, err, ErrNoUserTokenSource)
+
+	o.SetUserTokenSource(userTokenSourceFunc(func(ctx context.Context, userID uint) (string, string, error) {
+		assert.Equal(t, uint(1), userID)
+		return token.AccessToken, token.TokenType, nil
+	}))
+
+	search, err := o.Search(WithUserToken(context.Background(), 1), SearchParams{Query: "creep", Limit: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, "70LcF31zb1H0PyJoS1Sx1r", search.Tracks.Items[0].ID)
+
+	// the library of the linked account
+	userCtx := WithUserToken(context.Background(), 1)
+	fake.SaveTracks(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), "70LcF31zb1H0PyJoS1Sx1r")
+
+	_, err = o.GetSavedTracks(context.Background(), 50, 0)
+	assert.Error(t, err, "the app token has no library")
+
+	// more ids than spotify takes at once, unknown ones are ignored
+	ids := append(makeIDs(maxTracksPerRequest), "0DiWol3AO6WpXZgp0goxAV")
+	assert.NoError(t, o.SaveTracks(userCtx, ids))
+
+	saved, err := o.GetSavedTracks(userCtx, 1, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, saved.Total)
+	assert.NotNil(t, saved.Next)
+	assert.Equal(t, "0DiWol3AO6WpXZgp0goxAV", saved.Items[0].Track.ID)
+
+	saved, err = o.GetSavedTracks(userCtx, 1, 1)
+	assert.NoError(t, err)
+	assert.Nil(t, saved.Next)
+	assert.Equal(t, "70LcF31zb1H0PyJoS1Sx1r", saved.Items[0].Track.ID)
+	assert.Equal(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), saved.Items[0].AddedAt)
+
+	assert.NoError(t, o.RemoveSavedTracks(userCtx, []string{"70LcF31zb1H0PyJoS1Sx1r"}))
+	assert.Equal(t, []string{"0DiWol3AO6WpXZgp0goxAV"}, fake.SavedTrackIDs())
+
+	refreshed, err := o.RefreshUserToken(context.Background(), token.RefreshToken)
+	assert.NoError(t, err)
+	assert.NotEqual(t, token.AccessToken, refreshed.AccessToken)
+
+	// the refresh token was rotated
+	_, err = o.RefreshUserToken(context.Background(), token.RefreshToken)
+	assert.ErrorIs(t, err, ErrInvalidGrant)
+}
+
+func makeIDs(n int) []string {
+	ids := make([]string, n)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("id-%d", i)
+	}
+
+	return ids
+}
+
 func Test_spotifyRepository_Create(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	assert.NoError(t, err)
@@ -218,9 +685,10 @@ func Test_spotifyRepository_Create(t *testing.T) {
 						CreatedAt: now,
 						UpdatedAt: now,
 					},
-					UserID:    1,
-					SpotifyID: "spotifyID",
-					IsLiked:   &isLiked,
+					UserID:     1,
+					SpotifyID:  "spotifyID",
+					EntityType: spotify.EntityTypeTrack,
+					IsLiked:    &isLiked,
 				},
 			},
 			wantErr: false,
@@ -232,7 +700,11 @@ func Test_spotifyRepository_Create(t *testing.T) {
 					sqlmock.AnyArg(),
 					args.model.UserID,
 					args.model.SpotifyID,
+					args.model.EntityType,
 					args.model.IsLiked,
+					args.model.LikedChangedAt,
+					args.model.Rating,
+					args.model.Note,
 				).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
 				mock.ExpectCommit()
 			},
@@ -245,9 +717,10 @@ func Test_spotifyRepository_Create(t *testing.T) {
 						CreatedAt: now,
 						UpdatedAt: now,
 					},
-					UserID:    1,
-					SpotifyID: "spotifyID",
-					IsLiked:   &isLiked,
+					UserID:     1,
+					SpotifyID:  "spotifyID",
+					EntityType: spotify.EntityTypeTrack,
+					IsLiked:    &isLiked,
 				},
 			},
 			wantErr: true,
@@ -259,7 +732,11 @@ func Test_spotifyRepository_Create(t *testing.T) {
 					sqlmock.AnyArg(),
 					args.model.UserID,
 					args.model.SpotifyID,
+					args.model.EntityType,
 					args.model.IsLiked,
+					args.model.LikedChangedAt,
+					args.model.Rating,
+					args.model.Note,
 				).WillReturnError(assert.AnError)
 				mock.ExpectRollback()
 			},
@@ -291,6 +768,8 @@ func Test_spotifyRepository_Update(t *testing.T) {
 
 	now := time.Now()
 	isLiked := true
+	rating := 4
+	note := "great bridge"
 
 	type args struct {
 		model spotify.TrackActivity
@@ -311,9 +790,12 @@ func Test_spotifyRepository_Update(t *testing.T) {
 						CreatedAt: now,
 						UpdatedAt: now,
 					},
-					UserID:    1,
-					SpotifyID: "spotifyID",
-					IsLiked:   &isLiked,
+					UserID:     1,
+					SpotifyID:  "spotifyID",
+					EntityType: spotify.EntityTypeTrack,
+					IsLiked:    &isLiked,
+					Rating:    &rating,
+					Note:      &note,
 				},
 			},
 			wantErr: false,
@@ -325,7 +807,11 @@ func Test_spotifyRepository_Update(t *testing.T) {
 					sqlmock.AnyArg(),
 					args.model.UserID,
 					args.model.SpotifyID,
+					args.model.EntityType,
 					args.model.IsLiked,
+					args.model.LikedChangedAt,
+					args.model.Rating,
+					args.model.Note,
 					args.model.ID,
 				).WillReturnResult(sqlmock.NewResult(123, 1))
 				mock.ExpectCommit()
@@ -340,9 +826,10 @@ func Test_spotifyRepository_Update(t *testing.T) {
 						CreatedAt: now,
 						UpdatedAt: now,
 					},
-					UserID:    1,
-					SpotifyID: "spotifyID",
-					IsLiked:   &isLiked,
+					UserID:     1,
+					SpotifyID:  "spotifyID",
+					EntityType: spotify.EntityTypeTrack,
+					IsLiked:    &isLiked,
 				},
 			},
 			wantErr: true,
@@ -354,7 +841,11 @@ func Test_spotifyRepository_Update(t *testing.T) {
 					sqlmock.AnyArg(),
 					args.model.UserID,
 					args.model.SpotifyID,
+					args.model.EntityType,
 					args.model.IsLiked,
+					args.model.LikedChangedAt,
+					args.model.Rating,
+					args.model.Note,
 					args.model.ID,
 				).WillReturnError(assert.AnError)
 				mock.ExpectRollback()
@@ -564,3 +1055,217 @@ func Test_spotifyRepository_GetBulkSpotifyIDs(t *testing.T) {
 		})
 	}
 }
+
+func Test_spotifyRepository_ListActivities(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	gormDB, err := gorm.Open(postgres.New(postgres.Config{Conn: db}))
+	assert.NoError(t, err)
+
+	now := time.Now()
+	from := now.Add(-24 * time.Hour)
+	isLiked := true
+	rating := 5
+
+	type args struct {
+		UserID uint
+		filter spotify.ActivityFilter
+	}
+	tests := []struct {
+		name      string
+		args      args
+		want      []spotify.TrackActivity
+		wantTotal int64
+		wantErr   bool
+		mockFn    func(args args)
+	}{
+		{
+			name: "success",
+			args: args{
+				UserID: 1,
+				filter: spotify.ActivityFilter{
+					IsLiked: true,
+					From:    &from,
+					Limit:   10,
+					Offset:  10,
+				},
+			},
+			want: []spotify.TrackActivity{
+				{
+					Model: gorm.Model{
+						ID:        1,
+						CreatedAt: now,
+						UpdatedAt: now,
+					},
+					UserID:     1,
+					SpotifyID:  "spotifyID",
+					EntityType: spotify.EntityTypeTrack,
+					IsLiked:    &isLiked,
+				},
+			},
+			wantTotal: 11,
+			wantErr:   false,
+			mockFn: func(args args) {
+				mock.ExpectQuery(`SELECT count\(\*\) FROM "track_activities" WHERE user_id = \$1 AND entity_type = \$2 AND is_liked = \$3 AND liked_changed_at >= \$4`).
+					WithArgs(args.UserID, spotify.EntityTypeTrack, true, from).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(11))
+				mock.ExpectQuery(`SELECT \* FROM "track_activities" WHERE .+ ORDER BY liked_changed_at DESC, id DESC LIMIT \$5 OFFSET \$6`).
+					WithArgs(args.UserID, spotify.EntityTypeTrack, true, from, 10, 10).
+					WillReturnRows(sqlmock.NewRows(
+						[]string{"id", "created_at", "updated_at", "user_id", "spotify_id", "entity_type", "is_liked"},
+					).AddRow(1, now, now, args.UserID, "spotifyID", spotify.EntityTypeTrack, true))
+			},
+		},
+		{
+			name: "min rating",
+			args: args{
+				UserID: 1,
+				filter: spotify.ActivityFilter{
+					IsLiked:   true,
+					MinRating: 4,
+					Limit:     10,
+				},
+			},
+			want: []spotify.TrackActivity{
+				{
+					Model: gorm.Model{
+						ID:        1,
+						CreatedAt: now,
+						UpdatedAt: now,
+					},
+					UserID:     1,
+					SpotifyID:  "spotifyID",
+					EntityType: spotify.EntityTypeTrack,
+					IsLiked:    &isLiked,
+					Rating:     &rating,
+				},
+			},
+			wantTotal: 1,
+			wantErr:   false,
+			mockFn: func(args args) {
+				mock.ExpectQuery(`SELECT count\(\*\) FROM "track_activities" WHERE user_id = \$1 AND entity_type = \$2 AND is_liked = \$3 AND rating >= \$4`).
+					WithArgs(args.UserID, spotify.EntityTypeTrack, true, 4).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+				mock.ExpectQuery(`SELECT \* FROM "track_activities" WHERE .+ ORDER BY liked_changed_at DESC, id DESC LIMIT \$5`).
+					WithArgs(args.UserID, spotify.EntityTypeTrack, true, 4, 10).
+					WillReturnRows(sqlmock.NewRows(
+						[]string{"id", "created_at", "updated_at", "user_id", "spotify_id", "entity_type", "is_liked", "rating"},
+					).AddRow(1, now, now, args.UserID, "spotifyID", spotify.EntityTypeTrack, true, 5))
+			},
+		},
+		{
+			name: "tag",
+			args: args{
+				UserID: 1,
+				filter: spotify.ActivityFilter{
+					IsLiked: true,
+					Tag:     "workout",
+					Limit:   10,
+				},
+			},
+			want:      []spotify.TrackActivity{},
+			wantTotal: 0,
+			wantErr:   false,
+			mockFn: func(args args) {
+				mock.ExpectQuery(`SELECT count\(\*\) FROM "track_activities" WHERE user_id = \$1 AND entity_type = \$2 AND is_liked = \$3 AND spotify_id IN \(SELECT track_tags.spotify_id FROM "track_tags" JOIN tags ON tags.id = track_tags.tag_id WHERE tags.user_id = \$4 AND tags.name = \$5\)`).
+					WithArgs(args.UserID, spotify.EntityTypeTrack, true, args.UserID, "workout").
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+				mock.ExpectQuery(`SELECT \* FROM "track_activities" WHERE .+ ORDER BY liked_changed_at DESC, id DESC LIMIT \$6`).
+					WithArgs(args.UserID, spotify.EntityTypeTrack, true, args.UserID, "workout", 10).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}))
+			},
+		},
+		{
+			name: "failed",
+			args: args{
+				UserID: 1,
+				filter: spotify.ActivityFilter{
+					IsLiked: false,
+					SortAsc: true,
+					Limit:   10,
+				},
+			},
+			want:    nil,
+			wantErr: true,
+			mockFn: func(args args) {
+				mock.ExpectQuery(`SELECT count\(\*\) FROM "track_activities" .+`).
+					WithArgs(args.UserID, spotify.EntityTypeTrack, false).
+					WillReturnError(assert.AnError)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFn(tt.args)
+			r := &spotifyRepository{
+				db: gormDB,
+			}
+			got, total, err := r.ListActivities(context.Background(), tt.args.UserID, tt.args.filter)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("spotifyRepository.ListActivities() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("spotifyRepository.ListActivities() = %v, want %v", got, tt.want)
+			}
+			assert.Equal(t, tt.wantTotal, total)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func Test_spotifyRepository_GetBulkTags(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	gormDB, err := gorm.Open(postgres.New(postgres.Config{Conn: db}))
+	assert.NoError(t, err)
+
+	mock.ExpectQuery(`SELECT track_tags.spotify_id, tags.name FROM "track_tags" JOIN tags ON tags.id = track_tags.tag_id WHERE tags.user_id = \$1 AND track_tags.spotify_id IN \(\$2,\$3\) ORDER BY tags.name ASC`).
+		WithArgs(1, "a", "b").
+		WillReturnRows(sqlmock.NewRows([]string{"spotify_id", "name"}).
+			AddRow("a", "focus").
+			AddRow("b", "focus").
+			AddRow("a", "workout"))
+
+	r := &spotifyRepository{
+		db: gormDB,
+	}
+
+	got, err := r.GetBulkTags(context.Background(), 1, []string{"a", "b"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]string{
+		"a": {"focus", "workout"},
+		"b": {"focus"},
+	}, got)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func Test_spotifyRepository_ListLikedBefore(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	gormDB, err := gorm.Open(postgres.New(postgres.Config{Conn: db}))
+	assert.NoError(t, err)
+
+	// no count and no offset, the page starts right after the last id seen
+	mock.ExpectQuery(`SELECT \* FROM "track_activities" WHERE user_id = \$1 AND entity_type = \$2 AND is_liked = \$3 AND id < \$4 AND "track_activities"."deleted_at" IS NULL ORDER BY id DESC LIMIT \$5`).
+		WithArgs(1, spotify.EntityTypeTrack, true, 40, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "spotify_id"}).
+			AddRow(39, "a").
+			AddRow(37, "b"))
+
+	r := &spotifyRepository{
+		db: gormDB,
+	}
+
+	got, err := r.ListLikedBefore(context.Background(), 1, 40, 2)
+	assert.NoError(t, err)
+	assert.Len(t, got, 2)
+	assert.Equal(t, uint(37), got[1].ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -165,4 +165,46 @@ var searchResponse = `{
     "previous": null,
     "total": 905
   }
-}`
\ No newline at end of file
+}`
+var trackResponse = `{
+  "album": {
+    "album_type": "album",
+    "href": "https://api.spotify.com/v1/albums/6i6folBtxKV28WX3msQ4FE",
+    "id": "6i6folBtxKV28WX3msQ4FE",
+    "images": [
+      {
+        "height": 640,
+        "url": "https://i.scdn.co/image/ab67616d0000b273e8b066f70c206551210d902b",
+        "width": 640
+      }
+    ],
+    "name": "Bohemian Rhapsody (The Original Soundtrack)",
+    "release_date": "2018-10-19",
+    "release_date_precision": "day",
+    "total_tracks": 22,
+    "type": "album",
+    "uri": "spotify:album:6i6folBtxKV28WX3msQ4FE"
+  },
+  "artists": [
+    {
+      "href": "https://api.spotify.com/v1/artists/1dfeR4HaWDbWqFHLkxsg1d",
+      "id": "1dfeR4HaWDbWqFHLkxsg1d",
+      "name": "Queen",
+      "type": "artist",
+      "uri": "spotify:artist:1dfeR4HaWDbWqFHLkxsg1d"
+    }
+  ],
+  "disc_number": 1,
+  "duration_ms": 354947,
+  "explicit": false,
+  "external_ids": {
+    "isrc": "GBUM71029604"
+  },
+  "href": "https://api.spotify.com/v1/tracks/3z8h0TU7ReDPLIbEnYhWZb",
+  "id": "3z8h0TU7ReDPLIbEnYhWZb",
+  "name": "Bohemian Rhapsody",
+  "popularity": 72,
+  "track_number": 7,
+  "type": "track",
+  "uri": "spotify:track:3z8h0TU7ReDPLIbEnYhWZb"
+}`
@@ -1,14 +1,27 @@
 package spotify
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/rs/zerolog/log"
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	// refresh the app token this long before spotify expires it
+	tokenRefreshWindow = time.Minute
+
+	// bounds a token request that is shared by every waiting caller
+	tokenFetchTimeout = 10 * time.Second
 )
 
 type (
@@ -16,60 +29,152 @@ type (
 		AccessToken string `json:"access_token"`
 		TokenType   string `json:"token_type"`
 		ExpiresIn   int    `json:"expires_in"`
+		// only set for user tokens
+		RefreshToken string `json:"refresh_token"`
+		Scope        string `json:"scope"`
+	}
+
+	appToken struct {
+		AccessToken string
+		TokenType   string
+		ExpiredAt   time.Time
 	}
 )
 
-func (o *outbond) GetTokenDetails() (string, string, error) {
+// tokenSource caches the client credentials token. It is safe for
+// concurrent use: readers share the cached token, and any number of callers
+// that need a new one wait on a single outbound token request.
+type tokenSource struct {
+	fetch         func(ctx context.Context) (appToken, error)
+	now           func() time.Time
+	refreshWindow time.Duration
 
-	if o.AccessToken == "" || time.Now().After(o.ExpiredAt) {
-		// call spotify get token here
-		err := o.generateToken()
-		if err != nil {
-			return "", "", err
+	mu    sync.RWMutex
+	token appToken
+
+	group      singleflight.Group
+	refreshing atomic.Bool
+}
+
+func newTokenSource(fetch func(ctx context.Context) (appToken, error)) *tokenSource {
+	return &tokenSource{
+		fetch:         fetch,
+		now:           time.Now,
+		refreshWindow: tokenRefreshWindow,
+	}
+}
+
+// Token returns a usable token. A token close to expiry is still returned
+// while a refresh runs in the background; an expired one blocks until the
+// refresh completes or ctx is done.
+func (s *tokenSource) Token(ctx context.Context) (appToken, error) {
+	s.mu.RLock()
+	token := s.token
+	s.mu.RUnlock()
+
+	now := s.now()
+	if token.AccessToken != "" && now.Before(token.ExpiredAt) {
+		if now.Before(token.ExpiredAt.Add(-s.refreshWindow)) {
+			return token, nil
 		}
+
+		if s.refreshing.CompareAndSwap(false, true) {
+			go func() {
+				defer s.refreshing.Store(false)
+
+				if _, err := s.refresh(context.Background()); err != nil {
+					log.Error().Err(err).Msg("error refresh spotify token in background")
+				}
+			}()
+		}
+
+		return token, nil
 	}
 
-	return o.AccessToken, o.TokenType, nil
+	return s.refresh(ctx)
 }
 
-func (o *outbond) generateToken() error {
+func (s *tokenSource) refresh(ctx context.Context) (appToken, error) {
+	result := s.group.DoChan("token", func() (any, error) {
+		// detached from the first caller, a cancelled request must not fail
+		// everybody else waiting on the same refresh
+		fetchCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), tokenFetchTimeout)
+		defer cancel()
 
+		token, err := s.fetch(fetchCtx)
+		if err != nil {
+			return appToken{}, err
+		}
+
+		s.mu.Lock()
+		s.token = token
+		s.mu.Unlock()
+
+		return token, nil
+	})
+
+	select {
+	case res := <-result:
+		if res.Err != nil {
+			return appToken{}, res.Err
+		}
+
+		return res.Val.(appToken), nil
+	case <-ctx.Done():
+		return appToken{}, ctx.Err()
+	}
+}
+
+func (o *outbond) GetTokenDetails(ctx context.Context) (string, string, error) {
+	token, err := o.tokens.Token(ctx)
+	if err != nil {
+		return "", "", err
+	}
+
+	return token.AccessToken, token.TokenType, nil
+}
+
+func (o *outbond) generateToken(ctx context.Context) (appToken, error) {
 	if o.client == nil {
-		return errors.New("http client is nil")
+		return appToken{}, errors.New("http client is nil")
 	}
 
 	formData := url.Values{}
 	formData.Set("grant_type", "client_credentials")
 	formData.Set("client_id", o.cfg.SpotifyClientID)
 	formData.Set("client_secret", o.cfg.SpotifyClientSecret)
-
 	encodedUrl := formData.Encode()
 
-	req, err := http.NewRequest(http.MethodPost, `https://accounts.spotify.com/api/token`, strings.NewReader(encodedUrl))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, o.accountsURL("/api/token"), strings.NewReader(encodedUrl))
 	if err != nil {
 		log.Error().Err(err).Msg("error create request spotify token")
-		return err
+		return appToken{}, err
 	}
+
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 
 	resp, err := o.client.Do(req)
 	if err != nil {
 		log.Error().Err(err).Msg("error execute spotify token")
-		return err
+		return appToken{}, err
 	}
 
 	defer resp.Body.Close()
 
+	if resp.StatusCode != http.StatusOK {
+		return appToken{}, fmt.Errorf("spotify token: unexpected status code %d", resp.StatusCode)
+	}
+
 	var response SpotifyTokenResponse
 	err = json.NewDecoder(resp.Body).Decode(&response)
 	if err != nil {
 		log.Error().Err(err).Msg("error decoded spotify token response")
-		return err
+		return appToken{}, err
 	}
 
-	o.AccessToken = response.AccessToken
-	o.TokenType = response.TokenType
-	o.ExpiredAt = time.Now().Add(time.Duration(response.ExpiresIn) * time.Second)
-	
-	return nil
-}
\ No newline at end of file
+	return appToken{
+		AccessToken: response.AccessToken,
+		TokenType:   response.TokenType,
+		ExpiredAt:   time.Now().Add(time.Duration(response.ExpiresIn) * time.Second),
+	}, nil
+}
@@ -0,0 +1,160 @@
+package spotify
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newStaticTokenSource returns a source holding token that fails to refresh.
+func newStaticTokenSource(accessToken, tokenType string, expiredAt time.Time) *tokenSource {
+	s := newTokenSource(func(ctx context.Context) (appToken, error) {
+		return appToken{}, errors.New("static token source can't refresh")
+	})
+	s.token = appToken{
+		AccessToken: accessToken,
+		TokenType:   tokenType,
+		ExpiredAt:   expiredAt,
+	}
+
+	return s
+}
+
+func Test_tokenSource_Token(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		cached     appToken
+		fetchErr   error
+		want       string
+		wantErr    bool
+		wantFetchs int32
+	}{
+		{
+			name:       "should reuse a fresh token",
+			cached:     appToken{AccessToken: "cached", TokenType: "Bearer", ExpiredAt: now.Add(time.Hour)},
+			want:       "cached",
+			wantFetchs: 0,
+		},
+		{
+			name:       "should fetch when no token cached",
+			cached:     appToken{},
+			want:       "fetched",
+			wantFetchs: 1,
+		},
+		{
+			name:       "should fetch when token expired",
+			cached:     appToken{AccessToken: "cached", TokenType: "Bearer", ExpiredAt: now.Add(-time.Second)},
+			want:       "fetched",
+			wantFetchs: 1,
+		},
+		{
+			name:       "should serve token near expiry while refreshing in background",
+			cached:     appToken{AccessToken: "cached", TokenType: "Bearer", ExpiredAt: now.Add(30 * time.Second)},
+			want:       "cached",
+			wantFetchs: 1,
+		},
+		{
+			name:       "should fail when fetch fails",
+			cached:     appToken{},
+			fetchErr:   errors.New("spotify down"),
+			wantErr:    true,
+			wantFetchs: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var fetchs atomic.Int32
+			done := make(chan struct{}, 1)
+
+			s := newTokenSource(func(ctx context.Context) (appToken, error) {
+				fetchs.Add(1)
+				defer func() { done <- struct{}{} }()
+
+				if tt.fetchErr != nil {
+					return appToken{}, tt.fetchErr
+				}
+
+				return appToken{AccessToken: "fetched", TokenType: "Bearer", ExpiredAt: now.Add(time.Hour)}, nil
+			})
+			s.now = func() time.Time { return now }
+			s.token = tt.cached
+
+			got, err := s.Token(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Errorf("tokenSource.Token() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if tt.wantFetchs > 0 {
+				select {
+				case <-done:
+				case <-time.After(time.Second):
+					t.Fatal("token was never fetched")
+				}
+			}
+
+			assert.Equal(t, tt.want, got.AccessToken)
+			assert.Equal(t, tt.wantFetchs, fetchs.Load())
+		})
+	}
+}
+
+func Test_tokenSource_Token_SingleFlight(t *testing.T) {
+	var fetchs atomic.Int32
+	release := make(chan struct{})
+
+	s := newTokenSource(func(ctx context.Context) (appToken, error) {
+		fetchs.Add(1)
+		<-release
+
+		return appToken{AccessToken: "fetched", TokenType: "Bearer", ExpiredAt: time.Now().Add(time.Hour)}, nil
+	})
+
+	const callers = 50
+
+	var wg sync.WaitGroup
+	tokens := make([]string, callers)
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			token, err := s.Token(context.Background())
+			assert.NoError(t, err)
+			tokens[i] = token.AccessToken
+		}(i)
+	}
+
+	// let every caller queue up behind the in-flight refresh
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), fetchs.Load())
+	for _, token := range tokens {
+		assert.Equal(t, "fetched", token)
+	}
+}
+
+func Test_tokenSource_Token_ContextDone(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+
+	s := newTokenSource(func(ctx context.Context) (appToken, error) {
+		<-release
+		return appToken{AccessToken: "fetched"}, nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err := s.Token(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
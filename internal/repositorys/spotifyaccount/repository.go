@@ -0,0 +1,108 @@
+package spotifyaccount
+
+import (
+	"context"
+	"time"
+
+	"github.com/sgitwhyd/music-catalogue/internal/models/spotifyaccount"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type spotifyAccountRepository struct {
+	db *gorm.DB
+}
+
+func NewSpotifyAccountRepository(db *gorm.DB) *spotifyAccountRepository {
+	return &spotifyAccountRepository{
+		db: db,
+	}
+}
+
+//go:generate mockgen -source=repository.go -destination=../../services/spotifyaccount/service_mock_test.go -package=spotifyaccount
+type SpotifyAccountRepository interface {
+	CreateState(ctx context.Context, model *spotifyaccount.SpotifyAuthState) error
+	TakeState(ctx context.Context, UserID uint, state string) (*spotifyaccount.SpotifyAuthState, error)
+	Create(ctx context.Context, model *spotifyaccount.SpotifyAccount) error
+	Update(ctx context.Context, model *spotifyaccount.SpotifyAccount) error
+	Get(ctx context.Context, UserID uint) (*spotifyaccount.SpotifyAccount, error)
+	Delete(ctx context.Context, UserID uint) error
+	ListUserIDs(ctx context.Context) ([]uint, error)
+}
+
+// CreateState stores a new state and clears the ones that expired without
+// a callback.
+func (r *spotifyAccountRepository) CreateState(ctx context.Context, model *spotifyaccount.SpotifyAuthState) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("expires_at < ?", time.Now()).Delete(&spotifyaccount.SpotifyAuthState{}).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Create(model).Error
+	})
+}
+
+// TakeState deletes the user's state and returns it, so a state can only be
+// used once even by concurrent callbacks. The state of another user is not
+// found and stays usable for them. Expired states are returned as well,
+// checking ExpiresAt is up to the caller.
+func (r *spotifyAccountRepository) TakeState(ctx context.Context, UserID uint, state string) (*spotifyaccount.SpotifyAuthState, error) {
+	model := spotifyaccount.SpotifyAuthState{}
+
+	response := r.db.Clauses(clause.Returning{}).Where("state = ? AND user_id = ?", state, UserID).Delete(&model)
+	if response.Error != nil {
+		return nil, response.Error
+	}
+
+	if response.RowsAffected == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	return &model, nil
+}
+
+func (r *spotifyAccountRepository) Create(ctx context.Context, model *spotifyaccount.SpotifyAccount) error {
+	return r.db.Create(model).Error
+}
+
+func (r *spotifyAccountRepository) Update(ctx context.Context, model *spotifyaccount.SpotifyAccount) error {
+	return r.db.Save(model).Error
+}
+
+func (r *spotifyAccountRepository) Get(ctx context.Context, UserID uint) (*spotifyaccount.SpotifyAccount, error) {
+	model := spotifyaccount.SpotifyAccount{}
+
+	response := r.db.Where("user_id = ?", UserID).First(&model)
+	if response.Error != nil {
+		return nil, response.Error
+	}
+
+	return &model, nil
+}
+
+// Delete returns gorm.ErrRecordNotFound when the user has no linked account.
+func (r *spotifyAccountRepository) Delete(ctx context.Context, UserID uint) error {
+	response := r.db.Where("user_id = ?", UserID).Delete(&spotifyaccount.SpotifyAccount{})
+	if response.Error != nil {
+		return response.Error
+	}
+
+	if response.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// ListUserIDs returns the users that have a linked account.
+func (r *spotifyAccountRepository) ListUserIDs(ctx context.Context) ([]uint, error) {
+	userIDs := []uint{}
+
+	response := r.db.Model(&spotifyaccount.SpotifyAccount{}).Order("user_id ASC").Pluck("user_id", &userIDs)
+	if response.Error != nil {
+		return nil, response.Error
+	}
+
+	return userIDs, nil
+}
@@ -0,0 +1,176 @@
+package spotifysync
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/sgitwhyd/music-catalogue/internal/models/spotifysync"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrRunInProgress is returned by Create when the user has a running
+	// run already, possibly started by another replica.
+	ErrRunInProgress = errors.New("spotify sync: a run is in progress")
+	// ErrRunNotRunning is returned when a run can't be stored because it was
+	// failed meanwhile, usually as stale by another replica.
+	ErrRunNotRunning = errors.New("spotify sync: run is no longer running")
+)
+
+const (
+	uniqueViolation  = "23505"
+	userRunningIndex = "idx_spotify_sync_runs_user_running"
+)
+
+type spotifySyncRepository struct {
+	db *gorm.DB
+}
+
+func NewSpotifySyncRepository(db *gorm.DB) *spotifySyncRepository {
+	return &spotifySyncRepository{
+		db: db,
+	}
+}
+
+//go:generate mockgen -source=repository.go -destination=../../services/spotifysync/service_mock_test.go -package=spotifysync
+type SpotifySyncRepository interface {
+	Create(ctx context.Context, model *spotifysync.SpotifySyncRun) error
+	Update(ctx context.Context, model *spotifysync.SpotifySyncRun) error
+	Get(ctx context.Context, id uint) (*spotifysync.SpotifySyncRun, error)
+	List(ctx context.Context, UserID uint, limit, offset int) ([]spotifysync.SpotifySyncRun, int64, error)
+	LastCompleted(ctx context.Context, UserID uint) (*spotifysync.SpotifySyncRun, error)
+	Latest(ctx context.Context, UserID uint) (*spotifysync.SpotifySyncRun, error)
+	Heartbeat(ctx context.Context, id uint) error
+	FailUnfinished(ctx context.Context, message string, staleBefore time.Time) (int64, error)
+}
+
+// Create records a running run, ErrRunInProgress when the user has one.
+func (r *spotifySyncRepository) Create(ctx context.Context, model *spotifysync.SpotifySyncRun) error {
+	now := time.Now()
+	model.HeartbeatAt = &now
+
+	err := r.db.Create(model).Error
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation && pgErr.ConstraintName == userRunningIndex {
+		return ErrRunInProgress
+	}
+
+	return err
+}
+
+// Update stores the whole run as long as it is running, a run that was
+// failed meanwhile is never flipped back, ErrRunNotRunning then.
+func (r *spotifySyncRepository) Update(ctx context.Context, model *spotifysync.SpotifySyncRun) error {
+	response := r.db.Model(model).
+		Where("status = ?", spotifysync.StatusRunning).
+		Select("*").
+		Omit("id", "created_at").
+		Updates(model)
+	if response.Error != nil {
+		return response.Error
+	}
+
+	if response.RowsAffected == 0 {
+		return ErrRunNotRunning
+	}
+
+	return nil
+}
+
+func (r *spotifySyncRepository) Get(ctx context.Context, id uint) (*spotifysync.SpotifySyncRun, error) {
+	model := spotifysync.SpotifySyncRun{}
+
+	response := r.db.First(&model, id)
+	if response.Error != nil {
+		return nil, response.Error
+	}
+
+	return &model, nil
+}
+
+// List pages through the runs of a user, the latest first.
+func (r *spotifySyncRepository) List(ctx context.Context, UserID uint, limit, offset int) ([]spotifysync.SpotifySyncRun, int64, error) {
+	var total int64
+	response := r.db.Model(&spotifysync.SpotifySyncRun{}).Where("user_id = ?", UserID).Count(&total)
+	if response.Error != nil {
+		return nil, 0, response.Error
+	}
+
+	runs := []spotifysync.SpotifySyncRun{}
+	response = r.db.Where("user_id = ?", UserID).Order("id DESC").Limit(limit).Offset(offset).Find(&runs)
+	if response.Error != nil {
+		return nil, 0, response.Error
+	}
+
+	return runs, total, nil
+}
+
+// LastCompleted returns the run that finished last without an error.
+func (r *spotifySyncRepository) LastCompleted(ctx context.Context, UserID uint) (*spotifysync.SpotifySyncRun, error) {
+	model := spotifysync.SpotifySyncRun{}
+
+	response := r.db.Where("user_id = ?", UserID).
+		Where("status = ?", spotifysync.StatusCompleted).
+		Order("finished_at DESC").
+		First(&model)
+	if response.Error != nil {
+		return nil, response.Error
+	}
+
+	return &model, nil
+}
+
+// Latest returns the run that was started last, whatever its outcome.
+func (r *spotifySyncRepository) Latest(ctx context.Context, UserID uint) (*spotifysync.SpotifySyncRun, error) {
+	model := spotifysync.SpotifySyncRun{}
+
+	response := r.db.Where("user_id = ?", UserID).Order("id DESC").First(&model)
+	if response.Error != nil {
+		return nil, response.Error
+	}
+
+	return &model, nil
+}
+
+// Heartbeat records that the run is still being worked on, ErrRunNotRunning
+// when it was failed meanwhile.
+func (r *spotifySyncRepository) Heartbeat(ctx context.Context, id uint) error {
+	response := r.db.Model(&spotifysync.SpotifySyncRun{}).
+		Where("id = ?", id).
+		Where("status = ?", spotifysync.StatusRunning).
+		Update("heartbeat_at", time.Now())
+	if response.Error != nil {
+		return response.Error
+	}
+
+	if response.RowsAffected == 0 {
+		return ErrRunNotRunning
+	}
+
+	return nil
+}
+
+// FailUnfinished marks the runs that are still running as failed when their
+// heartbeat is older than staleBefore, like imports they only live as long
+// as the replica running them.
+func (r *spotifySyncRepository) FailUnfinished(ctx context.Context, message string, staleBefore time.Time) (int64, error) {
+	now := time.Now()
+
+	response := r.db.Model(&spotifysync.SpotifySyncRun{}).
+		Where("status = ?", spotifysync.StatusRunning).
+		Where("COALESCE(heartbeat_at, created_at) < ?", staleBefore).
+		Updates(map[string]interface{}{
+			"status":      spotifysync.StatusFailed,
+			"error":       message,
+			"finished_at": now,
+			"updated_at":  now,
+		})
+	if response.Error != nil {
+		return 0, response.Error
+	}
+
+	return response.RowsAffected, nil
+}
@@ -0,0 +1,181 @@
+package spotifysync
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/sgitwhyd/music-catalogue/internal/models/spotifysync"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func Test_spotifySyncRepository_LastCompleted(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	gormDB, err := gorm.Open(postgres.New(postgres.Config{Conn: db}))
+	assert.NoError(t, err)
+
+	finishedAt := time.Date(2026, 1, 2, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		mockFn  func()
+		want    *spotifysync.SpotifySyncRun
+		wantErr error
+	}{
+		{
+			name: "success",
+			mockFn: func() {
+				mock.ExpectQuery(`SELECT \* FROM "spotify_sync_runs" WHERE user_id = \$1 AND status = \$2 ORDER BY finished_at DESC,"spotify_sync_runs"."id" LIMIT \$3`).
+					WithArgs(1, spotifysync.StatusCompleted, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "status", "finished_at"}).
+						AddRow(3, 1, spotifysync.StatusCompleted, finishedAt))
+			},
+			want: &spotifysync.SpotifySyncRun{ID: 3, UserID: 1, Status: spotifysync.StatusCompleted, FinishedAt: &finishedAt},
+		},
+		{
+			name: "never synced",
+			mockFn: func() {
+				mock.ExpectQuery(`SELECT \* FROM "spotify_sync_runs"`).
+					WithArgs(1, spotifysync.StatusCompleted, 1).
+					WillReturnError(gorm.ErrRecordNotFound)
+			},
+			wantErr: gorm.ErrRecordNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFn()
+
+			r := &spotifySyncRepository{
+				db: gormDB,
+			}
+
+			got, err := r.LastCompleted(context.Background(), 1)
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.want, got)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func Test_spotifySyncRepository_FailUnfinished(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	gormDB, err := gorm.Open(postgres.New(postgres.Config{Conn: db}))
+	assert.NoError(t, err)
+
+	staleBefore := time.Date(2026, 1, 2, 12, 0, 0, 0, time.UTC)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "spotify_sync_runs" SET "error"=\$1,"finished_at"=\$2,"status"=\$3,"updated_at"=\$4 WHERE status = \$5 AND COALESCE\(heartbeat_at, created_at\) < \$6`).
+		WithArgs("interrupted", sqlmock.AnyArg(), spotifysync.StatusFailed, sqlmock.AnyArg(), spotifysync.StatusRunning, staleBefore).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	r := &spotifySyncRepository{
+		db: gormDB,
+	}
+
+	count, err := r.FailUnfinished(context.Background(), "interrupted", staleBefore)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func Test_spotifySyncRepository_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	gormDB, err := gorm.Open(postgres.New(postgres.Config{Conn: db}))
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		mockFn  func()
+		wantErr error
+	}{
+		{
+			name: "success",
+			mockFn: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`INSERT INTO "spotify_sync_runs"`).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(4))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "should map the running run index to ErrRunInProgress",
+			mockFn: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`INSERT INTO "spotify_sync_runs"`).
+					WillReturnError(&pgconn.PgError{Code: "23505", ConstraintName: "idx_spotify_sync_runs_user_running"})
+				mock.ExpectRollback()
+			},
+			wantErr: ErrRunInProgress,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFn()
+
+			r := &spotifySyncRepository{
+				db: gormDB,
+			}
+
+			err := r.Create(context.Background(), &spotifysync.SpotifySyncRun{UserID: 1, Trigger: spotifysync.TriggerManual, Status: spotifysync.StatusRunning})
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func Test_spotifySyncRepository_Update(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	gormDB, err := gorm.Open(postgres.New(postgres.Config{Conn: db}))
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		affected int64
+		wantErr  error
+	}{
+		{
+			name:     "success",
+			affected: 1,
+		},
+		{
+			name:     "should not flip back a run that was failed meanwhile",
+			affected: 0,
+			wantErr:  ErrRunNotRunning,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock.ExpectBegin()
+			mock.ExpectExec(`UPDATE "spotify_sync_runs" SET .*"status"=\$\d+.* WHERE status = \$\d+ AND "id" = \$\d+`).
+				WillReturnResult(sqlmock.NewResult(0, tt.affected))
+			mock.ExpectCommit()
+
+			r := &spotifySyncRepository{
+				db: gormDB,
+			}
+
+			err := r.Update(context.Background(), &spotifysync.SpotifySyncRun{ID: 4, UserID: 1, Status: spotifysync.StatusCompleted})
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
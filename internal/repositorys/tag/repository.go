@@ -0,0 +1,173 @@
+package tag
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/sgitwhyd/music-catalogue/internal/models/tag"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrNameTaken is returned by Create and Update when the user already has a
+// tag by that name, the service's check can race with another request.
+var ErrNameTaken = errors.New("tag: name already taken")
+
+const (
+	uniqueViolation = "23505"
+	userNameIndex   = "idx_tags_user_name"
+)
+
+type tagRepository struct {
+	db *gorm.DB
+}
+
+func NewTagRepository(db *gorm.DB) *tagRepository {
+	return &tagRepository{
+		db: db,
+	}
+}
+
+//go:generate mockgen -source=repository.go -destination=../../services/tag/service_mock_test.go -package=tag
+type TagRepository interface {
+	Create(ctx context.Context, model *tag.Tag) error
+	Update(ctx context.Context, model *tag.Tag) error
+	Delete(ctx context.Context, id uint) error
+	Get(ctx context.Context, id uint) (*tag.Tag, error)
+	FindByName(ctx context.Context, UserID uint, name string) (*tag.Tag, error)
+	List(ctx context.Context, UserID uint) ([]tag.Tag, error)
+	CountTracks(ctx context.Context, tagIDs []uint) (map[uint]int, error)
+	TagTracks(ctx context.Context, tagID uint, spotifyIDs []string) error
+	UntagTrack(ctx context.Context, tagID uint, spotifyID string) error
+}
+
+func (r *tagRepository) Create(ctx context.Context, model *tag.Tag) error {
+	return nameTaken(r.db.Create(model).Error)
+}
+
+func (r *tagRepository) Update(ctx context.Context, model *tag.Tag) error {
+	return nameTaken(r.db.Save(model).Error)
+}
+
+// nameTaken turns a violation of the per user name index into ErrNameTaken.
+func nameTaken(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation && pgErr.ConstraintName == userNameIndex {
+		return ErrNameTaken
+	}
+
+	return err
+}
+
+func (r *tagRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("tag_id = ?", id).Delete(&tag.TrackTag{}).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Delete(&tag.Tag{}, id).Error
+	})
+}
+
+func (r *tagRepository) Get(ctx context.Context, id uint) (*tag.Tag, error) {
+	model := tag.Tag{}
+
+	response := r.db.First(&model, id)
+	if response.Error != nil {
+		return nil, response.Error
+	}
+
+	return &model, nil
+}
+
+func (r *tagRepository) FindByName(ctx context.Context, UserID uint, name string) (*tag.Tag, error) {
+	model := tag.Tag{}
+
+	response := r.db.Where("user_id = ?", UserID).Where("name = ?", name).First(&model)
+	if response.Error != nil {
+		return nil, response.Error
+	}
+
+	return &model, nil
+}
+
+func (r *tagRepository) List(ctx context.Context, UserID uint) ([]tag.Tag, error) {
+	tags := []tag.Tag{}
+
+	response := r.db.Where("user_id = ?", UserID).Order("name ASC").Find(&tags)
+	if response.Error != nil {
+		return nil, response.Error
+	}
+
+	return tags, nil
+}
+
+func (r *tagRepository) CountTracks(ctx context.Context, tagIDs []uint) (map[uint]int, error) {
+	var rows []struct {
+		TagID uint
+		Count int
+	}
+
+	response := r.db.Model(&tag.TrackTag{}).
+		Select("tag_id, COUNT(*) AS count").
+		Where("tag_id IN ?", tagIDs).
+		Group("tag_id").
+		Scan(&rows)
+	if response.Error != nil {
+		return nil, response.Error
+	}
+
+	result := make(map[uint]int, len(rows))
+	for _, row := range rows {
+		result[row.TagID] = row.Count
+	}
+
+	return result, nil
+}
+
+// TagTracks attaches the tag to every spotify id in one transaction. Tracks
+// that already carry the tag are left as they are.
+func (r *tagRepository) TagTracks(ctx context.Context, tagID uint, spotifyIDs []string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		locked := tag.Tag{}
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&locked, tagID).Error
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		rows := make([]tag.TrackTag, len(spotifyIDs))
+		for idx, spotifyID := range spotifyIDs {
+			rows[idx] = tag.TrackTag{
+				TagID:     tagID,
+				SpotifyID: spotifyID,
+				CreatedAt: now,
+			}
+		}
+
+		err = tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&rows).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Model(&locked).Update("updated_at", now).Error
+	})
+}
+
+// UntagTrack returns gorm.ErrRecordNotFound when the track doesn't carry the
+// tag.
+func (r *tagRepository) UntagTrack(ctx context.Context, tagID uint, spotifyID string) error {
+	response := r.db.Where("tag_id = ?", tagID).Where("spotify_id = ?", spotifyID).Delete(&tag.TrackTag{})
+	if response.Error != nil {
+		return response.Error
+	}
+
+	if response.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
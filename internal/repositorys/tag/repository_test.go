@@ -0,0 +1,158 @@
+package tag
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/sgitwhyd/music-catalogue/internal/models/tag"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func Test_tagRepository_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	gormDB, err := gorm.Open(postgres.New(postgres.Config{Conn: db}))
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		mockFn  func()
+		wantErr error
+	}{
+		{
+			name: "should map the user name index to ErrNameTaken",
+			mockFn: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`INSERT INTO "tags"`).
+					WillReturnError(&pgconn.PgError{Code: "23505", ConstraintName: "idx_tags_user_name"})
+				mock.ExpectRollback()
+			},
+			wantErr: ErrNameTaken,
+		},
+		{
+			name: "should keep other errors",
+			mockFn: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`INSERT INTO "tags"`).
+					WillReturnError(assert.AnError)
+				mock.ExpectRollback()
+			},
+			wantErr: assert.AnError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFn()
+
+			r := NewTagRepository(gormDB)
+			err := r.Create(context.Background(), &tag.Tag{UserID: 1, Name: "workout"})
+			assert.ErrorIs(t, err, tt.wantErr)
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func Test_tagRepository_TagTracks(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	gormDB, err := gorm.Open(postgres.New(postgres.Config{Conn: db}))
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		mockFn  func()
+		wantErr error
+	}{
+		{
+			name: "should insert every track in one transaction",
+			mockFn: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`SELECT \* FROM "tags" WHERE "tags"."id" = \$1 .* FOR UPDATE`).
+					WithArgs(1, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "name"}).AddRow(1, 1, "workout"))
+				mock.ExpectExec(`INSERT INTO "track_tags" \("tag_id","spotify_id","created_at"\) VALUES \(\$1,\$2,\$3\),\(\$4,\$5,\$6\) ON CONFLICT DO NOTHING`).
+					WithArgs(1, "a", sqlmock.AnyArg(), 1, "b", sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(`UPDATE "tags" SET "updated_at"=\$1 WHERE "id" = \$2`).
+					WithArgs(sqlmock.AnyArg(), 1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "should roll back when the insert fails",
+			mockFn: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`SELECT \* FROM "tags"`).
+					WithArgs(1, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "name"}).AddRow(1, 1, "workout"))
+				mock.ExpectExec(`INSERT INTO "track_tags"`).
+					WillReturnError(assert.AnError)
+				mock.ExpectRollback()
+			},
+			wantErr: assert.AnError,
+		},
+		{
+			name: "should fail when the tag is gone",
+			mockFn: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`SELECT \* FROM "tags"`).
+					WithArgs(1, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}))
+				mock.ExpectRollback()
+			},
+			wantErr: gorm.ErrRecordNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFn()
+
+			r := NewTagRepository(gormDB)
+			err := r.TagTracks(context.Background(), 1, []string{"a", "b"})
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func Test_tagRepository_UntagTrack(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	gormDB, err := gorm.Open(postgres.New(postgres.Config{Conn: db}))
+	assert.NoError(t, err)
+
+	for _, affected := range []int64{1, 0} {
+		mock.ExpectBegin()
+		mock.ExpectExec(`DELETE FROM "track_tags" WHERE tag_id = \$1 AND spotify_id = \$2`).
+			WithArgs(1, "a").
+			WillReturnResult(sqlmock.NewResult(0, affected))
+		mock.ExpectCommit()
+	}
+
+	r := NewTagRepository(gormDB)
+
+	err = r.UntagTrack(context.Background(), 1, "a")
+	assert.NoError(t, err)
+
+	err = r.UntagTrack(context.Background(), 1, "a")
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
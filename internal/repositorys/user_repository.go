@@ -9,6 +9,7 @@ import (
 type UserRepository interface{
 	Upsert(model models.User) error
 	Find(email, username string, id uint) (*models.User, error)
+	UpdateRole(id uint, role string) error
 }
 
 type userRepository struct {
@@ -38,4 +39,18 @@ func (r *userRepository) Find(email, username string, id uint) (*models.User, er
 	}
 
 	return &user, nil
+}
+
+// UpdateRole returns gorm.ErrRecordNotFound when there is no user id.
+func (r *userRepository) UpdateRole(id uint, role string) error {
+	result := r.db.Model(&models.User{}).Where("id = ?", id).Update("role", role)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
 }
\ No newline at end of file
@@ -54,6 +54,7 @@ func Test_userRepository_Upsert(t *testing.T) {
 					args.model.Email,
 					args.model.Username,
 					args.model.Password,
+					models.RoleUser,
 				).WillReturnError(fmt.Errorf("UNIQUE constraint failed: users.username"))
 				mock.ExpectRollback()
 			},
@@ -77,6 +78,7 @@ func Test_userRepository_Upsert(t *testing.T) {
 					args.model.Email,
 					args.model.Username,
 					args.model.Password,
+					models.RoleUser,
 				).WillReturnError(fmt.Errorf("UNIQUE constraint failed: users.email"))
 				mock.ExpectRollback()
 			},
@@ -100,6 +102,7 @@ func Test_userRepository_Upsert(t *testing.T) {
 					args.model.Email,
 					args.model.Username,
 					args.model.Password,
+					models.RoleUser,
 				).WillReturnRows(
 					sqlmock.NewRows([]string{"id"}).AddRow(1),
 				)
@@ -246,3 +249,58 @@ func Test_userRepository_Find(t *testing.T) {
 	}
 }
 
+
+func Test_userRepository_UpdateRole(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	gormDB, err := gorm.Open(postgres.New(postgres.Config{Conn: db}))
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		wantErr error
+		mockFn  func()
+	}{
+		{
+			name: "should update role",
+			mockFn: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec(`UPDATE "users" SET "role"=\$1,"updated_at"=\$2 WHERE id = \$3`).
+					WithArgs(models.RoleAdmin, sqlmock.AnyArg(), 2).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name:    "should fail when user not found",
+			wantErr: gorm.ErrRecordNotFound,
+			mockFn: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec(`UPDATE "users" SET "role"=\$1,"updated_at"=\$2 WHERE id = \$3`).
+					WithArgs(models.RoleAdmin, sqlmock.AnyArg(), 2).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectCommit()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFn()
+
+			r := &userRepository{
+				db: gormDB,
+			}
+			err := r.UpdateRole(2, models.RoleAdmin)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
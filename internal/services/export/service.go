@@ -0,0 +1,190 @@
+package export
+
+import (
+	"context"
+	"io"
+
+	"github.com/rs/zerolog/log"
+	"github.com/sgitwhyd/music-catalogue/internal/models/export"
+	"github.com/sgitwhyd/music-catalogue/internal/models/playlist"
+	playlistRepo "github.com/sgitwhyd/music-catalogue/internal/repositorys/playlist"
+	spotifyRepo "github.com/sgitwhyd/music-catalogue/internal/repositorys/spotify"
+	playlistSvc "github.com/sgitwhyd/music-catalogue/internal/services/playlist"
+	"github.com/sgitwhyd/music-catalogue/pkg/trackio"
+	"gorm.io/gorm"
+)
+
+//go:generate mockgen -source=service.go -destination=../../handlers/export/handler_mock_test.go -package=export
+//go:generate mockgen -source=../../repositorys/spotify/repository.go -destination=spotify_mock_test.go -package=export
+//go:generate mockgen -source=../../repositorys/playlist/repository.go -destination=playlist_mock_test.go -package=export
+type ExportService interface {
+	Export(ctx context.Context, userID uint, request export.ExportRequest, w io.Writer) error
+}
+
+const (
+	// tracks looked up on spotify and flushed to the client at a time
+	exportChunkSize = 50
+
+	likedTracksTitle = "Liked tracks"
+)
+
+type exportService struct {
+	spotifyOutbond spotifyRepo.SpotifyOutbond
+	spotifyRepo    spotifyRepo.SpotifyRepository
+	playlistRepo   playlistRepo.PlaylistRepository
+}
+
+func NewExportService(spotifyOutbond spotifyRepo.SpotifyOutbond, spotifyRepo spotifyRepo.SpotifyRepository, playlistRepo playlistRepo.PlaylistRepository) *exportService {
+	return &exportService{
+		spotifyOutbond: spotifyOutbond,
+		spotifyRepo:    spotifyRepo,
+		playlistRepo:   playlistRepo,
+	}
+}
+
+// Export writes the liked tracks or a playlist to w in the requested format.
+// Tracks are written and flushed a chunk at a time, nothing reaches w
+// before the first chunk was resolved so early errors can still be reported
+// as a regular response.
+func (s *exportService) Export(ctx context.Context, userID uint, request export.ExportRequest, w io.Writer) error {
+	encoder, err := trackio.NewEncoder(request.Format, w)
+	if err != nil {
+		return err
+	}
+
+	if request.PlaylistID != 0 {
+		return s.exportPlaylist(ctx, userID, request.PlaylistID, encoder)
+	}
+
+	return s.exportLiked(ctx, userID, encoder)
+}
+
+func (s *exportService) exportLiked(ctx context.Context, userID uint, encoder trackio.Encoder) error {
+	err := encoder.Begin(likedTracksTitle)
+	if err != nil {
+		return err
+	}
+
+	var beforeID uint
+	for {
+		activities, err := s.spotifyRepo.ListLikedBefore(ctx, userID, beforeID, exportChunkSize)
+		if err != nil {
+			log.Error().Err(err).Msg("service: error list track activities for export")
+			return err
+		}
+
+		if len(activities) == 0 {
+			break
+		}
+		beforeID = activities[len(activities)-1].ID
+
+		spotifyIDs := make([]string, len(activities))
+		for idx, activity := range activities {
+			spotifyIDs[idx] = activity.SpotifyID
+		}
+
+		err = s.writeTracks(ctx, encoder, spotifyIDs)
+		if err != nil {
+			return err
+		}
+
+		if len(activities) < exportChunkSize {
+			break
+		}
+	}
+
+	return encoder.End()
+}
+
+func (s *exportService) exportPlaylist(ctx context.Context, userID, playlistID uint, encoder trackio.Encoder) error {
+	model, err := s.playlistRepo.Get(ctx, playlistID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return playlistSvc.ErrPlaylistNotFound
+		}
+
+		log.Error().Err(err).Msg("service: error get playlist for export")
+		return err
+	}
+
+	// same rule as viewing it, private playlists of other users don't exist
+	if model.UserID != userID && model.Visibility != playlist.VisibilityPublic {
+		return playlistSvc.ErrPlaylistNotFound
+	}
+
+	tracks, err := s.playlistRepo.ListTracks(ctx, playlistID)
+	if err != nil {
+		log.Error().Err(err).Msg("service: error list playlist tracks for export")
+		return err
+	}
+
+	err = encoder.Begin(model.Name)
+	if err != nil {
+		return err
+	}
+
+	for start := 0; start < len(tracks); start += exportChunkSize {
+		end := min(start+exportChunkSize, len(tracks))
+
+		spotifyIDs := make([]string, 0, end-start)
+		for _, track := range tracks[start:end] {
+			spotifyIDs = append(spotifyIDs, track.SpotifyID)
+		}
+
+		err = s.writeTracks(ctx, encoder, spotifyIDs)
+		if err != nil {
+			return err
+		}
+	}
+
+	return encoder.End()
+}
+
+// writeTracks resolves the spotify ids through the outbond and writes them
+// in order. Tracks spotify no longer knows are kept with their uri only.
+func (s *exportService) writeTracks(ctx context.Context, encoder trackio.Encoder, spotifyIDs []string) error {
+	if len(spotifyIDs) == 0 {
+		return nil
+	}
+
+	tracks, err := s.spotifyOutbond.GetSeveralTracks(ctx, spotifyIDs)
+	if err != nil {
+		log.Error().Err(err).Msg("error get several tracks spotify")
+		return err
+	}
+
+	mapTracks := make(map[string]spotifyRepo.SpotifyTrackObject, len(tracks))
+	for _, track := range tracks {
+		mapTracks[track.ID] = track
+	}
+
+	for _, spotifyID := range spotifyIDs {
+		track, ok := mapTracks[spotifyID]
+		if !ok {
+			err = encoder.Encode(trackio.Track{URI: "spotify:track:" + spotifyID})
+		} else {
+			err = encoder.Encode(toTrack(track))
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return encoder.Flush()
+}
+
+func toTrack(track spotifyRepo.SpotifyTrackObject) trackio.Track {
+	artists := make([]string, len(track.Artists))
+	for idx, artist := range track.Artists {
+		artists[idx] = artist.Name
+	}
+
+	return trackio.Track{
+		Name:       track.Name,
+		Artists:    artists,
+		Album:      track.Album.Name,
+		DurationMs: track.DurationMs,
+		ISRC:       track.ExternalIDs.ISRC,
+		URI:        track.URI,
+	}
+}
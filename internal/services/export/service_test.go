@@ -0,0 +1,149 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/sgitwhyd/music-catalogue/internal/models/export"
+	"github.com/sgitwhyd/music-catalogue/internal/models/playlist"
+	"github.com/sgitwhyd/music-catalogue/internal/models/spotify"
+	spotifyRepo "github.com/sgitwhyd/music-catalogue/internal/repositorys/spotify"
+	playlistSvc "github.com/sgitwhyd/music-catalogue/internal/services/playlist"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+func Test_exportService_Export(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockSpotifyOutbond := NewMockSpotifyOutbond(mockCtrl)
+	mockSpotifyRepo := NewMockSpotifyRepository(mockCtrl)
+	mockPlaylistRepo := NewMockPlaylistRepository(mockCtrl)
+
+	bohemian := spotifyRepo.SpotifyTrackObject{
+		ID:          "a",
+		Name:        "Bohemian Rhapsody",
+		Artists:     []spotifyRepo.SpotifyArtisObject{{Name: "Queen"}},
+		Album:       spotifyRepo.SpotifyAlbumObject{Name: "A Night At The Opera"},
+		DurationMs:  354947,
+		URI:         "spotify:track:a",
+		ExternalIDs: spotifyRepo.SpotifyExternalIDsObject{ISRC: "GBUM71029604"},
+	}
+
+	// one full chunk of likes and one more, so the export has to page
+	activities := make([]spotify.TrackActivity, exportChunkSize+1)
+	firstChunkIDs := make([]string, exportChunkSize)
+	firstChunkTracks := make([]spotifyRepo.SpotifyTrackObject, exportChunkSize)
+	for idx := range activities {
+		// newest first, the way they are paged
+		activities[idx] = spotify.TrackActivity{Model: gorm.Model{ID: uint(1000 - idx)}, SpotifyID: fmt.Sprintf("t%d", idx)}
+		if idx < exportChunkSize {
+			firstChunkIDs[idx] = activities[idx].SpotifyID
+			firstChunkTracks[idx] = spotifyRepo.SpotifyTrackObject{ID: activities[idx].SpotifyID, URI: "spotify:track:" + activities[idx].SpotifyID}
+		}
+	}
+
+	tests := []struct {
+		name      string
+		request   export.ExportRequest
+		mockFn    func()
+		wantLines []string
+		wantErr   error
+	}{
+		{
+			name:    "should page through the liked tracks",
+			request: export.ExportRequest{Format: "csv"},
+			mockFn: func() {
+				mockSpotifyRepo.EXPECT().ListLikedBefore(gomock.Any(), uint(1), uint(0), exportChunkSize).
+					Return(activities[:exportChunkSize], nil)
+				mockSpotifyOutbond.EXPECT().GetSeveralTracks(gomock.Any(), firstChunkIDs).Return(firstChunkTracks, nil)
+				// the next chunk continues after the last id seen
+				mockSpotifyRepo.EXPECT().ListLikedBefore(gomock.Any(), uint(1), uint(951), exportChunkSize).
+					Return(activities[exportChunkSize:], nil)
+				mockSpotifyOutbond.EXPECT().GetSeveralTracks(gomock.Any(), []string{"t50"}).Return([]spotifyRepo.SpotifyTrackObject{
+					{ID: "t50", URI: "spotify:track:t50"},
+				}, nil)
+			},
+			wantLines: []string{
+				"name,artists,album,duration_ms,isrc,uri",
+				",,,0,,spotify:track:t0",
+			},
+		},
+		{
+			name:    "should export a playlist in order keeping unknown tracks",
+			request: export.ExportRequest{Format: "m3u8", PlaylistID: 3},
+			mockFn: func() {
+				mockPlaylistRepo.EXPECT().Get(gomock.Any(), uint(3)).Return(&playlist.Playlist{
+					Model:      gorm.Model{ID: 3},
+					UserID:     2,
+					Name:       "Road Trip",
+					Visibility: playlist.VisibilityPublic,
+				}, nil)
+				mockPlaylistRepo.EXPECT().ListTracks(gomock.Any(), uint(3)).Return([]playlist.PlaylistTrack{
+					{SpotifyID: "gone", Position: 0},
+					{SpotifyID: "a", Position: 1},
+				}, nil)
+				mockSpotifyOutbond.EXPECT().GetSeveralTracks(gomock.Any(), []string{"gone", "a"}).
+					Return([]spotifyRepo.SpotifyTrackObject{bohemian}, nil)
+			},
+			wantLines: []string{
+				"#EXTM3U",
+				"#PLAYLIST:Road Trip",
+				"#EXTINF:-1,",
+				"spotify:track:gone",
+				"#EXTINF:355,Queen - Bohemian Rhapsody",
+				"spotify:track:a",
+			},
+		},
+		{
+			name:    "should hide private playlists of other users",
+			request: export.ExportRequest{Format: "json", PlaylistID: 3},
+			mockFn: func() {
+				mockPlaylistRepo.EXPECT().Get(gomock.Any(), uint(3)).Return(&playlist.Playlist{
+					UserID:     2,
+					Visibility: playlist.VisibilityPrivate,
+				}, nil)
+			},
+			wantErr: playlistSvc.ErrPlaylistNotFound,
+		},
+		{
+			name:    "should fail before writing when spotify fails",
+			request: export.ExportRequest{Format: "xspf"},
+			mockFn: func() {
+				mockSpotifyRepo.EXPECT().ListLikedBefore(gomock.Any(), uint(1), uint(0), exportChunkSize).
+					Return([]spotify.TrackActivity{{SpotifyID: "a"}}, nil)
+				mockSpotifyOutbond.EXPECT().GetSeveralTracks(gomock.Any(), []string{"a"}).Return(nil, assert.AnError)
+			},
+			wantErr: assert.AnError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFn()
+
+			var out bytes.Buffer
+
+			s := NewExportService(mockSpotifyOutbond, mockSpotifyRepo, mockPlaylistRepo)
+			err := s.Export(context.Background(), 1, tt.request, &out)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Zero(t, out.Len())
+				return
+			}
+
+			assert.NoError(t, err)
+
+			lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+			assert.Equal(t, tt.wantLines, lines[:len(tt.wantLines)])
+			if tt.request.PlaylistID == 0 {
+				// the header and every liked track
+				assert.Len(t, lines, len(activities)+1)
+			}
+		})
+	}
+}
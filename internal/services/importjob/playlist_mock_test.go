@@ -0,0 +1,159 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ../../repositorys/playlist/repository.go
+//
+// Generated by this command:
+//
+//	mockgen -source=../../repositorys/playlist/repository.go -destination=playlist_mock_test.go -package=importjob
+//
+
+// Package importjob is a generated GoMock package.
+package importjob
+
+import (
+	context "context"
+	reflect "reflect"
+
+	playlist "github.com/sgitwhyd/music-catalogue/internal/models/playlist"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockPlaylistRepository is a mock of PlaylistRepository interface.
+type MockPlaylistRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockPlaylistRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockPlaylistRepositoryMockRecorder is the mock recorder for MockPlaylistRepository.
+type MockPlaylistRepositoryMockRecorder struct {
+	mock *MockPlaylistRepository
+}
+
+// NewMockPlaylistRepository creates a new mock instance.
+func NewMockPlaylistRepository(ctrl *gomock.Controller) *MockPlaylistRepository {
+	mock := &MockPlaylistRepository{ctrl: ctrl}
+	mock.recorder = &MockPlaylistRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPlaylistRepository) EXPECT() *MockPlaylistRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CountTracks mocks base method.
+func (m *MockPlaylistRepository) CountTracks(ctx context.Context, playlistIDs []uint) (map[uint]int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountTracks", ctx, playlistIDs)
+	ret0, _ := ret[0].(map[uint]int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountTracks indicates an expected call of CountTracks.
+func (mr *MockPlaylistRepositoryMockRecorder) CountTracks(ctx, playlistIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountTracks", reflect.TypeOf((*MockPlaylistRepository)(nil).CountTracks), ctx, playlistIDs)
+}
+
+// Create mocks base method.
+func (m *MockPlaylistRepository) Create(ctx context.Context, model *playlist.Playlist) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, model)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockPlaylistRepositoryMockRecorder) Create(ctx, model any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockPlaylistRepository)(nil).Create), ctx, model)
+}
+
+// Delete mocks base method.
+func (m *MockPlaylistRepository) Delete(ctx context.Context, id uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockPlaylistRepositoryMockRecorder) Delete(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockPlaylistRepository)(nil).Delete), ctx, id)
+}
+
+// EditTracks mocks base method.
+func (m *MockPlaylistRepository) EditTracks(ctx context.Context, playlistID uint, edit func([]playlist.PlaylistTrack) ([]playlist.PlaylistTrack, error)) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EditTracks", ctx, playlistID, edit)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EditTracks indicates an expected call of EditTracks.
+func (mr *MockPlaylistRepositoryMockRecorder) EditTracks(ctx, playlistID, edit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EditTracks", reflect.TypeOf((*MockPlaylistRepository)(nil).EditTracks), ctx, playlistID, edit)
+}
+
+// Get mocks base method.
+func (m *MockPlaylistRepository) Get(ctx context.Context, id uint) (*playlist.Playlist, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, id)
+	ret0, _ := ret[0].(*playlist.Playlist)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockPlaylistRepositoryMockRecorder) Get(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockPlaylistRepository)(nil).Get), ctx, id)
+}
+
+// List mocks base method.
+func (m *MockPlaylistRepository) List(ctx context.Context, UserID uint, limit, offset int) ([]playlist.Playlist, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, UserID, limit, offset)
+	ret0, _ := ret[0].([]playlist.Playlist)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// List indicates an expected call of List.
+func (mr *MockPlaylistRepositoryMockRecorder) List(ctx, UserID, limit, offset any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockPlaylistRepository)(nil).List), ctx, UserID, limit, offset)
+}
+
+// ListTracks mocks base method.
+func (m *MockPlaylistRepository) ListTracks(ctx context.Context, playlistID uint) ([]playlist.PlaylistTrack, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTracks", ctx, playlistID)
+	ret0, _ := ret[0].([]playlist.PlaylistTrack)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTracks indicates an expected call of ListTracks.
+func (mr *MockPlaylistRepositoryMockRecorder) ListTracks(ctx, playlistID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTracks", reflect.TypeOf((*MockPlaylistRepository)(nil).ListTracks), ctx, playlistID)
+}
+
+// Update mocks base method.
+func (m *MockPlaylistRepository) Update(ctx context.Context, model *playlist.Playlist) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, model)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockPlaylistRepositoryMockRecorder) Update(ctx, model any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockPlaylistRepository)(nil).Update), ctx, model)
+}
@@ -0,0 +1,750 @@
+package importjob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/sgitwhyd/music-catalogue/internal/apperror"
+	"github.com/sgitwhyd/music-catalogue/internal/models/importjob"
+	"github.com/sgitwhyd/music-catalogue/internal/models/playlist"
+	"github.com/sgitwhyd/music-catalogue/internal/models/spotify"
+	importRepo "github.com/sgitwhyd/music-catalogue/internal/repositorys/importjob"
+	playlistRepo "github.com/sgitwhyd/music-catalogue/internal/repositorys/playlist"
+	spotifyRepo "github.com/sgitwhyd/music-catalogue/internal/repositorys/spotify"
+	"github.com/sgitwhyd/music-catalogue/pkg/trackio"
+	"gorm.io/gorm"
+)
+
+//go:generate mockgen -source=service.go -destination=../../handlers/importjob/handler_mock_test.go -package=importjob
+//go:generate mockgen -source=../../repositorys/spotify/repository.go -destination=spotify_mock_test.go -package=importjob
+//go:generate mockgen -source=../../repositorys/playlist/repository.go -destination=playlist_mock_test.go -package=importjob
+type ImportService interface {
+	Create(ctx context.Context, userID uint, request importjob.CreateImportRequest, filename string, file io.Reader) (*importjob.ImportJobResponse, error)
+	Get(ctx context.Context, userID, jobID uint) (*importjob.ImportJobResponse, error)
+	ListRows(ctx context.Context, userID, jobID uint, request importjob.ListImportRowsRequest) (*importjob.ListImportRowsResponse, error)
+}
+
+var (
+	ErrImportNotFound      = apperror.New(apperror.KindNotFound, "import_not_found", "import not found")
+	ErrUnknownImportFormat = apperror.New(apperror.KindInvalid, "unknown_import_format", "format must be csv, json, m3u, m3u8 or xspf")
+	ErrMalformedImport     = apperror.New(apperror.KindInvalid, "malformed_import", "the file can't be read in this format")
+	ErrEmptyImport         = apperror.New(apperror.KindInvalid, "empty_import", "the file has no tracks")
+	ErrImportTooLarge      = apperror.New(apperror.KindInvalid, "import_too_large", "at most 5000 tracks can be imported at once, 1000 into a playlist")
+)
+
+const (
+	defaultImportRowsPageSize = 50
+	maxImportRowsPageSize     = 200
+
+	maxImportTracks = 5000
+	// a playlist can't hold more than this
+	maxImportPlaylistTracks = 1000
+
+	// imports running at the same time, the rest wait for a slot
+	maxConcurrentImports = 2
+	// rows stored together with the job counters at a time
+	importBatchSize = 25
+	// search results weighed when an entry has no isrc match
+	searchCandidates = 5
+	// how far the length of a candidate may be off to count as the same
+	// recording
+	durationTolerance = 2 * time.Second
+	// attempts at an entry while spotify is unavailable or rate limiting,
+	// the job fails after the last. The delay grows with every attempt and
+	// starts at the time the circuit breaker stays open by default.
+	resolveAttempts   = 3
+	resolveRetryDelay = 30 * time.Second
+
+	// how often a process proves it still works on its jobs, a job that
+	// didn't beat for staleAfter was lost with its replica
+	heartbeatInterval = time.Minute
+	staleAfter        = 5 * time.Minute
+
+	interruptedMessage = "the import was interrupted, please upload the file again"
+)
+
+type importService struct {
+	importRepo     importRepo.ImportJobRepository
+	spotifyOutbond spotifyRepo.SpotifyOutbond
+	spotifyRepo    spotifyRepo.SpotifyRepository
+	playlistRepo   playlistRepo.PlaylistRepository
+	retryDelay     time.Duration
+
+	// jobs outlive the request that created them, they only stop when ctx is
+	// cancelled on shutdown
+	ctx      context.Context
+	cancel   context.CancelFunc
+	jobs     sync.WaitGroup
+	slots    chan struct{}
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+func NewImportService(importRepo importRepo.ImportJobRepository, spotifyOutbond spotifyRepo.SpotifyOutbond, spotifyRepo spotifyRepo.SpotifyRepository, playlistRepo playlistRepo.PlaylistRepository) *importService {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &importService{
+		importRepo:     importRepo,
+		spotifyOutbond: spotifyOutbond,
+		spotifyRepo:    spotifyRepo,
+		playlistRepo:   playlistRepo,
+		retryDelay:     resolveRetryDelay,
+		ctx:            ctx,
+		cancel:         cancel,
+		slots:          make(chan struct{}, maxConcurrentImports),
+		stop:           make(chan struct{}),
+	}
+}
+
+// Create reads the whole file up front so a file that can't be imported is
+// rejected right away, then resolves and applies the tracks in the
+// background. The returned job is pending, clients poll Get for progress.
+func (s *importService) Create(ctx context.Context, userID uint, request importjob.CreateImportRequest, filename string, file io.Reader) (*importjob.ImportJobResponse, error) {
+	format := request.Format
+	if format == "" {
+		var ok bool
+		format, ok = trackio.FormatFromFilename(filename)
+		if !ok {
+			return nil, ErrUnknownImportFormat
+		}
+	}
+
+	listing, err := trackio.Decode(format, file)
+	if err != nil {
+		if errors.Is(err, trackio.ErrMalformed) {
+			log.Warn().Err(err).Msg("service: malformed import file")
+			return nil, ErrMalformedImport
+		}
+
+		log.Error().Err(err).Msg("service: error read import file")
+		return nil, err
+	}
+
+	total := len(listing.Tracks)
+	if total == 0 {
+		return nil, ErrEmptyImport
+	}
+
+	if total > maxImportTracks || (request.Target == importjob.TargetPlaylist && total > maxImportPlaylistTracks) {
+		return nil, ErrImportTooLarge
+	}
+
+	model := importjob.ImportJob{
+		UserID:   userID,
+		Status:   importjob.StatusPending,
+		Target:   request.Target,
+		Format:   format,
+		Filename: truncate(path.Base(filename), 255),
+		Total:    total,
+	}
+
+	err = s.importRepo.Create(ctx, &model)
+	if err != nil {
+		log.Error().Err(err).Msg("service: error create import job")
+		return nil, err
+	}
+
+	response := toResponse(model)
+
+	s.start(model, listing.Tracks, playlistName(request.Name, listing.Title, filename))
+
+	return &response, nil
+}
+
+func (s *importService) Get(ctx context.Context, userID, jobID uint) (*importjob.ImportJobResponse, error) {
+	model, err := s.getOwned(ctx, userID, jobID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := toResponse(*model)
+	return &response, nil
+}
+
+func (s *importService) ListRows(ctx context.Context, userID, jobID uint, request importjob.ListImportRowsRequest) (*importjob.ListImportRowsResponse, error) {
+	_, err := s.getOwned(ctx, userID, jobID)
+	if err != nil {
+		return nil, err
+	}
+
+	pageSize := request.PageSize
+	if pageSize <= 0 || pageSize > maxImportRowsPageSize {
+		pageSize = defaultImportRowsPageSize
+	}
+
+	pageIndex := request.PageIndex
+	if pageIndex <= 0 {
+		pageIndex = 1
+	}
+
+	limit := pageSize
+	offset := (pageIndex - 1) * pageSize
+
+	rows, total, err := s.importRepo.ListRows(ctx, jobID, request.Status, limit, offset)
+	if err != nil {
+		log.Error().Err(err).Msg("service: error list import rows")
+		return nil, err
+	}
+
+	items := make([]importjob.ImportRowResponse, len(rows))
+	for idx, row := range rows {
+		items[idx] = toRowResponse(row)
+	}
+
+	return &importjob.ListImportRowsResponse{
+		Items:  items,
+		Limit:  limit,
+		Offset: offset,
+		Total:  int(total),
+	}, nil
+}
+
+// FailInterrupted marks the jobs no process beat for as failed. Jobs of
+// other replicas that are still running are left alone.
+func (s *importService) FailInterrupted(ctx context.Context) error {
+	count, err := s.importRepo.FailUnfinished(ctx, interruptedMessage, time.Now().Add(-staleAfter))
+	if err != nil {
+		log.Error().Err(err).Msg("service: error fail unfinished import jobs")
+		return err
+	}
+
+	if count > 0 {
+		log.Warn().Int64("jobs", count).Msg("service: failed import jobs interrupted by a restart")
+	}
+
+	return nil
+}
+
+// Start fails the jobs lost with another replica once per heartbeat interval
+// until Shutdown.
+func (s *importService) Start() {
+	s.jobs.Add(1)
+	go func() {
+		defer s.jobs.Done()
+
+		ticker := time.NewTicker(heartbeatInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				_ = s.FailInterrupted(s.ctx)
+			case <-s.stop:
+				return
+			case <-s.ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// Shutdown waits for the running and queued jobs. When ctx ends first they
+// are cancelled and recorded as failed.
+func (s *importService) Shutdown(ctx context.Context) error {
+	s.stopOnce.Do(func() { close(s.stop) })
+
+	done := make(chan struct{})
+	go func() {
+		s.jobs.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		s.cancel()
+		<-done
+		return ctx.Err()
+	}
+}
+
+func (s *importService) start(model importjob.ImportJob, tracks []trackio.Track, name string) {
+	ctx, cancel := context.WithCancel(s.ctx)
+
+	s.jobs.Add(2)
+	go func() {
+		defer s.jobs.Done()
+
+		s.heartbeat(ctx, cancel, model.ID)
+	}()
+
+	go func() {
+		defer s.jobs.Done()
+		defer cancel()
+
+		select {
+		case s.slots <- struct{}{}:
+			defer func() { <-s.slots }()
+		case <-ctx.Done():
+			s.finish(ctx, &model, ctx.Err())
+			return
+		}
+
+		err := s.process(ctx, &model, tracks, name)
+		s.finish(ctx, &model, err)
+	}()
+}
+
+// heartbeat beats for a queued or running job until ctx ends. The job is
+// cancelled when it was failed meanwhile, another replica took it for lost.
+func (s *importService) heartbeat(ctx context.Context, cancel context.CancelFunc, jobID uint) {
+	ticker := time.NewTicker(heartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			err := s.importRepo.Heartbeat(ctx, jobID)
+			if errors.Is(err, importRepo.ErrJobNotRunning) {
+				log.Warn().Uint("jobID", jobID).Msg("service: import job was failed meanwhile, stopping it")
+				cancel()
+				return
+			}
+			if err != nil {
+				log.Error().Err(err).Uint("jobID", jobID).Msg("service: error beat for import job")
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// process resolves every track and applies the matches a batch at a time,
+// storing the report as it goes, so a job that fails halfway keeps what it
+// already imported.
+func (s *importService) process(ctx context.Context, model *importjob.ImportJob, tracks []trackio.Track, name string) error {
+	model.Status = importjob.StatusRunning
+	err := s.importRepo.Update(ctx, model)
+	if err != nil {
+		return err
+	}
+
+	if model.Target == importjob.TargetPlaylist {
+		err = s.createPlaylist(ctx, model, name)
+		if err != nil {
+			return err
+		}
+	}
+
+	seen := make(map[string]struct{}, len(tracks))
+
+	batch := make([]importjob.ImportJobRow, 0, importBatchSize)
+	matched := make([]string, 0, importBatchSize)
+	for idx, track := range tracks {
+		err = ctx.Err()
+		if err != nil {
+			return err
+		}
+
+		row, err := s.resolveRow(ctx, track)
+		if err != nil {
+			if !isRowError(err) {
+				return err
+			}
+
+			// one entry spotify rejected doesn't end the import
+			log.Warn().Err(err).Uint("jobID", model.ID).Int("row", idx+1).Msg("service: error resolve import row")
+			row.Status = importjob.RowError
+		}
+
+		row.JobID = model.ID
+		row.RowNumber = idx + 1
+
+		model.Processed++
+		switch row.Status {
+		case importjob.RowMatched:
+			model.Matched++
+			if _, ok := seen[row.SpotifyID]; !ok {
+				seen[row.SpotifyID] = struct{}{}
+				matched = append(matched, row.SpotifyID)
+			}
+		case importjob.RowAmbiguous:
+			model.Ambiguous++
+		case importjob.RowError:
+			model.Errored++
+		default:
+			model.Unmatched++
+		}
+
+		batch = append(batch, row)
+		if len(batch) == importBatchSize || idx == len(tracks)-1 {
+			err = s.apply(ctx, model, matched)
+			if err != nil {
+				return err
+			}
+
+			err = s.importRepo.SaveProgress(ctx, model, batch)
+			if err != nil {
+				return err
+			}
+
+			batch = make([]importjob.ImportJobRow, 0, importBatchSize)
+			matched = make([]string, 0, importBatchSize)
+		}
+	}
+
+	return nil
+}
+
+// apply likes the tracks matched in a batch or adds them to the job's
+// playlist.
+func (s *importService) apply(ctx context.Context, model *importjob.ImportJob, spotifyIDs []string) error {
+	if len(spotifyIDs) == 0 {
+		return nil
+	}
+
+	if model.Target == importjob.TargetPlaylist {
+		return s.addPlaylistTracks(ctx, *model.PlaylistID, spotifyIDs)
+	}
+
+	return s.likeTracks(ctx, model.UserID, spotifyIDs)
+}
+
+// finish records how the job ended. It runs detached from ctx, a job that
+// was cancelled still has to be stored as failed.
+func (s *importService) finish(ctx context.Context, model *importjob.ImportJob, err error) {
+	now := time.Now()
+	model.FinishedAt = &now
+	model.Status = importjob.StatusCompleted
+
+	if err != nil {
+		log.Error().Err(err).Uint("jobID", model.ID).Msg("service: import job failed")
+
+		model.Status = importjob.StatusFailed
+		model.Error = apperror.From(err).Message
+		if errors.Is(err, context.Canceled) {
+			model.Error = interruptedMessage
+		}
+	}
+
+	err = s.importRepo.Update(context.WithoutCancel(ctx), model)
+	if errors.Is(err, importRepo.ErrJobNotRunning) {
+		log.Warn().Uint("jobID", model.ID).Msg("service: import job was failed meanwhile")
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Uint("jobID", model.ID).Msg("service: error finish import job")
+	}
+}
+
+// resolveRow resolves the track, waiting out an outage or a rate limit
+// before it tries again. Errors of the entry itself are returned right away.
+func (s *importService) resolveRow(ctx context.Context, track trackio.Track) (importjob.ImportJobRow, error) {
+	for attempt := 1; ; attempt++ {
+		row, err := s.resolve(ctx, track)
+		if err == nil || isRowError(err) || attempt == resolveAttempts {
+			return row, err
+		}
+
+		log.Warn().Err(err).Int("attempt", attempt).Msg("service: spotify unavailable, retry import row")
+
+		timer := time.NewTimer(s.retryDelay * time.Duration(attempt))
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return row, ctx.Err()
+		}
+	}
+}
+
+// isRowError reports whether spotify rejected the request made for one
+// entry, a client error other than a rate limit. Anything else would fail
+// every entry that follows as well.
+func isRowError(err error) bool {
+	var statusErr *spotifyRepo.StatusError
+	if !errors.As(err, &statusErr) {
+		return false
+	}
+
+	return statusErr.StatusCode >= http.StatusBadRequest &&
+		statusErr.StatusCode < http.StatusInternalServerError &&
+		statusErr.StatusCode != http.StatusTooManyRequests
+}
+
+// resolve looks the track up on spotify by its isrc and falls back to a
+// search on title and first artist. Several results only count as a match
+// when exactly one of them has the same title and artist.
+func (s *importService) resolve(ctx context.Context, track trackio.Track) (importjob.ImportJobRow, error) {
+	row := importjob.ImportJobRow{
+		Name:    track.Name,
+		Artists: strings.Join(track.Artists, "; "),
+		Album:   track.Album,
+		ISRC:    track.ISRC,
+		Status:  importjob.RowUnmatched,
+	}
+
+	if track.ISRC != "" {
+		result, err := s.spotifyOutbond.Search(ctx, spotifyRepo.SearchParams{
+			Query: "isrc:" + track.ISRC,
+			Limit: 1,
+		})
+		if err != nil {
+			log.Error().Err(err).Msg("service: error search track by isrc")
+			return row, err
+		}
+
+		if len(result.Tracks.Items) > 0 {
+			row.Status = importjob.RowMatched
+			row.SpotifyID = result.Tracks.Items[0].ID
+			return row, nil
+		}
+	}
+
+	if track.Name == "" {
+		return row, nil
+	}
+
+	query := fmt.Sprintf(`track:"%s"`, unquote(track.Name))
+	if len(track.Artists) > 0 {
+		query += fmt.Sprintf(` artist:"%s"`, unquote(track.Artists[0]))
+	}
+
+	result, err := s.spotifyOutbond.Search(ctx, spotifyRepo.SearchParams{
+		Query: query,
+		Limit: searchCandidates,
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("service: error search track by title")
+		return row, err
+	}
+
+	candidates := result.Tracks.Items
+	switch {
+	case len(candidates) == 0:
+		return row, nil
+	case len(candidates) == 1:
+		row.Status = importjob.RowMatched
+		row.SpotifyID = candidates[0].ID
+		return row, nil
+	}
+
+	if best := pickCandidate(track, candidates); best != nil {
+		row.Status = importjob.RowMatched
+		row.SpotifyID = best.ID
+		return row, nil
+	}
+
+	ids := make([]string, len(candidates))
+	for idx, candidate := range candidates {
+		ids[idx] = candidate.ID
+	}
+
+	row.Status = importjob.RowAmbiguous
+	row.Candidates = strings.Join(ids, ",")
+	return row, nil
+}
+
+// pickCandidate returns the only candidate with the exact title and first
+// artist of track, the length breaks a tie between several. Nil when that
+// doesn't single one out.
+func pickCandidate(track trackio.Track, candidates []spotifyRepo.SpotifyTrackObject) *spotifyRepo.SpotifyTrackObject {
+	exact := make([]spotifyRepo.SpotifyTrackObject, 0, len(candidates))
+	for _, candidate := range candidates {
+		if !strings.EqualFold(candidate.Name, track.Name) {
+			continue
+		}
+
+		if len(track.Artists) > 0 && !hasArtist(candidate, track.Artists[0]) {
+			continue
+		}
+
+		exact = append(exact, candidate)
+	}
+
+	if len(exact) > 1 && track.DurationMs > 0 {
+		sameLength := exact[:0]
+		for _, candidate := range exact {
+			diff := time.Duration(candidate.DurationMs-track.DurationMs) * time.Millisecond
+			if diff.Abs() <= durationTolerance {
+				sameLength = append(sameLength, candidate)
+			}
+		}
+
+		exact = sameLength
+	}
+
+	if len(exact) != 1 {
+		return nil
+	}
+
+	return &exact[0]
+}
+
+func hasArtist(track spotifyRepo.SpotifyTrackObject, name string) bool {
+	for _, artist := range track.Artists {
+		if strings.EqualFold(artist.Name, name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// likeTracks likes every track, tracks that are liked already stay as they
+// are.
+func (s *importService) likeTracks(ctx context.Context, userID uint, spotifyIDs []string) error {
+	liked := true
+
+	for _, spotifyID := range spotifyIDs {
+		activity, err := s.spotifyRepo.Get(ctx, userID, spotifyID)
+		if err != nil && err != gorm.ErrRecordNotFound {
+			log.Error().Err(err).Msg("service: error get record from db")
+			return err
+		}
+
+		if err == gorm.ErrRecordNotFound || activity == nil {
+			created := spotify.TrackActivity{
+				UserID:     userID,
+				SpotifyID:  spotifyID,
+				EntityType: spotify.EntityTypeTrack,
+			}
+			created.SetLiked(&liked, time.Now())
+			err = s.spotifyRepo.Create(ctx, created)
+		} else if activity.IsLiked == nil || !*activity.IsLiked {
+			activity.SetLiked(&liked, time.Now())
+			err = s.spotifyRepo.Update(ctx, *activity)
+		}
+		if err != nil {
+			log.Error().Err(err).Msg("service: error like imported track")
+			return err
+		}
+	}
+
+	return nil
+}
+
+// createPlaylist creates the new private playlist the matched tracks are
+// added to.
+func (s *importService) createPlaylist(ctx context.Context, model *importjob.ImportJob, name string) error {
+	created := playlist.Playlist{
+		UserID:      model.UserID,
+		Name:        name,
+		Description: truncate("Imported from "+model.Filename, 300),
+		Visibility:  playlist.VisibilityPrivate,
+	}
+
+	err := s.playlistRepo.Create(ctx, &created)
+	if err != nil {
+		log.Error().Err(err).Msg("service: error create imported playlist")
+		return err
+	}
+
+	model.PlaylistID = &created.ID
+	return nil
+}
+
+// addPlaylistTracks appends the tracks, in file order, to the playlist.
+func (s *importService) addPlaylistTracks(ctx context.Context, playlistID uint, spotifyIDs []string) error {
+	err := s.playlistRepo.EditTracks(ctx, playlistID, func(tracks []playlist.PlaylistTrack) ([]playlist.PlaylistTrack, error) {
+		for _, spotifyID := range spotifyIDs {
+			tracks = append(tracks, playlist.PlaylistTrack{SpotifyID: spotifyID})
+		}
+
+		return tracks, nil
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("service: error add imported playlist tracks")
+		return err
+	}
+
+	return nil
+}
+
+// getOwned reports jobs of other users as not found.
+func (s *importService) getOwned(ctx context.Context, userID, jobID uint) (*importjob.ImportJob, error) {
+	model, err := s.importRepo.Get(ctx, jobID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrImportNotFound
+		}
+
+		log.Error().Err(err).Msg("service: error get import job")
+		return nil, err
+	}
+
+	if model.UserID != userID {
+		return nil, ErrImportNotFound
+	}
+
+	return model, nil
+}
+
+// playlistName prefers the name sent with the upload, then the title in the
+// file, then the file name.
+func playlistName(name, title, filename string) string {
+	for _, candidate := range []string{name, title, strings.TrimSuffix(path.Base(filename), path.Ext(filename))} {
+		candidate = strings.TrimSpace(candidate)
+		if candidate != "" && candidate != "." && candidate != "/" {
+			return truncate(candidate, 100)
+		}
+	}
+
+	return "Imported playlist"
+}
+
+// unquote drops double quotes, they would end the quoted search filter.
+func unquote(value string) string {
+	return strings.ReplaceAll(value, `"`, "")
+}
+
+func truncate(value string, length int) string {
+	runes := []rune(value)
+	if len(runes) <= length {
+		return value
+	}
+
+	return string(runes[:length])
+}
+
+func toResponse(model importjob.ImportJob) importjob.ImportJobResponse {
+	return importjob.ImportJobResponse{
+		ID:         model.ID,
+		Status:     model.Status,
+		Target:     model.Target,
+		Format:     model.Format,
+		Filename:   model.Filename,
+		PlaylistID: model.PlaylistID,
+		Total:      model.Total,
+		Processed:  model.Processed,
+		Matched:    model.Matched,
+		Ambiguous:  model.Ambiguous,
+		Unmatched:  model.Unmatched,
+		Errored:    model.Errored,
+		Error:      model.Error,
+		CreatedAt:  model.CreatedAt,
+		FinishedAt: model.FinishedAt,
+	}
+}
+
+func toRowResponse(row importjob.ImportJobRow) importjob.ImportRowResponse {
+	artists := []string{}
+	if row.Artists != "" {
+		artists = strings.Split(row.Artists, "; ")
+	}
+
+	var candidates []string
+	if row.Candidates != "" {
+		candidates = strings.Split(row.Candidates, ",")
+	}
+
+	return importjob.ImportRowResponse{
+		RowNumber:  row.RowNumber,
+		Name:       row.Name,
+		Artists:    artists,
+		Album:      row.Album,
+		ISRC:       row.ISRC,
+		Status:     row.Status,
+		SpotifyID:  row.SpotifyID,
+		Candidates: candidates,
+	}
+}
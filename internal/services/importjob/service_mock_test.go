@@ -0,0 +1,145 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: repository.go
+//
+// Generated by this command:
+//
+//	mockgen -source=repository.go -destination=../../services/importjob/service_mock_test.go -package=importjob
+//
+
+// Package importjob is a generated GoMock package.
+package importjob
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	importjob "github.com/sgitwhyd/music-catalogue/internal/models/importjob"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockImportJobRepository is a mock of ImportJobRepository interface.
+type MockImportJobRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockImportJobRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockImportJobRepositoryMockRecorder is the mock recorder for MockImportJobRepository.
+type MockImportJobRepositoryMockRecorder struct {
+	mock *MockImportJobRepository
+}
+
+// NewMockImportJobRepository creates a new mock instance.
+func NewMockImportJobRepository(ctrl *gomock.Controller) *MockImportJobRepository {
+	mock := &MockImportJobRepository{ctrl: ctrl}
+	mock.recorder = &MockImportJobRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockImportJobRepository) EXPECT() *MockImportJobRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockImportJobRepository) Create(ctx context.Context, model *importjob.ImportJob) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, model)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockImportJobRepositoryMockRecorder) Create(ctx, model any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockImportJobRepository)(nil).Create), ctx, model)
+}
+
+// FailUnfinished mocks base method.
+func (m *MockImportJobRepository) FailUnfinished(ctx context.Context, message string, staleBefore time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FailUnfinished", ctx, message, staleBefore)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FailUnfinished indicates an expected call of FailUnfinished.
+func (mr *MockImportJobRepositoryMockRecorder) FailUnfinished(ctx, message, staleBefore any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FailUnfinished", reflect.TypeOf((*MockImportJobRepository)(nil).FailUnfinished), ctx, message, staleBefore)
+}
+
+// Get mocks base method.
+func (m *MockImportJobRepository) Get(ctx context.Context, id uint) (*importjob.ImportJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, id)
+	ret0, _ := ret[0].(*importjob.ImportJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockImportJobRepositoryMockRecorder) Get(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockImportJobRepository)(nil).Get), ctx, id)
+}
+
+// Heartbeat mocks base method.
+func (m *MockImportJobRepository) Heartbeat(ctx context.Context, id uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Heartbeat", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Heartbeat indicates an expected call of Heartbeat.
+func (mr *MockImportJobRepositoryMockRecorder) Heartbeat(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Heartbeat", reflect.TypeOf((*MockImportJobRepository)(nil).Heartbeat), ctx, id)
+}
+
+// ListRows mocks base method.
+func (m *MockImportJobRepository) ListRows(ctx context.Context, jobID uint, status string, limit, offset int) ([]importjob.ImportJobRow, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRows", ctx, jobID, status, limit, offset)
+	ret0, _ := ret[0].([]importjob.ImportJobRow)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListRows indicates an expected call of ListRows.
+func (mr *MockImportJobRepositoryMockRecorder) ListRows(ctx, jobID, status, limit, offset any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRows", reflect.TypeOf((*MockImportJobRepository)(nil).ListRows), ctx, jobID, status, limit, offset)
+}
+
+// SaveProgress mocks base method.
+func (m *MockImportJobRepository) SaveProgress(ctx context.Context, model *importjob.ImportJob, rows []importjob.ImportJobRow) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveProgress", ctx, model, rows)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveProgress indicates an expected call of SaveProgress.
+func (mr *MockImportJobRepositoryMockRecorder) SaveProgress(ctx, model, rows any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveProgress", reflect.TypeOf((*MockImportJobRepository)(nil).SaveProgress), ctx, model, rows)
+}
+
+// Update mocks base method.
+func (m *MockImportJobRepository) Update(ctx context.Context, model *importjob.ImportJob) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, model)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockImportJobRepositoryMockRecorder) Update(ctx, model any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockImportJobRepository)(nil).Update), ctx, model)
+}
@@ -0,0 +1,435 @@
+package importjob
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sgitwhyd/music-catalogue/internal/models/importjob"
+	"github.com/sgitwhyd/music-catalogue/internal/models/playlist"
+	"github.com/sgitwhyd/music-catalogue/internal/models/spotify"
+	spotifyRepo "github.com/sgitwhyd/music-catalogue/internal/repositorys/spotify"
+	"github.com/sgitwhyd/music-catalogue/pkg/httpclient"
+	"github.com/sgitwhyd/music-catalogue/pkg/trackio"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+func searchResult(tracks ...spotifyRepo.SpotifyTrackObject) *spotifyRepo.SpotifySearchResponse {
+	return &spotifyRepo.SpotifySearchResponse{
+		Tracks: spotifyRepo.SpotifyTrack{Items: tracks},
+	}
+}
+
+func Test_importService_Create(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockImportRepo := NewMockImportJobRepository(mockCtrl)
+	mockSpotifyOutbond := NewMockSpotifyOutbond(mockCtrl)
+	mockSpotifyRepo := NewMockSpotifyRepository(mockCtrl)
+	mockPlaylistRepo := NewMockPlaylistRepository(mockCtrl)
+
+	csvFile := "name,artists,isrc\n" +
+		"Bohemian Rhapsody,Queen,GBUM71029604\n" +
+		"Under Pressure,Queen; David Bowie,\n" +
+		"Nobody Knows,Nobody,\n"
+
+	// what the job looked like every time it was stored
+	var (
+		saved []importjob.ImportJob
+		rows  []importjob.ImportJobRow
+	)
+	recordUpdate := func(ctx context.Context, model *importjob.ImportJob) error {
+		saved = append(saved, *model)
+		return nil
+	}
+
+	resolveAll := func() {
+		mockImportRepo.EXPECT().Create(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, model *importjob.ImportJob) error {
+				model.ID = 7
+				return nil
+			})
+		mockImportRepo.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(recordUpdate).Times(2)
+		mockImportRepo.EXPECT().SaveProgress(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, model *importjob.ImportJob, batch []importjob.ImportJobRow) error {
+				rows = append(rows, batch...)
+				return nil
+			})
+		mockSpotifyOutbond.EXPECT().Search(gomock.Any(), spotifyRepo.SearchParams{Query: "isrc:GBUM71029604", Limit: 1}).
+			Return(searchResult(spotifyRepo.SpotifyTrackObject{ID: "a"}), nil)
+		mockSpotifyOutbond.EXPECT().Search(gomock.Any(), spotifyRepo.SearchParams{Query: `track:"Under Pressure" artist:"Queen"`, Limit: searchCandidates}).
+			Return(searchResult(
+				spotifyRepo.SpotifyTrackObject{ID: "b", Name: "Under Pressure"},
+				spotifyRepo.SpotifyTrackObject{ID: "c", Name: "Under Pressure"},
+			), nil)
+		mockSpotifyOutbond.EXPECT().Search(gomock.Any(), spotifyRepo.SearchParams{Query: `track:"Nobody Knows" artist:"Nobody"`, Limit: searchCandidates}).
+			Return(searchResult(), nil)
+	}
+
+	tests := []struct {
+		name     string
+		request  importjob.CreateImportRequest
+		filename string
+		file     string
+		mockFn   func()
+		wantJob  importjob.ImportJob
+		wantRows []string
+		wantErr  error
+	}{
+		{
+			name:     "should like the matched tracks",
+			request:  importjob.CreateImportRequest{Target: importjob.TargetLikes},
+			filename: "liked.csv",
+			file:     csvFile,
+			mockFn: func() {
+				resolveAll()
+				mockSpotifyRepo.EXPECT().Get(gomock.Any(), uint(1), "a").Return(nil, gorm.ErrRecordNotFound)
+				mockSpotifyRepo.EXPECT().Create(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, model spotify.TrackActivity) error {
+						assert.Equal(t, "a", model.SpotifyID)
+						assert.True(t, *model.IsLiked)
+						return nil
+					})
+			},
+			wantJob: importjob.ImportJob{
+				Status:    importjob.StatusCompleted,
+				Processed: 3,
+				Matched:   1,
+				Ambiguous: 1,
+				Unmatched: 1,
+			},
+			wantRows: []string{importjob.RowMatched, importjob.RowAmbiguous, importjob.RowUnmatched},
+		},
+		{
+			name:     "should put the matched tracks in a new playlist",
+			request:  importjob.CreateImportRequest{Target: importjob.TargetPlaylist, Format: "csv", Name: "Road Trip"},
+			filename: "export",
+			file:     csvFile,
+			mockFn: func() {
+				resolveAll()
+				mockPlaylistRepo.EXPECT().Create(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, model *playlist.Playlist) error {
+						assert.Equal(t, "Road Trip", model.Name)
+						assert.Equal(t, playlist.VisibilityPrivate, model.Visibility)
+						model.ID = 3
+						return nil
+					})
+				mockPlaylistRepo.EXPECT().EditTracks(gomock.Any(), uint(3), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, playlistID uint, edit func([]playlist.PlaylistTrack) ([]playlist.PlaylistTrack, error)) error {
+						tracks, err := edit([]playlist.PlaylistTrack{})
+						assert.Equal(t, []playlist.PlaylistTrack{{SpotifyID: "a"}}, tracks)
+						return err
+					})
+			},
+			wantJob: importjob.ImportJob{
+				Status:    importjob.StatusCompleted,
+				Processed: 3,
+				Matched:   1,
+				Ambiguous: 1,
+				Unmatched: 1,
+			},
+			wantRows: []string{importjob.RowMatched, importjob.RowAmbiguous, importjob.RowUnmatched},
+		},
+		{
+			name:     "should report the rows spotify rejects and keep going",
+			request:  importjob.CreateImportRequest{Target: importjob.TargetLikes},
+			filename: "liked.m3u8",
+			file:     "#EXTINF:355,Queen - Bohemian Rhapsody\n01.mp3\n#EXTINF:248,Queen - Under Pressure\n02.mp3\n",
+			mockFn: func() {
+				mockImportRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+				mockImportRepo.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(recordUpdate).Times(2)
+				mockImportRepo.EXPECT().SaveProgress(gomock.Any(), gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, model *importjob.ImportJob, batch []importjob.ImportJobRow) error {
+						rows = append(rows, batch...)
+						return nil
+					})
+				mockSpotifyOutbond.EXPECT().Search(gomock.Any(), spotifyRepo.SearchParams{Query: `track:"Bohemian Rhapsody" artist:"Queen"`, Limit: searchCandidates}).
+					Return(nil, &spotifyRepo.StatusError{StatusCode: 400})
+				mockSpotifyOutbond.EXPECT().Search(gomock.Any(), spotifyRepo.SearchParams{Query: `track:"Under Pressure" artist:"Queen"`, Limit: searchCandidates}).
+					Return(searchResult(spotifyRepo.SpotifyTrackObject{ID: "b"}), nil)
+				mockSpotifyRepo.EXPECT().Get(gomock.Any(), uint(1), "b").Return(nil, gorm.ErrRecordNotFound)
+				mockSpotifyRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+			},
+			wantJob: importjob.ImportJob{
+				Status:    importjob.StatusCompleted,
+				Processed: 2,
+				Matched:   1,
+				Errored:   1,
+			},
+			wantRows: []string{importjob.RowError, importjob.RowMatched},
+		},
+		{
+			name:     "should retry a row until the breaker closes again",
+			request:  importjob.CreateImportRequest{Target: importjob.TargetLikes},
+			filename: "liked.m3u8",
+			file:     "#EXTINF:355,Queen - Bohemian Rhapsody\n01.mp3\n",
+			mockFn: func() {
+				mockImportRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+				mockImportRepo.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(recordUpdate).Times(2)
+				mockImportRepo.EXPECT().SaveProgress(gomock.Any(), gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, model *importjob.ImportJob, batch []importjob.ImportJobRow) error {
+						rows = append(rows, batch...)
+						return nil
+					})
+				gomock.InOrder(
+					mockSpotifyOutbond.EXPECT().Search(gomock.Any(), gomock.Any()).Return(nil, httpclient.ErrCircuitOpen),
+					mockSpotifyOutbond.EXPECT().Search(gomock.Any(), gomock.Any()).Return(searchResult(spotifyRepo.SpotifyTrackObject{ID: "a"}), nil),
+				)
+				mockSpotifyRepo.EXPECT().Get(gomock.Any(), uint(1), "a").Return(nil, gorm.ErrRecordNotFound)
+				mockSpotifyRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+			},
+			wantJob: importjob.ImportJob{
+				Status:    importjob.StatusCompleted,
+				Processed: 1,
+				Matched:   1,
+			},
+			wantRows: []string{importjob.RowMatched},
+		},
+		{
+			name:     "should fail the job while the breaker stays open",
+			request:  importjob.CreateImportRequest{Target: importjob.TargetLikes},
+			filename: "liked.m3u8",
+			file:     "#EXTINF:355,Queen - Bohemian Rhapsody\n01.mp3\n#EXTINF:248,Queen - Under Pressure\n02.mp3\n",
+			mockFn: func() {
+				mockImportRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+				mockImportRepo.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(recordUpdate).Times(2)
+				mockSpotifyOutbond.EXPECT().Search(gomock.Any(), gomock.Any()).Return(nil, httpclient.ErrCircuitOpen).Times(resolveAttempts)
+			},
+			wantJob: importjob.ImportJob{
+				Status: importjob.StatusFailed,
+				Error:  "the music provider is unavailable, try again later",
+			},
+		},
+		{
+			name:     "should fail the job when the matches can't be applied",
+			request:  importjob.CreateImportRequest{Target: importjob.TargetLikes},
+			filename: "liked.m3u8",
+			file:     "#EXTINF:355,Queen - Bohemian Rhapsody\n01.mp3\n",
+			mockFn: func() {
+				mockImportRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+				mockImportRepo.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(recordUpdate).Times(2)
+				mockSpotifyOutbond.EXPECT().Search(gomock.Any(), gomock.Any()).Return(searchResult(spotifyRepo.SpotifyTrackObject{ID: "a"}), nil)
+				mockSpotifyRepo.EXPECT().Get(gomock.Any(), uint(1), "a").Return(nil, assert.AnError)
+			},
+			wantJob: importjob.ImportJob{
+				Status:    importjob.StatusFailed,
+				Processed: 1,
+				Matched:   1,
+				Error:     "internal server error",
+			},
+		},
+		{
+			name:     "should reject an unknown extension",
+			request:  importjob.CreateImportRequest{Target: importjob.TargetLikes},
+			filename: "liked.txt",
+			mockFn:   func() {},
+			wantErr:  ErrUnknownImportFormat,
+		},
+		{
+			name:     "should reject a malformed file",
+			request:  importjob.CreateImportRequest{Target: importjob.TargetLikes},
+			filename: "liked.json",
+			file:     "{",
+			mockFn:   func() {},
+			wantErr:  ErrMalformedImport,
+		},
+		{
+			name:     "should reject a file without tracks",
+			request:  importjob.CreateImportRequest{Target: importjob.TargetLikes},
+			filename: "liked.csv",
+			file:     "name\n",
+			mockFn:   func() {},
+			wantErr:  ErrEmptyImport,
+		},
+		{
+			name:     "should reject more tracks than a playlist holds",
+			request:  importjob.CreateImportRequest{Target: importjob.TargetPlaylist},
+			filename: "big.m3u",
+			file:     strings.Repeat("song.mp3\n", maxImportPlaylistTracks+1),
+			mockFn:   func() {},
+			wantErr:  ErrImportTooLarge,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			saved, rows = nil, nil
+			tt.mockFn()
+
+			s := NewImportService(mockImportRepo, mockSpotifyOutbond, mockSpotifyRepo, mockPlaylistRepo)
+			s.retryDelay = time.Millisecond
+
+			got, err := s.Create(context.Background(), 1, tt.request, tt.filename, strings.NewReader(tt.file))
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, importjob.StatusPending, got.Status)
+
+			s.jobs.Wait()
+
+			assert.Len(t, saved, 2)
+			assert.Equal(t, importjob.StatusRunning, saved[0].Status)
+
+			final := saved[len(saved)-1]
+			assert.Equal(t, tt.wantJob.Status, final.Status)
+			assert.Equal(t, tt.wantJob.Processed, final.Processed)
+			assert.Equal(t, tt.wantJob.Matched, final.Matched)
+			assert.Equal(t, tt.wantJob.Ambiguous, final.Ambiguous)
+			assert.Equal(t, tt.wantJob.Unmatched, final.Unmatched)
+			assert.Equal(t, tt.wantJob.Errored, final.Errored)
+			assert.Equal(t, tt.wantJob.Error, final.Error)
+			assert.NotNil(t, final.FinishedAt)
+
+			statuses := make([]string, 0, len(rows))
+			for idx, row := range rows {
+				assert.Equal(t, idx+1, row.RowNumber)
+				statuses = append(statuses, row.Status)
+				if row.Status == importjob.RowAmbiguous {
+					assert.Equal(t, "b,c", row.Candidates)
+				}
+			}
+			if tt.wantRows != nil {
+				assert.Equal(t, tt.wantRows, statuses)
+			}
+		})
+	}
+}
+
+func Test_importService_resolve(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockSpotifyOutbond := NewMockSpotifyOutbond(mockCtrl)
+
+	queen := []spotifyRepo.SpotifyArtisObject{{Name: "Queen"}}
+
+	tests := []struct {
+		name          string
+		track         trackio.Track
+		mockFn        func()
+		wantStatus    string
+		wantSpotifyID string
+	}{
+		{
+			name:  "should fall back to the title when the isrc is unknown",
+			track: trackio.Track{Name: "Bohemian Rhapsody", Artists: []string{"Queen"}, ISRC: "XX0000000000"},
+			mockFn: func() {
+				mockSpotifyOutbond.EXPECT().Search(gomock.Any(), spotifyRepo.SearchParams{Query: "isrc:XX0000000000", Limit: 1}).
+					Return(searchResult(), nil)
+				mockSpotifyOutbond.EXPECT().Search(gomock.Any(), spotifyRepo.SearchParams{Query: `track:"Bohemian Rhapsody" artist:"Queen"`, Limit: searchCandidates}).
+					Return(searchResult(spotifyRepo.SpotifyTrackObject{ID: "a"}), nil)
+			},
+			wantStatus:    importjob.RowMatched,
+			wantSpotifyID: "a",
+		},
+		{
+			name:  "should pick the only exact title and artist",
+			track: trackio.Track{Name: "bohemian rhapsody", Artists: []string{"queen"}},
+			mockFn: func() {
+				mockSpotifyOutbond.EXPECT().Search(gomock.Any(), gomock.Any()).Return(searchResult(
+					spotifyRepo.SpotifyTrackObject{ID: "live", Name: "Bohemian Rhapsody - Live Aid", Artists: queen},
+					spotifyRepo.SpotifyTrackObject{ID: "a", Name: "Bohemian Rhapsody", Artists: queen},
+					spotifyRepo.SpotifyTrackObject{ID: "cover", Name: "Bohemian Rhapsody", Artists: []spotifyRepo.SpotifyArtisObject{{Name: "Panic! At The Disco"}}},
+				), nil)
+			},
+			wantStatus:    importjob.RowMatched,
+			wantSpotifyID: "a",
+		},
+		{
+			name:  "should break a tie on the length",
+			track: trackio.Track{Name: "Bohemian Rhapsody", Artists: []string{"Queen"}, DurationMs: 355000},
+			mockFn: func() {
+				mockSpotifyOutbond.EXPECT().Search(gomock.Any(), gomock.Any()).Return(searchResult(
+					spotifyRepo.SpotifyTrackObject{ID: "remaster", Name: "Bohemian Rhapsody", Artists: queen, DurationMs: 359000},
+					spotifyRepo.SpotifyTrackObject{ID: "a", Name: "Bohemian Rhapsody", Artists: queen, DurationMs: 354947},
+				), nil)
+			},
+			wantStatus:    importjob.RowMatched,
+			wantSpotifyID: "a",
+		},
+		{
+			name:  "should be ambiguous when the length doesn't tell them apart",
+			track: trackio.Track{Name: "Bohemian Rhapsody", Artists: []string{"Queen"}},
+			mockFn: func() {
+				mockSpotifyOutbond.EXPECT().Search(gomock.Any(), gomock.Any()).Return(searchResult(
+					spotifyRepo.SpotifyTrackObject{ID: "remaster", Name: "Bohemian Rhapsody", Artists: queen},
+					spotifyRepo.SpotifyTrackObject{ID: "a", Name: "Bohemian Rhapsody", Artists: queen},
+				), nil)
+			},
+			wantStatus: importjob.RowAmbiguous,
+		},
+		{
+			name:       "should not search without a title",
+			track:      trackio.Track{URI: "01.mp3"},
+			mockFn:     func() {},
+			wantStatus: importjob.RowUnmatched,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFn()
+
+			s := NewImportService(nil, mockSpotifyOutbond, nil, nil)
+			row, err := s.resolve(context.Background(), tt.track)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, row.Status)
+			assert.Equal(t, tt.wantSpotifyID, row.SpotifyID)
+		})
+	}
+}
+
+func Test_importService_Get(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockImportRepo := NewMockImportJobRepository(mockCtrl)
+
+	tests := []struct {
+		name    string
+		mockFn  func()
+		wantErr error
+	}{
+		{
+			name: "success",
+			mockFn: func() {
+				mockImportRepo.EXPECT().Get(gomock.Any(), uint(7)).Return(&importjob.ImportJob{ID: 7, UserID: 1, Status: importjob.StatusRunning}, nil)
+			},
+		},
+		{
+			name: "should hide jobs of other users",
+			mockFn: func() {
+				mockImportRepo.EXPECT().Get(gomock.Any(), uint(7)).Return(&importjob.ImportJob{ID: 7, UserID: 2}, nil)
+			},
+			wantErr: ErrImportNotFound,
+		},
+		{
+			name: "not found",
+			mockFn: func() {
+				mockImportRepo.EXPECT().Get(gomock.Any(), uint(7)).Return(nil, gorm.ErrRecordNotFound)
+			},
+			wantErr: ErrImportNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFn()
+
+			s := NewImportService(mockImportRepo, nil, nil, nil)
+			got, err := s.Get(context.Background(), 1, 7)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, uint(7), got.ID)
+			assert.Equal(t, importjob.StatusRunning, got.Status)
+		})
+	}
+}
@@ -0,0 +1,321 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ../../repositorys/spotify/repository.go
+//
+// Generated by this command:
+//
+//	mockgen -source=../../repositorys/spotify/repository.go -destination=spotify_mock_test.go -package=importjob
+//
+
+// Package importjob is a generated GoMock package.
+package importjob
+
+import (
+	context "context"
+	reflect "reflect"
+
+	spotify "github.com/sgitwhyd/music-catalogue/internal/models/spotify"
+	spotify0 "github.com/sgitwhyd/music-catalogue/internal/repositorys/spotify"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockSpotifyOutbond is a mock of SpotifyOutbond interface.
+type MockSpotifyOutbond struct {
+	ctrl     *gomock.Controller
+	recorder *MockSpotifyOutbondMockRecorder
+	isgomock struct{}
+}
+
+// MockSpotifyOutbondMockRecorder is the mock recorder for MockSpotifyOutbond.
+type MockSpotifyOutbondMockRecorder struct {
+	mock *MockSpotifyOutbond
+}
+
+// NewMockSpotifyOutbond creates a new mock instance.
+func NewMockSpotifyOutbond(ctrl *gomock.Controller) *MockSpotifyOutbond {
+	mock := &MockSpotifyOutbond{ctrl: ctrl}
+	mock.recorder = &MockSpotifyOutbondMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSpotifyOutbond) EXPECT() *MockSpotifyOutbondMockRecorder {
+	return m.recorder
+}
+
+// GetAlbum mocks base method.
+func (m *MockSpotifyOutbond) GetAlbum(ctx context.Context, albumID, market string) (*spotify0.SpotifyFullAlbumObject, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAlbum", ctx, albumID, market)
+	ret0, _ := ret[0].(*spotify0.SpotifyFullAlbumObject)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAlbum indicates an expected call of GetAlbum.
+func (mr *MockSpotifyOutbondMockRecorder) GetAlbum(ctx, albumID, market any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAlbum", reflect.TypeOf((*MockSpotifyOutbond)(nil).GetAlbum), ctx, albumID, market)
+}
+
+// GetAlbumTracks mocks base method.
+func (m *MockSpotifyOutbond) GetAlbumTracks(ctx context.Context, params spotify0.AlbumTracksParams) (*spotify0.SpotifyTrack, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAlbumTracks", ctx, params)
+	ret0, _ := ret[0].(*spotify0.SpotifyTrack)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAlbumTracks indicates an expected call of GetAlbumTracks.
+func (mr *MockSpotifyOutbondMockRecorder) GetAlbumTracks(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAlbumTracks", reflect.TypeOf((*MockSpotifyOutbond)(nil).GetAlbumTracks), ctx, params)
+}
+
+// GetArtist mocks base method.
+func (m *MockSpotifyOutbond) GetArtist(ctx context.Context, artistID string) (*spotify0.SpotifyFullArtistObject, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetArtist", ctx, artistID)
+	ret0, _ := ret[0].(*spotify0.SpotifyFullArtistObject)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetArtist indicates an expected call of GetArtist.
+func (mr *MockSpotifyOutbondMockRecorder) GetArtist(ctx, artistID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetArtist", reflect.TypeOf((*MockSpotifyOutbond)(nil).GetArtist), ctx, artistID)
+}
+
+// GetArtistAlbums mocks base method.
+func (m *MockSpotifyOutbond) GetArtistAlbums(ctx context.Context, params spotify0.ArtistAlbumsParams) (*spotify0.SpotifyAlbums, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetArtistAlbums", ctx, params)
+	ret0, _ := ret[0].(*spotify0.SpotifyAlbums)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetArtistAlbums indicates an expected call of GetArtistAlbums.
+func (mr *MockSpotifyOutbondMockRecorder) GetArtistAlbums(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetArtistAlbums", reflect.TypeOf((*MockSpotifyOutbond)(nil).GetArtistAlbums), ctx, params)
+}
+
+// GetArtistTopTracks mocks base method.
+func (m *MockSpotifyOutbond) GetArtistTopTracks(ctx context.Context, artistID, market string) ([]spotify0.SpotifyTrackObject, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetArtistTopTracks", ctx, artistID, market)
+	ret0, _ := ret[0].([]spotify0.SpotifyTrackObject)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetArtistTopTracks indicates an expected call of GetArtistTopTracks.
+func (mr *MockSpotifyOutbondMockRecorder) GetArtistTopTracks(ctx, artistID, market any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetArtistTopTracks", reflect.TypeOf((*MockSpotifyOutbond)(nil).GetArtistTopTracks), ctx, artistID, market)
+}
+
+// GetRecommendations mocks base method.
+func (m *MockSpotifyOutbond) GetRecommendations(ctx context.Context, params spotify0.RecommendationsParams) (*spotify0.SpotifyRecommendationsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRecommendations", ctx, params)
+	ret0, _ := ret[0].(*spotify0.SpotifyRecommendationsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRecommendations indicates an expected call of GetRecommendations.
+func (mr *MockSpotifyOutbondMockRecorder) GetRecommendations(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRecommendations", reflect.TypeOf((*MockSpotifyOutbond)(nil).GetRecommendations), ctx, params)
+}
+
+// GetSeveralTracks mocks base method.
+func (m *MockSpotifyOutbond) GetSeveralTracks(ctx context.Context, spotifyIDs []string) ([]spotify0.SpotifyTrackObject, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSeveralTracks", ctx, spotifyIDs)
+	ret0, _ := ret[0].([]spotify0.SpotifyTrackObject)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSeveralTracks indicates an expected call of GetSeveralTracks.
+func (mr *MockSpotifyOutbondMockRecorder) GetSeveralTracks(ctx, spotifyIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSeveralTracks", reflect.TypeOf((*MockSpotifyOutbond)(nil).GetSeveralTracks), ctx, spotifyIDs)
+}
+
+// GetTrack mocks base method.
+func (m *MockSpotifyOutbond) GetTrack(ctx context.Context, spotifyID string) (*spotify0.SpotifyTrackObject, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTrack", ctx, spotifyID)
+	ret0, _ := ret[0].(*spotify0.SpotifyTrackObject)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTrack indicates an expected call of GetTrack.
+func (mr *MockSpotifyOutbondMockRecorder) GetTrack(ctx, spotifyID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTrack", reflect.TypeOf((*MockSpotifyOutbond)(nil).GetTrack), ctx, spotifyID)
+}
+
+// Search mocks base method.
+func (m *MockSpotifyOutbond) Search(ctx context.Context, params spotify0.SearchParams) (*spotify0.SpotifySearchResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Search", ctx, params)
+	ret0, _ := ret[0].(*spotify0.SpotifySearchResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Search indicates an expected call of Search.
+func (mr *MockSpotifyOutbondMockRecorder) Search(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockSpotifyOutbond)(nil).Search), ctx, params)
+}
+
+// MockSpotifyRepository is a mock of SpotifyRepository interface.
+type MockSpotifyRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockSpotifyRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockSpotifyRepositoryMockRecorder is the mock recorder for MockSpotifyRepository.
+type MockSpotifyRepositoryMockRecorder struct {
+	mock *MockSpotifyRepository
+}
+
+// NewMockSpotifyRepository creates a new mock instance.
+func NewMockSpotifyRepository(ctrl *gomock.Controller) *MockSpotifyRepository {
+	mock := &MockSpotifyRepository{ctrl: ctrl}
+	mock.recorder = &MockSpotifyRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSpotifyRepository) EXPECT() *MockSpotifyRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockSpotifyRepository) Create(ctx context.Context, model spotify.TrackActivity) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, model)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockSpotifyRepositoryMockRecorder) Create(ctx, model any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockSpotifyRepository)(nil).Create), ctx, model)
+}
+
+// Get mocks base method.
+func (m *MockSpotifyRepository) Get(ctx context.Context, UserID uint, spotifyID string) (*spotify.TrackActivity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, UserID, spotifyID)
+	ret0, _ := ret[0].(*spotify.TrackActivity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockSpotifyRepositoryMockRecorder) Get(ctx, UserID, spotifyID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockSpotifyRepository)(nil).Get), ctx, UserID, spotifyID)
+}
+
+// GetBulkSpotifyIDs mocks base method.
+func (m *MockSpotifyRepository) GetBulkSpotifyIDs(ctx context.Context, UserID uint, spotifyIDs []string) (map[string]spotify.TrackActivity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBulkSpotifyIDs", ctx, UserID, spotifyIDs)
+	ret0, _ := ret[0].(map[string]spotify.TrackActivity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBulkSpotifyIDs indicates an expected call of GetBulkSpotifyIDs.
+func (mr *MockSpotifyRepositoryMockRecorder) GetBulkSpotifyIDs(ctx, UserID, spotifyIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBulkSpotifyIDs", reflect.TypeOf((*MockSpotifyRepository)(nil).GetBulkSpotifyIDs), ctx, UserID, spotifyIDs)
+}
+
+// GetBulkTags mocks base method.
+func (m *MockSpotifyRepository) GetBulkTags(ctx context.Context, UserID uint, spotifyIDs []string) (map[string][]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBulkTags", ctx, UserID, spotifyIDs)
+	ret0, _ := ret[0].(map[string][]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBulkTags indicates an expected call of GetBulkTags.
+func (mr *MockSpotifyRepositoryMockRecorder) GetBulkTags(ctx, UserID, spotifyIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBulkTags", reflect.TypeOf((*MockSpotifyRepository)(nil).GetBulkTags), ctx, UserID, spotifyIDs)
+}
+
+// ListActivities mocks base method.
+func (m *MockSpotifyRepository) ListActivities(ctx context.Context, UserID uint, filter spotify.ActivityFilter) ([]spotify.TrackActivity, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListActivities", ctx, UserID, filter)
+	ret0, _ := ret[0].([]spotify.TrackActivity)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListActivities indicates an expected call of ListActivities.
+func (mr *MockSpotifyRepositoryMockRecorder) ListActivities(ctx, UserID, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListActivities", reflect.TypeOf((*MockSpotifyRepository)(nil).ListActivities), ctx, UserID, filter)
+}
+
+// ListAllActivities mocks base method.
+func (m *MockSpotifyRepository) ListAllActivities(ctx context.Context, UserID uint) ([]spotify.TrackActivity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAllActivities", ctx, UserID)
+	ret0, _ := ret[0].([]spotify.TrackActivity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAllActivities indicates an expected call of ListAllActivities.
+func (mr *MockSpotifyRepositoryMockRecorder) ListAllActivities(ctx, UserID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAllActivities", reflect.TypeOf((*MockSpotifyRepository)(nil).ListAllActivities), ctx, UserID)
+}
+
+// ListLikedBefore mocks base method.
+func (m *MockSpotifyRepository) ListLikedBefore(ctx context.Context, UserID, beforeID uint, limit int) ([]spotify.TrackActivity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListLikedBefore", ctx, UserID, beforeID, limit)
+	ret0, _ := ret[0].([]spotify.TrackActivity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListLikedBefore indicates an expected call of ListLikedBefore.
+func (mr *MockSpotifyRepositoryMockRecorder) ListLikedBefore(ctx, UserID, beforeID, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLikedBefore", reflect.TypeOf((*MockSpotifyRepository)(nil).ListLikedBefore), ctx, UserID, beforeID, limit)
+}
+
+// Update mocks base method.
+func (m *MockSpotifyRepository) Update(ctx context.Context, model spotify.TrackActivity) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, model)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockSpotifyRepositoryMockRecorder) Update(ctx, model any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockSpotifyRepository)(nil).Update), ctx, model)
+}
@@ -0,0 +1,223 @@
+package play
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/sgitwhyd/music-catalogue/internal/apperror"
+	"github.com/sgitwhyd/music-catalogue/internal/models/play"
+	"github.com/sgitwhyd/music-catalogue/internal/models/spotify"
+	playRepo "github.com/sgitwhyd/music-catalogue/internal/repositorys/play"
+	spotifyRepo "github.com/sgitwhyd/music-catalogue/internal/repositorys/spotify"
+	spotifySvc "github.com/sgitwhyd/music-catalogue/internal/services/spotify"
+)
+
+//go:generate mockgen -source=service.go -destination=../../handlers/play/handler_mock_test.go -package=play
+//go:generate mockgen -source=../../repositorys/spotify/repository.go -destination=spotify_mock_test.go -package=play
+type PlayService interface {
+	Record(ctx context.Context, userID uint, request play.CreatePlayRequest) (*play.PlayResponse, error)
+	ListRecent(ctx context.Context, userID uint, request play.RecentPlaysRequest) (*play.RecentPlaysResponse, error)
+}
+
+var (
+	ErrInvalidPlayedAt = apperror.New(apperror.KindInvalid, "invalid_played_at", "played_at can't be in the future")
+	ErrPlayTooOld      = apperror.New(apperror.KindInvalid, "play_too_old", "played_at can't be more than 30 days ago")
+	ErrInvalidCursor   = apperror.New(apperror.KindInvalid, "invalid_cursor", "cursor is malformed")
+)
+
+const (
+	defaultRecentPlaysLimit = 20
+	maxRecentPlaysLimit     = 50
+
+	// how far ahead of the server clock a client may report a play
+	maxPlayedAtSkew = 5 * time.Minute
+	// how far back a client may report a play, only the partitions for this
+	// window are kept ready
+	maxPlayAge = 30 * 24 * time.Hour
+)
+
+type playService struct {
+	playRepo       playRepo.PlayRepository
+	spotifyOutbond spotifyRepo.SpotifyOutbond
+	spotifyRepo    spotifyRepo.SpotifyRepository
+
+	now func() time.Time
+}
+
+func NewPlayService(playRepo playRepo.PlayRepository, spotifyOutbond spotifyRepo.SpotifyOutbond, spotifyRepo spotifyRepo.SpotifyRepository) *playService {
+	return &playService{
+		playRepo:       playRepo,
+		spotifyOutbond: spotifyOutbond,
+		spotifyRepo:    spotifyRepo,
+		now:            time.Now,
+	}
+}
+
+// Record stores a play as reported by the client. The track isn't looked up,
+// plays of tracks spotify doesn't know are listed without metadata.
+func (s *playService) Record(ctx context.Context, userID uint, request play.CreatePlayRequest) (*play.PlayResponse, error) {
+	now := s.now()
+
+	// postgres keeps microseconds, keep the response in line with what is stored
+	playedAt := now
+	if request.PlayedAt != nil {
+		playedAt = *request.PlayedAt
+	}
+	playedAt = playedAt.UTC().Truncate(time.Microsecond)
+
+	if playedAt.After(now.Add(maxPlayedAtSkew)) {
+		return nil, ErrInvalidPlayedAt
+	}
+
+	if playedAt.Before(now.Add(-maxPlayAge)) {
+		return nil, ErrPlayTooOld
+	}
+
+	model := play.PlayEvent{
+		UserID:           userID,
+		SpotifyID:        request.SpotifyID,
+		PlayedAt:         playedAt,
+		DurationPlayedMs: request.DurationPlayedMs,
+		Client:           request.Client,
+		Device:           request.Device,
+	}
+
+	err := s.playRepo.Create(ctx, &model)
+	if err != nil {
+		log.Error().Err(err).Msg("service: error create play event")
+		return nil, err
+	}
+
+	response := toResponse(model)
+	return &response, nil
+}
+
+// EnsurePartitions creates the partitions plays can currently be recorded
+// in, from the oldest accepted play through next month. Call it on boot and
+// daily so the next month's partition exists before it is needed.
+func (s *playService) EnsurePartitions(ctx context.Context) error {
+	now := s.now()
+
+	err := s.playRepo.EnsurePartitions(ctx, now.Add(-maxPlayAge), now.AddDate(0, 1, 0))
+	if err != nil {
+		log.Error().Err(err).Msg("service: error ensure play event partitions")
+		return err
+	}
+
+	return nil
+}
+
+func (s *playService) ListRecent(ctx context.Context, userID uint, request play.RecentPlaysRequest) (*play.RecentPlaysResponse, error) {
+	limit := request.Limit
+	if limit <= 0 || limit > maxRecentPlaysLimit {
+		limit = defaultRecentPlaysLimit
+	}
+
+	var after *play.Cursor
+	if request.Cursor != "" {
+		cursor, err := DecodeCursor(request.Cursor)
+		if err != nil {
+			return nil, ErrInvalidCursor
+		}
+		after = &cursor
+	}
+
+	// one extra play tells whether there is a next page
+	events, err := s.playRepo.ListRecent(ctx, userID, after, limit+1)
+	if err != nil {
+		log.Error().Err(err).Msg("service: error list play events")
+		return nil, err
+	}
+
+	var nextCursor string
+	if len(events) > limit {
+		events = events[:limit]
+
+		last := events[len(events)-1]
+		nextCursor = EncodeCursor(play.Cursor{PlayedAt: last.PlayedAt, ID: last.ID})
+	}
+
+	spotifyIDs := make([]string, 0, len(events))
+	seen := make(map[string]struct{}, len(events))
+	for _, event := range events {
+		if _, ok := seen[event.SpotifyID]; !ok {
+			seen[event.SpotifyID] = struct{}{}
+			spotifyIDs = append(spotifyIDs, event.SpotifyID)
+		}
+	}
+
+	tracksByID := make(map[string]spotify.SpotifyTrackObjectResponse, len(spotifyIDs))
+	if len(spotifyIDs) > 0 {
+		tracks, err := s.spotifyOutbond.GetSeveralTracks(ctx, spotifyIDs)
+		if err != nil {
+			log.Error().Err(err).Msg("error get several tracks spotify")
+			return nil, err
+		}
+
+		trackActivities, err := s.spotifyRepo.GetBulkSpotifyIDs(ctx, userID, spotifyIDs)
+		if err != nil {
+			log.Error().Err(err).Msg("error get track activities from db")
+			return nil, err
+		}
+
+		for _, track := range tracks {
+			tracksByID[track.ID] = spotifySvc.TrackToResponse(track, trackActivities)
+		}
+	}
+
+	items := make([]play.RecentPlayResponse, len(events))
+	for idx, event := range events {
+		items[idx] = play.RecentPlayResponse{
+			PlayResponse: toResponse(event),
+		}
+
+		if track, ok := tracksByID[event.SpotifyID]; ok {
+			items[idx].Track = &track
+		}
+	}
+
+	return &play.RecentPlaysResponse{
+		Items:      items,
+		NextCursor: nextCursor,
+	}, nil
+}
+
+func toResponse(model play.PlayEvent) play.PlayResponse {
+	return play.PlayResponse{
+		ID:               model.ID,
+		SpotifyID:        model.SpotifyID,
+		PlayedAt:         model.PlayedAt,
+		DurationPlayedMs: model.DurationPlayedMs,
+		Client:           model.Client,
+		Device:           model.Device,
+	}
+}
+
+// EncodeCursor makes an opaque cursor out of the position of a play.
+func EncodeCursor(cursor play.Cursor) string {
+	raw := fmt.Sprintf("%d:%d", cursor.PlayedAt.UnixMicro(), cursor.ID)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeCursor reverses EncodeCursor.
+func DecodeCursor(value string) (play.Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return play.Cursor{}, err
+	}
+
+	var micros int64
+	var id uint
+	_, err = fmt.Sscanf(string(raw), "%d:%d", &micros, &id)
+	if err != nil {
+		return play.Cursor{}, err
+	}
+
+	return play.Cursor{
+		PlayedAt: time.UnixMicro(micros).UTC(),
+		ID:       id,
+	}, nil
+}
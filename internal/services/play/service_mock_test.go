@@ -0,0 +1,86 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: repository.go
+//
+// Generated by this command:
+//
+//	mockgen -source=repository.go -destination=../../services/play/service_mock_test.go -package=play
+//
+
+// Package play is a generated GoMock package.
+package play
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	play "github.com/sgitwhyd/music-catalogue/internal/models/play"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockPlayRepository is a mock of PlayRepository interface.
+type MockPlayRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockPlayRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockPlayRepositoryMockRecorder is the mock recorder for MockPlayRepository.
+type MockPlayRepositoryMockRecorder struct {
+	mock *MockPlayRepository
+}
+
+// NewMockPlayRepository creates a new mock instance.
+func NewMockPlayRepository(ctrl *gomock.Controller) *MockPlayRepository {
+	mock := &MockPlayRepository{ctrl: ctrl}
+	mock.recorder = &MockPlayRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPlayRepository) EXPECT() *MockPlayRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockPlayRepository) Create(ctx context.Context, model *play.PlayEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, model)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockPlayRepositoryMockRecorder) Create(ctx, model any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockPlayRepository)(nil).Create), ctx, model)
+}
+
+// EnsurePartitions mocks base method.
+func (m *MockPlayRepository) EnsurePartitions(ctx context.Context, from, to time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnsurePartitions", ctx, from, to)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EnsurePartitions indicates an expected call of EnsurePartitions.
+func (mr *MockPlayRepositoryMockRecorder) EnsurePartitions(ctx, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnsurePartitions", reflect.TypeOf((*MockPlayRepository)(nil).EnsurePartitions), ctx, from, to)
+}
+
+// ListRecent mocks base method.
+func (m *MockPlayRepository) ListRecent(ctx context.Context, UserID uint, after *play.Cursor, limit int) ([]play.PlayEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRecent", ctx, UserID, after, limit)
+	ret0, _ := ret[0].([]play.PlayEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRecent indicates an expected call of ListRecent.
+func (mr *MockPlayRepositoryMockRecorder) ListRecent(ctx, UserID, after, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRecent", reflect.TypeOf((*MockPlayRepository)(nil).ListRecent), ctx, UserID, after, limit)
+}
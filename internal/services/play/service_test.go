@@ -0,0 +1,213 @@
+package play
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sgitwhyd/music-catalogue/internal/models/play"
+	"github.com/sgitwhyd/music-catalogue/internal/models/spotify"
+	spotifyRepo "github.com/sgitwhyd/music-catalogue/internal/repositorys/spotify"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func Test_playService_Record(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockPlayRepo := NewMockPlayRepository(mockCtrl)
+
+	now := time.Date(2026, 10, 18, 7, 0, 0, 123456789, time.UTC)
+	earlier := now.Add(-time.Hour)
+	future := now.Add(time.Hour)
+	tooOld := now.AddDate(0, 0, -31)
+
+	tests := []struct {
+		name    string
+		request play.CreatePlayRequest
+		mockFn  func()
+		want    *play.PlayResponse
+		wantErr error
+	}{
+		{
+			name:    "should default played at to now",
+			request: play.CreatePlayRequest{SpotifyID: "a", DurationPlayedMs: 30000, Client: "web"},
+			mockFn: func() {
+				mockPlayRepo.EXPECT().Create(gomock.Any(), &play.PlayEvent{
+					UserID:           1,
+					SpotifyID:        "a",
+					PlayedAt:         now.Truncate(time.Microsecond),
+					DurationPlayedMs: 30000,
+					Client:           "web",
+				}).DoAndReturn(func(ctx context.Context, model *play.PlayEvent) error {
+					model.ID = 7
+					return nil
+				})
+			},
+			want: &play.PlayResponse{
+				ID:               7,
+				SpotifyID:        "a",
+				PlayedAt:         now.Truncate(time.Microsecond),
+				DurationPlayedMs: 30000,
+				Client:           "web",
+			},
+		},
+		{
+			name:    "should keep the reported played at",
+			request: play.CreatePlayRequest{SpotifyID: "a", PlayedAt: &earlier, Device: "phone"},
+			mockFn: func() {
+				mockPlayRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+			},
+			want: &play.PlayResponse{
+				SpotifyID: "a",
+				PlayedAt:  earlier.Truncate(time.Microsecond),
+				Device:    "phone",
+			},
+		},
+		{
+			name:    "should reject plays in the future",
+			request: play.CreatePlayRequest{SpotifyID: "a", PlayedAt: &future},
+			mockFn:  func() {},
+			wantErr: ErrInvalidPlayedAt,
+		},
+		{
+			name:    "should reject plays older than 30 days",
+			request: play.CreatePlayRequest{SpotifyID: "a", PlayedAt: &tooOld},
+			mockFn:  func() {},
+			wantErr: ErrPlayTooOld,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFn()
+
+			s := NewPlayService(mockPlayRepo, nil, nil)
+			s.now = func() time.Time { return now }
+
+			got, err := s.Record(context.Background(), 1, tt.request)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_playService_EnsurePartitions(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockPlayRepo := NewMockPlayRepository(mockCtrl)
+
+	now := time.Date(2026, 10, 18, 7, 0, 0, 0, time.UTC)
+	mockPlayRepo.EXPECT().EnsurePartitions(gomock.Any(), now.Add(-maxPlayAge), time.Date(2026, 11, 18, 7, 0, 0, 0, time.UTC)).Return(nil)
+
+	s := NewPlayService(mockPlayRepo, nil, nil)
+	s.now = func() time.Time { return now }
+
+	err := s.EnsurePartitions(context.Background())
+	assert.NoError(t, err)
+}
+
+func Test_playService_ListRecent(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockPlayRepo := NewMockPlayRepository(mockCtrl)
+	mockSpotifyOutbond := NewMockSpotifyOutbond(mockCtrl)
+	mockSpotifyRepo := NewMockSpotifyRepository(mockCtrl)
+
+	isLiked := true
+	playedAt := time.Date(2026, 10, 18, 7, 0, 0, 0, time.UTC)
+	cursor := play.Cursor{PlayedAt: playedAt, ID: 9}
+
+	tests := []struct {
+		name    string
+		request play.RecentPlaysRequest
+		mockFn  func()
+		want    *play.RecentPlaysResponse
+		wantErr error
+	}{
+		{
+			name:    "success",
+			request: play.RecentPlaysRequest{Limit: 2, Cursor: EncodeCursor(cursor)},
+			mockFn: func() {
+				mockPlayRepo.EXPECT().ListRecent(gomock.Any(), uint(1), &cursor, 3).Return([]play.PlayEvent{
+					{ID: 8, SpotifyID: "a", PlayedAt: playedAt},
+					{ID: 7, SpotifyID: "gone", PlayedAt: playedAt.Add(-time.Minute)},
+					{ID: 6, SpotifyID: "a", PlayedAt: playedAt.Add(-2 * time.Minute)},
+				}, nil)
+				mockSpotifyOutbond.EXPECT().GetSeveralTracks(gomock.Any(), []string{"a", "gone"}).Return([]spotifyRepo.SpotifyTrackObject{
+					{ID: "a", Name: "Track A"},
+				}, nil)
+				mockSpotifyRepo.EXPECT().GetBulkSpotifyIDs(gomock.Any(), uint(1), []string{"a", "gone"}).Return(map[string]spotify.TrackActivity{
+					"a": {SpotifyID: "a", IsLiked: &isLiked},
+				}, nil)
+			},
+			want: &play.RecentPlaysResponse{
+				Items: []play.RecentPlayResponse{
+					{
+						PlayResponse: play.PlayResponse{ID: 8, SpotifyID: "a", PlayedAt: playedAt},
+						Track: &spotify.SpotifyTrackObjectResponse{
+							AlbumImagesURL: []string{},
+							ArtistsName:    []string{},
+							ArtistsID:      []string{},
+							ID:             "a",
+							Name:           "Track A",
+							IsLiked:        &isLiked,
+						},
+					},
+					{
+						PlayResponse: play.PlayResponse{ID: 7, SpotifyID: "gone", PlayedAt: playedAt.Add(-time.Minute)},
+					},
+				},
+				NextCursor: EncodeCursor(play.Cursor{PlayedAt: playedAt.Add(-time.Minute), ID: 7}),
+			},
+		},
+		{
+			name: "last page",
+			mockFn: func() {
+				mockPlayRepo.EXPECT().ListRecent(gomock.Any(), uint(1), nil, 21).Return([]play.PlayEvent{}, nil)
+			},
+			want: &play.RecentPlaysResponse{
+				Items: []play.RecentPlayResponse{},
+			},
+		},
+		{
+			name:    "invalid cursor",
+			request: play.RecentPlaysRequest{Cursor: "not a cursor"},
+			mockFn:  func() {},
+			wantErr: ErrInvalidCursor,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFn()
+
+			s := NewPlayService(mockPlayRepo, mockSpotifyOutbond, mockSpotifyRepo)
+			got, err := s.ListRecent(context.Background(), 1, tt.request)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_DecodeCursor(t *testing.T) {
+	cursor := play.Cursor{PlayedAt: time.Date(2026, 10, 18, 7, 0, 0, 123456000, time.UTC), ID: 42}
+
+	got, err := DecodeCursor(EncodeCursor(cursor))
+	assert.NoError(t, err)
+	assert.Equal(t, cursor, got)
+
+	_, err = DecodeCursor("bm90LWEtY3Vyc29y")
+	assert.Error(t, err)
+}
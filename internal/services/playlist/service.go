@@ -0,0 +1,425 @@
+package playlist
+
+import (
+	"context"
+	"errors"
+
+	"github.com/rs/zerolog/log"
+	"github.com/sgitwhyd/music-catalogue/internal/apperror"
+	"github.com/sgitwhyd/music-catalogue/internal/models/playlist"
+	playlistRepo "github.com/sgitwhyd/music-catalogue/internal/repositorys/playlist"
+	spotifyRepo "github.com/sgitwhyd/music-catalogue/internal/repositorys/spotify"
+	spotifySvc "github.com/sgitwhyd/music-catalogue/internal/services/spotify"
+	"github.com/sgitwhyd/music-catalogue/pkg/uniq"
+	"gorm.io/gorm"
+)
+
+//go:generate mockgen -source=service.go -destination=../../handlers/playlist/handler_mock_test.go -package=playlist
+//go:generate mockgen -source=../../repositorys/spotify/repository.go -destination=spotify_mock_test.go -package=playlist
+type PlaylistService interface {
+	Create(ctx context.Context, userID uint, request playlist.CreatePlaylistRequest) (*playlist.PlaylistResponse, error)
+	List(ctx context.Context, userID uint, request playlist.ListPlaylistRequest) (*playlist.ListPlaylistResponse, error)
+	Get(ctx context.Context, userID, playlistID uint) (*playlist.PlaylistDetailResponse, error)
+	Update(ctx context.Context, userID, playlistID uint, request playlist.UpdatePlaylistRequest) (*playlist.PlaylistResponse, error)
+	Delete(ctx context.Context, userID, playlistID uint) error
+	AddTracks(ctx context.Context, userID, playlistID uint, request playlist.AddTracksRequest) error
+	RemoveTrack(ctx context.Context, userID, playlistID uint, spotifyID string) error
+	ReorderTracks(ctx context.Context, userID, playlistID uint, request playlist.ReorderTracksRequest) error
+}
+
+var (
+	ErrPlaylistNotFound   = apperror.New(apperror.KindNotFound, "playlist_not_found", "playlist not found")
+	ErrPlaylistForbidden  = apperror.New(apperror.KindForbidden, "playlist_forbidden", "only the owner can change this playlist")
+	ErrTrackNotInPlaylist = apperror.New(apperror.KindNotFound, "track_not_in_playlist", "track is not in the playlist")
+	ErrUnknownTrack       = apperror.New(apperror.KindInvalid, "unknown_track", "spotify doesn't know one of the tracks")
+	ErrInvalidPosition    = apperror.New(apperror.KindInvalid, "invalid_position", "position is out of range")
+	ErrPlaylistFull       = apperror.New(apperror.KindInvalid, "playlist_full", "playlist can't hold more tracks")
+)
+
+const (
+	defaultPlaylistPageSize = 10
+	maxPlaylistPageSize     = 50
+
+	maxPlaylistTracks = 1000
+)
+
+type playlistService struct {
+	playlistRepo   playlistRepo.PlaylistRepository
+	spotifyOutbond spotifyRepo.SpotifyOutbond
+	spotifyRepo    spotifyRepo.SpotifyRepository
+}
+
+func NewPlaylistService(playlistRepo playlistRepo.PlaylistRepository, spotifyOutbond spotifyRepo.SpotifyOutbond, spotifyRepo spotifyRepo.SpotifyRepository) *playlistService {
+	return &playlistService{
+		playlistRepo:   playlistRepo,
+		spotifyOutbond: spotifyOutbond,
+		spotifyRepo:    spotifyRepo,
+	}
+}
+
+func (s *playlistService) Create(ctx context.Context, userID uint, request playlist.CreatePlaylistRequest) (*playlist.PlaylistResponse, error) {
+	visibility := request.Visibility
+	if visibility == "" {
+		visibility = playlist.VisibilityPrivate
+	}
+
+	model := playlist.Playlist{
+		UserID:      userID,
+		Name:        request.Name,
+		Description: request.Description,
+		Visibility:  visibility,
+	}
+
+	err := s.playlistRepo.Create(ctx, &model)
+	if err != nil {
+		log.Error().Err(err).Msg("service: error create playlist")
+		return nil, err
+	}
+
+	response := toResponse(model, 0)
+	return &response, nil
+}
+
+func (s *playlistService) List(ctx context.Context, userID uint, request playlist.ListPlaylistRequest) (*playlist.ListPlaylistResponse, error) {
+	pageSize := request.PageSize
+	if pageSize <= 0 || pageSize > maxPlaylistPageSize {
+		pageSize = defaultPlaylistPageSize
+	}
+
+	pageIndex := request.PageIndex
+	if pageIndex <= 0 {
+		pageIndex = 1
+	}
+
+	limit := pageSize
+	offset := (pageIndex - 1) * pageSize
+
+	playlists, total, err := s.playlistRepo.List(ctx, userID, limit, offset)
+	if err != nil {
+		log.Error().Err(err).Msg("service: error list playlists")
+		return nil, err
+	}
+
+	ids := make([]uint, len(playlists))
+	for idx, model := range playlists {
+		ids[idx] = model.ID
+	}
+
+	counts, err := s.playlistRepo.CountTracks(ctx, ids)
+	if err != nil {
+		log.Error().Err(err).Msg("service: error count playlist tracks")
+		return nil, err
+	}
+
+	items := make([]playlist.PlaylistResponse, len(playlists))
+	for idx, model := range playlists {
+		items[idx] = toResponse(model, counts[model.ID])
+	}
+
+	return &playlist.ListPlaylistResponse{
+		Items:  items,
+		Limit:  limit,
+		Offset: offset,
+		Total:  int(total),
+	}, nil
+}
+
+func (s *playlistService) Get(ctx context.Context, userID, playlistID uint) (*playlist.PlaylistDetailResponse, error) {
+	model, err := s.getVisible(ctx, userID, playlistID)
+	if err != nil {
+		return nil, err
+	}
+
+	tracks, err := s.playlistRepo.ListTracks(ctx, playlistID)
+	if err != nil {
+		log.Error().Err(err).Msg("service: error list playlist tracks")
+		return nil, err
+	}
+
+	trackIDs := make([]string, len(tracks))
+	for idx, track := range tracks {
+		trackIDs[idx] = track.SpotifyID
+	}
+
+	spotifyTracks, err := s.spotifyOutbond.GetSeveralTracks(ctx, trackIDs)
+	if err != nil {
+		log.Error().Err(err).Msg("error get several tracks spotify")
+		return nil, err
+	}
+
+	trackActivities, err := s.spotifyRepo.GetBulkSpotifyIDs(ctx, userID, trackIDs)
+	if err != nil {
+		log.Error().Err(err).Msg("error get track activities from db")
+		return nil, err
+	}
+
+	mapTracks := make(map[string]spotifyRepo.SpotifyTrackObject, len(spotifyTracks))
+	for _, track := range spotifyTracks {
+		mapTracks[track.ID] = track
+	}
+
+	items := make([]playlist.PlaylistTrackResponse, len(tracks))
+	for idx, track := range tracks {
+		// keep tracks spotify no longer knows so positions stay meaningful
+		spotifyTrack, ok := mapTracks[track.SpotifyID]
+		if !ok {
+			spotifyTrack = spotifyRepo.SpotifyTrackObject{ID: track.SpotifyID}
+		}
+
+		items[idx] = playlist.PlaylistTrackResponse{
+			SpotifyTrackObjectResponse: spotifySvc.TrackToResponse(spotifyTrack, trackActivities),
+			Position:                   track.Position,
+			AddedAt:                    track.CreatedAt,
+		}
+	}
+
+	return &playlist.PlaylistDetailResponse{
+		PlaylistResponse: toResponse(*model, len(tracks)),
+		Tracks:           items,
+	}, nil
+}
+
+func (s *playlistService) Update(ctx context.Context, userID, playlistID uint, request playlist.UpdatePlaylistRequest) (*playlist.PlaylistResponse, error) {
+	model, err := s.getOwned(ctx, userID, playlistID)
+	if err != nil {
+		return nil, err
+	}
+
+	if request.Name != nil {
+		model.Name = *request.Name
+	}
+	if request.Description != nil {
+		model.Description = *request.Description
+	}
+	if request.Visibility != nil {
+		model.Visibility = *request.Visibility
+	}
+
+	err = s.playlistRepo.Update(ctx, model)
+	if err != nil {
+		log.Error().Err(err).Msg("service: error update playlist")
+		return nil, err
+	}
+
+	counts, err := s.playlistRepo.CountTracks(ctx, []uint{model.ID})
+	if err != nil {
+		log.Error().Err(err).Msg("service: error count playlist tracks")
+		return nil, err
+	}
+
+	response := toResponse(*model, counts[model.ID])
+	return &response, nil
+}
+
+func (s *playlistService) Delete(ctx context.Context, userID, playlistID uint) error {
+	_, err := s.getOwned(ctx, userID, playlistID)
+	if err != nil {
+		return err
+	}
+
+	err = s.playlistRepo.Delete(ctx, playlistID)
+	if err != nil {
+		log.Error().Err(err).Msg("service: error delete playlist")
+		return err
+	}
+
+	return nil
+}
+
+func (s *playlistService) AddTracks(ctx context.Context, userID, playlistID uint, request playlist.AddTracksRequest) error {
+	_, err := s.getOwned(ctx, userID, playlistID)
+	if err != nil {
+		return err
+	}
+
+	spotifyIDs := uniq.Values(request.SpotifyIDs)
+
+	known, err := s.spotifyOutbond.GetSeveralTracks(ctx, spotifyIDs)
+	if err != nil {
+		log.Error().Err(err).Msg("error get several tracks spotify")
+		return err
+	}
+
+	if len(known) != len(spotifyIDs) {
+		return ErrUnknownTrack
+	}
+
+	return s.editTracks(ctx, playlistID, func(tracks []playlist.PlaylistTrack) ([]playlist.PlaylistTrack, error) {
+		return addTracks(tracks, spotifyIDs, request.Position)
+	})
+}
+
+func (s *playlistService) RemoveTrack(ctx context.Context, userID, playlistID uint, spotifyID string) error {
+	_, err := s.getOwned(ctx, userID, playlistID)
+	if err != nil {
+		return err
+	}
+
+	return s.editTracks(ctx, playlistID, func(tracks []playlist.PlaylistTrack) ([]playlist.PlaylistTrack, error) {
+		return removeTrack(tracks, spotifyID)
+	})
+}
+
+func (s *playlistService) ReorderTracks(ctx context.Context, userID, playlistID uint, request playlist.ReorderTracksRequest) error {
+	_, err := s.getOwned(ctx, userID, playlistID)
+	if err != nil {
+		return err
+	}
+
+	rangeLength := request.RangeLength
+	if rangeLength == 0 {
+		rangeLength = 1
+	}
+
+	return s.editTracks(ctx, playlistID, func(tracks []playlist.PlaylistTrack) ([]playlist.PlaylistTrack, error) {
+		return reorderTracks(tracks, *request.RangeStart, *request.InsertBefore, rangeLength)
+	})
+}
+
+func (s *playlistService) editTracks(ctx context.Context, playlistID uint, edit func(tracks []playlist.PlaylistTrack) ([]playlist.PlaylistTrack, error)) error {
+	err := s.playlistRepo.EditTracks(ctx, playlistID, edit)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return ErrPlaylistNotFound
+		}
+
+		var appErr *apperror.Error
+		if !errors.As(err, &appErr) {
+			log.Error().Err(err).Msg("service: error edit playlist tracks")
+		}
+		return err
+	}
+
+	return nil
+}
+
+// getVisible returns the playlist when userID owns it or it is public.
+// Private playlists of other users are reported as not found.
+func (s *playlistService) getVisible(ctx context.Context, userID, playlistID uint) (*playlist.Playlist, error) {
+	model, err := s.playlistRepo.Get(ctx, playlistID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrPlaylistNotFound
+		}
+
+		log.Error().Err(err).Msg("service: error get playlist")
+		return nil, err
+	}
+
+	if model.UserID != userID && model.Visibility != playlist.VisibilityPublic {
+		return nil, ErrPlaylistNotFound
+	}
+
+	return model, nil
+}
+
+func (s *playlistService) getOwned(ctx context.Context, userID, playlistID uint) (*playlist.Playlist, error) {
+	model, err := s.getVisible(ctx, userID, playlistID)
+	if err != nil {
+		return nil, err
+	}
+
+	if model.UserID != userID {
+		return nil, ErrPlaylistForbidden
+	}
+
+	return model, nil
+}
+
+// addTracks inserts the spotify ids missing from tracks at position, or at
+// the end when position is nil. Ids already in the playlist are skipped.
+func addTracks(tracks []playlist.PlaylistTrack, spotifyIDs []string, position *int) ([]playlist.PlaylistTrack, error) {
+	at := len(tracks)
+	if position != nil {
+		at = *position
+	}
+
+	if at < 0 || at > len(tracks) {
+		return nil, ErrInvalidPosition
+	}
+
+	existing := make(map[string]struct{}, len(tracks))
+	for _, track := range tracks {
+		existing[track.SpotifyID] = struct{}{}
+	}
+
+	added := make([]playlist.PlaylistTrack, 0, len(spotifyIDs))
+	for _, spotifyID := range spotifyIDs {
+		if _, ok := existing[spotifyID]; ok {
+			continue
+		}
+
+		added = append(added, playlist.PlaylistTrack{SpotifyID: spotifyID})
+	}
+
+	if len(tracks)+len(added) > maxPlaylistTracks {
+		return nil, ErrPlaylistFull
+	}
+
+	result := make([]playlist.PlaylistTrack, 0, len(tracks)+len(added))
+	result = append(result, tracks[:at]...)
+	result = append(result, added...)
+	result = append(result, tracks[at:]...)
+
+	return result, nil
+}
+
+func removeTrack(tracks []playlist.PlaylistTrack, spotifyID string) ([]playlist.PlaylistTrack, error) {
+	for idx, track := range tracks {
+		if track.SpotifyID == spotifyID {
+			result := make([]playlist.PlaylistTrack, 0, len(tracks)-1)
+			result = append(result, tracks[:idx]...)
+			return append(result, tracks[idx+1:]...), nil
+		}
+	}
+
+	return nil, ErrTrackNotInPlaylist
+}
+
+// reorderTracks moves rangeLength tracks from rangeStart to just before the
+// track at insertBefore, insertBefore == len(tracks) moves them to the end.
+func reorderTracks(tracks []playlist.PlaylistTrack, rangeStart, insertBefore, rangeLength int) ([]playlist.PlaylistTrack, error) {
+	if rangeStart < 0 || rangeLength < 1 || rangeStart+rangeLength > len(tracks) {
+		return nil, ErrInvalidPosition
+	}
+
+	if insertBefore < 0 || insertBefore > len(tracks) {
+		return nil, ErrInvalidPosition
+	}
+
+	// inserting inside the moved range leaves the order unchanged
+	if insertBefore >= rangeStart && insertBefore <= rangeStart+rangeLength {
+		return tracks, nil
+	}
+
+	moved := tracks[rangeStart : rangeStart+rangeLength]
+
+	rest := make([]playlist.PlaylistTrack, 0, len(tracks)-rangeLength)
+	rest = append(rest, tracks[:rangeStart]...)
+	rest = append(rest, tracks[rangeStart+rangeLength:]...)
+
+	at := insertBefore
+	if insertBefore > rangeStart {
+		at -= rangeLength
+	}
+
+	result := make([]playlist.PlaylistTrack, 0, len(tracks))
+	result = append(result, rest[:at]...)
+	result = append(result, moved...)
+	result = append(result, rest[at:]...)
+
+	return result, nil
+}
+
+func toResponse(model playlist.Playlist, trackCount int) playlist.PlaylistResponse {
+	return playlist.PlaylistResponse{
+		ID:          model.ID,
+		OwnerID:     model.UserID,
+		Name:        model.Name,
+		Description: model.Description,
+		Visibility:  model.Visibility,
+		TrackCount:  trackCount,
+		CreatedAt:   model.CreatedAt,
+		UpdatedAt:   model.UpdatedAt,
+	}
+}
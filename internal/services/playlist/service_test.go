@@ -0,0 +1,332 @@
+package playlist
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sgitwhyd/music-catalogue/internal/models/playlist"
+	"github.com/sgitwhyd/music-catalogue/internal/models/spotify"
+	spotifyRepo "github.com/sgitwhyd/music-catalogue/internal/repositorys/spotify"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+func tracksOf(ids ...string) []playlist.PlaylistTrack {
+	tracks := make([]playlist.PlaylistTrack, len(ids))
+	for idx, id := range ids {
+		tracks[idx] = playlist.PlaylistTrack{ID: uint(idx + 1), SpotifyID: id, Position: idx}
+	}
+	return tracks
+}
+
+func spotifyIDsOf(tracks []playlist.PlaylistTrack) []string {
+	ids := make([]string, len(tracks))
+	for idx, track := range tracks {
+		ids[idx] = track.SpotifyID
+	}
+	return ids
+}
+
+func Test_addTracks(t *testing.T) {
+	position := func(p int) *int { return &p }
+
+	tests := []struct {
+		name     string
+		tracks   []playlist.PlaylistTrack
+		ids      []string
+		position *int
+		want     []string
+		wantErr  error
+	}{
+		{
+			name:   "should append without a position",
+			tracks: tracksOf("a", "b"),
+			ids:    []string{"c", "d"},
+			want:   []string{"a", "b", "c", "d"},
+		},
+		{
+			name:     "should insert at position",
+			tracks:   tracksOf("a", "b"),
+			ids:      []string{"c"},
+			position: position(1),
+			want:     []string{"a", "c", "b"},
+		},
+		{
+			name:   "should skip tracks already in the playlist",
+			tracks: tracksOf("a", "b"),
+			ids:    []string{"b", "c"},
+			want:   []string{"a", "b", "c"},
+		},
+		{
+			name:     "should fail on a position past the end",
+			tracks:   tracksOf("a"),
+			ids:      []string{"b"},
+			position: position(2),
+			wantErr:  ErrInvalidPosition,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := addTracks(tt.tracks, tt.ids, tt.position)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, spotifyIDsOf(got))
+		})
+	}
+}
+
+func Test_removeTrack(t *testing.T) {
+	got, err := removeTrack(tracksOf("a", "b", "c"), "b")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "c"}, spotifyIDsOf(got))
+
+	_, err = removeTrack(tracksOf("a"), "z")
+	assert.ErrorIs(t, err, ErrTrackNotInPlaylist)
+}
+
+func Test_reorderTracks(t *testing.T) {
+	tests := []struct {
+		name         string
+		rangeStart   int
+		insertBefore int
+		rangeLength  int
+		want         []string
+		wantErr      error
+	}{
+		{
+			name:         "should move a track to the front",
+			rangeStart:   3,
+			insertBefore: 0,
+			rangeLength:  1,
+			want:         []string{"d", "a", "b", "c", "e"},
+		},
+		{
+			name:         "should move a range forward",
+			rangeStart:   0,
+			insertBefore: 4,
+			rangeLength:  2,
+			want:         []string{"c", "d", "a", "b", "e"},
+		},
+		{
+			name:         "should move a track to the end",
+			rangeStart:   1,
+			insertBefore: 5,
+			rangeLength:  1,
+			want:         []string{"a", "c", "d", "e", "b"},
+		},
+		{
+			name:         "should keep the order when inserting inside the range",
+			rangeStart:   1,
+			insertBefore: 2,
+			rangeLength:  2,
+			want:         []string{"a", "b", "c", "d", "e"},
+		},
+		{
+			name:         "should fail on a range past the end",
+			rangeStart:   4,
+			insertBefore: 0,
+			rangeLength:  2,
+			wantErr:      ErrInvalidPosition,
+		},
+		{
+			name:         "should fail on insert before past the end",
+			rangeStart:   0,
+			insertBefore: 6,
+			rangeLength:  1,
+			wantErr:      ErrInvalidPosition,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := reorderTracks(tracksOf("a", "b", "c", "d", "e"), tt.rangeStart, tt.insertBefore, tt.rangeLength)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, spotifyIDsOf(got))
+		})
+	}
+}
+
+func Test_playlistService_Get(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockPlaylistRepo := NewMockPlaylistRepository(mockCtrl)
+	mockSpotifyOutbond := NewMockSpotifyOutbond(mockCtrl)
+	mockSpotifyRepo := NewMockSpotifyRepository(mockCtrl)
+
+	isLiked := true
+
+	privatePlaylist := &playlist.Playlist{
+		Model:      gorm.Model{ID: 1},
+		UserID:     1,
+		Name:       "Road Trip",
+		Visibility: playlist.VisibilityPrivate,
+	}
+
+	tests := []struct {
+		name    string
+		userID  uint
+		mockFn  func()
+		want    *playlist.PlaylistDetailResponse
+		wantErr error
+	}{
+		{
+			name:   "success",
+			userID: 1,
+			mockFn: func() {
+				mockPlaylistRepo.EXPECT().Get(gomock.Any(), uint(1)).Return(privatePlaylist, nil)
+				mockPlaylistRepo.EXPECT().ListTracks(gomock.Any(), uint(1)).Return(tracksOf("a", "gone"), nil)
+				mockSpotifyOutbond.EXPECT().GetSeveralTracks(gomock.Any(), []string{"a", "gone"}).Return([]spotifyRepo.SpotifyTrackObject{
+					{ID: "a", Name: "Track A", Artists: []spotifyRepo.SpotifyArtisObject{{ID: "1dfeR4HaWDbWqFHLkxsg1d", Name: "Queen"}}},
+				}, nil)
+				mockSpotifyRepo.EXPECT().GetBulkSpotifyIDs(gomock.Any(), uint(1), []string{"a", "gone"}).Return(map[string]spotify.TrackActivity{
+					"a": {SpotifyID: "a", IsLiked: &isLiked},
+				}, nil)
+			},
+			want: &playlist.PlaylistDetailResponse{
+				PlaylistResponse: playlist.PlaylistResponse{
+					ID:         1,
+					OwnerID:    1,
+					Name:       "Road Trip",
+					Visibility: playlist.VisibilityPrivate,
+					TrackCount: 2,
+				},
+				Tracks: []playlist.PlaylistTrackResponse{
+					{
+						SpotifyTrackObjectResponse: spotify.SpotifyTrackObjectResponse{
+							AlbumImagesURL: []string{},
+							ArtistsName:    []string{"Queen"},
+							ArtistsID:      []string{"1dfeR4HaWDbWqFHLkxsg1d"},
+							ID:             "a",
+							Name:           "Track A",
+							IsLiked:        &isLiked,
+						},
+						Position: 0,
+					},
+					{
+						SpotifyTrackObjectResponse: spotify.SpotifyTrackObjectResponse{
+							AlbumImagesURL: []string{},
+							ArtistsName:    []string{},
+							ArtistsID:      []string{},
+							ID:             "gone",
+						},
+						Position: 1,
+					},
+				},
+			},
+		},
+		{
+			name:   "should hide private playlists of other users",
+			userID: 2,
+			mockFn: func() {
+				mockPlaylistRepo.EXPECT().Get(gomock.Any(), uint(1)).Return(privatePlaylist, nil)
+			},
+			wantErr: ErrPlaylistNotFound,
+		},
+		{
+			name:   "not found",
+			userID: 1,
+			mockFn: func() {
+				mockPlaylistRepo.EXPECT().Get(gomock.Any(), uint(1)).Return(nil, gorm.ErrRecordNotFound)
+			},
+			wantErr: ErrPlaylistNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFn()
+
+			s := NewPlaylistService(mockPlaylistRepo, mockSpotifyOutbond, mockSpotifyRepo)
+			got, err := s.Get(context.Background(), tt.userID, 1)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_playlistService_AddTracks(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockPlaylistRepo := NewMockPlaylistRepository(mockCtrl)
+	mockSpotifyOutbond := NewMockSpotifyOutbond(mockCtrl)
+	mockSpotifyRepo := NewMockSpotifyRepository(mockCtrl)
+
+	ownedPlaylist := &playlist.Playlist{
+		Model:      gorm.Model{ID: 1},
+		UserID:     1,
+		Visibility: playlist.VisibilityPublic,
+	}
+
+	tests := []struct {
+		name    string
+		userID  uint
+		request playlist.AddTracksRequest
+		mockFn  func()
+		want    []string
+		wantErr error
+	}{
+		{
+			name:    "success",
+			userID:  1,
+			request: playlist.AddTracksRequest{SpotifyIDs: []string{"b", "c", "b"}},
+			mockFn: func() {
+				mockPlaylistRepo.EXPECT().Get(gomock.Any(), uint(1)).Return(ownedPlaylist, nil)
+				mockSpotifyOutbond.EXPECT().GetSeveralTracks(gomock.Any(), []string{"b", "c"}).Return([]spotifyRepo.SpotifyTrackObject{{ID: "b"}, {ID: "c"}}, nil)
+				mockPlaylistRepo.EXPECT().EditTracks(gomock.Any(), uint(1), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, playlistID uint, edit func([]playlist.PlaylistTrack) ([]playlist.PlaylistTrack, error)) error {
+						got, err := edit(tracksOf("a"))
+						assert.Equal(t, []string{"a", "b", "c"}, spotifyIDsOf(got))
+						return err
+					})
+			},
+		},
+		{
+			name:    "should reject tracks spotify doesn't know",
+			userID:  1,
+			request: playlist.AddTracksRequest{SpotifyIDs: []string{"b", "unknown"}},
+			mockFn: func() {
+				mockPlaylistRepo.EXPECT().Get(gomock.Any(), uint(1)).Return(ownedPlaylist, nil)
+				mockSpotifyOutbond.EXPECT().GetSeveralTracks(gomock.Any(), []string{"b", "unknown"}).Return([]spotifyRepo.SpotifyTrackObject{{ID: "b"}}, nil)
+			},
+			wantErr: ErrUnknownTrack,
+		},
+		{
+			name:    "should forbid changes by other users",
+			userID:  2,
+			request: playlist.AddTracksRequest{SpotifyIDs: []string{"b"}},
+			mockFn: func() {
+				mockPlaylistRepo.EXPECT().Get(gomock.Any(), uint(1)).Return(ownedPlaylist, nil)
+			},
+			wantErr: ErrPlaylistForbidden,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFn()
+
+			s := NewPlaylistService(mockPlaylistRepo, mockSpotifyOutbond, mockSpotifyRepo)
+			err := s.AddTracks(context.Background(), tt.userID, 1, tt.request)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -2,85 +2,360 @@ package spotify
 
 import (
 	"context"
+	"errors"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/rs/zerolog/log"
+	"github.com/sgitwhyd/music-catalogue/internal/apperror"
 	"github.com/sgitwhyd/music-catalogue/internal/models/spotify"
+	"github.com/sgitwhyd/music-catalogue/internal/models/tag"
 	spotifyRepo "github.com/sgitwhyd/music-catalogue/internal/repositorys/spotify"
+	"github.com/sgitwhyd/music-catalogue/pkg/lrucache"
+	"github.com/sgitwhyd/music-catalogue/pkg/uniq"
 	"gorm.io/gorm"
 )
 
 //go:generate mockgen -source=service.go -destination=../../handlers/spotify/handler_mock_test.go -package=spotify
 type SpotifyService interface {
-	Search(ctx context.Context, query string, pageSize, pageIndex int,  userID uint) (*spotify.SearchResponse, error)
+	Search(ctx context.Context, request spotify.SearchRequest, userID uint) (*spotify.SearchResponse, error)
 	UpSertActivity(ctx context.Context, userID uint, request spotify.TrackActivityRequest) error
+	UpdateActivity(ctx context.Context, userID uint, spotifyID string, request spotify.UpdateActivityRequest) (*spotify.ActivityResponse, error)
+	GetTrack(ctx context.Context, spotifyID string, userID uint) (*spotify.SpotifyTrackObjectResponse, error)
+	GetTracks(ctx context.Context, spotifyIDs []string, userID uint) (*spotify.TrackLookupResponse, error)
+	GetLibrary(ctx context.Context, userID uint, isLiked bool, request spotify.LibraryRequest) (*spotify.LibraryResponse, error)
+	GetArtist(ctx context.Context, artistID string) (*spotify.SpotifyArtistObjectResponse, error)
+	GetArtistTopTracks(ctx context.Context, artistID, market string, userID uint) (*spotify.ArtistTopTracksResponse, error)
+	GetArtistAlbums(ctx context.Context, artistID string, request spotify.ArtistAlbumsRequest, userID uint) (*spotify.Page[spotify.SpotifyAlbumObjectResponse], error)
+	GetAlbum(ctx context.Context, albumID, market string, userID uint) (*spotify.AlbumResponse, error)
+	GetAlbumTracks(ctx context.Context, albumID string, request spotify.AlbumTracksRequest, userID uint) (*spotify.AlbumTracksResponse, error)
+	GetRecommendations(ctx context.Context, userID uint, request spotify.RecommendationsRequest) (*spotify.RecommendationsResponse, error)
+}
+
+var (
+	ErrTrackNotFound = apperror.New(apperror.KindNotFound, "track_not_found", "track not found")
+
+	ErrInvalidSearchType = apperror.New(apperror.KindInvalid, "invalid_search_type", "type must be track, album, artist or playlist")
+
+	ErrArtistNotFound = apperror.New(apperror.KindNotFound, "artist_not_found", "artist not found")
+
+	ErrAlbumNotFound = apperror.New(apperror.KindNotFound, "album_not_found", "album not found")
+
+	ErrInvalidAlbumGroup = apperror.New(apperror.KindInvalid, "invalid_album_group", "include_groups must be album, single, appears_on or compilation")
+
+	ErrTooManySeeds = apperror.New(apperror.KindInvalid, "too_many_seeds", "at most 5 seed tracks, artists and genres in total")
+
+	ErrNoRecommendationSeeds = apperror.New(apperror.KindInvalid, "no_recommendation_seeds", "like a track or pass seed_tracks, seed_artists or seed_genres")
+
+	ErrInvalidRecommendationAttribute = apperror.New(apperror.KindInvalid, "invalid_recommendation_attribute", "tunable attributes must be a min_, max_ or target_ prefixed track attribute with a numeric value")
+
+	ErrInvalidRating = apperror.New(apperror.KindInvalid, "invalid_rating", "rating must be between 1 and 5")
+
+	ErrNoteTooLong = apperror.New(apperror.KindInvalid, "note_too_long", "note can't be longer than 2000 characters")
+)
+
+const (
+	defaultLibraryPageSize = 10
+	maxLibraryPageSize     = 50
+
+	defaultArtistAlbumsPageSize = 10
+	maxArtistAlbumsPageSize     = 50
+
+	defaultAlbumTracksPageSize = 10
+	// also the page size used when collecting every track of an album
+	maxAlbumTracksPageSize = 50
+	// how many albums' track ids are kept, and for how long, so paging
+	// through an album doesn't collect every track again for each page
+	albumTracksCacheSize = 500
+	albumTracksCacheTTL  = time.Hour
+
+	defaultRecommendationsLimit = 20
+	maxRecommendationsLimit     = 100
+	// how many of the latest likes are considered as seeds
+	recommendationSeedCandidates = 20
+
+	minRating = 1
+	maxRating = 5
+	// counted in characters, not bytes
+	maxNoteLength = 2000
+)
+
+// recommendationAttributes are the tunable track attributes spotify accepts
+// behind a min_, max_ or target_ prefix.
+var recommendationAttributes = []string{
+	"acousticness", "danceability", "duration_ms", "energy", "instrumentalness", "key", "liveness",
+	"loudness", "mode", "popularity", "speechiness", "tempo", "time_signature", "valence",
 }
 
 type spotifyService struct {
 	spotifyOutbond spotifyRepo.SpotifyOutbond
 	spotifyRepo spotifyRepo.SpotifyRepository
+
+	albumTracks *lrucache.Cache[string, albumTracksEntry]
+	now         func() time.Time
+}
+
+type albumTracksEntry struct {
+	trackIDs  []string
+	fetchedAt time.Time
 }
 
 func NewSpotifyServie(spotifyOutbond spotifyRepo.SpotifyOutbond, spotifyRepo spotifyRepo.SpotifyRepository) *spotifyService {
 	return &spotifyService{
 		spotifyOutbond: spotifyOutbond,
 		spotifyRepo: spotifyRepo,
+		albumTracks: lrucache.New[string, albumTracksEntry](albumTracksCacheSize),
+		now: time.Now,
 	}
 }
 
-func (s *spotifyService) Search(ctx context.Context, query string, pageSize, pageIndex int, userID uint) (*spotify.SearchResponse, error) {
-	limit := pageSize
-	offset := (pageIndex - 1) * pageSize
+func (s *spotifyService) Search(ctx context.Context, request spotify.SearchRequest, userID uint) (*spotify.SearchResponse, error) {
+	types, err := searchTypes(request.Types)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := request.PageSize
+	offset := (request.PageIndex - 1) * request.PageSize
 
 
-	trackDetails, err := s.spotifyOutbond.Search(ctx, query, limit, offset)
+	searchResult, err := s.spotifyOutbond.Search(ctx, spotifyRepo.SearchParams{
+		Query: request.Query,
+		Limit: limit,
+		Offset: offset,
+		Market: request.Market,
+		Types: types,
+	})
 	if err != nil {
-		log.Error().Err(err).Msg("error search track spotify")
+		log.Error().Err(err).Msg("error search spotify")
 		return nil, err
 	}
 
-	trackIDs := make([]string, len(trackDetails.Tracks.Items))
-	for idx, track := range trackDetails.Tracks.Items {
-		trackIDs[idx] = track.ID
+	// only tracks and albums can be liked, artists and playlists are followed
+	likeableIDs := make([]string, 0, len(searchResult.Tracks.Items))
+	for _, track := range searchResult.Tracks.Items {
+		likeableIDs = append(likeableIDs, track.ID)
+	}
+	if searchResult.Albums != nil {
+		for _, album := range searchResult.Albums.Items {
+			likeableIDs = append(likeableIDs, album.ID)
+		}
 	}
 
-	trackActivities, err := s.spotifyRepo.GetBulkSpotifyIDs(ctx, userID, trackIDs)
-	if err != nil {
-		log.Error().Err(err).Msg("error get track activities from db")
-		return nil, err
+	activities := map[string]spotify.TrackActivity{}
+	if len(likeableIDs) > 0 {
+		activities, err = s.spotifyRepo.GetBulkSpotifyIDs(ctx, userID, likeableIDs)
+		if err != nil {
+			log.Error().Err(err).Msg("error get track activities from db")
+			return nil, err
+		}
+	}
+
+	response := modelToResponse(searchResult, types, activities)
+	if response.Tracks != nil && len(response.Tracks.Items) > 0 {
+		trackIDs := make([]string, len(response.Tracks.Items))
+		for idx, track := range response.Tracks.Items {
+			trackIDs[idx] = track.ID
+		}
+
+		tags, err := s.spotifyRepo.GetBulkTags(ctx, userID, trackIDs)
+		if err != nil {
+			log.Error().Err(err).Msg("error get track tags from db")
+			return nil, err
+		}
+
+		for idx := range response.Tracks.Items {
+			response.Tracks.Items[idx].Tags = tags[response.Tracks.Items[idx].ID]
+		}
+	}
+
+	return response, nil
+}
+
+// searchTypes lower-cases and de-duplicates the requested search types,
+// defaulting to tracks.
+func searchTypes(requested []string) ([]string, error) {
+	types := make([]string, 0, len(requested))
+	for _, searchType := range requested {
+		searchType = strings.ToLower(strings.TrimSpace(searchType))
+
+		switch searchType {
+		case spotifyRepo.SearchTypeTrack, spotifyRepo.SearchTypeAlbum, spotifyRepo.SearchTypeArtist, spotifyRepo.SearchTypePlaylist:
+		default:
+			return nil, ErrInvalidSearchType
+		}
+
+		if !slices.Contains(types, searchType) {
+			types = append(types, searchType)
+		}
 	}
 
+	if len(types) == 0 {
+		types = append(types, spotifyRepo.SearchTypeTrack)
+	}
 
-	return modelToResponse(trackDetails, trackActivities), nil
+	return types, nil
 }
 
-func modelToResponse(data *spotifyRepo.SpotifySearchResponse, mapTrackActivities map[string]spotify.TrackActivity) *spotify.SearchResponse {
+func modelToResponse(data *spotifyRepo.SpotifySearchResponse, types []string, mapActivities map[string]spotify.TrackActivity) *spotify.SearchResponse {
 	if data == nil {
 		return nil
 	}
 
-	items := make([]spotify.SpotifyTrackObjectResponse, len(data.Tracks.Items))
+	response := &spotify.SearchResponse{
+		CacheStatus: data.CacheStatus,
+	}
+
+	if slices.Contains(types, spotifyRepo.SearchTypeTrack) {
+		items := make([]spotify.SpotifyTrackObjectResponse, len(data.Tracks.Items))
+		for i, item := range data.Tracks.Items{
+			items[i] = TrackToResponse(item, mapActivities)
+		}
+
+		response.Tracks = &spotify.Page[spotify.SpotifyTrackObjectResponse]{
+			Limit: data.Tracks.Limit,
+			Offset: data.Tracks.Offset,
+			Total: data.Tracks.Total,
+			Items: items,
+		}
+	}
+
+	if slices.Contains(types, spotifyRepo.SearchTypeAlbum) && data.Albums != nil {
+		items := make([]spotify.SpotifyAlbumObjectResponse, len(data.Albums.Items))
+		for i, item := range data.Albums.Items {
+			items[i] = albumToResponse(item, mapActivities)
+		}
+
+		response.Albums = &spotify.Page[spotify.SpotifyAlbumObjectResponse]{
+			Limit: data.Albums.Limit,
+			Offset: data.Albums.Offset,
+			Total: data.Albums.Total,
+			Items: items,
+		}
+	}
+
+	if slices.Contains(types, spotifyRepo.SearchTypeArtist) && data.Artists != nil {
+		items := make([]spotify.SpotifyArtistObjectResponse, len(data.Artists.Items))
+		for i, item := range data.Artists.Items {
+			items[i] = artistToResponse(item)
+		}
 
-	for i, item := range data.Tracks.Items{
+		response.Artists = &spotify.Page[spotify.SpotifyArtistObjectResponse]{
+			Limit: data.Artists.Limit,
+			Offset: data.Artists.Offset,
+			Total: data.Artists.Total,
+			Items: items,
+		}
+	}
 
-		artisName := make([]string, len(item.Artists))
-		for idx, artist := range item.Artists {
-			artisName[idx] = artist.Name
+	if slices.Contains(types, spotifyRepo.SearchTypePlaylist) && data.Playlists != nil {
+		items := make([]spotify.SpotifyPlaylistObjectResponse, 0, len(data.Playlists.Items))
+		for _, item := range data.Playlists.Items {
+			if item != nil {
+				items = append(items, playlistToResponse(*item))
+			}
 		}
 
-		imageUrl := make([]string, len(item.Album.Images))
-		for idx, image := range item.Album.Images {
-			imageUrl[idx] = image.URL
+		response.Playlists = &spotify.Page[spotify.SpotifyPlaylistObjectResponse]{
+			Limit: data.Playlists.Limit,
+			Offset: data.Playlists.Offset,
+			Total: data.Playlists.Total,
+			Items: items,
 		}
+	}
 
-		items[i] = spotify.SpotifyTrackObjectResponse{
+	return response
+}
+
+func albumToResponse(item spotifyRepo.SpotifySimplifiedAlbumObject, mapActivities map[string]spotify.TrackActivity) spotify.SpotifyAlbumObjectResponse {
+	artistsName := make([]string, len(item.Artists))
+	artistsID := make([]string, len(item.Artists))
+	for idx, artist := range item.Artists {
+		artistsName[idx] = artist.Name
+		artistsID[idx] = artist.ID
+	}
+
+	return spotify.SpotifyAlbumObjectResponse{
+		AlbumType:            item.AlbumType,
+		TotalTracks:          item.TotalTracks,
+		ImagesURL:            imagesURL(item.Images),
+		ArtistsName:          artistsName,
+		ArtistsID:            artistsID,
+		ReleaseDate:          item.ReleaseDate,
+		ReleaseDatePrecision: item.ReleaseDatePrecision,
+		AlbumGroup:           item.AlbumGroup,
+		Href:                 item.Href,
+		ID:                   item.ID,
+		Name:                 item.Name,
+		IsLiked:              mapActivities[item.ID].IsLiked,
+		Rating:               mapActivities[item.ID].Rating,
+		Note:                 mapActivities[item.ID].Note,
+	}
+}
+
+func artistToResponse(item spotifyRepo.SpotifyFullArtistObject) spotify.SpotifyArtistObjectResponse {
+	return spotify.SpotifyArtistObjectResponse{
+		Genres:     item.Genres,
+		ImagesURL:  imagesURL(item.Images),
+		Followers:  item.Followers.Total,
+		Popularity: item.Popularity,
+		Href:       item.Href,
+		ID:         item.ID,
+		Name:       item.Name,
+	}
+}
+
+func playlistToResponse(item spotifyRepo.SpotifyPlaylistObject) spotify.SpotifyPlaylistObjectResponse {
+	return spotify.SpotifyPlaylistObjectResponse{
+		Description: item.Description,
+		ImagesURL:   imagesURL(item.Images),
+		OwnerName:   item.Owner.DisplayName,
+		TotalTracks: item.Tracks.Total,
+		Href:        item.Href,
+		ID:          item.ID,
+		Name:        item.Name,
+	}
+}
+
+func imagesURL(images []spotifyRepo.SpotifyImagesObject) []string {
+	urls := make([]string, len(images))
+	for idx, image := range images {
+		urls[idx] = image.URL
+	}
+
+	return urls
+}
+
+// TrackToResponse flattens a spotify track for the API, taking the liked
+// flag, rating and note from the user's activity on it, if any.
+func TrackToResponse(item spotifyRepo.SpotifyTrackObject, mapTrackActivities map[string]spotify.TrackActivity) spotify.SpotifyTrackObjectResponse {
+	artisName := make([]string, len(item.Artists))
+	artistsID := make([]string, len(item.Artists))
+	for idx, artist := range item.Artists {
+		artisName[idx] = artist.Name
+		artistsID[idx] = artist.ID
+	}
+
+	imageUrl := make([]string, len(item.Album.Images))
+	for idx, image := range item.Album.Images {
+		imageUrl[idx] = image.URL
+	}
+
+	return spotify.SpotifyTrackObjectResponse{
 		// album related fields
 		AlbumType       : item.Album.AlbumType,
 		AlbumTotalTracks : item.Album.TotalTracks,
 		AlbumImagesURL    : imageUrl,
 		AlbumName        : item.Album.Name,
+		AlbumID          : item.Album.ID,
 
 		// artists related field
 		ArtistsName : artisName,
+		ArtistsID : artistsID,
 
 		// track related field
 		Explicit : item.Explicit,
@@ -88,15 +363,593 @@ func modelToResponse(data *spotifyRepo.SpotifySearchResponse, mapTrackActivities
 		ID      : item.ID,
 		Name     : item.Name,
 		IsLiked: mapTrackActivities[item.ID].IsLiked,
+		Rating: mapTrackActivities[item.ID].Rating,
+		Note: mapTrackActivities[item.ID].Note,
+		DiscNumber: item.DiscNumber,
+		TrackNumber: item.TrackNumber,
+		DurationMs: item.DurationMs,
+	}
+}
+
+func (s *spotifyService) GetTrack(ctx context.Context, spotifyID string, userID uint) (*spotify.SpotifyTrackObjectResponse, error) {
+	track, err := s.spotifyOutbond.GetTrack(ctx, spotifyID)
+	if err != nil {
+		if errors.Is(err, spotifyRepo.ErrNotFound) {
+			return nil, ErrTrackNotFound
 		}
+
+		log.Error().Err(err).Msg("error get track spotify")
+		return nil, err
+	}
+
+	trackActivities, err := s.spotifyRepo.GetBulkSpotifyIDs(ctx, userID, []string{track.ID})
+	if err != nil {
+		log.Error().Err(err).Msg("error get track activities from db")
+		return nil, err
+	}
+
+	response := TrackToResponse(*track, trackActivities)
+	return &response, nil
+}
+
+func (s *spotifyService) GetTracks(ctx context.Context, spotifyIDs []string, userID uint) (*spotify.TrackLookupResponse, error) {
+	tracks, err := s.spotifyOutbond.GetSeveralTracks(ctx, uniq.Values(spotifyIDs))
+	if err != nil {
+		log.Error().Err(err).Msg("error get several tracks spotify")
+		return nil, err
 	}
 
-	return &spotify.SearchResponse{
-		Limit: data.Tracks.Limit,
-		Offset: data.Tracks.Offset,
-		Total: data.Tracks.Total,
+	trackIDs := make([]string, len(tracks))
+	for idx, track := range tracks {
+		trackIDs[idx] = track.ID
+	}
+
+	trackActivities, err := s.spotifyRepo.GetBulkSpotifyIDs(ctx, userID, trackIDs)
+	if err != nil {
+		log.Error().Err(err).Msg("error get track activities from db")
+		return nil, err
+	}
+
+	items := make([]spotify.SpotifyTrackObjectResponse, len(tracks))
+	for idx, track := range tracks {
+		items[idx] = TrackToResponse(track, trackActivities)
+	}
+
+	return &spotify.TrackLookupResponse{
 		Items: items,
+	}, nil
+}
+
+func (s *spotifyService) GetLibrary(ctx context.Context, userID uint, isLiked bool, request spotify.LibraryRequest) (*spotify.LibraryResponse, error) {
+	pageSize := request.PageSize
+	if pageSize <= 0 || pageSize > maxLibraryPageSize {
+		pageSize = defaultLibraryPageSize
+	}
+
+	pageIndex := request.PageIndex
+	if pageIndex <= 0 {
+		pageIndex = 1
+	}
+
+	filter := spotify.ActivityFilter{
+		IsLiked: isLiked,
+		From:    request.From,
+		To:      request.To,
+		SortAsc: request.Sort == "oldest",
+		Limit:   pageSize,
+		Offset:  (pageIndex - 1) * pageSize,
+
+		MinRating: request.MinRating,
+		Tag:       tag.NormalizeName(request.Tag),
+	}
+
+	activities, total, err := s.spotifyRepo.ListActivities(ctx, userID, filter)
+	if err != nil {
+		log.Error().Err(err).Msg("error list track activities from db")
+		return nil, err
+	}
+
+	trackIDs := make([]string, len(activities))
+	mapTrackActivities := make(map[string]spotify.TrackActivity, len(activities))
+	for idx, activity := range activities {
+		trackIDs[idx] = activity.SpotifyID
+		mapTrackActivities[activity.SpotifyID] = activity
 	}
+
+	tracks, err := s.spotifyOutbond.GetSeveralTracks(ctx, trackIDs)
+	if err != nil {
+		log.Error().Err(err).Msg("error get several tracks spotify")
+		return nil, err
+	}
+
+	tags, err := s.spotifyRepo.GetBulkTags(ctx, userID, trackIDs)
+	if err != nil {
+		log.Error().Err(err).Msg("error get track tags from db")
+		return nil, err
+	}
+
+	mapTracks := make(map[string]spotifyRepo.SpotifyTrackObject, len(tracks))
+	for _, track := range tracks {
+		mapTracks[track.ID] = track
+	}
+
+	items := make([]spotify.LibraryItemResponse, len(activities))
+	for idx, activity := range activities {
+		// keep tracks spotify no longer knows, so the page size matches total
+		track, ok := mapTracks[activity.SpotifyID]
+		if !ok {
+			track = spotifyRepo.SpotifyTrackObject{ID: activity.SpotifyID}
+		}
+
+		activityAt := activity.UpdatedAt
+		if activity.LikedChangedAt != nil {
+			activityAt = *activity.LikedChangedAt
+		}
+
+		items[idx] = spotify.LibraryItemResponse{
+			SpotifyTrackObjectResponse: TrackToResponse(track, mapTrackActivities),
+			ActivityAt:                 activityAt,
+		}
+		items[idx].Tags = tags[activity.SpotifyID]
+	}
+
+	return &spotify.LibraryResponse{
+		Items:  items,
+		Limit:  filter.Limit,
+		Offset: filter.Offset,
+		Total:  int(total),
+	}, nil
+}
+
+func (s *spotifyService) GetArtist(ctx context.Context, artistID string) (*spotify.SpotifyArtistObjectResponse, error) {
+	artist, err := s.spotifyOutbond.GetArtist(ctx, artistID)
+	if err != nil {
+		if errors.Is(err, spotifyRepo.ErrNotFound) {
+			return nil, ErrArtistNotFound
+		}
+
+		log.Error().Err(err).Msg("error get artist spotify")
+		return nil, err
+	}
+
+	response := artistToResponse(*artist)
+	return &response, nil
+}
+
+func (s *spotifyService) GetArtistTopTracks(ctx context.Context, artistID, market string, userID uint) (*spotify.ArtistTopTracksResponse, error) {
+	tracks, err := s.spotifyOutbond.GetArtistTopTracks(ctx, artistID, market)
+	if err != nil {
+		if errors.Is(err, spotifyRepo.ErrNotFound) {
+			return nil, ErrArtistNotFound
+		}
+
+		log.Error().Err(err).Msg("error get artist top tracks spotify")
+		return nil, err
+	}
+
+	trackIDs := make([]string, len(tracks))
+	for idx, track := range tracks {
+		trackIDs[idx] = track.ID
+	}
+
+	trackActivities, err := s.spotifyRepo.GetBulkSpotifyIDs(ctx, userID, trackIDs)
+	if err != nil {
+		log.Error().Err(err).Msg("error get track activities from db")
+		return nil, err
+	}
+
+	items := make([]spotify.SpotifyTrackObjectResponse, len(tracks))
+	for idx, track := range tracks {
+		items[idx] = TrackToResponse(track, trackActivities)
+	}
+
+	return &spotify.ArtistTopTracksResponse{
+		Items: items,
+	}, nil
+}
+
+func (s *spotifyService) GetArtistAlbums(ctx context.Context, artistID string, request spotify.ArtistAlbumsRequest, userID uint) (*spotify.Page[spotify.SpotifyAlbumObjectResponse], error) {
+	groups, err := albumGroups(request.IncludeGroups)
+	if err != nil {
+		return nil, err
+	}
+
+	pageSize := request.PageSize
+	if pageSize <= 0 || pageSize > maxArtistAlbumsPageSize {
+		pageSize = defaultArtistAlbumsPageSize
+	}
+
+	pageIndex := request.PageIndex
+	if pageIndex <= 0 {
+		pageIndex = 1
+	}
+
+	albums, err := s.spotifyOutbond.GetArtistAlbums(ctx, spotifyRepo.ArtistAlbumsParams{
+		ArtistID:      artistID,
+		IncludeGroups: groups,
+		Market:        request.Market,
+		Limit:         pageSize,
+		Offset:        (pageIndex - 1) * pageSize,
+	})
+	if err != nil {
+		if errors.Is(err, spotifyRepo.ErrNotFound) {
+			return nil, ErrArtistNotFound
+		}
+
+		log.Error().Err(err).Msg("error get artist albums spotify")
+		return nil, err
+	}
+
+	albumIDs := make([]string, len(albums.Items))
+	for idx, album := range albums.Items {
+		albumIDs[idx] = album.ID
+	}
+
+	albumActivities, err := s.spotifyRepo.GetBulkSpotifyIDs(ctx, userID, albumIDs)
+	if err != nil {
+		log.Error().Err(err).Msg("error get album activities from db")
+		return nil, err
+	}
+
+	items := make([]spotify.SpotifyAlbumObjectResponse, len(albums.Items))
+	for idx, album := range albums.Items {
+		items[idx] = albumToResponse(album, albumActivities)
+	}
+
+	return &spotify.Page[spotify.SpotifyAlbumObjectResponse]{
+		Items:  items,
+		Limit:  albums.Limit,
+		Offset: albums.Offset,
+		Total:  albums.Total,
+	}, nil
+}
+
+func (s *spotifyService) GetAlbum(ctx context.Context, albumID, market string, userID uint) (*spotify.AlbumResponse, error) {
+	album, err := s.spotifyOutbond.GetAlbum(ctx, albumID, market)
+	if err != nil {
+		if errors.Is(err, spotifyRepo.ErrNotFound) {
+			return nil, ErrAlbumNotFound
+		}
+
+		log.Error().Err(err).Msg("error get album spotify")
+		return nil, err
+	}
+
+	trackIDs, err := s.albumTrackIDs(ctx, albumID, market, album.Tracks)
+	if err != nil {
+		log.Error().Err(err).Msg("error get album tracks spotify")
+		return nil, err
+	}
+
+	activities, err := s.spotifyRepo.GetBulkSpotifyIDs(ctx, userID, append([]string{album.ID}, trackIDs...))
+	if err != nil {
+		log.Error().Err(err).Msg("error get album activities from db")
+		return nil, err
+	}
+
+	copyrights := make([]spotify.AlbumCopyrightResponse, len(album.Copyrights))
+	for idx, copyright := range album.Copyrights {
+		copyrights[idx] = spotify.AlbumCopyrightResponse{
+			Text: copyright.Text,
+			Type: copyright.Type,
+		}
+	}
+
+	tracks := make([]spotify.SpotifyTrackObjectResponse, len(album.Tracks.Items))
+	for idx, track := range album.Tracks.Items {
+		// album tracks come without their album
+		track.Album = album.SpotifyAlbumObject
+		tracks[idx] = TrackToResponse(track, activities)
+	}
+
+	return &spotify.AlbumResponse{
+		SpotifyAlbumObjectResponse: albumToResponse(spotifyRepo.SpotifySimplifiedAlbumObject{SpotifyAlbumObject: album.SpotifyAlbumObject}, activities),
+		Label:      album.Label,
+		Popularity: album.Popularity,
+		Copyrights: copyrights,
+		Tracks: spotify.Page[spotify.SpotifyTrackObjectResponse]{
+			Items:  tracks,
+			Limit:  album.Tracks.Limit,
+			Offset: album.Tracks.Offset,
+			Total:  album.Tracks.Total,
+		},
+		Completion: albumCompletion(trackIDs, activities),
+	}, nil
+}
+
+func (s *spotifyService) GetAlbumTracks(ctx context.Context, albumID string, request spotify.AlbumTracksRequest, userID uint) (*spotify.AlbumTracksResponse, error) {
+	pageSize := request.PageSize
+	if pageSize <= 0 || pageSize > maxAlbumTracksPageSize {
+		pageSize = defaultAlbumTracksPageSize
+	}
+
+	pageIndex := request.PageIndex
+	if pageIndex <= 0 {
+		pageIndex = 1
+	}
+
+	page, err := s.spotifyOutbond.GetAlbumTracks(ctx, spotifyRepo.AlbumTracksParams{
+		AlbumID: albumID,
+		Market:  request.Market,
+		Limit:   pageSize,
+		Offset:  (pageIndex - 1) * pageSize,
+	})
+	if err != nil {
+		if errors.Is(err, spotifyRepo.ErrNotFound) {
+			return nil, ErrAlbumNotFound
+		}
+
+		log.Error().Err(err).Msg("error get album tracks spotify")
+		return nil, err
+	}
+
+	trackIDs, err := s.albumTrackIDs(ctx, albumID, request.Market, *page)
+	if err != nil {
+		log.Error().Err(err).Msg("error get album tracks spotify")
+		return nil, err
+	}
+
+	trackActivities, err := s.spotifyRepo.GetBulkSpotifyIDs(ctx, userID, trackIDs)
+	if err != nil {
+		log.Error().Err(err).Msg("error get track activities from db")
+		return nil, err
+	}
+
+	items := make([]spotify.SpotifyTrackObjectResponse, len(page.Items))
+	for idx, track := range page.Items {
+		track.Album.ID = albumID
+		items[idx] = TrackToResponse(track, trackActivities)
+	}
+
+	return &spotify.AlbumTracksResponse{
+		Page: spotify.Page[spotify.SpotifyTrackObjectResponse]{
+			Items:  items,
+			Limit:  page.Limit,
+			Offset: page.Offset,
+			Total:  page.Total,
+		},
+		Completion: albumCompletion(trackIDs, trackActivities),
+	}, nil
+}
+
+// albumTrackIDs collects the ids of every track on the album, reusing
+// first when it is the album's first page and fetching the rest. The ids are
+// cached until they expire or the album's total changes.
+func (s *spotifyService) albumTrackIDs(ctx context.Context, albumID, market string, first spotifyRepo.SpotifyTrack) ([]string, error) {
+	key := albumID + ":" + market
+	if cached, ok := s.albumTracks.Get(key); ok && len(cached.trackIDs) == first.Total && s.now().Sub(cached.fetchedAt) < albumTracksCacheTTL {
+		return cached.trackIDs, nil
+	}
+
+	trackIDs := make([]string, 0, first.Total)
+	if first.Offset == 0 {
+		for _, track := range first.Items {
+			trackIDs = append(trackIDs, track.ID)
+		}
+	}
+
+	for len(trackIDs) < first.Total {
+		page, err := s.spotifyOutbond.GetAlbumTracks(ctx, spotifyRepo.AlbumTracksParams{
+			AlbumID: albumID,
+			Market:  market,
+			Limit:   maxAlbumTracksPageSize,
+			Offset:  len(trackIDs),
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		// the album shrank while paging
+		if len(page.Items) == 0 {
+			break
+		}
+
+		for _, track := range page.Items {
+			trackIDs = append(trackIDs, track.ID)
+		}
+	}
+
+	s.albumTracks.Add(key, albumTracksEntry{
+		trackIDs:  trackIDs,
+		fetchedAt: s.now(),
+	})
+
+	return trackIDs, nil
+}
+
+func albumCompletion(trackIDs []string, mapActivities map[string]spotify.TrackActivity) spotify.AlbumCompletionResponse {
+	completion := spotify.AlbumCompletionResponse{
+		TotalTracks: len(trackIDs),
+	}
+	for _, trackID := range trackIDs {
+		if isLiked := mapActivities[trackID].IsLiked; isLiked != nil && *isLiked {
+			completion.LikedTracks++
+		}
+	}
+
+	return completion
+}
+
+func (s *spotifyService) GetRecommendations(ctx context.Context, userID uint, request spotify.RecommendationsRequest) (*spotify.RecommendationsResponse, error) {
+	attributes, err := tunableAttributes(request.Attributes)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := request.Limit
+	if limit <= 0 || limit > maxRecommendationsLimit {
+		limit = defaultRecommendationsLimit
+	}
+
+	seedTracks := uniq.Values(request.SeedTracks)
+	seedArtists := uniq.Values(request.SeedArtists)
+	seedGenres := uniq.Values(request.SeedGenres)
+
+	seeds := len(seedTracks) + len(seedArtists) + len(seedGenres)
+	if seeds > spotifyRepo.MaxRecommendationSeeds {
+		return nil, ErrTooManySeeds
+	}
+
+	if seeds < spotifyRepo.MaxRecommendationSeeds {
+		likedTracks, err := s.recentlyLikedTracks(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, trackID := range likedTracks {
+			if seeds == spotifyRepo.MaxRecommendationSeeds {
+				break
+			}
+
+			if !slices.Contains(seedTracks, trackID) {
+				seedTracks = append(seedTracks, trackID)
+				seeds++
+			}
+		}
+	}
+
+	if seeds == 0 {
+		return nil, ErrNoRecommendationSeeds
+	}
+
+	recommendations, err := s.spotifyOutbond.GetRecommendations(ctx, spotifyRepo.RecommendationsParams{
+		SeedTracks:  seedTracks,
+		SeedArtists: seedArtists,
+		SeedGenres:  seedGenres,
+		Market:      request.Market,
+		// ask for more than needed, the tracks the user knows are dropped
+		Limit:      min(2*limit, maxRecommendationsLimit),
+		Attributes: attributes,
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("error get recommendations spotify")
+		return nil, err
+	}
+
+	trackIDs := make([]string, len(recommendations.Tracks))
+	for idx, track := range recommendations.Tracks {
+		trackIDs[idx] = track.ID
+	}
+
+	trackActivities, err := s.spotifyRepo.GetBulkSpotifyIDs(ctx, userID, trackIDs)
+	if err != nil {
+		log.Error().Err(err).Msg("error get track activities from db")
+		return nil, err
+	}
+
+	items := make([]spotify.SpotifyTrackObjectResponse, 0, limit)
+	for _, track := range recommendations.Tracks {
+		if len(items) == limit {
+			break
+		}
+
+		// liked and disliked tracks are already known to the user
+		if trackActivities[track.ID].IsLiked != nil || slices.Contains(seedTracks, track.ID) {
+			continue
+		}
+
+		items = append(items, TrackToResponse(track, trackActivities))
+	}
+
+	seedsResponse := make([]spotify.RecommendationSeedResponse, len(recommendations.Seeds))
+	for idx, seed := range recommendations.Seeds {
+		seedsResponse[idx] = spotify.RecommendationSeedResponse{
+			ID:   seed.ID,
+			Type: strings.ToLower(seed.Type),
+		}
+	}
+
+	return &spotify.RecommendationsResponse{
+		Seeds: seedsResponse,
+		Items: items,
+	}, nil
+}
+
+// recentlyLikedTracks returns the ids of the user's latest liked tracks,
+// newest first.
+func (s *spotifyService) recentlyLikedTracks(ctx context.Context, userID uint) ([]string, error) {
+	activities, _, err := s.spotifyRepo.ListActivities(ctx, userID, spotify.ActivityFilter{
+		IsLiked: true,
+		Limit:   recommendationSeedCandidates,
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("error list liked activities from db")
+		return nil, err
+	}
+
+	if len(activities) == 0 {
+		return nil, nil
+	}
+
+	trackIDs := make([]string, len(activities))
+	for idx, activity := range activities {
+		trackIDs[idx] = activity.SpotifyID
+	}
+
+	return trackIDs, nil
+}
+
+// tunableAttributes validates the min_, max_ and target_ prefixed track
+// attributes and parses their values.
+func tunableAttributes(requested map[string]string) (map[string]float64, error) {
+	if len(requested) == 0 {
+		return nil, nil
+	}
+
+	attributes := make(map[string]float64, len(requested))
+	for key, value := range requested {
+		key = strings.ToLower(key)
+
+		var attribute string
+		for _, prefix := range []string{"min_", "max_", "target_"} {
+			if name, ok := strings.CutPrefix(key, prefix); ok {
+				attribute = name
+				break
+			}
+		}
+
+		if !slices.Contains(recommendationAttributes, attribute) {
+			return nil, ErrInvalidRecommendationAttribute
+		}
+
+		number, err := strconv.ParseFloat(value, 64)
+		if err != nil || math.IsNaN(number) || math.IsInf(number, 0) {
+			return nil, ErrInvalidRecommendationAttribute
+		}
+
+		attributes[key] = number
+	}
+
+	return attributes, nil
+}
+
+// albumGroups lower-cases and de-duplicates the requested album groups.
+func albumGroups(requested []string) ([]string, error) {
+	groups := make([]string, 0, len(requested))
+	for _, group := range requested {
+		group = strings.ToLower(strings.TrimSpace(group))
+
+		switch group {
+		case spotifyRepo.AlbumGroupAlbum, spotifyRepo.AlbumGroupSingle, spotifyRepo.AlbumGroupAppearsOn, spotifyRepo.AlbumGroupCompilation:
+		default:
+			return nil, ErrInvalidAlbumGroup
+		}
+
+		if !slices.Contains(groups, group) {
+			groups = append(groups, group)
+		}
+	}
+
+	return groups, nil
+}
+
+// entityType defaults activities created without a type to tracks.
+func entityType(requested string) string {
+	if requested == "" {
+		return spotify.EntityTypeTrack
+	}
+
+	return requested
 }
 
 func (s *spotifyService) UpSertActivity(ctx context.Context, userID uint, request spotify.TrackActivityRequest) error {
@@ -108,12 +961,14 @@ func (s *spotifyService) UpSertActivity(ctx context.Context, userID uint, reques
 	}
 
 	if err == gorm.ErrRecordNotFound || foundedActivity == nil {
-		err = s.spotifyRepo.Create(ctx, spotify.TrackActivity{
+		activity := spotify.TrackActivity{
 			UserID: userID,
 			SpotifyID: request.SpotifyID,
-			IsLiked: request.IsLiked,
-		})
+			EntityType: entityType(request.Type),
+		}
+		activity.SetLiked(request.IsLiked, time.Now())
 
+		err = s.spotifyRepo.Create(ctx, activity)
 		if err != nil {
 			log.Error().Err(err).Msg("service: error create record from db")
 			return err
@@ -122,7 +977,7 @@ func (s *spotifyService) UpSertActivity(ctx context.Context, userID uint, reques
 		return nil
 	} 
 
-	foundedActivity.IsLiked = request.IsLiked
+	foundedActivity.SetLiked(request.IsLiked, time.Now())
 	err = s.spotifyRepo.Update(ctx, *foundedActivity)
 	if err != nil {
 		log.Error().Err(err).Msg("service: error update record from db")
@@ -130,4 +985,62 @@ func (s *spotifyService) UpSertActivity(ctx context.Context, userID uint, reques
 	}
 	
 	return nil
-}
\ No newline at end of file
+}
+
+// UpdateActivity sets or clears the liked flag, rating and note of a track
+// independently, leaving the fields missing from the request as they are.
+func (s *spotifyService) UpdateActivity(ctx context.Context, userID uint, spotifyID string, request spotify.UpdateActivityRequest) (*spotify.ActivityResponse, error) {
+	if rating := request.Rating.Value; rating != nil && (*rating < minRating || *rating > maxRating) {
+		return nil, ErrInvalidRating
+	}
+
+	if note := request.Note.Value; note != nil && utf8.RuneCountInString(*note) > maxNoteLength {
+		return nil, ErrNoteTooLong
+	}
+
+	activity, err := s.spotifyRepo.Get(ctx, userID, spotifyID)
+	if err != nil && err != gorm.ErrRecordNotFound {
+		log.Error().Err(err).Msg("service: error get record from db")
+		return nil, err
+	}
+
+	isNew := err == gorm.ErrRecordNotFound || activity == nil
+	if isNew {
+		activity = &spotify.TrackActivity{
+			UserID:     userID,
+			SpotifyID:  spotifyID,
+			EntityType: entityType(request.Type),
+		}
+	}
+
+	if request.IsLiked.Set {
+		activity.SetLiked(request.IsLiked.Value, time.Now())
+	}
+	if request.Rating.Set {
+		activity.Rating = request.Rating.Value
+	}
+	if request.Note.Set {
+		activity.Note = request.Note.Value
+		// an empty note clears it as well
+		if activity.Note != nil && strings.TrimSpace(*activity.Note) == "" {
+			activity.Note = nil
+		}
+	}
+
+	if isNew {
+		err = s.spotifyRepo.Create(ctx, *activity)
+	} else {
+		err = s.spotifyRepo.Update(ctx, *activity)
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("service: error save record to db")
+		return nil, err
+	}
+
+	return &spotify.ActivityResponse{
+		SpotifyID: activity.SpotifyID,
+		IsLiked:   activity.IsLiked,
+		Rating:    activity.Rating,
+		Note:      activity.Note,
+	}, nil
+}
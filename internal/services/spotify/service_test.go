@@ -3,10 +3,14 @@ package spotify
 import (
 	"context"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/sgitwhyd/music-catalogue/internal/models/spotify"
 	spotifyRepo "github.com/sgitwhyd/music-catalogue/internal/repositorys/spotify"
+	"github.com/sgitwhyd/music-catalogue/pkg/httpclient"
+	"github.com/sgitwhyd/music-catalogue/pkg/lrucache"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
 	"gorm.io/gorm"
@@ -43,7 +47,7 @@ func Test_spotifyService_Search(t *testing.T) {
 							Name: "Bohemian Rhapsody (The Original Soundtrack)",
 						},
 						Artists: []spotifyRepo.SpotifyArtisObject{
-							{Name: "Queen"},
+							{ID: "1dfeR4HaWDbWqFHLkxsg1d", Name: "Queen"},
 						},
 						Explicit: false,
 						ID:       "3z8h0TU7ReDPLIbEnYhWZb",
@@ -61,7 +65,7 @@ func Test_spotifyService_Search(t *testing.T) {
 							Name: "A Night At The Opera (2011 Remaster)",
 						},
 						Artists: []spotifyRepo.SpotifyArtisObject{
-							{Name: "Queen"},
+							{ID: "1dfeR4HaWDbWqFHLkxsg1d", Name: "Queen"},
 						},
 						Explicit: false,
 						ID:       "4u7EnebtmKWzUH433cf5Qv",
@@ -76,13 +80,15 @@ func Test_spotifyService_Search(t *testing.T) {
 		query     string
 		pageSize  int
 		pageIndex int
+		types     []string
 	}
 	tests := []struct {
-		name    string
-		args    args
-		want    *spotify.SearchResponse
-		wantErr bool
-		mockFn  func(args args)
+		name      string
+		args      args
+		want      *spotify.SearchResponse
+		wantErr   bool
+		wantErrIs error
+		mockFn    func(args args)
 	}{
 		// TODO: Add test cases.
 		{
@@ -93,37 +99,42 @@ func Test_spotifyService_Search(t *testing.T) {
 				pageIndex: 1,
 			},
 			want: &spotify.SearchResponse{
-				Limit:  10,
-				Offset: 0,
-				Items: []spotify.SpotifyTrackObjectResponse{
-					{
-						AlbumType:        "album",
-						AlbumTotalTracks: 22,
-						AlbumImagesURL:   []string{"https://i.scdn.co/image/ab67616d0000b273e8b066f70c206551210d902b", "https://i.scdn.co/image/ab67616d00001e02e8b066f70c206551210d902b", "https://i.scdn.co/image/ab67616d00004851e8b066f70c206551210d902b"},
-						AlbumName:        "Bohemian Rhapsody (The Original Soundtrack)",
-						ArtistsName:      []string{"Queen"},
-						Explicit:         false,
-						ID:               "3z8h0TU7ReDPLIbEnYhWZb",
-						Name:             "Bohemian Rhapsody",
-						IsLiked:          &isLikedTrue,
-					},
-					{
-						AlbumType:        "album",
-						AlbumTotalTracks: 12,
-						AlbumImagesURL:   []string{"https://i.scdn.co/image/ab67616d0000b273e319baafd16e84f0408af2a0", "https://i.scdn.co/image/ab67616d00001e02e319baafd16e84f0408af2a0", "https://i.scdn.co/image/ab67616d00004851e319baafd16e84f0408af2a0"},
-						AlbumName:        "A Night At The Opera (2011 Remaster)",
-						ArtistsName:      []string{"Queen"},
-						Explicit:         false,
-						ID:               "4u7EnebtmKWzUH433cf5Qv",
-						Name:             "Bohemian Rhapsody - Remastered 2011",
-						IsLiked:          &isLikedFalse,
+				Tracks: &spotify.Page[spotify.SpotifyTrackObjectResponse]{
+					Limit:  10,
+					Offset: 0,
+					Items: []spotify.SpotifyTrackObjectResponse{
+						{
+							AlbumType:        "album",
+							AlbumTotalTracks: 22,
+							AlbumImagesURL:   []string{"https://i.scdn.co/image/ab67616d0000b273e8b066f70c206551210d902b", "https://i.scdn.co/image/ab67616d00001e02e8b066f70c206551210d902b", "https://i.scdn.co/image/ab67616d00004851e8b066f70c206551210d902b"},
+							AlbumName:        "Bohemian Rhapsody (The Original Soundtrack)",
+							ArtistsName:      []string{"Queen"},
+							ArtistsID:        []string{"1dfeR4HaWDbWqFHLkxsg1d"},
+							Explicit:         false,
+							ID:               "3z8h0TU7ReDPLIbEnYhWZb",
+							Name:             "Bohemian Rhapsody",
+							IsLiked:          &isLikedTrue,
+							Tags:             []string{"focus", "workout"},
+						},
+						{
+							AlbumType:        "album",
+							AlbumTotalTracks: 12,
+							AlbumImagesURL:   []string{"https://i.scdn.co/image/ab67616d0000b273e319baafd16e84f0408af2a0", "https://i.scdn.co/image/ab67616d00001e02e319baafd16e84f0408af2a0", "https://i.scdn.co/image/ab67616d00004851e319baafd16e84f0408af2a0"},
+							AlbumName:        "A Night At The Opera (2011 Remaster)",
+							ArtistsName:      []string{"Queen"},
+							ArtistsID:        []string{"1dfeR4HaWDbWqFHLkxsg1d"},
+							Explicit:         false,
+							ID:               "4u7EnebtmKWzUH433cf5Qv",
+							Name:             "Bohemian Rhapsody - Remastered 2011",
+							IsLiked:          &isLikedFalse,
+						},
 					},
+					Total: 905,
 				},
-				Total: 905,
 			},
 			wantErr: false,
 			mockFn: func(args args) {
-				mockSpotifyOutbond.EXPECT().Search(gomock.Any(), "bohemian rhapsody", 10, 0).Return(createMockResponse(), nil)
+				mockSpotifyOutbond.EXPECT().Search(gomock.Any(), spotifyRepo.SearchParams{Query: "bohemian rhapsody", Limit: 10, Offset: 0, Types: []string{"track"}}).Return(createMockResponse(), nil)
 				mockSpotifyRepo.EXPECT().GetBulkSpotifyIDs(gomock.Any(), uint(1), []string{
 					"3z8h0TU7ReDPLIbEnYhWZb",
 					"4u7EnebtmKWzUH433cf5Qv",
@@ -135,8 +146,98 @@ func Test_spotifyService_Search(t *testing.T) {
 						IsLiked: &isLikedFalse,
 					},
 				}, nil)
+				mockSpotifyRepo.EXPECT().GetBulkTags(gomock.Any(), uint(1), []string{
+					"3z8h0TU7ReDPLIbEnYhWZb",
+					"4u7EnebtmKWzUH433cf5Qv",
+				}).Return(map[string][]string{
+					"3z8h0TU7ReDPLIbEnYhWZb": {"focus", "workout"},
+				}, nil)
 			},
 		},
+		{
+			name: "success albums and artists",
+			args: args{
+				query:     "queen",
+				pageSize:  10,
+				pageIndex: 1,
+				types:     []string{"Album", "artist", "album"},
+			},
+			want: &spotify.SearchResponse{
+				Albums: &spotify.Page[spotify.SpotifyAlbumObjectResponse]{
+					Limit: 10,
+					Items: []spotify.SpotifyAlbumObjectResponse{
+						{
+							AlbumType:   "album",
+							TotalTracks: 12,
+							ImagesURL:   []string{},
+							ArtistsName: []string{"Queen"},
+							ArtistsID:   []string{"1dfeR4HaWDbWqFHLkxsg1d"},
+							ReleaseDate: "1975-11-21",
+							ID:          "1GbtB4zTqAsyfZEsm1RZfx",
+							Name:        "A Night At The Opera (2011 Remaster)",
+							IsLiked:     &isLikedTrue,
+						},
+					},
+					Total: 1,
+				},
+				Artists: &spotify.Page[spotify.SpotifyArtistObjectResponse]{
+					Limit: 10,
+					Items: []spotify.SpotifyArtistObjectResponse{
+						{
+							Genres:     []string{"classic rock"},
+							ImagesURL:  []string{},
+							Followers:  53000000,
+							Popularity: 88,
+							ID:         "1dfeR4HaWDbWqFHLkxsg1d",
+							Name:       "Queen",
+						},
+					},
+					Total: 1,
+				},
+			},
+			mockFn: func(args args) {
+				album := spotifyRepo.SpotifySimplifiedAlbumObject{
+					SpotifyAlbumObject: spotifyRepo.SpotifyAlbumObject{
+						AlbumType:   "album",
+						TotalTracks: 12,
+						Name:        "A Night At The Opera (2011 Remaster)",
+						ID:          "1GbtB4zTqAsyfZEsm1RZfx",
+						ReleaseDate: "1975-11-21",
+						Artists:     []spotifyRepo.SpotifyArtisObject{{ID: "1dfeR4HaWDbWqFHLkxsg1d", Name: "Queen"}},
+					},
+				}
+				artist := spotifyRepo.SpotifyFullArtistObject{
+					SpotifyArtisObject: spotifyRepo.SpotifyArtisObject{ID: "1dfeR4HaWDbWqFHLkxsg1d", Name: "Queen"},
+					Genres:             []string{"classic rock"},
+					Popularity:         88,
+				}
+				artist.Followers.Total = 53000000
+
+				mockSpotifyOutbond.EXPECT().Search(gomock.Any(), spotifyRepo.SearchParams{Query: "queen", Limit: 10, Offset: 0, Types: []string{"album", "artist"}}).Return(&spotifyRepo.SpotifySearchResponse{
+					Albums:  &spotifyRepo.SpotifyAlbums{Limit: 10, Total: 1, Items: []spotifyRepo.SpotifySimplifiedAlbumObject{album}},
+					Artists: &spotifyRepo.SpotifyArtists{Limit: 10, Total: 1, Items: []spotifyRepo.SpotifyFullArtistObject{artist}},
+				}, nil)
+				// artists can't be liked, only the album is looked up
+				mockSpotifyRepo.EXPECT().GetBulkSpotifyIDs(gomock.Any(), uint(1), []string{"1GbtB4zTqAsyfZEsm1RZfx"}).Return(map[string]spotify.TrackActivity{
+					"1GbtB4zTqAsyfZEsm1RZfx": {
+						IsLiked: &isLikedTrue,
+					},
+				}, nil)
+			},
+		},
+		{
+			name: "invalid type",
+			args: args{
+				query:     "queen",
+				pageSize:  10,
+				pageIndex: 1,
+				types:     []string{"podcast"},
+			},
+			want:      nil,
+			wantErr:   true,
+			wantErrIs: ErrInvalidSearchType,
+			mockFn:    func(args args) {},
+		},
 		{
 			name: "failed",
 			args: args{
@@ -147,7 +248,21 @@ func Test_spotifyService_Search(t *testing.T) {
 			want:    nil,
 			wantErr: true,
 			mockFn: func(args args) {
-				mockSpotifyOutbond.EXPECT().Search(gomock.Any(), "bohemian rhapsody", 10, 0).Return(nil, assert.AnError)
+				mockSpotifyOutbond.EXPECT().Search(gomock.Any(), spotifyRepo.SearchParams{Query: "bohemian rhapsody", Limit: 10, Offset: 0, Types: []string{"track"}}).Return(nil, assert.AnError)
+			},
+		},
+		{
+			name: "circuit open",
+			args: args{
+				query:     "bohemian rhapsody",
+				pageSize:  10,
+				pageIndex: 1,
+			},
+			want:      nil,
+			wantErr:   true,
+			wantErrIs: httpclient.ErrCircuitOpen,
+			mockFn: func(args args) {
+				mockSpotifyOutbond.EXPECT().Search(gomock.Any(), spotifyRepo.SearchParams{Query: "bohemian rhapsody", Limit: 10, Offset: 0, Types: []string{"track"}}).Return(nil, httpclient.ErrCircuitOpen)
 			},
 		},
 	}
@@ -159,11 +274,19 @@ func Test_spotifyService_Search(t *testing.T) {
 				spotifyRepo:    mockSpotifyRepo,
 			}
 
-			got, err := s.Search(context.Background(), tt.args.query, tt.args.pageSize, tt.args.pageIndex, 1)
+			got, err := s.Search(context.Background(), spotify.SearchRequest{
+				Query:     tt.args.query,
+				PageSize:  tt.args.pageSize,
+				PageIndex: tt.args.pageIndex,
+				Types:     tt.args.types,
+			}, 1)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("spotifyService.Search() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
+			if tt.wantErrIs != nil {
+				assert.ErrorIs(t, err, tt.wantErrIs)
+			}
 			if !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("\ngot = %v, \nwant %v", got, tt.want)
 			}
@@ -206,11 +329,35 @@ func Test_spotifyService_UpSertActivity(t *testing.T) {
 				mockSpotifyRepo.EXPECT().Get(gomock.Any(), uint(1), args.request.SpotifyID).
 					Return(nil, gorm.ErrRecordNotFound)
 				
-				mockSpotifyRepo.EXPECT().Create(gomock.Any(), spotify.TrackActivity{
-					UserID: args.userID,
-					SpotifyID: args.request.SpotifyID,
-					IsLiked: args.request.IsLiked,
-				}).Return(nil)
+				mockSpotifyRepo.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, model spotify.TrackActivity) error {
+					assert.Equal(t, args.userID, model.UserID)
+					assert.Equal(t, args.request.SpotifyID, model.SpotifyID)
+					assert.Equal(t, spotify.EntityTypeTrack, model.EntityType)
+					assert.Equal(t, args.request.IsLiked, model.IsLiked)
+					assert.NotNil(t, model.LikedChangedAt)
+					return nil
+				})
+			},
+		},
+		{
+			name: "success_create_album",
+			args: args{
+				userID: uint(1),
+				request: spotify.TrackActivityRequest{
+					SpotifyID: "AlbumID",
+					Type:      spotify.EntityTypeAlbum,
+					IsLiked:   &isLikedTrue,
+				},
+			},
+			wantErr: false,
+			mockFn: func(args args) {
+				mockSpotifyRepo.EXPECT().Get(gomock.Any(), uint(1), args.request.SpotifyID).
+					Return(nil, gorm.ErrRecordNotFound)
+
+				mockSpotifyRepo.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, model spotify.TrackActivity) error {
+					assert.Equal(t, spotify.EntityTypeAlbum, model.EntityType)
+					return nil
+				})
 			},
 		},
 		{
@@ -229,9 +376,11 @@ func Test_spotifyService_UpSertActivity(t *testing.T) {
 						IsLiked: &isLikedFalse,
 					}, nil)
 				
-				mockSpotifyRepo.EXPECT().Update(gomock.Any(), spotify.TrackActivity{
-					IsLiked: args.request.IsLiked,
-				}).Return(nil)
+				mockSpotifyRepo.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, model spotify.TrackActivity) error {
+					assert.Equal(t, args.request.IsLiked, model.IsLiked)
+					assert.NotNil(t, model.LikedChangedAt)
+					return nil
+				})
 			},
 		},
 		{
@@ -248,11 +397,7 @@ func Test_spotifyService_UpSertActivity(t *testing.T) {
 				mockSpotifyRepo.EXPECT().Get(gomock.Any(), uint(1), args.request.SpotifyID).
 					Return(nil, nil)
 				
-				mockSpotifyRepo.EXPECT().Create(gomock.Any(), spotify.TrackActivity{
-					UserID: args.userID,
-					SpotifyID: args.request.SpotifyID,
-					IsLiked: args.request.IsLiked,
-				}).Return(assert.AnError)
+				mockSpotifyRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(assert.AnError)
 			},
 		},
 	}
@@ -271,3 +416,899 @@ func Test_spotifyService_UpSertActivity(t *testing.T) {
 		})
 	}
 }
+
+func Test_spotifyService_UpdateActivity(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockSpotifyRepo := NewMockSpotifyRepository(mockCtrl)
+
+	isLiked := true
+	rating := 4
+	outOfRange := 6
+	note := "great bridge"
+	blank := "  "
+	longNote := strings.Repeat("é", maxNoteLength+1)
+
+	tests := []struct {
+		name    string
+		request spotify.UpdateActivityRequest
+		mockFn  func()
+		want    *spotify.ActivityResponse
+		wantErr error
+	}{
+		{
+			name: "should create the activity with a rating only",
+			request: spotify.UpdateActivityRequest{
+				Rating: spotify.Optional[int]{Set: true, Value: &rating},
+			},
+			mockFn: func() {
+				mockSpotifyRepo.EXPECT().Get(gomock.Any(), uint(1), "SpotifyID").Return(nil, gorm.ErrRecordNotFound)
+				mockSpotifyRepo.EXPECT().Create(gomock.Any(), spotify.TrackActivity{
+					UserID:     1,
+					SpotifyID:  "SpotifyID",
+					EntityType: spotify.EntityTypeTrack,
+					Rating:     &rating,
+				}).Return(nil)
+			},
+			want: &spotify.ActivityResponse{SpotifyID: "SpotifyID", Rating: &rating},
+		},
+		{
+			name: "should only touch the fields that are set",
+			request: spotify.UpdateActivityRequest{
+				Rating: spotify.Optional[int]{Set: true},
+				Note:   spotify.Optional[string]{Set: true, Value: &note},
+			},
+			mockFn: func() {
+				mockSpotifyRepo.EXPECT().Get(gomock.Any(), uint(1), "SpotifyID").Return(&spotify.TrackActivity{
+					UserID:    1,
+					SpotifyID: "SpotifyID",
+					IsLiked:   &isLiked,
+					Rating:    &rating,
+				}, nil)
+				mockSpotifyRepo.EXPECT().Update(gomock.Any(), spotify.TrackActivity{
+					UserID:    1,
+					SpotifyID: "SpotifyID",
+					IsLiked:   &isLiked,
+					Note:      &note,
+				}).Return(nil)
+			},
+			want: &spotify.ActivityResponse{SpotifyID: "SpotifyID", IsLiked: &isLiked, Note: &note},
+		},
+		{
+			name: "should clear a blank note",
+			request: spotify.UpdateActivityRequest{
+				Note: spotify.Optional[string]{Set: true, Value: &blank},
+			},
+			mockFn: func() {
+				mockSpotifyRepo.EXPECT().Get(gomock.Any(), uint(1), "SpotifyID").Return(&spotify.TrackActivity{
+					SpotifyID: "SpotifyID",
+					Note:      &note,
+				}, nil)
+				mockSpotifyRepo.EXPECT().Update(gomock.Any(), spotify.TrackActivity{SpotifyID: "SpotifyID"}).Return(nil)
+			},
+			want: &spotify.ActivityResponse{SpotifyID: "SpotifyID"},
+		},
+		{
+			name: "should reject a rating out of range",
+			request: spotify.UpdateActivityRequest{
+				Rating: spotify.Optional[int]{Set: true, Value: &outOfRange},
+			},
+			mockFn:  func() {},
+			wantErr: ErrInvalidRating,
+		},
+		{
+			name: "should reject a note that is too long",
+			request: spotify.UpdateActivityRequest{
+				Note: spotify.Optional[string]{Set: true, Value: &longNote},
+			},
+			mockFn:  func() {},
+			wantErr: ErrNoteTooLong,
+		},
+		{
+			name: "should fail when saving fails",
+			request: spotify.UpdateActivityRequest{
+				IsLiked: spotify.Optional[bool]{Set: true, Value: &isLiked},
+			},
+			mockFn: func() {
+				mockSpotifyRepo.EXPECT().Get(gomock.Any(), uint(1), "SpotifyID").Return(&spotify.TrackActivity{SpotifyID: "SpotifyID"}, nil)
+				mockSpotifyRepo.EXPECT().Update(gomock.Any(), gomock.Any()).Return(assert.AnError)
+			},
+			wantErr: assert.AnError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFn()
+
+			s := &spotifyService{
+				spotifyRepo: mockSpotifyRepo,
+			}
+
+			got, err := s.UpdateActivity(context.Background(), 1, "SpotifyID", tt.request)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_spotifyService_GetTrack(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockSpotifyOutbond := NewMockSpotifyOutbond(mockCtrl)
+	mockSpotifyRepo := NewMockSpotifyRepository(mockCtrl)
+
+	isLikedTrue := true
+
+	tests := []struct {
+		name    string
+		id      string
+		want    *spotify.SpotifyTrackObjectResponse
+		wantErr error
+		mockFn  func(id string)
+	}{
+		{
+			name: "success",
+			id:   "3z8h0TU7ReDPLIbEnYhWZb",
+			want: &spotify.SpotifyTrackObjectResponse{
+				AlbumType:        "album",
+				AlbumTotalTracks: 22,
+				AlbumImagesURL:   []string{"https://i.scdn.co/image/ab67616d0000b273e8b066f70c206551210d902b"},
+				AlbumName:        "Bohemian Rhapsody (The Original Soundtrack)",
+				ArtistsName:      []string{"Queen"},
+				ArtistsID:        []string{"1dfeR4HaWDbWqFHLkxsg1d"},
+				ID:               "3z8h0TU7ReDPLIbEnYhWZb",
+				Name:             "Bohemian Rhapsody",
+				IsLiked:          &isLikedTrue,
+			},
+			mockFn: func(id string) {
+				mockSpotifyOutbond.EXPECT().GetTrack(gomock.Any(), id).Return(&spotifyRepo.SpotifyTrackObject{
+					Album: spotifyRepo.SpotifyAlbumObject{
+						AlbumType:   "album",
+						TotalTracks: 22,
+						Images: []spotifyRepo.SpotifyImagesObject{
+							{URL: "https://i.scdn.co/image/ab67616d0000b273e8b066f70c206551210d902b"},
+						},
+						Name: "Bohemian Rhapsody (The Original Soundtrack)",
+					},
+					Artists: []spotifyRepo.SpotifyArtisObject{
+						{ID: "1dfeR4HaWDbWqFHLkxsg1d", Name: "Queen"},
+					},
+					ID:   id,
+					Name: "Bohemian Rhapsody",
+				}, nil)
+				mockSpotifyRepo.EXPECT().GetBulkSpotifyIDs(gomock.Any(), uint(1), []string{id}).
+					Return(map[string]spotify.TrackActivity{
+						id: {IsLiked: &isLikedTrue},
+					}, nil)
+			},
+		},
+		{
+			name:    "not found",
+			id:      "unknown",
+			want:    nil,
+			wantErr: ErrTrackNotFound,
+			mockFn: func(id string) {
+				mockSpotifyOutbond.EXPECT().GetTrack(gomock.Any(), id).Return(nil, spotifyRepo.ErrNotFound)
+			},
+		},
+		{
+			name:    "failed",
+			id:      "3z8h0TU7ReDPLIbEnYhWZb",
+			want:    nil,
+			wantErr: assert.AnError,
+			mockFn: func(id string) {
+				mockSpotifyOutbond.EXPECT().GetTrack(gomock.Any(), id).Return(nil, assert.AnError)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFn(tt.id)
+			s := &spotifyService{
+				spotifyOutbond: mockSpotifyOutbond,
+				spotifyRepo:    mockSpotifyRepo,
+			}
+
+			got, err := s.GetTrack(context.Background(), tt.id, 1)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_spotifyService_GetTracks(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockSpotifyOutbond := NewMockSpotifyOutbond(mockCtrl)
+	mockSpotifyRepo := NewMockSpotifyRepository(mockCtrl)
+
+	isLikedFalse := false
+
+	tests := []struct {
+		name    string
+		ids     []string
+		want    *spotify.TrackLookupResponse
+		wantErr bool
+		mockFn  func()
+	}{
+		{
+			name: "should dedupe ids and fill is_liked",
+			ids:  []string{"first", "second", "first"},
+			want: &spotify.TrackLookupResponse{
+				Items: []spotify.SpotifyTrackObjectResponse{
+					{ID: "first", Name: "First", ArtistsName: []string{}, ArtistsID: []string{}, AlbumImagesURL: []string{}},
+					{ID: "second", Name: "Second", ArtistsName: []string{}, ArtistsID: []string{}, AlbumImagesURL: []string{}, IsLiked: &isLikedFalse},
+				},
+			},
+			wantErr: false,
+			mockFn: func() {
+				mockSpotifyOutbond.EXPECT().GetSeveralTracks(gomock.Any(), []string{"first", "second"}).
+					Return([]spotifyRepo.SpotifyTrackObject{
+						{ID: "first", Name: "First"},
+						{ID: "second", Name: "Second"},
+					}, nil)
+				mockSpotifyRepo.EXPECT().GetBulkSpotifyIDs(gomock.Any(), uint(1), []string{"first", "second"}).
+					Return(map[string]spotify.TrackActivity{
+						"second": {IsLiked: &isLikedFalse},
+					}, nil)
+			},
+		},
+		{
+			name:    "failed",
+			ids:     []string{"first"},
+			want:    nil,
+			wantErr: true,
+			mockFn: func() {
+				mockSpotifyOutbond.EXPECT().GetSeveralTracks(gomock.Any(), []string{"first"}).Return(nil, assert.AnError)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFn()
+			s := &spotifyService{
+				spotifyOutbond: mockSpotifyOutbond,
+				spotifyRepo:    mockSpotifyRepo,
+			}
+
+			got, err := s.GetTracks(context.Background(), tt.ids, 1)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("spotifyService.GetTracks() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("\ngot = %v, \nwant %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_spotifyService_GetLibrary(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockSpotifyOutbond := NewMockSpotifyOutbond(mockCtrl)
+	mockSpotifyRepo := NewMockSpotifyRepository(mockCtrl)
+
+	isLikedTrue := true
+	now := time.Now()
+	earlier := now.Add(-1 * time.Hour)
+
+	type args struct {
+		isLiked bool
+		request spotify.LibraryRequest
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    *spotify.LibraryResponse
+		wantErr bool
+		mockFn  func(args args)
+	}{
+		{
+			name: "should enrich activities in repository order",
+			args: args{
+				isLiked: true,
+				request: spotify.LibraryRequest{PageIndex: 2, PageSize: 2, Tag: " Workout "},
+			},
+			want: &spotify.LibraryResponse{
+				Items: []spotify.LibraryItemResponse{
+					{
+						SpotifyTrackObjectResponse: spotify.SpotifyTrackObjectResponse{
+							ID: "second", Name: "Second", ArtistsName: []string{}, ArtistsID: []string{}, AlbumImagesURL: []string{}, IsLiked: &isLikedTrue, Tags: []string{"workout"},
+						},
+						ActivityAt: now,
+					},
+					{
+						SpotifyTrackObjectResponse: spotify.SpotifyTrackObjectResponse{
+							ID: "removed", ArtistsName: []string{}, ArtistsID: []string{}, AlbumImagesURL: []string{}, IsLiked: &isLikedTrue,
+						},
+						ActivityAt: earlier,
+					},
+				},
+				Limit:  2,
+				Offset: 2,
+				Total:  4,
+			},
+			wantErr: false,
+			mockFn: func(args args) {
+				mockSpotifyRepo.EXPECT().ListActivities(gomock.Any(), uint(1), spotify.ActivityFilter{
+					IsLiked: true,
+					Tag:     "workout",
+					Limit:   2,
+					Offset:  2,
+				}).Return([]spotify.TrackActivity{
+					{Model: gorm.Model{UpdatedAt: now}, SpotifyID: "second", IsLiked: &isLikedTrue},
+					{Model: gorm.Model{UpdatedAt: earlier}, SpotifyID: "removed", IsLiked: &isLikedTrue},
+				}, int64(4), nil)
+				mockSpotifyOutbond.EXPECT().GetSeveralTracks(gomock.Any(), []string{"second", "removed"}).
+					Return([]spotifyRepo.SpotifyTrackObject{
+						{ID: "second", Name: "Second"},
+					}, nil)
+				mockSpotifyRepo.EXPECT().GetBulkTags(gomock.Any(), uint(1), []string{"second", "removed"}).
+					Return(map[string][]string{"second": {"workout"}}, nil)
+			},
+		},
+		{
+			name: "should apply default page size and sort",
+			args: args{
+				isLiked: false,
+				request: spotify.LibraryRequest{Sort: "oldest"},
+			},
+			want: &spotify.LibraryResponse{
+				Items:  []spotify.LibraryItemResponse{},
+				Limit:  10,
+				Offset: 0,
+				Total:  0,
+			},
+			wantErr: false,
+			mockFn: func(args args) {
+				mockSpotifyRepo.EXPECT().ListActivities(gomock.Any(), uint(1), spotify.ActivityFilter{
+					IsLiked: false,
+					SortAsc: true,
+					Limit:   10,
+					Offset:  0,
+				}).Return([]spotify.TrackActivity{}, int64(0), nil)
+				mockSpotifyOutbond.EXPECT().GetSeveralTracks(gomock.Any(), []string{}).
+					Return([]spotifyRepo.SpotifyTrackObject{}, nil)
+				mockSpotifyRepo.EXPECT().GetBulkTags(gomock.Any(), uint(1), []string{}).
+					Return(map[string][]string{}, nil)
+			},
+		},
+		{
+			name: "failed",
+			args: args{
+				isLiked: true,
+			},
+			want:    nil,
+			wantErr: true,
+			mockFn: func(args args) {
+				mockSpotifyRepo.EXPECT().ListActivities(gomock.Any(), uint(1), gomock.Any()).
+					Return(nil, int64(0), assert.AnError)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFn(tt.args)
+			s := &spotifyService{
+				spotifyOutbond: mockSpotifyOutbond,
+				spotifyRepo:    mockSpotifyRepo,
+			}
+
+			got, err := s.GetLibrary(context.Background(), 1, tt.args.isLiked, tt.args.request)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("spotifyService.GetLibrary() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("\ngot = %v, \nwant %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_spotifyService_GetArtistTopTracks(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockSpotifyOutbond := NewMockSpotifyOutbond(mockCtrl)
+	mockSpotifyRepo := NewMockSpotifyRepository(mockCtrl)
+
+	isLikedTrue := true
+
+	tests := []struct {
+		name    string
+		want    *spotify.ArtistTopTracksResponse
+		wantErr error
+		mockFn  func()
+	}{
+		{
+			name: "success",
+			want: &spotify.ArtistTopTracksResponse{
+				Items: []spotify.SpotifyTrackObjectResponse{
+					{
+						AlbumImagesURL: []string{},
+						ArtistsName:    []string{"Queen"},
+						ArtistsID:      []string{"1dfeR4HaWDbWqFHLkxsg1d"},
+						ID:             "7hQJA50XrCWABAu5v6QZ4i",
+						Name:           "Don't Stop Me Now - Remastered 2011",
+						IsLiked:        &isLikedTrue,
+					},
+				},
+			},
+			mockFn: func() {
+				mockSpotifyOutbond.EXPECT().GetArtistTopTracks(gomock.Any(), "1dfeR4HaWDbWqFHLkxsg1d", "ID").Return([]spotifyRepo.SpotifyTrackObject{
+					{
+						Artists: []spotifyRepo.SpotifyArtisObject{{ID: "1dfeR4HaWDbWqFHLkxsg1d", Name: "Queen"}},
+						ID:      "7hQJA50XrCWABAu5v6QZ4i",
+						Name:    "Don't Stop Me Now - Remastered 2011",
+					},
+				}, nil)
+				mockSpotifyRepo.EXPECT().GetBulkSpotifyIDs(gomock.Any(), uint(1), []string{"7hQJA50XrCWABAu5v6QZ4i"}).Return(map[string]spotify.TrackActivity{
+					"7hQJA50XrCWABAu5v6QZ4i": {IsLiked: &isLikedTrue},
+				}, nil)
+			},
+		},
+		{
+			name:    "not found",
+			wantErr: ErrArtistNotFound,
+			mockFn: func() {
+				mockSpotifyOutbond.EXPECT().GetArtistTopTracks(gomock.Any(), "1dfeR4HaWDbWqFHLkxsg1d", "ID").Return(nil, spotifyRepo.ErrNotFound)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFn()
+			s := &spotifyService{
+				spotifyOutbond: mockSpotifyOutbond,
+				spotifyRepo:    mockSpotifyRepo,
+			}
+
+			got, err := s.GetArtistTopTracks(context.Background(), "1dfeR4HaWDbWqFHLkxsg1d", "ID", 1)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_spotifyService_GetArtistAlbums(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockSpotifyOutbond := NewMockSpotifyOutbond(mockCtrl)
+	mockSpotifyRepo := NewMockSpotifyRepository(mockCtrl)
+
+	isLikedTrue := true
+
+	tests := []struct {
+		name    string
+		request spotify.ArtistAlbumsRequest
+		want    *spotify.Page[spotify.SpotifyAlbumObjectResponse]
+		wantErr error
+		mockFn  func()
+	}{
+		{
+			name: "success",
+			request: spotify.ArtistAlbumsRequest{
+				PageIndex:     2,
+				PageSize:      1,
+				IncludeGroups: []string{"Album", "single", "album"},
+			},
+			want: &spotify.Page[spotify.SpotifyAlbumObjectResponse]{
+				Items: []spotify.SpotifyAlbumObjectResponse{
+					{
+						AlbumType:   "album",
+						ImagesURL:   []string{},
+						ArtistsName: []string{},
+						ArtistsID:   []string{},
+						AlbumGroup:  "album",
+						ID:          "2noRn2Aes5aoNVsU6iWThc",
+						Name:        "Discovery",
+						IsLiked:     &isLikedTrue,
+					},
+				},
+				Limit:  1,
+				Offset: 1,
+				Total:  2,
+			},
+			mockFn: func() {
+				mockSpotifyOutbond.EXPECT().GetArtistAlbums(gomock.Any(), spotifyRepo.ArtistAlbumsParams{
+					ArtistID:      "4tZwfgrHOc3mvqYlEYSvVi",
+					IncludeGroups: []string{"album", "single"},
+					Limit:         1,
+					Offset:        1,
+				}).Return(&spotifyRepo.SpotifyAlbums{
+					Items: []spotifyRepo.SpotifySimplifiedAlbumObject{
+						{
+							SpotifyAlbumObject: spotifyRepo.SpotifyAlbumObject{AlbumType: "album", Name: "Discovery", ID: "2noRn2Aes5aoNVsU6iWThc"},
+							AlbumGroup:         "album",
+						},
+					},
+					Limit:  1,
+					Offset: 1,
+					Total:  2,
+				}, nil)
+				mockSpotifyRepo.EXPECT().GetBulkSpotifyIDs(gomock.Any(), uint(1), []string{"2noRn2Aes5aoNVsU6iWThc"}).Return(map[string]spotify.TrackActivity{
+					"2noRn2Aes5aoNVsU6iWThc": {IsLiked: &isLikedTrue},
+				}, nil)
+			},
+		},
+		{
+			name:    "invalid album group",
+			request: spotify.ArtistAlbumsRequest{IncludeGroups: []string{"ep"}},
+			wantErr: ErrInvalidAlbumGroup,
+			mockFn:  func() {},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFn()
+			s := &spotifyService{
+				spotifyOutbond: mockSpotifyOutbond,
+				spotifyRepo:    mockSpotifyRepo,
+			}
+
+			got, err := s.GetArtistAlbums(context.Background(), "4tZwfgrHOc3mvqYlEYSvVi", tt.request, 1)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_spotifyService_GetAlbum(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockSpotifyOutbond := NewMockSpotifyOutbond(mockCtrl)
+	mockSpotifyRepo := NewMockSpotifyRepository(mockCtrl)
+
+	isLikedTrue := true
+	isLikedFalse := false
+
+	album := spotifyRepo.SpotifyAlbumObject{
+		AlbumType:            "album",
+		TotalTracks:          3,
+		Name:                 "Discovery",
+		ID:                   "2noRn2Aes5aoNVsU6iWThc",
+		ReleaseDate:          "2001-03-12",
+		ReleaseDatePrecision: "day",
+	}
+
+	tests := []struct {
+		name    string
+		want    *spotify.AlbumResponse
+		wantErr error
+		mockFn  func()
+	}{
+		{
+			name: "success",
+			want: &spotify.AlbumResponse{
+				SpotifyAlbumObjectResponse: spotify.SpotifyAlbumObjectResponse{
+					AlbumType:            "album",
+					TotalTracks:          3,
+					ImagesURL:            []string{},
+					ArtistsName:          []string{},
+					ArtistsID:            []string{},
+					ReleaseDate:          "2001-03-12",
+					ReleaseDatePrecision: "day",
+					ID:                   "2noRn2Aes5aoNVsU6iWThc",
+					Name:                 "Discovery",
+				},
+				Label:      "Parlophone",
+				Copyrights: []spotify.AlbumCopyrightResponse{{Text: "(P) 2001 Daft Life Ltd.", Type: "P"}},
+				Tracks: spotify.Page[spotify.SpotifyTrackObjectResponse]{
+					Items: []spotify.SpotifyTrackObjectResponse{
+						{
+							AlbumType:        "album",
+							AlbumTotalTracks: 3,
+							AlbumImagesURL:   []string{},
+							AlbumName:        "Discovery",
+							AlbumID:          "2noRn2Aes5aoNVsU6iWThc",
+							ArtistsName:      []string{},
+							ArtistsID:        []string{},
+							ID:               "a",
+							IsLiked:          &isLikedTrue,
+							TrackNumber:      1,
+						},
+						{
+							AlbumType:        "album",
+							AlbumTotalTracks: 3,
+							AlbumImagesURL:   []string{},
+							AlbumName:        "Discovery",
+							AlbumID:          "2noRn2Aes5aoNVsU6iWThc",
+							ArtistsName:      []string{},
+							ArtistsID:        []string{},
+							ID:               "b",
+							IsLiked:          &isLikedFalse,
+							TrackNumber:      2,
+						},
+					},
+					Limit: 2,
+					Total: 3,
+				},
+				Completion: spotify.AlbumCompletionResponse{
+					LikedTracks: 2,
+					TotalTracks: 3,
+				},
+			},
+			mockFn: func() {
+				mockSpotifyOutbond.EXPECT().GetAlbum(gomock.Any(), "2noRn2Aes5aoNVsU6iWThc", "ID").Return(&spotifyRepo.SpotifyFullAlbumObject{
+					SpotifyAlbumObject: album,
+					Label:              "Parlophone",
+					Copyrights:         []spotifyRepo.SpotifyCopyrightObject{{Text: "(P) 2001 Daft Life Ltd.", Type: "P"}},
+					Tracks: spotifyRepo.SpotifyTrack{
+						Items: []spotifyRepo.SpotifyTrackObject{{ID: "a", TrackNumber: 1}, {ID: "b", TrackNumber: 2}},
+						Limit: 2,
+						Total: 3,
+					},
+				}, nil)
+				// the tracks past the first page are still counted
+				mockSpotifyOutbond.EXPECT().GetAlbumTracks(gomock.Any(), spotifyRepo.AlbumTracksParams{
+					AlbumID: "2noRn2Aes5aoNVsU6iWThc",
+					Market:  "ID",
+					Limit:   50,
+					Offset:  2,
+				}).Return(&spotifyRepo.SpotifyTrack{
+					Items: []spotifyRepo.SpotifyTrackObject{{ID: "c", TrackNumber: 3}},
+					Limit:  50,
+					Offset: 2,
+					Total:  3,
+				}, nil)
+				mockSpotifyRepo.EXPECT().GetBulkSpotifyIDs(gomock.Any(), uint(1), []string{"2noRn2Aes5aoNVsU6iWThc", "a", "b", "c"}).Return(map[string]spotify.TrackActivity{
+					"a": {IsLiked: &isLikedTrue},
+					"b": {IsLiked: &isLikedFalse},
+					"c": {IsLiked: &isLikedTrue},
+				}, nil)
+			},
+		},
+		{
+			name:    "not found",
+			wantErr: ErrAlbumNotFound,
+			mockFn: func() {
+				mockSpotifyOutbond.EXPECT().GetAlbum(gomock.Any(), "2noRn2Aes5aoNVsU6iWThc", "ID").Return(nil, spotifyRepo.ErrNotFound)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFn()
+			s := &spotifyService{
+				spotifyOutbond: mockSpotifyOutbond,
+				spotifyRepo:    mockSpotifyRepo,
+				albumTracks:    lrucache.New[string, albumTracksEntry](1),
+				now:            time.Now,
+			}
+
+			got, err := s.GetAlbum(context.Background(), "2noRn2Aes5aoNVsU6iWThc", "ID", 1)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_spotifyService_GetAlbumTracks(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockSpotifyOutbond := NewMockSpotifyOutbond(mockCtrl)
+	mockSpotifyRepo := NewMockSpotifyRepository(mockCtrl)
+
+	isLikedTrue := true
+
+	mockSpotifyOutbond.EXPECT().GetAlbumTracks(gomock.Any(), spotifyRepo.AlbumTracksParams{
+		AlbumID: "2noRn2Aes5aoNVsU6iWThc",
+		Limit:   1,
+		Offset:  1,
+	}).Return(&spotifyRepo.SpotifyTrack{
+		Items:  []spotifyRepo.SpotifyTrackObject{{ID: "b", TrackNumber: 2}},
+		Limit:  1,
+		Offset: 1,
+		Total:  2,
+	}, nil)
+	// a later page can't be reused, the ids are collected from the start
+	mockSpotifyOutbond.EXPECT().GetAlbumTracks(gomock.Any(), spotifyRepo.AlbumTracksParams{
+		AlbumID: "2noRn2Aes5aoNVsU6iWThc",
+		Limit:   50,
+	}).Return(&spotifyRepo.SpotifyTrack{
+		Items: []spotifyRepo.SpotifyTrackObject{{ID: "a", TrackNumber: 1}, {ID: "b", TrackNumber: 2}},
+		Limit: 50,
+		Total: 2,
+	}, nil)
+	mockSpotifyRepo.EXPECT().GetBulkSpotifyIDs(gomock.Any(), uint(1), []string{"a", "b"}).Return(map[string]spotify.TrackActivity{
+		"a": {IsLiked: &isLikedTrue},
+	}, nil).Times(2)
+
+	s := &spotifyService{
+		spotifyOutbond: mockSpotifyOutbond,
+		spotifyRepo:    mockSpotifyRepo,
+		albumTracks:    lrucache.New[string, albumTracksEntry](1),
+		now:            time.Now,
+	}
+
+	got, err := s.GetAlbumTracks(context.Background(), "2noRn2Aes5aoNVsU6iWThc", spotify.AlbumTracksRequest{PageIndex: 2, PageSize: 1}, 1)
+	assert.NoError(t, err)
+
+	// the next page reuses the collected ids
+	mockSpotifyOutbond.EXPECT().GetAlbumTracks(gomock.Any(), spotifyRepo.AlbumTracksParams{
+		AlbumID: "2noRn2Aes5aoNVsU6iWThc",
+		Limit:   1,
+	}).Return(&spotifyRepo.SpotifyTrack{
+		Items: []spotifyRepo.SpotifyTrackObject{{ID: "a", TrackNumber: 1}},
+		Limit: 1,
+		Total: 2,
+	}, nil)
+
+	first, err := s.GetAlbumTracks(context.Background(), "2noRn2Aes5aoNVsU6iWThc", spotify.AlbumTracksRequest{PageIndex: 1, PageSize: 1}, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, spotify.AlbumCompletionResponse{LikedTracks: 1, TotalTracks: 2}, first.Completion)
+	assert.Equal(t, &spotify.AlbumTracksResponse{
+		Page: spotify.Page[spotify.SpotifyTrackObjectResponse]{
+			Items: []spotify.SpotifyTrackObjectResponse{
+				{
+					AlbumImagesURL: []string{},
+					AlbumID:        "2noRn2Aes5aoNVsU6iWThc",
+					ArtistsName:    []string{},
+					ArtistsID:      []string{},
+					ID:             "b",
+					TrackNumber:    2,
+				},
+			},
+			Limit:  1,
+			Offset: 1,
+			Total:  2,
+		},
+		Completion: spotify.AlbumCompletionResponse{
+			LikedTracks: 1,
+			TotalTracks: 2,
+		},
+	}, got)
+}
+
+func Test_spotifyService_GetRecommendations(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockSpotifyOutbond := NewMockSpotifyOutbond(mockCtrl)
+	mockSpotifyRepo := NewMockSpotifyRepository(mockCtrl)
+
+	isLikedTrue := true
+	isLikedFalse := false
+
+	likedFilter := spotify.ActivityFilter{IsLiked: true, Limit: 20}
+
+	tests := []struct {
+		name    string
+		request spotify.RecommendationsRequest
+		want    *spotify.RecommendationsResponse
+		wantErr error
+		mockFn  func()
+	}{
+		{
+			name: "success",
+			request: spotify.RecommendationsRequest{
+				Limit:      2,
+				SeedGenres: []string{"french house"},
+				Attributes: map[string]string{"Target_Energy": "0.8"},
+			},
+			want: &spotify.RecommendationsResponse{
+				Seeds: []spotify.RecommendationSeedResponse{
+					{ID: "liked-1", Type: "track"},
+					{ID: "liked-2", Type: "track"},
+					{ID: "french house", Type: "genre"},
+				},
+				Items: []spotify.SpotifyTrackObjectResponse{
+					{AlbumImagesURL: []string{}, ArtistsName: []string{}, ArtistsID: []string{}, ID: "new-1"},
+					{AlbumImagesURL: []string{}, ArtistsName: []string{}, ArtistsID: []string{}, ID: "new-2"},
+				},
+			},
+			mockFn: func() {
+				mockSpotifyRepo.EXPECT().ListActivities(gomock.Any(), uint(1), likedFilter).Return([]spotify.TrackActivity{
+					{SpotifyID: "liked-1", IsLiked: &isLikedTrue},
+					{SpotifyID: "liked-2", IsLiked: &isLikedTrue},
+				}, int64(2), nil)
+				mockSpotifyOutbond.EXPECT().GetRecommendations(gomock.Any(), spotifyRepo.RecommendationsParams{
+					SeedTracks:  []string{"liked-1", "liked-2"},
+					SeedArtists: []string{},
+					SeedGenres:  []string{"french house"},
+					Limit:       4,
+					Attributes:  map[string]float64{"target_energy": 0.8},
+				}).Return(&spotifyRepo.SpotifyRecommendationsResponse{
+					Seeds: []spotifyRepo.SpotifyRecommendationSeedObject{
+						{ID: "liked-1", Type: "TRACK"},
+						{ID: "liked-2", Type: "TRACK"},
+						{ID: "french house", Type: "GENRE"},
+					},
+					Tracks: []spotifyRepo.SpotifyTrackObject{{ID: "liked-3"}, {ID: "new-1"}, {ID: "disliked"}, {ID: "new-2"}, {ID: "new-3"}},
+				}, nil)
+				mockSpotifyRepo.EXPECT().GetBulkSpotifyIDs(gomock.Any(), uint(1), []string{"liked-3", "new-1", "disliked", "new-2", "new-3"}).Return(map[string]spotify.TrackActivity{
+					"liked-3":  {IsLiked: &isLikedTrue},
+					"disliked": {IsLiked: &isLikedFalse},
+					// activity without a verdict
+					"new-2": {},
+				}, nil)
+			},
+		},
+		{
+			name: "too many seeds",
+			request: spotify.RecommendationsRequest{
+				SeedArtists: []string{"a", "b", "c"},
+				SeedGenres:  []string{"d", "e", "f"},
+			},
+			wantErr: ErrTooManySeeds,
+			mockFn:  func() {},
+		},
+		{
+			name:    "no seeds",
+			wantErr: ErrNoRecommendationSeeds,
+			mockFn: func() {
+				mockSpotifyRepo.EXPECT().ListActivities(gomock.Any(), uint(1), likedFilter).Return([]spotify.TrackActivity{}, int64(0), nil)
+			},
+		},
+		{
+			name: "invalid attribute",
+			request: spotify.RecommendationsRequest{
+				SeedGenres: []string{"jazz"},
+				Attributes: map[string]string{"target_mood": "1"},
+			},
+			wantErr: ErrInvalidRecommendationAttribute,
+			mockFn:  func() {},
+		},
+		{
+			name: "invalid attribute value",
+			request: spotify.RecommendationsRequest{
+				SeedGenres: []string{"jazz"},
+				Attributes: map[string]string{"min_tempo": "fast"},
+			},
+			wantErr: ErrInvalidRecommendationAttribute,
+			mockFn:  func() {},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFn()
+			s := &spotifyService{
+				spotifyOutbond: mockSpotifyOutbond,
+				spotifyRepo:    mockSpotifyRepo,
+			}
+
+			got, err := s.GetRecommendations(context.Background(), 1, tt.request)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,141 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: account.go
+//
+// Generated by this command:
+//
+//	mockgen -source=account.go -destination=../../services/spotifyaccount/outbond_mock_test.go -package=spotifyaccount
+//
+
+// Package spotifyaccount is a generated GoMock package.
+package spotifyaccount
+
+import (
+	context "context"
+	reflect "reflect"
+
+	spotify "github.com/sgitwhyd/music-catalogue/internal/repositorys/spotify"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockSpotifyAuthOutbond is a mock of SpotifyAuthOutbond interface.
+type MockSpotifyAuthOutbond struct {
+	ctrl     *gomock.Controller
+	recorder *MockSpotifyAuthOutbondMockRecorder
+	isgomock struct{}
+}
+
+// MockSpotifyAuthOutbondMockRecorder is the mock recorder for MockSpotifyAuthOutbond.
+type MockSpotifyAuthOutbondMockRecorder struct {
+	mock *MockSpotifyAuthOutbond
+}
+
+// NewMockSpotifyAuthOutbond creates a new mock instance.
+func NewMockSpotifyAuthOutbond(ctrl *gomock.Controller) *MockSpotifyAuthOutbond {
+	mock := &MockSpotifyAuthOutbond{ctrl: ctrl}
+	mock.recorder = &MockSpotifyAuthOutbondMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSpotifyAuthOutbond) EXPECT() *MockSpotifyAuthOutbondMockRecorder {
+	return m.recorder
+}
+
+// AuthorizeURL mocks base method.
+func (m *MockSpotifyAuthOutbond) AuthorizeURL(params spotify.AuthorizeParams) string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AuthorizeURL", params)
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// AuthorizeURL indicates an expected call of AuthorizeURL.
+func (mr *MockSpotifyAuthOutbondMockRecorder) AuthorizeURL(params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AuthorizeURL", reflect.TypeOf((*MockSpotifyAuthOutbond)(nil).AuthorizeURL), params)
+}
+
+// ExchangeCode mocks base method.
+func (m *MockSpotifyAuthOutbond) ExchangeCode(ctx context.Context, code, codeVerifier, redirectURI string) (*spotify.SpotifyTokenResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExchangeCode", ctx, code, codeVerifier, redirectURI)
+	ret0, _ := ret[0].(*spotify.SpotifyTokenResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExchangeCode indicates an expected call of ExchangeCode.
+func (mr *MockSpotifyAuthOutbondMockRecorder) ExchangeCode(ctx, code, codeVerifier, redirectURI any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExchangeCode", reflect.TypeOf((*MockSpotifyAuthOutbond)(nil).ExchangeCode), ctx, code, codeVerifier, redirectURI)
+}
+
+// GetCurrentUser mocks base method.
+func (m *MockSpotifyAuthOutbond) GetCurrentUser(ctx context.Context, accessToken string) (*spotify.SpotifyUserProfile, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCurrentUser", ctx, accessToken)
+	ret0, _ := ret[0].(*spotify.SpotifyUserProfile)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCurrentUser indicates an expected call of GetCurrentUser.
+func (mr *MockSpotifyAuthOutbondMockRecorder) GetCurrentUser(ctx, accessToken any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCurrentUser", reflect.TypeOf((*MockSpotifyAuthOutbond)(nil).GetCurrentUser), ctx, accessToken)
+}
+
+// RefreshUserToken mocks base method.
+func (m *MockSpotifyAuthOutbond) RefreshUserToken(ctx context.Context, refreshToken string) (*spotify.SpotifyTokenResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefreshUserToken", ctx, refreshToken)
+	ret0, _ := ret[0].(*spotify.SpotifyTokenResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RefreshUserToken indicates an expected call of RefreshUserToken.
+func (mr *MockSpotifyAuthOutbondMockRecorder) RefreshUserToken(ctx, refreshToken any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshUserToken", reflect.TypeOf((*MockSpotifyAuthOutbond)(nil).RefreshUserToken), ctx, refreshToken)
+}
+
+// MockUserTokenSource is a mock of UserTokenSource interface.
+type MockUserTokenSource struct {
+	ctrl     *gomock.Controller
+	recorder *MockUserTokenSourceMockRecorder
+	isgomock struct{}
+}
+
+// MockUserTokenSourceMockRecorder is the mock recorder for MockUserTokenSource.
+type MockUserTokenSourceMockRecorder struct {
+	mock *MockUserTokenSource
+}
+
+// NewMockUserTokenSource creates a new mock instance.
+func NewMockUserTokenSource(ctrl *gomock.Controller) *MockUserTokenSource {
+	mock := &MockUserTokenSource{ctrl: ctrl}
+	mock.recorder = &MockUserTokenSourceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUserTokenSource) EXPECT() *MockUserTokenSourceMockRecorder {
+	return m.recorder
+}
+
+// UserToken mocks base method.
+func (m *MockUserTokenSource) UserToken(ctx context.Context, userID uint) (string, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UserToken", ctx, userID)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// UserToken indicates an expected call of UserToken.
+func (mr *MockUserTokenSourceMockRecorder) UserToken(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UserToken", reflect.TypeOf((*MockUserTokenSource)(nil).UserToken), ctx, userID)
+}
@@ -0,0 +1,381 @@
+package spotifyaccount
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/sgitwhyd/music-catalogue/internal/apperror"
+	"github.com/sgitwhyd/music-catalogue/internal/configs"
+	"github.com/sgitwhyd/music-catalogue/internal/models/spotifyaccount"
+	spotifyRepo "github.com/sgitwhyd/music-catalogue/internal/repositorys/spotify"
+	accountRepo "github.com/sgitwhyd/music-catalogue/internal/repositorys/spotifyaccount"
+	"github.com/sgitwhyd/music-catalogue/pkg/pkce"
+	"github.com/sgitwhyd/music-catalogue/pkg/secretbox"
+	"golang.org/x/sync/singleflight"
+	"gorm.io/gorm"
+)
+
+//go:generate mockgen -source=service.go -destination=../../handlers/spotifyaccount/handler_mock_test.go -package=spotifyaccount
+type SpotifyAccountService interface {
+	Connect(ctx context.Context, userID uint) (*spotifyaccount.ConnectResponse, error)
+	Callback(ctx context.Context, userID uint, request spotifyaccount.CallbackRequest) (*spotifyaccount.ConnectionResponse, error)
+	GetConnection(ctx context.Context, userID uint) (*spotifyaccount.ConnectionResponse, error)
+	Disconnect(ctx context.Context, userID uint) error
+}
+
+var (
+	ErrSpotifyLinkingDisabled   = apperror.New(apperror.KindUnavailable, "spotify_linking_disabled", "linking spotify accounts is not configured")
+	ErrInvalidOAuthState        = apperror.New(apperror.KindInvalid, "invalid_oauth_state", "the authorization request is unknown or expired, connect again")
+	ErrSpotifyAuthDenied        = apperror.New(apperror.KindForbidden, "spotify_auth_denied", "access to the spotify account was not granted")
+	ErrSpotifyAuthFailed        = apperror.New(apperror.KindInvalid, "spotify_auth_failed", "spotify didn't accept the authorization, connect again")
+	ErrSpotifyNotConnected      = apperror.New(apperror.KindForbidden, "spotify_not_connected", "link a spotify account first")
+	ErrSpotifyReconnectRequired = apperror.New(apperror.KindForbidden, "spotify_reconnect_required", "spotify revoked access, connect the account again")
+)
+
+const (
+	// how long a user has to finish the consent screen
+	authStateTTL = 10 * time.Minute
+
+	// refresh a user token this long before spotify expires it
+	userTokenRefreshWindow = time.Minute
+)
+
+// what the catalogue needs to read and change a user's library and playlists
+var defaultScopes = []string{
+	"user-library-read",
+	"user-library-modify",
+	"playlist-read-private",
+	"playlist-modify-private",
+}
+
+type spotifyAccountService struct {
+	accountRepo accountRepo.SpotifyAccountRepository
+	authOutbond spotifyRepo.SpotifyAuthOutbond
+	// nil when no SPOTIFY_TOKEN_KEY is configured, linking is off then
+	box    *secretbox.Box
+	config *configs.Config
+	now    func() time.Time
+
+	// one refresh per user at a time, spotify may rotate the refresh token
+	refreshes singleflight.Group
+}
+
+func NewSpotifyAccountService(accountRepo accountRepo.SpotifyAccountRepository, authOutbond spotifyRepo.SpotifyAuthOutbond, box *secretbox.Box, config *configs.Config) *spotifyAccountService {
+	return &spotifyAccountService{
+		accountRepo: accountRepo,
+		authOutbond: authOutbond,
+		box:         box,
+		config:      config,
+		now:         time.Now,
+	}
+}
+
+// Connect starts the authorization code flow. The client sends the user to
+// the returned url, spotify sends them back to the client which posts the
+// outcome to the callback.
+func (s *spotifyAccountService) Connect(ctx context.Context, userID uint) (*spotifyaccount.ConnectResponse, error) {
+	if !s.enabled() {
+		return nil, ErrSpotifyLinkingDisabled
+	}
+
+	state, err := pkce.NewState()
+	if err != nil {
+		return nil, err
+	}
+
+	verifier, err := pkce.NewVerifier()
+	if err != nil {
+		return nil, err
+	}
+
+	model := spotifyaccount.SpotifyAuthState{
+		State:        state,
+		UserID:       userID,
+		CodeVerifier: verifier,
+		ExpiresAt:    s.now().Add(authStateTTL),
+	}
+
+	err = s.accountRepo.CreateState(ctx, &model)
+	if err != nil {
+		log.Error().Err(err).Msg("service: error create spotify auth state")
+		return nil, err
+	}
+
+	return &spotifyaccount.ConnectResponse{
+		AuthorizeURL: s.authOutbond.AuthorizeURL(spotifyRepo.AuthorizeParams{
+			State:         state,
+			CodeChallenge: pkce.Challenge(verifier),
+			RedirectURI:   s.config.SpotifyRedirectURL,
+			Scopes:        s.scopes(),
+		}),
+		ExpiresAt: model.ExpiresAt,
+	}, nil
+}
+
+// Callback finishes the flow: the state has to be one the signed in user
+// started, the code is exchanged for tokens which are stored sealed.
+// Connecting again replaces the linked account.
+func (s *spotifyAccountService) Callback(ctx context.Context, userID uint, request spotifyaccount.CallbackRequest) (*spotifyaccount.ConnectionResponse, error) {
+	if !s.enabled() {
+		return nil, ErrSpotifyLinkingDisabled
+	}
+
+	if request.State == "" {
+		return nil, ErrInvalidOAuthState
+	}
+
+	// a state of another user is as unknown as a forged one, otherwise a
+	// victim could be made to link the attacker's spotify account
+	state, err := s.accountRepo.TakeState(ctx, userID, request.State)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrInvalidOAuthState
+		}
+
+		log.Error().Err(err).Msg("service: error take spotify auth state")
+		return nil, err
+	}
+
+	if s.now().After(state.ExpiresAt) {
+		return nil, ErrInvalidOAuthState
+	}
+
+	if request.Error != "" {
+		log.Warn().Str("error", request.Error).Msg("service: spotify authorization not granted")
+		return nil, ErrSpotifyAuthDenied
+	}
+
+	if request.Code == "" {
+		return nil, ErrSpotifyAuthFailed
+	}
+
+	token, err := s.authOutbond.ExchangeCode(ctx, request.Code, state.CodeVerifier, s.config.SpotifyRedirectURL)
+	if err != nil {
+		if err == spotifyRepo.ErrInvalidGrant {
+			return nil, ErrSpotifyAuthFailed
+		}
+
+		log.Error().Err(err).Msg("service: error exchange spotify code")
+		return nil, err
+	}
+
+	profile, err := s.authOutbond.GetCurrentUser(ctx, token.AccessToken)
+	if err != nil {
+		log.Error().Err(err).Msg("service: error get spotify profile")
+		return nil, err
+	}
+
+	account, err := s.accountRepo.Get(ctx, state.UserID)
+	if err != nil && err != gorm.ErrRecordNotFound {
+		log.Error().Err(err).Msg("service: error get spotify account")
+		return nil, err
+	}
+
+	isNew := err == gorm.ErrRecordNotFound || account == nil
+	if isNew {
+		account = &spotifyaccount.SpotifyAccount{
+			UserID: state.UserID,
+		}
+	}
+
+	account.SpotifyUserID = profile.ID
+	account.DisplayName = profile.DisplayName
+	account.Scope = token.Scope
+
+	err = s.setTokens(account, token, "")
+	if err != nil {
+		return nil, err
+	}
+
+	if isNew {
+		err = s.accountRepo.Create(ctx, account)
+	} else {
+		err = s.accountRepo.Update(ctx, account)
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("service: error save spotify account")
+		return nil, err
+	}
+
+	response := toResponse(account)
+	return &response, nil
+}
+
+func (s *spotifyAccountService) GetConnection(ctx context.Context, userID uint) (*spotifyaccount.ConnectionResponse, error) {
+	account, err := s.accountRepo.Get(ctx, userID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return &spotifyaccount.ConnectionResponse{Connected: false}, nil
+		}
+
+		log.Error().Err(err).Msg("service: error get spotify account")
+		return nil, err
+	}
+
+	response := toResponse(account)
+	return &response, nil
+}
+
+// Disconnect forgets the tokens, disconnecting twice is fine. Access stays
+// granted on spotify's side until the user removes the app there.
+func (s *spotifyAccountService) Disconnect(ctx context.Context, userID uint) error {
+	err := s.accountRepo.Delete(ctx, userID)
+	if err != nil && err != gorm.ErrRecordNotFound {
+		log.Error().Err(err).Msg("service: error delete spotify account")
+		return err
+	}
+
+	return nil
+}
+
+// UserToken implements spotify.UserTokenSource, refreshing the token when it
+// is about to expire.
+func (s *spotifyAccountService) UserToken(ctx context.Context, userID uint) (string, string, error) {
+	if !s.enabled() {
+		return "", "", ErrSpotifyNotConnected
+	}
+
+	account, err := s.getAccount(ctx, userID)
+	if err != nil {
+		return "", "", err
+	}
+
+	if s.now().After(account.ExpiresAt.Add(-userTokenRefreshWindow)) {
+		result, err, _ := s.refreshes.Do(strconv.FormatUint(uint64(userID), 10), func() (any, error) {
+			// shared by every caller waiting on the refresh, one of them
+			// going away must not cancel it
+			return s.refresh(context.WithoutCancel(ctx), userID)
+		})
+		if err != nil {
+			return "", "", err
+		}
+
+		account = result.(*spotifyaccount.SpotifyAccount)
+	}
+
+	accessToken, err := s.box.Open(account.AccessToken)
+	if err != nil {
+		log.Error().Err(err).Msg("service: error open spotify access token")
+		return "", "", err
+	}
+
+	return accessToken, account.TokenType, nil
+}
+
+func (s *spotifyAccountService) refresh(ctx context.Context, userID uint) (*spotifyaccount.SpotifyAccount, error) {
+	// another caller may have refreshed it in the meantime
+	account, err := s.getAccount(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.now().Before(account.ExpiresAt.Add(-userTokenRefreshWindow)) {
+		return account, nil
+	}
+
+	refreshToken, err := s.box.Open(account.RefreshToken)
+	if err != nil {
+		log.Error().Err(err).Msg("service: error open spotify refresh token")
+		return nil, err
+	}
+
+	token, err := s.authOutbond.RefreshUserToken(ctx, refreshToken)
+	if err != nil {
+		if err == spotifyRepo.ErrInvalidGrant {
+			// the tokens are worthless now, the user has to connect again
+			err = s.accountRepo.Delete(ctx, userID)
+			if err != nil && err != gorm.ErrRecordNotFound {
+				log.Error().Err(err).Msg("service: error delete revoked spotify account")
+			}
+
+			return nil, ErrSpotifyReconnectRequired
+		}
+
+		log.Error().Err(err).Msg("service: error refresh spotify user token")
+		return nil, err
+	}
+
+	if token.Scope != "" {
+		account.Scope = token.Scope
+	}
+
+	err = s.setTokens(account, token, refreshToken)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.accountRepo.Update(ctx, account)
+	if err != nil {
+		log.Error().Err(err).Msg("service: error update spotify account tokens")
+		return nil, err
+	}
+
+	return account, nil
+}
+
+func (s *spotifyAccountService) getAccount(ctx context.Context, userID uint) (*spotifyaccount.SpotifyAccount, error) {
+	account, err := s.accountRepo.Get(ctx, userID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrSpotifyNotConnected
+		}
+
+		log.Error().Err(err).Msg("service: error get spotify account")
+		return nil, err
+	}
+
+	return account, nil
+}
+
+// setTokens seals the tokens into account. previousRefreshToken is kept
+// when spotify didn't rotate it.
+func (s *spotifyAccountService) setTokens(account *spotifyaccount.SpotifyAccount, token *spotifyRepo.SpotifyTokenResponse, previousRefreshToken string) error {
+	refreshToken := token.RefreshToken
+	if refreshToken == "" {
+		refreshToken = previousRefreshToken
+	}
+
+	accessToken, err := s.box.Seal(token.AccessToken)
+	if err != nil {
+		return err
+	}
+
+	sealedRefreshToken, err := s.box.Seal(refreshToken)
+	if err != nil {
+		return err
+	}
+
+	account.AccessToken = accessToken
+	account.RefreshToken = sealedRefreshToken
+	account.TokenType = token.TokenType
+	account.ExpiresAt = s.now().Add(time.Duration(token.ExpiresIn) * time.Second)
+
+	return nil
+}
+
+func (s *spotifyAccountService) enabled() bool {
+	return s.box != nil && s.config.SpotifyRedirectURL != ""
+}
+
+func (s *spotifyAccountService) scopes() []string {
+	if scopes := strings.Fields(s.config.SpotifyScopes); len(scopes) > 0 {
+		return scopes
+	}
+
+	return defaultScopes
+}
+
+func toResponse(account *spotifyaccount.SpotifyAccount) spotifyaccount.ConnectionResponse {
+	connectedAt := account.CreatedAt
+
+	return spotifyaccount.ConnectionResponse{
+		Connected:     true,
+		SpotifyUserID: account.SpotifyUserID,
+		DisplayName:   account.DisplayName,
+		Scopes:        strings.Fields(account.Scope),
+		ConnectedAt:   &connectedAt,
+	}
+}
@@ -0,0 +1,327 @@
+package spotifyaccount
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/sgitwhyd/music-catalogue/internal/configs"
+	"github.com/sgitwhyd/music-catalogue/internal/models/spotifyaccount"
+	spotifyRepo "github.com/sgitwhyd/music-catalogue/internal/repositorys/spotify"
+	"github.com/sgitwhyd/music-catalogue/pkg/pkce"
+	"github.com/sgitwhyd/music-catalogue/pkg/secretbox"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+var testNow = time.Date(2026, 1, 2, 15, 4, 5, 0, time.UTC)
+
+func newTestBox(t *testing.T) *secretbox.Box {
+	t.Helper()
+
+	key := make([]byte, secretbox.KeySize)
+	_, err := rand.Read(key)
+	assert.NoError(t, err)
+
+	box, err := secretbox.New(base64.StdEncoding.EncodeToString(key))
+	assert.NoError(t, err)
+
+	return box
+}
+
+func seal(t *testing.T, box *secretbox.Box, plaintext string) string {
+	t.Helper()
+
+	sealed, err := box.Seal(plaintext)
+	assert.NoError(t, err)
+
+	return sealed
+}
+
+func Test_spotifyAccountService_Connect(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockAccountRepo := NewMockSpotifyAccountRepository(mockCtrl)
+	mockAuthOutbond := NewMockSpotifyAuthOutbond(mockCtrl)
+
+	s := NewSpotifyAccountService(mockAccountRepo, mockAuthOutbond, newTestBox(t), &configs.Config{
+		SpotifyRedirectURL: "http://localhost:8080/api/v1/spotify/callback",
+	})
+	s.now = func() time.Time { return testNow }
+
+	var stored spotifyaccount.SpotifyAuthState
+	mockAccountRepo.EXPECT().CreateState(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, model *spotifyaccount.SpotifyAuthState) error {
+			stored = *model
+			return nil
+		})
+	mockAuthOutbond.EXPECT().AuthorizeURL(gomock.Any()).
+		DoAndReturn(func(params spotifyRepo.AuthorizeParams) string {
+			assert.Equal(t, stored.State, params.State)
+			assert.Equal(t, pkce.Challenge(stored.CodeVerifier), params.CodeChallenge)
+			assert.Equal(t, "http://localhost:8080/api/v1/spotify/callback", params.RedirectURI)
+			assert.Equal(t, defaultScopes, params.Scopes)
+
+			return "https://accounts.spotify.com/authorize?state=" + url.QueryEscape(params.State)
+		})
+
+	got, err := s.Connect(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(1), stored.UserID)
+	assert.NotEmpty(t, stored.CodeVerifier)
+	assert.Equal(t, testNow.Add(authStateTTL), got.ExpiresAt)
+	assert.Contains(t, got.AuthorizeURL, url.QueryEscape(stored.State))
+
+	t.Run("disabled without a token key", func(t *testing.T) {
+		s := NewSpotifyAccountService(mockAccountRepo, mockAuthOutbond, nil, &configs.Config{
+			SpotifyRedirectURL: "http://localhost:8080/api/v1/spotify/callback",
+		})
+
+		_, err := s.Connect(context.Background(), 1)
+		assert.ErrorIs(t, err, ErrSpotifyLinkingDisabled)
+	})
+}
+
+func Test_spotifyAccountService_Callback(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockAccountRepo := NewMockSpotifyAccountRepository(mockCtrl)
+	mockAuthOutbond := NewMockSpotifyAuthOutbond(mockCtrl)
+
+	box := newTestBox(t)
+	redirectURL := "http://localhost:8080/api/v1/spotify/callback"
+
+	state := &spotifyaccount.SpotifyAuthState{
+		State:        "state",
+		UserID:       1,
+		CodeVerifier: "verifier",
+		ExpiresAt:    testNow.Add(time.Minute),
+	}
+
+	type args struct {
+		request spotifyaccount.CallbackRequest
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    *spotifyaccount.ConnectionResponse
+		wantErr error
+		mockFn  func(args args)
+	}{
+		{
+			name: "success",
+			args: args{
+				request: spotifyaccount.CallbackRequest{Code: "code", State: "state"},
+			},
+			want: &spotifyaccount.ConnectionResponse{
+				Connected:     true,
+				SpotifyUserID: "listener",
+				DisplayName:   "Listener",
+				Scopes:        []string{"user-library-read", "playlist-read-private"},
+				ConnectedAt:   &time.Time{},
+			},
+			mockFn: func(args args) {
+				mockAccountRepo.EXPECT().TakeState(gomock.Any(), uint(1), "state").Return(state, nil)
+				mockAuthOutbond.EXPECT().ExchangeCode(gomock.Any(), "code", "verifier", redirectURL).
+					Return(&spotifyRepo.SpotifyTokenResponse{
+						AccessToken:  "access",
+						TokenType:    "Bearer",
+						ExpiresIn:    3600,
+						RefreshToken: "refresh",
+						Scope:        "user-library-read playlist-read-private",
+					}, nil)
+				mockAuthOutbond.EXPECT().GetCurrentUser(gomock.Any(), "access").
+					Return(&spotifyRepo.SpotifyUserProfile{ID: "listener", DisplayName: "Listener"}, nil)
+				mockAccountRepo.EXPECT().Get(gomock.Any(), uint(1)).Return(nil, gorm.ErrRecordNotFound)
+				mockAccountRepo.EXPECT().Create(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, model *spotifyaccount.SpotifyAccount) error {
+						assert.Equal(t, uint(1), model.UserID)
+						assert.Equal(t, testNow.Add(time.Hour), model.ExpiresAt)
+
+						// never stored in the clear
+						assert.NotEqual(t, "access", model.AccessToken)
+						assert.NotEqual(t, "refresh", model.RefreshToken)
+
+						accessToken, err := box.Open(model.AccessToken)
+						assert.NoError(t, err)
+						assert.Equal(t, "access", accessToken)
+
+						refreshToken, err := box.Open(model.RefreshToken)
+						assert.NoError(t, err)
+						assert.Equal(t, "refresh", refreshToken)
+
+						return nil
+					})
+			},
+		},
+		{
+			name: "unknown state",
+			args: args{
+				request: spotifyaccount.CallbackRequest{Code: "code", State: "forged"},
+			},
+			wantErr: ErrInvalidOAuthState,
+			mockFn: func(args args) {
+				mockAccountRepo.EXPECT().TakeState(gomock.Any(), uint(1), "forged").Return(nil, gorm.ErrRecordNotFound)
+			},
+		},
+		{
+			name: "state of another user",
+			args: args{
+				request: spotifyaccount.CallbackRequest{Code: "code", State: "theirs"},
+			},
+			wantErr: ErrInvalidOAuthState,
+			mockFn: func(args args) {
+				mockAccountRepo.EXPECT().TakeState(gomock.Any(), uint(1), "theirs").Return(nil, gorm.ErrRecordNotFound)
+			},
+		},
+		{
+			name: "missing state",
+			args: args{
+				request: spotifyaccount.CallbackRequest{Code: "code"},
+			},
+			wantErr: ErrInvalidOAuthState,
+			mockFn:  func(args args) {},
+		},
+		{
+			name: "expired state",
+			args: args{
+				request: spotifyaccount.CallbackRequest{Code: "code", State: "state"},
+			},
+			wantErr: ErrInvalidOAuthState,
+			mockFn: func(args args) {
+				mockAccountRepo.EXPECT().TakeState(gomock.Any(), uint(1), "state").
+					Return(&spotifyaccount.SpotifyAuthState{State: "state", UserID: 1, ExpiresAt: testNow.Add(-time.Second)}, nil)
+			},
+		},
+		{
+			name: "user denied access",
+			args: args{
+				request: spotifyaccount.CallbackRequest{Error: "access_denied", State: "state"},
+			},
+			wantErr: ErrSpotifyAuthDenied,
+			mockFn: func(args args) {
+				mockAccountRepo.EXPECT().TakeState(gomock.Any(), uint(1), "state").Return(state, nil)
+			},
+		},
+		{
+			name: "code rejected",
+			args: args{
+				request: spotifyaccount.CallbackRequest{Code: "used", State: "state"},
+			},
+			wantErr: ErrSpotifyAuthFailed,
+			mockFn: func(args args) {
+				mockAccountRepo.EXPECT().TakeState(gomock.Any(), uint(1), "state").Return(state, nil)
+				mockAuthOutbond.EXPECT().ExchangeCode(gomock.Any(), "used", "verifier", redirectURL).
+					Return(nil, spotifyRepo.ErrInvalidGrant)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFn(tt.args)
+
+			s := NewSpotifyAccountService(mockAccountRepo, mockAuthOutbond, box, &configs.Config{
+				SpotifyRedirectURL: redirectURL,
+			})
+			s.now = func() time.Time { return testNow }
+
+			got, err := s.Callback(context.Background(), 1, tt.args.request)
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_spotifyAccountService_UserToken(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockAccountRepo := NewMockSpotifyAccountRepository(mockCtrl)
+	mockAuthOutbond := NewMockSpotifyAuthOutbond(mockCtrl)
+
+	box := newTestBox(t)
+
+	account := func(expiresAt time.Time) *spotifyaccount.SpotifyAccount {
+		return &spotifyaccount.SpotifyAccount{
+			UserID:       1,
+			AccessToken:  seal(t, box, "access"),
+			RefreshToken: seal(t, box, "refresh"),
+			TokenType:    "Bearer",
+			ExpiresAt:    expiresAt,
+		}
+	}
+
+	tests := []struct {
+		name    string
+		want    string
+		wantErr error
+		mockFn  func()
+	}{
+		{
+			name: "valid token",
+			want: "access",
+			mockFn: func() {
+				mockAccountRepo.EXPECT().Get(gomock.Any(), uint(1)).Return(account(testNow.Add(time.Hour)), nil)
+			},
+		},
+		{
+			name: "refreshes a token about to expire",
+			want: "refreshed",
+			mockFn: func() {
+				mockAccountRepo.EXPECT().Get(gomock.Any(), uint(1)).Return(account(testNow.Add(time.Second)), nil).Times(2)
+				mockAuthOutbond.EXPECT().RefreshUserToken(gomock.Any(), "refresh").
+					Return(&spotifyRepo.SpotifyTokenResponse{AccessToken: "refreshed", TokenType: "Bearer", ExpiresIn: 3600}, nil)
+				mockAccountRepo.EXPECT().Update(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, model *spotifyaccount.SpotifyAccount) error {
+						assert.Equal(t, testNow.Add(time.Hour), model.ExpiresAt)
+
+						// not rotated, the old one stays
+						refreshToken, err := box.Open(model.RefreshToken)
+						assert.NoError(t, err)
+						assert.Equal(t, "refresh", refreshToken)
+
+						return nil
+					})
+			},
+		},
+		{
+			name:    "access revoked",
+			wantErr: ErrSpotifyReconnectRequired,
+			mockFn: func() {
+				mockAccountRepo.EXPECT().Get(gomock.Any(), uint(1)).Return(account(testNow.Add(-time.Hour)), nil).Times(2)
+				mockAuthOutbond.EXPECT().RefreshUserToken(gomock.Any(), "refresh").Return(nil, spotifyRepo.ErrInvalidGrant)
+				mockAccountRepo.EXPECT().Delete(gomock.Any(), uint(1)).Return(nil)
+			},
+		},
+		{
+			name:    "not connected",
+			wantErr: ErrSpotifyNotConnected,
+			mockFn: func() {
+				mockAccountRepo.EXPECT().Get(gomock.Any(), uint(1)).Return(nil, gorm.ErrRecordNotFound)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFn()
+
+			s := NewSpotifyAccountService(mockAccountRepo, mockAuthOutbond, box, &configs.Config{
+				SpotifyRedirectURL: "http://localhost:8080/api/v1/spotify/callback",
+			})
+			s.now = func() time.Time { return testNow }
+
+			got, tokenType, err := s.UserToken(context.Background(), 1)
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.want, got)
+			if tt.wantErr == nil {
+				assert.Equal(t, "Bearer", tokenType)
+			}
+		})
+	}
+}
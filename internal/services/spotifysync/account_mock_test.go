@@ -0,0 +1,143 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ../../repositorys/spotifyaccount/repository.go
+//
+// Generated by this command:
+//
+//	mockgen -source=../../repositorys/spotifyaccount/repository.go -destination=account_mock_test.go -package=spotifysync
+//
+
+// Package spotifysync is a generated GoMock package.
+package spotifysync
+
+import (
+	context "context"
+	reflect "reflect"
+
+	spotifyaccount "github.com/sgitwhyd/music-catalogue/internal/models/spotifyaccount"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockSpotifyAccountRepository is a mock of SpotifyAccountRepository interface.
+type MockSpotifyAccountRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockSpotifyAccountRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockSpotifyAccountRepositoryMockRecorder is the mock recorder for MockSpotifyAccountRepository.
+type MockSpotifyAccountRepositoryMockRecorder struct {
+	mock *MockSpotifyAccountRepository
+}
+
+// NewMockSpotifyAccountRepository creates a new mock instance.
+func NewMockSpotifyAccountRepository(ctrl *gomock.Controller) *MockSpotifyAccountRepository {
+	mock := &MockSpotifyAccountRepository{ctrl: ctrl}
+	mock.recorder = &MockSpotifyAccountRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSpotifyAccountRepository) EXPECT() *MockSpotifyAccountRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockSpotifyAccountRepository) Create(ctx context.Context, model *spotifyaccount.SpotifyAccount) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, model)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockSpotifyAccountRepositoryMockRecorder) Create(ctx, model any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockSpotifyAccountRepository)(nil).Create), ctx, model)
+}
+
+// CreateState mocks base method.
+func (m *MockSpotifyAccountRepository) CreateState(ctx context.Context, model *spotifyaccount.SpotifyAuthState) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateState", ctx, model)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateState indicates an expected call of CreateState.
+func (mr *MockSpotifyAccountRepositoryMockRecorder) CreateState(ctx, model any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateState", reflect.TypeOf((*MockSpotifyAccountRepository)(nil).CreateState), ctx, model)
+}
+
+// Delete mocks base method.
+func (m *MockSpotifyAccountRepository) Delete(ctx context.Context, UserID uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, UserID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockSpotifyAccountRepositoryMockRecorder) Delete(ctx, UserID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockSpotifyAccountRepository)(nil).Delete), ctx, UserID)
+}
+
+// Get mocks base method.
+func (m *MockSpotifyAccountRepository) Get(ctx context.Context, UserID uint) (*spotifyaccount.SpotifyAccount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, UserID)
+	ret0, _ := ret[0].(*spotifyaccount.SpotifyAccount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockSpotifyAccountRepositoryMockRecorder) Get(ctx, UserID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockSpotifyAccountRepository)(nil).Get), ctx, UserID)
+}
+
+// ListUserIDs mocks base method.
+func (m *MockSpotifyAccountRepository) ListUserIDs(ctx context.Context) ([]uint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUserIDs", ctx)
+	ret0, _ := ret[0].([]uint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUserIDs indicates an expected call of ListUserIDs.
+func (mr *MockSpotifyAccountRepositoryMockRecorder) ListUserIDs(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUserIDs", reflect.TypeOf((*MockSpotifyAccountRepository)(nil).ListUserIDs), ctx)
+}
+
+// TakeState mocks base method.
+func (m *MockSpotifyAccountRepository) TakeState(ctx context.Context, UserID uint, state string) (*spotifyaccount.SpotifyAuthState, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TakeState", ctx, UserID, state)
+	ret0, _ := ret[0].(*spotifyaccount.SpotifyAuthState)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TakeState indicates an expected call of TakeState.
+func (mr *MockSpotifyAccountRepositoryMockRecorder) TakeState(ctx, UserID, state any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TakeState", reflect.TypeOf((*MockSpotifyAccountRepository)(nil).TakeState), ctx, UserID, state)
+}
+
+// Update mocks base method.
+func (m *MockSpotifyAccountRepository) Update(ctx context.Context, model *spotifyaccount.SpotifyAccount) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, model)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockSpotifyAccountRepositoryMockRecorder) Update(ctx, model any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockSpotifyAccountRepository)(nil).Update), ctx, model)
+}
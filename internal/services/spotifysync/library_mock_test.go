@@ -0,0 +1,85 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: library.go
+//
+// Generated by this command:
+//
+//	mockgen -source=library.go -destination=../../services/spotifysync/library_mock_test.go -package=spotifysync
+//
+
+// Package spotifysync is a generated GoMock package.
+package spotifysync
+
+import (
+	context "context"
+	reflect "reflect"
+
+	spotify "github.com/sgitwhyd/music-catalogue/internal/repositorys/spotify"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockSpotifyLibraryOutbond is a mock of SpotifyLibraryOutbond interface.
+type MockSpotifyLibraryOutbond struct {
+	ctrl     *gomock.Controller
+	recorder *MockSpotifyLibraryOutbondMockRecorder
+	isgomock struct{}
+}
+
+// MockSpotifyLibraryOutbondMockRecorder is the mock recorder for MockSpotifyLibraryOutbond.
+type MockSpotifyLibraryOutbondMockRecorder struct {
+	mock *MockSpotifyLibraryOutbond
+}
+
+// NewMockSpotifyLibraryOutbond creates a new mock instance.
+func NewMockSpotifyLibraryOutbond(ctrl *gomock.Controller) *MockSpotifyLibraryOutbond {
+	mock := &MockSpotifyLibraryOutbond{ctrl: ctrl}
+	mock.recorder = &MockSpotifyLibraryOutbondMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSpotifyLibraryOutbond) EXPECT() *MockSpotifyLibraryOutbondMockRecorder {
+	return m.recorder
+}
+
+// GetSavedTracks mocks base method.
+func (m *MockSpotifyLibraryOutbond) GetSavedTracks(ctx context.Context, limit, offset int) (*spotify.SpotifySavedTracks, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSavedTracks", ctx, limit, offset)
+	ret0, _ := ret[0].(*spotify.SpotifySavedTracks)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSavedTracks indicates an expected call of GetSavedTracks.
+func (mr *MockSpotifyLibraryOutbondMockRecorder) GetSavedTracks(ctx, limit, offset any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSavedTracks", reflect.TypeOf((*MockSpotifyLibraryOutbond)(nil).GetSavedTracks), ctx, limit, offset)
+}
+
+// RemoveSavedTracks mocks base method.
+func (m *MockSpotifyLibraryOutbond) RemoveSavedTracks(ctx context.Context, spotifyIDs []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveSavedTracks", ctx, spotifyIDs)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveSavedTracks indicates an expected call of RemoveSavedTracks.
+func (mr *MockSpotifyLibraryOutbondMockRecorder) RemoveSavedTracks(ctx, spotifyIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveSavedTracks", reflect.TypeOf((*MockSpotifyLibraryOutbond)(nil).RemoveSavedTracks), ctx, spotifyIDs)
+}
+
+// SaveTracks mocks base method.
+func (m *MockSpotifyLibraryOutbond) SaveTracks(ctx context.Context, spotifyIDs []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveTracks", ctx, spotifyIDs)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveTracks indicates an expected call of SaveTracks.
+func (mr *MockSpotifyLibraryOutbondMockRecorder) SaveTracks(ctx, spotifyIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveTracks", reflect.TypeOf((*MockSpotifyLibraryOutbond)(nil).SaveTracks), ctx, spotifyIDs)
+}
@@ -0,0 +1,586 @@
+package spotifysync
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/sgitwhyd/music-catalogue/internal/apperror"
+	"github.com/sgitwhyd/music-catalogue/internal/models/spotify"
+	"github.com/sgitwhyd/music-catalogue/internal/models/spotifysync"
+	spotifyRepo "github.com/sgitwhyd/music-catalogue/internal/repositorys/spotify"
+	accountRepo "github.com/sgitwhyd/music-catalogue/internal/repositorys/spotifyaccount"
+	syncRepo "github.com/sgitwhyd/music-catalogue/internal/repositorys/spotifysync"
+	"gorm.io/gorm"
+)
+
+//go:generate mockgen -source=service.go -destination=../../handlers/spotifysync/handler_mock_test.go -package=spotifysync
+//go:generate mockgen -source=../../repositorys/spotify/repository.go -destination=spotify_mock_test.go -package=spotifysync
+//go:generate mockgen -source=../../repositorys/spotifyaccount/repository.go -destination=account_mock_test.go -package=spotifysync
+type SpotifySyncService interface {
+	Sync(ctx context.Context, userID uint) (*spotifysync.SyncRunResponse, error)
+	ListRuns(ctx context.Context, userID uint, request spotifysync.ListSyncRunsRequest) (*spotifysync.ListSyncRunsResponse, error)
+	GetRun(ctx context.Context, userID, runID uint) (*spotifysync.SyncRunResponse, error)
+}
+
+var (
+	ErrSyncRunNotFound     = apperror.New(apperror.KindNotFound, "sync_run_not_found", "sync run not found")
+	ErrSpotifyNotConnected = apperror.New(apperror.KindForbidden, "spotify_not_connected", "link a spotify account first")
+	ErrSyncInProgress      = apperror.New(apperror.KindConflict, "sync_in_progress", "the account is being synced already")
+)
+
+const (
+	defaultSyncRunsPageSize = 20
+	maxSyncRunsPageSize     = 100
+
+	// the largest page of saved tracks spotify returns
+	savedTracksPageSize = 50
+
+	// how often a replica proves it still works on a run, a run that didn't
+	// beat for staleAfter was lost with its replica
+	heartbeatInterval = time.Minute
+	staleAfter        = 5 * time.Minute
+
+	interruptedMessage = "the sync was interrupted, it runs again with the next scheduled sync"
+)
+
+type spotifySyncService struct {
+	syncRepo       syncRepo.SpotifySyncRepository
+	accountRepo    accountRepo.SpotifyAccountRepository
+	libraryOutbond spotifyRepo.SpotifyLibraryOutbond
+	spotifyRepo    spotifyRepo.SpotifyRepository
+	now            func() time.Time
+
+	// runs outlive the request that started them, they only stop when ctx is
+	// cancelled on shutdown
+	ctx      context.Context
+	cancel   context.CancelFunc
+	runs     sync.WaitGroup
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+func NewSpotifySyncService(syncRepo syncRepo.SpotifySyncRepository, accountRepo accountRepo.SpotifyAccountRepository, libraryOutbond spotifyRepo.SpotifyLibraryOutbond, spotifyRepo spotifyRepo.SpotifyRepository) *spotifySyncService {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &spotifySyncService{
+		syncRepo:       syncRepo,
+		accountRepo:    accountRepo,
+		libraryOutbond: libraryOutbond,
+		spotifyRepo:    spotifyRepo,
+		now:            time.Now,
+		ctx:            ctx,
+		cancel:         cancel,
+		stop:           make(chan struct{}),
+	}
+}
+
+// Sync starts a sync of the user's likes in the background. The returned run
+// is running, clients poll GetRun for the report.
+func (s *spotifySyncService) Sync(ctx context.Context, userID uint) (*spotifysync.SyncRunResponse, error) {
+	_, err := s.accountRepo.Get(ctx, userID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrSpotifyNotConnected
+		}
+
+		log.Error().Err(err).Msg("service: error get spotify account")
+		return nil, err
+	}
+
+	run, err := s.begin(ctx, userID, spotifysync.TriggerManual)
+	if err != nil {
+		return nil, err
+	}
+
+	response := toResponse(*run)
+
+	s.runs.Add(1)
+	go func() {
+		defer s.runs.Done()
+
+		s.execute(s.ctx, run)
+	}()
+
+	return &response, nil
+}
+
+func (s *spotifySyncService) ListRuns(ctx context.Context, userID uint, request spotifysync.ListSyncRunsRequest) (*spotifysync.ListSyncRunsResponse, error) {
+	pageSize := request.PageSize
+	if pageSize <= 0 || pageSize > maxSyncRunsPageSize {
+		pageSize = defaultSyncRunsPageSize
+	}
+
+	pageIndex := request.PageIndex
+	if pageIndex <= 0 {
+		pageIndex = 1
+	}
+
+	limit := pageSize
+	offset := (pageIndex - 1) * pageSize
+
+	runs, total, err := s.syncRepo.List(ctx, userID, limit, offset)
+	if err != nil {
+		log.Error().Err(err).Msg("service: error list sync runs")
+		return nil, err
+	}
+
+	items := make([]spotifysync.SyncRunResponse, len(runs))
+	for idx, run := range runs {
+		items[idx] = toResponse(run)
+	}
+
+	return &spotifysync.ListSyncRunsResponse{
+		Items:  items,
+		Limit:  limit,
+		Offset: offset,
+		Total:  int(total),
+	}, nil
+}
+
+func (s *spotifySyncService) GetRun(ctx context.Context, userID, runID uint) (*spotifysync.SyncRunResponse, error) {
+	run, err := s.syncRepo.Get(ctx, runID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrSyncRunNotFound
+		}
+
+		log.Error().Err(err).Msg("service: error get sync run")
+		return nil, err
+	}
+
+	// another user's run is reported as missing rather than forbidden
+	if run.UserID != userID {
+		return nil, ErrSyncRunNotFound
+	}
+
+	response := toResponse(*run)
+	return &response, nil
+}
+
+// Start syncs every linked account once per interval, one account after the
+// other, until Shutdown. Every replica runs the schedule, an account that
+// another replica synced lately is skipped. Runs lost with another replica
+// are failed once per heartbeat interval.
+func (s *spotifySyncService) Start(interval time.Duration) {
+	s.runs.Add(1)
+	go func() {
+		defer s.runs.Done()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		stale := time.NewTicker(heartbeatInterval)
+		defer stale.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				s.syncAll(interval)
+			case <-stale.C:
+				_ = s.FailInterrupted(s.ctx)
+			case <-s.stop:
+				return
+			case <-s.ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// FailInterrupted marks the runs no replica beat for as failed. Runs of
+// other replicas that are still going are left alone.
+func (s *spotifySyncService) FailInterrupted(ctx context.Context) error {
+	count, err := s.syncRepo.FailUnfinished(ctx, interruptedMessage, s.now().Add(-staleAfter))
+	if err != nil {
+		log.Error().Err(err).Msg("service: error fail unfinished sync runs")
+		return err
+	}
+
+	if count > 0 {
+		log.Warn().Int64("runs", count).Msg("service: failed sync runs interrupted by a restart")
+	}
+
+	return nil
+}
+
+// Shutdown stops the schedule and waits for the running syncs. When ctx ends
+// first they are cancelled and recorded as failed.
+func (s *spotifySyncService) Shutdown(ctx context.Context) error {
+	s.stopOnce.Do(func() { close(s.stop) })
+
+	done := make(chan struct{})
+	go func() {
+		s.runs.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		s.cancel()
+		<-done
+		return ctx.Err()
+	}
+}
+
+func (s *spotifySyncService) syncAll(interval time.Duration) {
+	userIDs, err := s.accountRepo.ListUserIDs(s.ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("service: error list linked spotify accounts")
+		return
+	}
+
+	for _, userID := range userIDs {
+		select {
+		case <-s.stop:
+			return
+		default:
+		}
+
+		// the schedules of the replicas tick out of step, half an interval
+		// tells a run of this round from one of the last
+		if s.syncedSince(userID, s.now().Add(-interval/2)) {
+			continue
+		}
+
+		run, err := s.begin(s.ctx, userID, spotifysync.TriggerScheduled)
+		if err != nil {
+			continue
+		}
+
+		s.execute(s.ctx, run)
+	}
+}
+
+// syncedSince reports whether a run for the user was started after since,
+// on any replica. A user whose runs can't be read is skipped as well.
+func (s *spotifySyncService) syncedSince(userID uint, since time.Time) bool {
+	latest, err := s.syncRepo.Latest(s.ctx, userID)
+	if err == gorm.ErrRecordNotFound {
+		return false
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("service: error get latest sync run")
+		return true
+	}
+
+	return latest.CreatedAt.After(since)
+}
+
+// begin records the run, the database lets one run per user be running
+// across all replicas. ErrSyncInProgress when the user is being synced
+// already.
+func (s *spotifySyncService) begin(ctx context.Context, userID uint, trigger string) (*spotifysync.SpotifySyncRun, error) {
+	run := spotifysync.SpotifySyncRun{
+		UserID:  userID,
+		Trigger: trigger,
+		Status:  spotifysync.StatusRunning,
+	}
+
+	err := s.syncRepo.Create(ctx, &run)
+	if err != nil {
+		if errors.Is(err, syncRepo.ErrRunInProgress) {
+			return nil, ErrSyncInProgress
+		}
+
+		log.Error().Err(err).Msg("service: error create sync run")
+		return nil, err
+	}
+
+	return &run, nil
+}
+
+func (s *spotifySyncService) execute(ctx context.Context, run *spotifysync.SpotifySyncRun) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	s.runs.Add(1)
+	go func() {
+		defer s.runs.Done()
+
+		s.heartbeat(ctx, cancel, run.ID)
+	}()
+
+	err := s.process(ctx, run)
+	s.finish(ctx, run, err)
+}
+
+// heartbeat beats for a run until ctx ends. The run is cancelled when it was
+// failed meanwhile, another replica took it for lost.
+func (s *spotifySyncService) heartbeat(ctx context.Context, cancel context.CancelFunc, runID uint) {
+	ticker := time.NewTicker(heartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			err := s.syncRepo.Heartbeat(ctx, runID)
+			if errors.Is(err, syncRepo.ErrRunNotRunning) {
+				log.Warn().Uint("runID", runID).Msg("service: sync run was failed meanwhile, stopping it")
+				cancel()
+				return
+			}
+			if err != nil {
+				log.Error().Err(err).Uint("runID", runID).Msg("service: error beat for sync run")
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// process brings both sides to the outcome of planSync. Spotify is changed
+// first, the counters only count what was applied.
+func (s *spotifySyncService) process(ctx context.Context, run *spotifysync.SpotifySyncRun) error {
+	var since time.Time
+
+	last, err := s.syncRepo.LastCompleted(ctx, run.UserID)
+	if err != nil && err != gorm.ErrRecordNotFound {
+		log.Error().Err(err).Msg("service: error get last sync run")
+		return err
+	}
+	if err == nil && last.SnapshotAt != nil {
+		since = *last.SnapshotAt
+	}
+
+	// taken before either side is read: a change made while the run goes on
+	// is newer than the snapshot and left to the next run, instead of being
+	// mistaken for one the run already saw
+	snapshot := s.now()
+	run.SnapshotAt = &snapshot
+
+	userCtx := spotifyRepo.WithUserToken(ctx, run.UserID)
+
+	saved, err := s.savedTracks(userCtx)
+	if err != nil {
+		return err
+	}
+
+	activities, err := s.spotifyRepo.ListAllActivities(ctx, run.UserID)
+	if err != nil {
+		log.Error().Err(err).Msg("service: error list track activities")
+		return err
+	}
+
+	plan := planSync(saved, activities, since)
+
+	run.RemoteTracks = plan.remoteTracks
+	run.LocalTracks = plan.localTracks
+
+	if len(plan.saveRemote) > 0 {
+		err = s.libraryOutbond.SaveTracks(userCtx, plan.saveRemote)
+		if err != nil {
+			return err
+		}
+		run.SavedRemote = len(plan.saveRemote)
+	}
+
+	if len(plan.removeRemote) > 0 {
+		err = s.libraryOutbond.RemoveSavedTracks(userCtx, plan.removeRemote)
+		if err != nil {
+			return err
+		}
+		run.RemovedRemote = len(plan.removeRemote)
+	}
+
+	local := make(map[string]spotify.TrackActivity, len(activities))
+	for _, activity := range activities {
+		local[activity.SpotifyID] = activity
+	}
+
+	run.LikedLocal, err = s.setLiked(ctx, run.UserID, local, plan.likeLocal, true, snapshot)
+	if err != nil {
+		return err
+	}
+
+	run.UnlikedLocal, err = s.setLiked(ctx, run.UserID, local, plan.unlikeLocal, false, snapshot)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// savedTracks pages through all of the user's saved tracks.
+func (s *spotifySyncService) savedTracks(ctx context.Context) ([]spotifyRepo.SpotifySavedTrackObject, error) {
+	var tracks []spotifyRepo.SpotifySavedTrackObject
+
+	for offset := 0; ; offset += savedTracksPageSize {
+		page, err := s.libraryOutbond.GetSavedTracks(ctx, savedTracksPageSize, offset)
+		if err != nil {
+			return nil, err
+		}
+
+		tracks = append(tracks, page.Items...)
+
+		if page.Next == nil || len(page.Items) == 0 {
+			return tracks, nil
+		}
+	}
+}
+
+// setLiked returns how many of the tracks it changed before an error. The
+// changes are dated at the snapshot, they take over spotify's state as it
+// was then and aren't changes of the user's the next run has to push.
+func (s *spotifySyncService) setLiked(ctx context.Context, userID uint, local map[string]spotify.TrackActivity, spotifyIDs []string, liked bool, at time.Time) (int, error) {
+	for idx, spotifyID := range spotifyIDs {
+		var err error
+
+		activity, ok := local[spotifyID]
+		if !ok {
+			activity = spotify.TrackActivity{
+				UserID:     userID,
+				SpotifyID:  spotifyID,
+				EntityType: spotify.EntityTypeTrack,
+			}
+			activity.SetLiked(&liked, at)
+			err = s.spotifyRepo.Create(ctx, activity)
+		} else {
+			activity.SetLiked(&liked, at)
+			err = s.spotifyRepo.Update(ctx, activity)
+		}
+		if err != nil {
+			log.Error().Err(err).Msg("service: error apply synced like")
+			return idx, err
+		}
+	}
+
+	return len(spotifyIDs), nil
+}
+
+// finish stores the outcome even when ctx was cancelled.
+func (s *spotifySyncService) finish(ctx context.Context, run *spotifysync.SpotifySyncRun, err error) {
+	now := s.now()
+	run.FinishedAt = &now
+	run.Status = spotifysync.StatusCompleted
+
+	if err != nil {
+		log.Error().Err(err).Uint("runID", run.ID).Msg("service: spotify sync failed")
+
+		run.Status = spotifysync.StatusFailed
+		run.Error = apperror.From(err).Message
+		if errors.Is(err, context.Canceled) {
+			run.Error = interruptedMessage
+		}
+	}
+
+	err = s.syncRepo.Update(context.WithoutCancel(ctx), run)
+	if errors.Is(err, syncRepo.ErrRunNotRunning) {
+		log.Warn().Uint("runID", run.ID).Msg("service: sync run was failed meanwhile")
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Uint("runID", run.ID).Msg("service: error finish sync run")
+	}
+}
+
+// syncPlan lists the changes that make both sides agree.
+type syncPlan struct {
+	likeLocal    []string
+	unlikeLocal  []string
+	saveRemote   []string
+	removeRemote []string
+
+	// liked tracks on each side before the changes
+	remoteTracks int
+	localTracks  int
+}
+
+// planSync decides every track liked on one side only by last writer wins.
+// A local like or unlike is dated by the activity's liked_changed_at, a
+// saved track by when it was saved. Spotify doesn't date removals: a track
+// that is missing there was removed at the earliest right after the last
+// sync read the library, so a local change since then is the later one.
+// An unlike and a cleared like both count as a local removal, tracks whose
+// like was never set have no say. Only tracks are planned, spotify's saved tracks can't
+// hold albums.
+func planSync(saved []spotifyRepo.SpotifySavedTrackObject, activities []spotify.TrackActivity, since time.Time) syncPlan {
+	var plan syncPlan
+
+	tracks := make([]spotify.TrackActivity, 0, len(activities))
+	for _, activity := range activities {
+		if activity.EntityType == spotify.EntityTypeTrack {
+			tracks = append(tracks, activity)
+		}
+	}
+
+	local := make(map[string]spotify.TrackActivity, len(tracks))
+	for _, activity := range tracks {
+		local[activity.SpotifyID] = activity
+	}
+
+	remote := make(map[string]bool, len(saved))
+	for _, item := range saved {
+		spotifyID := item.Track.ID
+		if spotifyID == "" || remote[spotifyID] {
+			continue
+		}
+		remote[spotifyID] = true
+
+		activity, ok := local[spotifyID]
+		switch {
+		case ok && isLiked(activity):
+		// unliked or cleared here after it was saved there
+		case ok && likedChangedAt(activity).After(item.AddedAt):
+			plan.removeRemote = append(plan.removeRemote, spotifyID)
+		default:
+			plan.likeLocal = append(plan.likeLocal, spotifyID)
+		}
+	}
+
+	for _, activity := range tracks {
+		if !isLiked(activity) {
+			continue
+		}
+		plan.localTracks++
+
+		if remote[activity.SpotifyID] {
+			continue
+		}
+
+		if likedChangedAt(activity).After(since) {
+			plan.saveRemote = append(plan.saveRemote, activity.SpotifyID)
+		} else {
+			plan.unlikeLocal = append(plan.unlikeLocal, activity.SpotifyID)
+		}
+	}
+
+	plan.remoteTracks = len(remote)
+
+	return plan
+}
+
+func isLiked(activity spotify.TrackActivity) bool {
+	return activity.IsLiked != nil && *activity.IsLiked
+}
+
+// likedChangedAt is the zero time for an activity that was never liked or
+// unliked.
+func likedChangedAt(activity spotify.TrackActivity) time.Time {
+	if activity.LikedChangedAt == nil {
+		return time.Time{}
+	}
+
+	return *activity.LikedChangedAt
+}
+
+func toResponse(run spotifysync.SpotifySyncRun) spotifysync.SyncRunResponse {
+	return spotifysync.SyncRunResponse{
+		ID:            run.ID,
+		Trigger:       run.Trigger,
+		Status:        run.Status,
+		RemoteTracks:  run.RemoteTracks,
+		LocalTracks:   run.LocalTracks,
+		LikedLocal:    run.LikedLocal,
+		UnlikedLocal:  run.UnlikedLocal,
+		SavedRemote:   run.SavedRemote,
+		RemovedRemote: run.RemovedRemote,
+		Error:         run.Error,
+		CreatedAt:     run.CreatedAt,
+		FinishedAt:    run.FinishedAt,
+	}
+}
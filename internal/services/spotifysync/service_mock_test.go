@@ -0,0 +1,161 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: repository.go
+//
+// Generated by this command:
+//
+//	mockgen -source=repository.go -destination=../../services/spotifysync/service_mock_test.go -package=spotifysync
+//
+
+// Package spotifysync is a generated GoMock package.
+package spotifysync
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	spotifysync "github.com/sgitwhyd/music-catalogue/internal/models/spotifysync"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockSpotifySyncRepository is a mock of SpotifySyncRepository interface.
+type MockSpotifySyncRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockSpotifySyncRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockSpotifySyncRepositoryMockRecorder is the mock recorder for MockSpotifySyncRepository.
+type MockSpotifySyncRepositoryMockRecorder struct {
+	mock *MockSpotifySyncRepository
+}
+
+// NewMockSpotifySyncRepository creates a new mock instance.
+func NewMockSpotifySyncRepository(ctrl *gomock.Controller) *MockSpotifySyncRepository {
+	mock := &MockSpotifySyncRepository{ctrl: ctrl}
+	mock.recorder = &MockSpotifySyncRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSpotifySyncRepository) EXPECT() *MockSpotifySyncRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockSpotifySyncRepository) Create(ctx context.Context, model *spotifysync.SpotifySyncRun) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, model)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockSpotifySyncRepositoryMockRecorder) Create(ctx, model any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockSpotifySyncRepository)(nil).Create), ctx, model)
+}
+
+// FailUnfinished mocks base method.
+func (m *MockSpotifySyncRepository) FailUnfinished(ctx context.Context, message string, staleBefore time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FailUnfinished", ctx, message, staleBefore)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FailUnfinished indicates an expected call of FailUnfinished.
+func (mr *MockSpotifySyncRepositoryMockRecorder) FailUnfinished(ctx, message, staleBefore any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FailUnfinished", reflect.TypeOf((*MockSpotifySyncRepository)(nil).FailUnfinished), ctx, message, staleBefore)
+}
+
+// Get mocks base method.
+func (m *MockSpotifySyncRepository) Get(ctx context.Context, id uint) (*spotifysync.SpotifySyncRun, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, id)
+	ret0, _ := ret[0].(*spotifysync.SpotifySyncRun)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockSpotifySyncRepositoryMockRecorder) Get(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockSpotifySyncRepository)(nil).Get), ctx, id)
+}
+
+// Heartbeat mocks base method.
+func (m *MockSpotifySyncRepository) Heartbeat(ctx context.Context, id uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Heartbeat", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Heartbeat indicates an expected call of Heartbeat.
+func (mr *MockSpotifySyncRepositoryMockRecorder) Heartbeat(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Heartbeat", reflect.TypeOf((*MockSpotifySyncRepository)(nil).Heartbeat), ctx, id)
+}
+
+// LastCompleted mocks base method.
+func (m *MockSpotifySyncRepository) LastCompleted(ctx context.Context, UserID uint) (*spotifysync.SpotifySyncRun, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LastCompleted", ctx, UserID)
+	ret0, _ := ret[0].(*spotifysync.SpotifySyncRun)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LastCompleted indicates an expected call of LastCompleted.
+func (mr *MockSpotifySyncRepositoryMockRecorder) LastCompleted(ctx, UserID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LastCompleted", reflect.TypeOf((*MockSpotifySyncRepository)(nil).LastCompleted), ctx, UserID)
+}
+
+// Latest mocks base method.
+func (m *MockSpotifySyncRepository) Latest(ctx context.Context, UserID uint) (*spotifysync.SpotifySyncRun, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Latest", ctx, UserID)
+	ret0, _ := ret[0].(*spotifysync.SpotifySyncRun)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Latest indicates an expected call of Latest.
+func (mr *MockSpotifySyncRepositoryMockRecorder) Latest(ctx, UserID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Latest", reflect.TypeOf((*MockSpotifySyncRepository)(nil).Latest), ctx, UserID)
+}
+
+// List mocks base method.
+func (m *MockSpotifySyncRepository) List(ctx context.Context, UserID uint, limit, offset int) ([]spotifysync.SpotifySyncRun, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, UserID, limit, offset)
+	ret0, _ := ret[0].([]spotifysync.SpotifySyncRun)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// List indicates an expected call of List.
+func (mr *MockSpotifySyncRepositoryMockRecorder) List(ctx, UserID, limit, offset any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockSpotifySyncRepository)(nil).List), ctx, UserID, limit, offset)
+}
+
+// Update mocks base method.
+func (m *MockSpotifySyncRepository) Update(ctx context.Context, model *spotifysync.SpotifySyncRun) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, model)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockSpotifySyncRepositoryMockRecorder) Update(ctx, model any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockSpotifySyncRepository)(nil).Update), ctx, model)
+}
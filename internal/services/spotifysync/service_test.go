@@ -0,0 +1,325 @@
+package spotifysync
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sgitwhyd/music-catalogue/internal/models/spotify"
+	"github.com/sgitwhyd/music-catalogue/internal/models/spotifyaccount"
+	"github.com/sgitwhyd/music-catalogue/internal/models/spotifysync"
+	spotifyRepo "github.com/sgitwhyd/music-catalogue/internal/repositorys/spotify"
+	syncRepo "github.com/sgitwhyd/music-catalogue/internal/repositorys/spotifysync"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+var (
+	lastSync = time.Date(2026, 1, 2, 12, 0, 0, 0, time.UTC)
+	liked    = true
+	unliked  = false
+)
+
+func savedTrack(spotifyID string, addedAt time.Time) spotifyRepo.SpotifySavedTrackObject {
+	return spotifyRepo.SpotifySavedTrackObject{
+		AddedAt: addedAt,
+		Track:   spotifyRepo.SpotifyTrackObject{ID: spotifyID},
+	}
+}
+
+func activity(spotifyID string, isLiked *bool, likedChangedAt time.Time) spotify.TrackActivity {
+	activity := spotify.TrackActivity{
+		UserID:     1,
+		SpotifyID:  spotifyID,
+		EntityType: spotify.EntityTypeTrack,
+		IsLiked:    isLiked,
+	}
+	if isLiked != nil {
+		activity.LikedChangedAt = &likedChangedAt
+	}
+	activity.UpdatedAt = likedChangedAt
+
+	return activity
+}
+
+func Test_planSync(t *testing.T) {
+	before := lastSync.Add(-time.Hour)
+	after := lastSync.Add(time.Hour)
+
+	tests := []struct {
+		name       string
+		saved      []spotifyRepo.SpotifySavedTrackObject
+		activities []spotify.TrackActivity
+		since      time.Time
+		want       syncPlan
+	}{
+		{
+			name:       "liked on both sides",
+			saved:      []spotifyRepo.SpotifySavedTrackObject{savedTrack("a", before)},
+			activities: []spotify.TrackActivity{activity("a", &liked, after)},
+			since:      lastSync,
+			want:       syncPlan{remoteTracks: 1, localTracks: 1},
+		},
+		{
+			name:  "saved on spotify only",
+			saved: []spotifyRepo.SpotifySavedTrackObject{savedTrack("a", after), savedTrack("b", before)},
+			// a rating without a like state has no say
+			activities: []spotify.TrackActivity{activity("b", nil, after)},
+			since:      lastSync,
+			want:       syncPlan{likeLocal: []string{"a", "b"}, remoteTracks: 2},
+		},
+		{
+			name:       "unliked here after it was saved on spotify",
+			saved:      []spotifyRepo.SpotifySavedTrackObject{savedTrack("a", before)},
+			activities: []spotify.TrackActivity{activity("a", &unliked, after)},
+			since:      lastSync,
+			want:       syncPlan{removeRemote: []string{"a"}, remoteTracks: 1},
+		},
+		{
+			name:  "like cleared here after it was saved on spotify",
+			saved: []spotifyRepo.SpotifySavedTrackObject{savedTrack("a", before)},
+			activities: []spotify.TrackActivity{func() spotify.TrackActivity {
+				cleared := activity("a", nil, after)
+				cleared.LikedChangedAt = &after
+				return cleared
+			}()},
+			since: lastSync,
+			want:  syncPlan{removeRemote: []string{"a"}, remoteTracks: 1},
+		},
+		{
+			name:  "saved on spotify after the like was cleared here",
+			saved: []spotifyRepo.SpotifySavedTrackObject{savedTrack("a", after)},
+			activities: []spotify.TrackActivity{func() spotify.TrackActivity {
+				cleared := activity("a", nil, before)
+				cleared.LikedChangedAt = &before
+				return cleared
+			}()},
+			since: lastSync,
+			want:  syncPlan{likeLocal: []string{"a"}, remoteTracks: 1},
+		},
+		{
+			name:       "saved on spotify after it was unliked here",
+			saved:      []spotifyRepo.SpotifySavedTrackObject{savedTrack("a", after)},
+			activities: []spotify.TrackActivity{activity("a", &unliked, before)},
+			since:      lastSync,
+			want:       syncPlan{likeLocal: []string{"a"}, remoteTracks: 1},
+		},
+		{
+			name:       "liked here since the last sync",
+			activities: []spotify.TrackActivity{activity("a", &liked, after)},
+			since:      lastSync,
+			want:       syncPlan{saveRemote: []string{"a"}, localTracks: 1},
+		},
+		{
+			name:       "removed on spotify since the last sync",
+			activities: []spotify.TrackActivity{activity("a", &liked, before)},
+			since:      lastSync,
+			want:       syncPlan{unlikeLocal: []string{"a"}, localTracks: 1},
+		},
+		{
+			name: "dated by the like, not by later edits",
+			activities: []spotify.TrackActivity{func() spotify.TrackActivity {
+				rated := activity("a", &liked, before)
+				rated.UpdatedAt = after
+				return rated
+			}()},
+			since: lastSync,
+			want:  syncPlan{unlikeLocal: []string{"a"}, localTracks: 1},
+		},
+		{
+			name: "albums are left out",
+			activities: []spotify.TrackActivity{func() spotify.TrackActivity {
+				album := activity("album", &liked, after)
+				album.EntityType = spotify.EntityTypeAlbum
+				return album
+			}()},
+			since: lastSync,
+			want:  syncPlan{},
+		},
+		{
+			name:       "first sync merges both sides",
+			saved:      []spotifyRepo.SpotifySavedTrackObject{savedTrack("a", before)},
+			activities: []spotify.TrackActivity{activity("b", &liked, before)},
+			want:       syncPlan{likeLocal: []string{"a"}, saveRemote: []string{"b"}, remoteTracks: 1, localTracks: 1},
+		},
+		{
+			name:  "local files and duplicates are skipped",
+			saved: []spotifyRepo.SpotifySavedTrackObject{savedTrack("", after), savedTrack("a", after), savedTrack("a", before)},
+			want:  syncPlan{likeLocal: []string{"a"}, remoteTracks: 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, planSync(tt.saved, tt.activities, tt.since))
+		})
+	}
+}
+
+func Test_spotifySyncService_process(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockSyncRepo := NewMockSpotifySyncRepository(mockCtrl)
+	mockAccountRepo := NewMockSpotifyAccountRepository(mockCtrl)
+	mockLibraryOutbond := NewMockSpotifyLibraryOutbond(mockCtrl)
+	mockSpotifyRepo := NewMockSpotifyRepository(mockCtrl)
+
+	next := "https://api.spotify.com/v1/me/tracks?offset=50&limit=50"
+	// "liked" was liked while the last run was going, after it read the
+	// libraries but before it finished
+	snapshotAt := lastSync
+	finishedAt := lastSync.Add(5 * time.Minute)
+	now := lastSync.Add(2 * time.Hour)
+
+	mockSyncRepo.EXPECT().LastCompleted(gomock.Any(), uint(1)).
+		Return(&spotifysync.SpotifySyncRun{ID: 3, UserID: 1, Status: spotifysync.StatusCompleted, SnapshotAt: &snapshotAt, FinishedAt: &finishedAt}, nil)
+	mockLibraryOutbond.EXPECT().GetSavedTracks(gomock.Any(), 50, 0).
+		Return(&spotifyRepo.SpotifySavedTracks{
+			Items: []spotifyRepo.SpotifySavedTrackObject{savedTrack("saved", lastSync.Add(time.Minute))},
+			Next:  &next,
+		}, nil)
+	mockLibraryOutbond.EXPECT().GetSavedTracks(gomock.Any(), 50, 50).
+		Return(&spotifyRepo.SpotifySavedTracks{
+			Items: []spotifyRepo.SpotifySavedTrackObject{savedTrack("unliked", lastSync.Add(-time.Hour))},
+		}, nil)
+	mockSpotifyRepo.EXPECT().ListAllActivities(gomock.Any(), uint(1)).
+		Return([]spotify.TrackActivity{
+			activity("unliked", &unliked, lastSync.Add(time.Minute)),
+			activity("liked", &liked, lastSync.Add(time.Minute)),
+			activity("removed", &liked, lastSync.Add(-time.Hour)),
+		}, nil)
+	mockLibraryOutbond.EXPECT().SaveTracks(gomock.Any(), []string{"liked"}).Return(nil)
+	mockLibraryOutbond.EXPECT().RemoveSavedTracks(gomock.Any(), []string{"unliked"}).Return(nil)
+	mockSpotifyRepo.EXPECT().Create(gomock.Any(), spotify.TrackActivity{UserID: 1, SpotifyID: "saved", EntityType: spotify.EntityTypeTrack, IsLiked: &liked, LikedChangedAt: &now}).Return(nil)
+	mockSpotifyRepo.EXPECT().Update(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, model spotify.TrackActivity) error {
+			assert.Equal(t, "removed", model.SpotifyID)
+			assert.False(t, *model.IsLiked)
+			assert.Equal(t, now, *model.LikedChangedAt)
+			return nil
+		})
+
+	s := NewSpotifySyncService(mockSyncRepo, mockAccountRepo, mockLibraryOutbond, mockSpotifyRepo)
+	s.now = func() time.Time { return now }
+
+	run := &spotifysync.SpotifySyncRun{ID: 4, UserID: 1, Trigger: spotifysync.TriggerManual, Status: spotifysync.StatusRunning}
+	err := s.process(context.Background(), run)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, run.RemoteTracks)
+	assert.Equal(t, 2, run.LocalTracks)
+	assert.Equal(t, 1, run.LikedLocal)
+	assert.Equal(t, 1, run.UnlikedLocal)
+	assert.Equal(t, 1, run.SavedRemote)
+	assert.Equal(t, 1, run.RemovedRemote)
+	assert.Equal(t, now, *run.SnapshotAt)
+}
+
+func Test_spotifySyncService_Sync(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockSyncRepo := NewMockSpotifySyncRepository(mockCtrl)
+	mockAccountRepo := NewMockSpotifyAccountRepository(mockCtrl)
+	mockLibraryOutbond := NewMockSpotifyLibraryOutbond(mockCtrl)
+	mockSpotifyRepo := NewMockSpotifyRepository(mockCtrl)
+
+	tests := []struct {
+		name    string
+		want    *spotifysync.SyncRunResponse
+		wantErr error
+		mockFn  func()
+	}{
+		{
+			name: "success",
+			want: &spotifysync.SyncRunResponse{ID: 4, Trigger: spotifysync.TriggerManual, Status: spotifysync.StatusRunning},
+			mockFn: func() {
+				mockAccountRepo.EXPECT().Get(gomock.Any(), uint(1)).Return(&spotifyaccount.SpotifyAccount{UserID: 1}, nil)
+				mockSyncRepo.EXPECT().Create(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, model *spotifysync.SpotifySyncRun) error {
+						model.ID = 4
+						return nil
+					})
+
+				// the background part, spotify is down
+				mockSyncRepo.EXPECT().LastCompleted(gomock.Any(), uint(1)).Return(nil, gorm.ErrRecordNotFound)
+				mockLibraryOutbond.EXPECT().GetSavedTracks(gomock.Any(), 50, 0).Return(nil, assert.AnError)
+				mockSyncRepo.EXPECT().Update(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, model *spotifysync.SpotifySyncRun) error {
+						assert.Equal(t, spotifysync.StatusFailed, model.Status)
+						assert.NotEmpty(t, model.Error)
+						assert.NotNil(t, model.FinishedAt)
+						return nil
+					})
+			},
+		},
+		{
+			name:    "not connected",
+			wantErr: ErrSpotifyNotConnected,
+			mockFn: func() {
+				mockAccountRepo.EXPECT().Get(gomock.Any(), uint(1)).Return(nil, gorm.ErrRecordNotFound)
+			},
+		},
+		{
+			name:    "already syncing",
+			wantErr: ErrSyncInProgress,
+			mockFn: func() {
+				mockAccountRepo.EXPECT().Get(gomock.Any(), uint(1)).Return(&spotifyaccount.SpotifyAccount{UserID: 1}, nil)
+				mockSyncRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(syncRepo.ErrRunInProgress)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFn()
+
+			s := NewSpotifySyncService(mockSyncRepo, mockAccountRepo, mockLibraryOutbond, mockSpotifyRepo)
+
+			got, err := s.Sync(context.Background(), 1)
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.want, got)
+
+			assert.NoError(t, s.Shutdown(context.Background()))
+		})
+	}
+}
+
+func Test_spotifySyncService_syncAll(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockSyncRepo := NewMockSpotifySyncRepository(mockCtrl)
+	mockAccountRepo := NewMockSpotifyAccountRepository(mockCtrl)
+	mockLibraryOutbond := NewMockSpotifyLibraryOutbond(mockCtrl)
+	mockSpotifyRepo := NewMockSpotifyRepository(mockCtrl)
+
+	now := lastSync.Add(time.Hour)
+
+	mockAccountRepo.EXPECT().ListUserIDs(gomock.Any()).Return([]uint{1, 2}, nil)
+	// another replica synced user 1 this round, user 2 is being synced by one
+	mockSyncRepo.EXPECT().Latest(gomock.Any(), uint(1)).
+		Return(&spotifysync.SpotifySyncRun{ID: 3, UserID: 1, CreatedAt: now.Add(-10 * time.Minute)}, nil)
+	mockSyncRepo.EXPECT().Latest(gomock.Any(), uint(2)).
+		Return(&spotifysync.SpotifySyncRun{ID: 2, UserID: 2, CreatedAt: now.Add(-time.Hour)}, nil)
+	mockSyncRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(syncRepo.ErrRunInProgress)
+
+	s := NewSpotifySyncService(mockSyncRepo, mockAccountRepo, mockLibraryOutbond, mockSpotifyRepo)
+	s.now = func() time.Time { return now }
+
+	s.syncAll(time.Hour)
+}
+
+func Test_spotifySyncService_GetRun(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockSyncRepo := NewMockSpotifySyncRepository(mockCtrl)
+
+	s := NewSpotifySyncService(mockSyncRepo, nil, nil, nil)
+
+	mockSyncRepo.EXPECT().Get(gomock.Any(), uint(4)).
+		Return(&spotifysync.SpotifySyncRun{ID: 4, UserID: 2, Status: spotifysync.StatusCompleted}, nil)
+
+	_, err := s.GetRun(context.Background(), 1, 4)
+	assert.ErrorIs(t, err, ErrSyncRunNotFound)
+}
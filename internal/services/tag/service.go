@@ -0,0 +1,229 @@
+package tag
+
+import (
+	"context"
+	"errors"
+
+	"github.com/rs/zerolog/log"
+	"github.com/sgitwhyd/music-catalogue/internal/apperror"
+	"github.com/sgitwhyd/music-catalogue/internal/models/tag"
+	tagRepo "github.com/sgitwhyd/music-catalogue/internal/repositorys/tag"
+	"github.com/sgitwhyd/music-catalogue/pkg/uniq"
+	"gorm.io/gorm"
+)
+
+//go:generate mockgen -source=service.go -destination=../../handlers/tag/handler_mock_test.go -package=tag
+type TagService interface {
+	Create(ctx context.Context, userID uint, request tag.CreateTagRequest) (*tag.TagResponse, error)
+	List(ctx context.Context, userID uint) (*tag.ListTagResponse, error)
+	Update(ctx context.Context, userID, tagID uint, request tag.UpdateTagRequest) (*tag.TagResponse, error)
+	Delete(ctx context.Context, userID, tagID uint) error
+	TagTracks(ctx context.Context, userID, tagID uint, request tag.TagTracksRequest) error
+	UntagTrack(ctx context.Context, userID, tagID uint, spotifyID string) error
+}
+
+var (
+	ErrTagNotFound    = apperror.New(apperror.KindNotFound, "tag_not_found", "tag not found")
+	ErrTagExists      = apperror.New(apperror.KindConflict, "tag_exists", "a tag with this name already exists")
+	ErrInvalidTagName = apperror.New(apperror.KindInvalid, "invalid_tag_name", "tag name can't be blank")
+	ErrTrackNotTagged = apperror.New(apperror.KindNotFound, "track_not_tagged", "track doesn't carry the tag")
+)
+
+type tagService struct {
+	tagRepo tagRepo.TagRepository
+}
+
+func NewTagService(tagRepo tagRepo.TagRepository) *tagService {
+	return &tagService{
+		tagRepo: tagRepo,
+	}
+}
+
+func (s *tagService) Create(ctx context.Context, userID uint, request tag.CreateTagRequest) (*tag.TagResponse, error) {
+	name, err := s.availableName(ctx, userID, request.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	model := tag.Tag{
+		UserID: userID,
+		Name:   name,
+	}
+
+	err = s.tagRepo.Create(ctx, &model)
+	if err != nil {
+		if errors.Is(err, tagRepo.ErrNameTaken) {
+			return nil, ErrTagExists
+		}
+
+		log.Error().Err(err).Msg("service: error create tag")
+		return nil, err
+	}
+
+	response := toResponse(model, 0)
+	return &response, nil
+}
+
+func (s *tagService) List(ctx context.Context, userID uint) (*tag.ListTagResponse, error) {
+	tags, err := s.tagRepo.List(ctx, userID)
+	if err != nil {
+		log.Error().Err(err).Msg("service: error list tags")
+		return nil, err
+	}
+
+	ids := make([]uint, len(tags))
+	for idx, model := range tags {
+		ids[idx] = model.ID
+	}
+
+	counts, err := s.tagRepo.CountTracks(ctx, ids)
+	if err != nil {
+		log.Error().Err(err).Msg("service: error count tag tracks")
+		return nil, err
+	}
+
+	items := make([]tag.TagResponse, len(tags))
+	for idx, model := range tags {
+		items[idx] = toResponse(model, counts[model.ID])
+	}
+
+	return &tag.ListTagResponse{
+		Items: items,
+	}, nil
+}
+
+func (s *tagService) Update(ctx context.Context, userID, tagID uint, request tag.UpdateTagRequest) (*tag.TagResponse, error) {
+	model, err := s.getOwned(ctx, userID, tagID)
+	if err != nil {
+		return nil, err
+	}
+
+	if tag.NormalizeName(request.Name) != model.Name {
+		model.Name, err = s.availableName(ctx, userID, request.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		err = s.tagRepo.Update(ctx, model)
+		if err != nil {
+			if errors.Is(err, tagRepo.ErrNameTaken) {
+				return nil, ErrTagExists
+			}
+
+			log.Error().Err(err).Msg("service: error update tag")
+			return nil, err
+		}
+	}
+
+	counts, err := s.tagRepo.CountTracks(ctx, []uint{model.ID})
+	if err != nil {
+		log.Error().Err(err).Msg("service: error count tag tracks")
+		return nil, err
+	}
+
+	response := toResponse(*model, counts[model.ID])
+	return &response, nil
+}
+
+func (s *tagService) Delete(ctx context.Context, userID, tagID uint) error {
+	_, err := s.getOwned(ctx, userID, tagID)
+	if err != nil {
+		return err
+	}
+
+	err = s.tagRepo.Delete(ctx, tagID)
+	if err != nil {
+		log.Error().Err(err).Msg("service: error delete tag")
+		return err
+	}
+
+	return nil
+}
+
+func (s *tagService) TagTracks(ctx context.Context, userID, tagID uint, request tag.TagTracksRequest) error {
+	_, err := s.getOwned(ctx, userID, tagID)
+	if err != nil {
+		return err
+	}
+
+	err = s.tagRepo.TagTracks(ctx, tagID, uniq.Values(request.SpotifyIDs))
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return ErrTagNotFound
+		}
+
+		log.Error().Err(err).Msg("service: error tag tracks")
+		return err
+	}
+
+	return nil
+}
+
+func (s *tagService) UntagTrack(ctx context.Context, userID, tagID uint, spotifyID string) error {
+	_, err := s.getOwned(ctx, userID, tagID)
+	if err != nil {
+		return err
+	}
+
+	err = s.tagRepo.UntagTrack(ctx, tagID, spotifyID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return ErrTrackNotTagged
+		}
+
+		log.Error().Err(err).Msg("service: error untag track")
+		return err
+	}
+
+	return nil
+}
+
+// availableName normalizes name and checks the user has no tag by that name
+// yet.
+func (s *tagService) availableName(ctx context.Context, userID uint, name string) (string, error) {
+	name = tag.NormalizeName(name)
+	if name == "" {
+		return "", ErrInvalidTagName
+	}
+
+	_, err := s.tagRepo.FindByName(ctx, userID, name)
+	if err == nil {
+		return "", ErrTagExists
+	}
+
+	if err != gorm.ErrRecordNotFound {
+		log.Error().Err(err).Msg("service: error find tag by name")
+		return "", err
+	}
+
+	return name, nil
+}
+
+// getOwned reports tags of other users as not found, tags are never shared.
+func (s *tagService) getOwned(ctx context.Context, userID, tagID uint) (*tag.Tag, error) {
+	model, err := s.tagRepo.Get(ctx, tagID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrTagNotFound
+		}
+
+		log.Error().Err(err).Msg("service: error get tag")
+		return nil, err
+	}
+
+	if model.UserID != userID {
+		return nil, ErrTagNotFound
+	}
+
+	return model, nil
+}
+
+func toResponse(model tag.Tag, trackCount int) tag.TagResponse {
+	return tag.TagResponse{
+		ID:         model.ID,
+		Name:       model.Name,
+		TrackCount: trackCount,
+		CreatedAt:  model.CreatedAt,
+		UpdatedAt:  model.UpdatedAt,
+	}
+}
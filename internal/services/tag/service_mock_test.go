@@ -0,0 +1,172 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: repository.go
+//
+// Generated by this command:
+//
+//	mockgen -source=repository.go -destination=../../services/tag/service_mock_test.go -package=tag
+//
+
+// Package tag is a generated GoMock package.
+package tag
+
+import (
+	context "context"
+	reflect "reflect"
+
+	tag "github.com/sgitwhyd/music-catalogue/internal/models/tag"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockTagRepository is a mock of TagRepository interface.
+type MockTagRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockTagRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockTagRepositoryMockRecorder is the mock recorder for MockTagRepository.
+type MockTagRepositoryMockRecorder struct {
+	mock *MockTagRepository
+}
+
+// NewMockTagRepository creates a new mock instance.
+func NewMockTagRepository(ctrl *gomock.Controller) *MockTagRepository {
+	mock := &MockTagRepository{ctrl: ctrl}
+	mock.recorder = &MockTagRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTagRepository) EXPECT() *MockTagRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CountTracks mocks base method.
+func (m *MockTagRepository) CountTracks(ctx context.Context, tagIDs []uint) (map[uint]int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountTracks", ctx, tagIDs)
+	ret0, _ := ret[0].(map[uint]int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountTracks indicates an expected call of CountTracks.
+func (mr *MockTagRepositoryMockRecorder) CountTracks(ctx, tagIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountTracks", reflect.TypeOf((*MockTagRepository)(nil).CountTracks), ctx, tagIDs)
+}
+
+// Create mocks base method.
+func (m *MockTagRepository) Create(ctx context.Context, model *tag.Tag) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, model)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockTagRepositoryMockRecorder) Create(ctx, model any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockTagRepository)(nil).Create), ctx, model)
+}
+
+// Delete mocks base method.
+func (m *MockTagRepository) Delete(ctx context.Context, id uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockTagRepositoryMockRecorder) Delete(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockTagRepository)(nil).Delete), ctx, id)
+}
+
+// FindByName mocks base method.
+func (m *MockTagRepository) FindByName(ctx context.Context, UserID uint, name string) (*tag.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByName", ctx, UserID, name)
+	ret0, _ := ret[0].(*tag.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByName indicates an expected call of FindByName.
+func (mr *MockTagRepositoryMockRecorder) FindByName(ctx, UserID, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByName", reflect.TypeOf((*MockTagRepository)(nil).FindByName), ctx, UserID, name)
+}
+
+// Get mocks base method.
+func (m *MockTagRepository) Get(ctx context.Context, id uint) (*tag.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, id)
+	ret0, _ := ret[0].(*tag.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockTagRepositoryMockRecorder) Get(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockTagRepository)(nil).Get), ctx, id)
+}
+
+// List mocks base method.
+func (m *MockTagRepository) List(ctx context.Context, UserID uint) ([]tag.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, UserID)
+	ret0, _ := ret[0].([]tag.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockTagRepositoryMockRecorder) List(ctx, UserID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockTagRepository)(nil).List), ctx, UserID)
+}
+
+// TagTracks mocks base method.
+func (m *MockTagRepository) TagTracks(ctx context.Context, tagID uint, spotifyIDs []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TagTracks", ctx, tagID, spotifyIDs)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// TagTracks indicates an expected call of TagTracks.
+func (mr *MockTagRepositoryMockRecorder) TagTracks(ctx, tagID, spotifyIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TagTracks", reflect.TypeOf((*MockTagRepository)(nil).TagTracks), ctx, tagID, spotifyIDs)
+}
+
+// UntagTrack mocks base method.
+func (m *MockTagRepository) UntagTrack(ctx context.Context, tagID uint, spotifyID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UntagTrack", ctx, tagID, spotifyID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UntagTrack indicates an expected call of UntagTrack.
+func (mr *MockTagRepositoryMockRecorder) UntagTrack(ctx, tagID, spotifyID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UntagTrack", reflect.TypeOf((*MockTagRepository)(nil).UntagTrack), ctx, tagID, spotifyID)
+}
+
+// Update mocks base method.
+func (m *MockTagRepository) Update(ctx context.Context, model *tag.Tag) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, model)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockTagRepositoryMockRecorder) Update(ctx, model any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockTagRepository)(nil).Update), ctx, model)
+}
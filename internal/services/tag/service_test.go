@@ -0,0 +1,128 @@
+package tag
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sgitwhyd/music-catalogue/internal/models/tag"
+	tagRepo "github.com/sgitwhyd/music-catalogue/internal/repositorys/tag"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+func Test_tagService_Create(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockTagRepo := NewMockTagRepository(mockCtrl)
+
+	tests := []struct {
+		name    string
+		request tag.CreateTagRequest
+		mockFn  func()
+		want    *tag.TagResponse
+		wantErr error
+	}{
+		{
+			name:    "should store the normalized name",
+			request: tag.CreateTagRequest{Name: " Workout "},
+			mockFn: func() {
+				mockTagRepo.EXPECT().FindByName(gomock.Any(), uint(1), "workout").Return(nil, gorm.ErrRecordNotFound)
+				mockTagRepo.EXPECT().Create(gomock.Any(), &tag.Tag{UserID: 1, Name: "workout"}).
+					DoAndReturn(func(ctx context.Context, model *tag.Tag) error {
+						model.ID = 3
+						return nil
+					})
+			},
+			want: &tag.TagResponse{ID: 3, Name: "workout"},
+		},
+		{
+			name:    "should reject a name already taken",
+			request: tag.CreateTagRequest{Name: "WORKOUT"},
+			mockFn: func() {
+				mockTagRepo.EXPECT().FindByName(gomock.Any(), uint(1), "workout").Return(&tag.Tag{ID: 3, UserID: 1, Name: "workout"}, nil)
+			},
+			wantErr: ErrTagExists,
+		},
+		{
+			name:    "should reject a name taken by a concurrent request",
+			request: tag.CreateTagRequest{Name: "workout"},
+			mockFn: func() {
+				mockTagRepo.EXPECT().FindByName(gomock.Any(), uint(1), "workout").Return(nil, gorm.ErrRecordNotFound)
+				mockTagRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(tagRepo.ErrNameTaken)
+			},
+			wantErr: ErrTagExists,
+		},
+		{
+			name:    "should reject a blank name",
+			request: tag.CreateTagRequest{Name: "   "},
+			mockFn:  func() {},
+			wantErr: ErrInvalidTagName,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFn()
+
+			s := NewTagService(mockTagRepo)
+			got, err := s.Create(context.Background(), 1, tt.request)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_tagService_TagTracks(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockTagRepo := NewMockTagRepository(mockCtrl)
+
+	tests := []struct {
+		name    string
+		mockFn  func()
+		wantErr error
+	}{
+		{
+			name: "should tag every id once",
+			mockFn: func() {
+				mockTagRepo.EXPECT().Get(gomock.Any(), uint(3)).Return(&tag.Tag{ID: 3, UserID: 1}, nil)
+				mockTagRepo.EXPECT().TagTracks(gomock.Any(), uint(3), []string{"a", "b"}).Return(nil)
+			},
+		},
+		{
+			name: "should hide tags of other users",
+			mockFn: func() {
+				mockTagRepo.EXPECT().Get(gomock.Any(), uint(3)).Return(&tag.Tag{ID: 3, UserID: 2}, nil)
+			},
+			wantErr: ErrTagNotFound,
+		},
+		{
+			name: "should fail when the tag doesn't exist",
+			mockFn: func() {
+				mockTagRepo.EXPECT().Get(gomock.Any(), uint(3)).Return(nil, gorm.ErrRecordNotFound)
+			},
+			wantErr: ErrTagNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFn()
+
+			s := NewTagService(mockTagRepo)
+			err := s.TagTracks(context.Background(), 1, 3, tag.TagTracksRequest{SpotifyIDs: []string{"a", "b", "a"}})
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
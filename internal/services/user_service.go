@@ -2,12 +2,15 @@ package services
 
 import (
 	"errors"
+	"time"
 
 	"github.com/rs/zerolog/log"
+	"github.com/sgitwhyd/music-catalogue/internal/apperror"
 	"github.com/sgitwhyd/music-catalogue/internal/configs"
 	"github.com/sgitwhyd/music-catalogue/internal/models"
 	"github.com/sgitwhyd/music-catalogue/internal/repositorys"
 	"github.com/sgitwhyd/music-catalogue/pkg/jwt"
+	"github.com/sgitwhyd/music-catalogue/pkg/refreshtoken"
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
 )
@@ -18,19 +21,41 @@ type UserRepo interface {
 	repositorys.UserRepository
 } 
 
+type RefreshTokenRepo interface {
+	repositorys.RefreshTokenRepository
+}
+
 type UserService interface{
 	Register(request models.SignUpRequest) error
-	Login(request models.SignInRequest) (string, error)
+	Login(request models.SignInRequest) (*models.LoginResponse, error)
+	Refresh(userID uint, request models.RefreshTokenRequest) (*models.LoginResponse, error)
+	UpdateRole(adminID, userID uint, request models.UpdateRoleRequest) (*models.UserResponse, error)
 }
 
+const defaultRefreshTokenTTL = 30 * 24 * time.Hour
+
+var (
+	ErrUserAlreadyRegistered = apperror.New(apperror.KindConflict, "user_already_registered", "email or username already registered")
+	// one error for unknown email and wrong password, so login can't be used
+	// to find out which emails are registered
+	ErrInvalidCredentials  = apperror.New(apperror.KindUnauthorized, "invalid_credentials", "invalid email or password")
+	ErrInvalidRefreshToken = apperror.New(apperror.KindUnauthorized, "invalid_refresh_token", "invalid refresh token")
+	ErrRefreshTokenReused  = apperror.New(apperror.KindUnauthorized, "refresh_token_reused", "refresh token reused")
+	ErrUserNotFound        = apperror.New(apperror.KindNotFound, "user_not_found", "user not found")
+	// an admin demoting themselves could leave nobody able to hand out roles
+	ErrOwnRoleChange = apperror.New(apperror.KindForbidden, "own_role_change", "you can't change your own role")
+)
+
 type userService struct {
 	config *configs.Config
 	userRepo UserRepo
+	refreshTokenRepo RefreshTokenRepo
 }
 
-func NewUserService(userRepo UserRepo, config *configs.Config) *userService {
+func NewUserService(userRepo UserRepo, refreshTokenRepo RefreshTokenRepo, config *configs.Config) *userService {
 	return &userService{
 		userRepo: userRepo,
+		refreshTokenRepo: refreshTokenRepo,
 		config: config,
 	}
 }
@@ -38,8 +63,12 @@ func NewUserService(userRepo UserRepo, config *configs.Config) *userService {
 func (s *userService)  Register(request models.SignUpRequest) error {
 	// check the user already registered
 	_, err := s.userRepo.Find(request.Email, request.Username, 0)
+	if err == nil {
+		return ErrUserAlreadyRegistered
+	}
+
 	if err != gorm.ErrRecordNotFound {
-		return errors.New("email or username already registered")
+		return err
 	}
 
 	// bind with user model
@@ -65,28 +94,165 @@ func (s *userService)  Register(request models.SignUpRequest) error {
 }
 
 
-func (s *userService) Login(request models.SignInRequest) (string, error) {
+func (s *userService) Login(request models.SignInRequest) (*models.LoginResponse, error) {
 	foundedUser, err := s.userRepo.Find(request.Email, "", uint(0))
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return "", errors.New("email not registered")
+			return nil, ErrInvalidCredentials
 		}
 
-		return "", err
+		return nil, err
 	}
 
 	err = bcrypt.CompareHashAndPassword([]byte(foundedUser.Password), []byte(request.Password))
 	if err != nil {
-		return "", errors.New("password doesn't match")
+		return nil, ErrInvalidCredentials
+	}
+
+	jwtToken, err := jwt.CreateToken(foundedUser.ID, foundedUser.Username, foundedUser.Role, s.config.SecretJWT)
+	if err != nil {
+		return nil, err
+	}
+
+	// every login starts a new refresh token family
+	familyID, err := refreshtoken.NewFamilyID()
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, err := refreshtoken.Generate()
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.refreshTokenRepo.Create(s.newRefreshToken(foundedUser.ID, familyID, refreshToken))
+	if err != nil {
+		log.Error().Err(err).Msgf("service login: error store refresh token for user id: %d", foundedUser.ID)
+		return nil, err
+	}
+
+	return &models.LoginResponse{
+		AccessToken: jwtToken,
+		RefreshToken: refreshToken,
+	}, nil
+}
+
+func (s *userService) Refresh(userID uint, request models.RefreshTokenRequest) (*models.LoginResponse, error) {
+	storedToken, err := s.refreshTokenRepo.FindByHash(refreshtoken.Hash(request.RefreshToken))
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrInvalidRefreshToken
+		}
+
+		return nil, err
+	}
+
+	if storedToken.UserID != userID {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	// a rotated token showing up again means it leaked, kill the whole family
+	if storedToken.RevokedAt != nil {
+		return nil, s.revokeFamily(storedToken)
+	}
+
+	if time.Now().After(storedToken.ExpiresAt) {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	foundedUser, err := s.userRepo.Find("", "", userID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrInvalidRefreshToken
+		}
+
+		return nil, err
+	}
+
+	refreshToken, err := refreshtoken.Generate()
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.refreshTokenRepo.Rotate(storedToken.ID, s.newRefreshToken(userID, storedToken.FamilyID, refreshToken))
+	if err != nil {
+		if errors.Is(err, repositorys.ErrRefreshTokenRevoked) {
+			return nil, s.revokeFamily(storedToken)
+		}
+
+		log.Error().Err(err).Msgf("service refresh: error rotate refresh token id: %d", storedToken.ID)
+		return nil, err
+	}
+
+	jwtToken, err := jwt.CreateToken(foundedUser.ID, foundedUser.Username, foundedUser.Role, s.config.SecretJWT)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.LoginResponse{
+		AccessToken: jwtToken,
+		RefreshToken: refreshToken,
+	}, nil
+}
+
+// UpdateRole changes the role of userID. Access tokens already issued keep
+// the old role until they are refreshed.
+func (s *userService) UpdateRole(adminID, userID uint, request models.UpdateRoleRequest) (*models.UserResponse, error) {
+	if adminID == userID {
+		return nil, ErrOwnRoleChange
+	}
+
+	foundedUser, err := s.userRepo.Find("", "", userID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrUserNotFound
+		}
+
+		return nil, err
 	}
 
-	log.Info().Msgf("service level secret: %v", s.config.SecretJWT)
-	jwtToken, err := jwt.CreateToken(foundedUser.ID, foundedUser.Username, s.config.SecretJWT)
+	err = s.userRepo.UpdateRole(foundedUser.ID, request.Role)
 	if err != nil {
-		return "", err
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrUserNotFound
+		}
+
+		log.Error().Err(err).Msgf("service update role: error update role of user id: %d", userID)
+		return nil, err
 	}
 
+	log.Info().Msgf("service update role: user id %d changed role of user id %d from %s to %s", adminID, userID, foundedUser.Role, request.Role)
+
+	return &models.UserResponse{
+		ID: foundedUser.ID,
+		Email: foundedUser.Email,
+		Username: foundedUser.Username,
+		Role: request.Role,
+	}, nil
+}
+
+func (s *userService) newRefreshToken(userID uint, familyID, token string) models.RefreshToken {
+	ttl := s.config.RefreshTokenTTL
+	if ttl <= 0 {
+		ttl = defaultRefreshTokenTTL
+	}
+
+	return models.RefreshToken{
+		UserID: userID,
+		FamilyID: familyID,
+		TokenHash: refreshtoken.Hash(token),
+		ExpiresAt: time.Now().Add(ttl),
+	}
+}
+
+func (s *userService) revokeFamily(token *models.RefreshToken) error {
+	log.Warn().Msgf("service refresh: reused refresh token for user id: %d, revoking family %s", token.UserID, token.FamilyID)
+
+	err := s.refreshTokenRepo.RevokeFamily(token.FamilyID)
+	if err != nil {
+		log.Error().Err(err).Msgf("service refresh: error revoke refresh token family %s", token.FamilyID)
+		return err
+	}
 
-	return jwtToken, nil
-	
+	return ErrRefreshTokenReused
 }
\ No newline at end of file
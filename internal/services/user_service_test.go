@@ -2,9 +2,13 @@ package services
 
 import (
 	"testing"
+	"time"
 
 	"github.com/sgitwhyd/music-catalogue/internal/configs"
 	"github.com/sgitwhyd/music-catalogue/internal/models"
+	"github.com/sgitwhyd/music-catalogue/internal/repositorys"
+	"github.com/sgitwhyd/music-catalogue/pkg/jwt"
+	"github.com/sgitwhyd/music-catalogue/pkg/refreshtoken"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
 	"gorm.io/gorm"
@@ -88,6 +92,7 @@ func Test_userService_Login(t *testing.T) {
 
 	// Initialize the mock repository
 	mockRepo := NewMockUserRepo(ctrlMock)
+	mockRefreshTokenRepo := NewMockRefreshTokenRepo(ctrlMock)
 
 	// Initialize the userService with the mock repository
 	userService := &userService{
@@ -98,6 +103,7 @@ func Test_userService_Login(t *testing.T) {
 			ENV: "",
 		},
 		userRepo: mockRepo,
+		refreshTokenRepo: mockRefreshTokenRepo,
 	}
 
 	type args struct {
@@ -159,6 +165,7 @@ func Test_userService_Login(t *testing.T) {
 					Email:    "developer@testing.com",
 					Password: "$2a$10$Dxw4T8EYw0eCR17VLxt.yu0MkKXlW3wJpdgJaE/n0CRlj97OkkiFa",
 				}, nil)
+				mockRefreshTokenRepo.EXPECT().Create(gomock.Any()).Return(nil)
 			},
 		},
 	}
@@ -170,6 +177,248 @@ func Test_userService_Login(t *testing.T) {
 				t.Errorf("userService.Login() error = %v, wantErr %v, got %v", err, tt.wantErr, got)
 				return
 			}
+			if !tt.wantErr {
+				assert.NotEmpty(t, got.AccessToken)
+				assert.NotEmpty(t, got.RefreshToken)
+			}
+		})
+	}
+}
+
+
+func Test_userService_Refresh(t *testing.T) {
+	ctrlMock := gomock.NewController(t)
+	defer ctrlMock.Finish()
+
+	mockRepo := NewMockUserRepo(ctrlMock)
+	mockRefreshTokenRepo := NewMockRefreshTokenRepo(ctrlMock)
+
+	userService := &userService{
+		config: &configs.Config{
+			SecretJWT: "secret",
+		},
+		userRepo:         mockRepo,
+		refreshTokenRepo: mockRefreshTokenRepo,
+	}
+
+	revokedAt := time.Now().Add(-1 * time.Minute)
+	storedToken := func() *models.RefreshToken {
+		return &models.RefreshToken{
+			Model: gorm.Model{
+				ID: 10,
+			},
+			UserID:    1,
+			FamilyID:  "family",
+			TokenHash: refreshtoken.Hash("refresh token"),
+			ExpiresAt: time.Now().Add(time.Hour),
+		}
+	}
+
+	type args struct {
+		userID  uint
+		request models.RefreshTokenRequest
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr error
+		mockFn  func(args args)
+	}{
+		{
+			name: "should rotate refresh token",
+			args: args{
+				userID:  1,
+				request: models.RefreshTokenRequest{RefreshToken: "refresh token"},
+			},
+			wantErr: nil,
+			mockFn: func(args args) {
+				mockRefreshTokenRepo.EXPECT().FindByHash(refreshtoken.Hash(args.request.RefreshToken)).Return(storedToken(), nil)
+				// promoted since the access token was issued
+				mockRepo.EXPECT().Find("", "", args.userID).Return(&models.User{
+					Model:    gorm.Model{ID: 1},
+					Username: "developer",
+					Role:     models.RoleAdmin,
+				}, nil)
+				mockRefreshTokenRepo.EXPECT().Rotate(uint(10), gomock.Any()).DoAndReturn(func(oldID uint, next models.RefreshToken) error {
+					assert.Equal(t, "family", next.FamilyID)
+					assert.Equal(t, uint(1), next.UserID)
+					assert.NotEqual(t, refreshtoken.Hash(args.request.RefreshToken), next.TokenHash)
+					return nil
+				})
+			},
+		},
+		{
+			name: "should fail when refresh token not found",
+			args: args{
+				userID:  1,
+				request: models.RefreshTokenRequest{RefreshToken: "unknown"},
+			},
+			wantErr: ErrInvalidRefreshToken,
+			mockFn: func(args args) {
+				mockRefreshTokenRepo.EXPECT().FindByHash(refreshtoken.Hash(args.request.RefreshToken)).Return(nil, gorm.ErrRecordNotFound)
+			},
+		},
+		{
+			name: "should fail when refresh token belongs to another user",
+			args: args{
+				userID:  2,
+				request: models.RefreshTokenRequest{RefreshToken: "refresh token"},
+			},
+			wantErr: ErrInvalidRefreshToken,
+			mockFn: func(args args) {
+				mockRefreshTokenRepo.EXPECT().FindByHash(refreshtoken.Hash(args.request.RefreshToken)).Return(storedToken(), nil)
+			},
+		},
+		{
+			name: "should fail when refresh token expired",
+			args: args{
+				userID:  1,
+				request: models.RefreshTokenRequest{RefreshToken: "refresh token"},
+			},
+			wantErr: ErrInvalidRefreshToken,
+			mockFn: func(args args) {
+				token := storedToken()
+				token.ExpiresAt = time.Now().Add(-1 * time.Minute)
+				mockRefreshTokenRepo.EXPECT().FindByHash(refreshtoken.Hash(args.request.RefreshToken)).Return(token, nil)
+			},
+		},
+		{
+			name: "should revoke family when rotated token reused",
+			args: args{
+				userID:  1,
+				request: models.RefreshTokenRequest{RefreshToken: "refresh token"},
+			},
+			wantErr: ErrRefreshTokenReused,
+			mockFn: func(args args) {
+				token := storedToken()
+				token.RevokedAt = &revokedAt
+				mockRefreshTokenRepo.EXPECT().FindByHash(refreshtoken.Hash(args.request.RefreshToken)).Return(token, nil)
+				mockRefreshTokenRepo.EXPECT().RevokeFamily("family").Return(nil)
+			},
+		},
+		{
+			name: "should revoke family when concurrent rotation wins",
+			args: args{
+				userID:  1,
+				request: models.RefreshTokenRequest{RefreshToken: "refresh token"},
+			},
+			wantErr: ErrRefreshTokenReused,
+			mockFn: func(args args) {
+				mockRefreshTokenRepo.EXPECT().FindByHash(refreshtoken.Hash(args.request.RefreshToken)).Return(storedToken(), nil)
+				mockRepo.EXPECT().Find("", "", args.userID).Return(&models.User{
+					Model:    gorm.Model{ID: 1},
+					Username: "developer",
+				}, nil)
+				mockRefreshTokenRepo.EXPECT().Rotate(uint(10), gomock.Any()).Return(repositorys.ErrRefreshTokenRevoked)
+				mockRefreshTokenRepo.EXPECT().RevokeFamily("family").Return(nil)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFn(tt.args)
+
+			got, err := userService.Refresh(tt.args.userID, tt.args.request)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, got)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.NotEmpty(t, got.AccessToken)
+			assert.NotEmpty(t, got.RefreshToken)
+			assert.NotEqual(t, tt.args.request.RefreshToken, got.RefreshToken)
+
+			_, _, role, err := jwt.ValidateToken(got.AccessToken, "secret")
+			assert.NoError(t, err)
+			assert.Equal(t, models.RoleAdmin, role)
+		})
+	}
+}
+
+func Test_userService_UpdateRole(t *testing.T) {
+	ctrlMock := gomock.NewController(t)
+	defer ctrlMock.Finish()
+
+	mockRepo := NewMockUserRepo(ctrlMock)
+
+	userService := &userService{
+		userRepo: mockRepo,
+	}
+
+	user := func() *models.User {
+		return &models.User{
+			Model:    gorm.Model{ID: 2},
+			Email:    "developer@gmail.com",
+			Username: "developer",
+			Role:     models.RoleUser,
+		}
+	}
+
+	type args struct {
+		adminID uint
+		userID  uint
+		request models.UpdateRoleRequest
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    *models.UserResponse
+		wantErr error
+		mockFn  func(args args)
+	}{
+		{
+			name: "should update role",
+			args: args{adminID: 1, userID: 2, request: models.UpdateRoleRequest{Role: models.RoleAdmin}},
+			want: &models.UserResponse{
+				ID:       2,
+				Email:    "developer@gmail.com",
+				Username: "developer",
+				Role:     models.RoleAdmin,
+			},
+			mockFn: func(args args) {
+				mockRepo.EXPECT().Find("", "", args.userID).Return(user(), nil)
+				mockRepo.EXPECT().UpdateRole(args.userID, models.RoleAdmin).Return(nil)
+			},
+		},
+		{
+			name:    "should refuse to change own role",
+			args:    args{adminID: 1, userID: 1, request: models.UpdateRoleRequest{Role: models.RoleUser}},
+			wantErr: ErrOwnRoleChange,
+			mockFn:  func(args args) {},
+		},
+		{
+			name:    "should fail when user not found",
+			args:    args{adminID: 1, userID: 2, request: models.UpdateRoleRequest{Role: models.RoleAdmin}},
+			wantErr: ErrUserNotFound,
+			mockFn: func(args args) {
+				mockRepo.EXPECT().Find("", "", args.userID).Return(nil, gorm.ErrRecordNotFound)
+			},
+		},
+		{
+			name:    "should fail when update fails",
+			args:    args{adminID: 1, userID: 2, request: models.UpdateRoleRequest{Role: models.RoleAdmin}},
+			wantErr: assert.AnError,
+			mockFn: func(args args) {
+				mockRepo.EXPECT().Find("", "", args.userID).Return(user(), nil)
+				mockRepo.EXPECT().UpdateRole(args.userID, models.RoleAdmin).Return(assert.AnError)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFn(tt.args)
+
+			got, err := userService.UpdateRole(tt.args.adminID, tt.args.userID, tt.args.request)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, got)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
 		})
 	}
 }
@@ -0,0 +1,180 @@
+package httpclient
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// ErrCircuitOpen is returned without sending the request while the circuit
+// breaker is open.
+var ErrCircuitOpen = errors.New("httpclient: circuit breaker is open")
+
+type State int
+
+const (
+	StateClosed State = iota
+	StateOpen
+	StateHalfOpen
+)
+
+func (s State) String() string {
+	switch s {
+	case StateClosed:
+		return "closed"
+	case StateOpen:
+		return "open"
+	case StateHalfOpen:
+		return "half-open"
+	}
+
+	return "unknown"
+}
+
+// BreakerPolicy configures the circuit breaker. The circuit opens after
+// FailureThreshold consecutive failures and rejects requests for
+// OpenTimeout. It then lets HalfOpenMaxRequests trial requests through; one
+// failure reopens it, that many successes close it.
+type BreakerPolicy struct {
+	FailureThreshold    int
+	OpenTimeout         time.Duration
+	HalfOpenMaxRequests int
+}
+
+// WithCircuitBreaker guards the client with a circuit breaker. Network
+// errors, 5xx and 429 responses count as failures, after any retries.
+func WithCircuitBreaker(policy BreakerPolicy) Option {
+	return func(c *Client) {
+		c.breaker = newBreaker(policy)
+	}
+}
+
+type breaker struct {
+	policy BreakerPolicy
+	now    func() time.Time
+
+	mu         sync.Mutex
+	state      State
+	generation uint64
+	failures   int
+	openedAt   time.Time
+	inFlight   int
+	successes  int
+}
+
+func newBreaker(policy BreakerPolicy) *breaker {
+	if policy.FailureThreshold < 1 {
+		policy.FailureThreshold = 1
+	}
+	if policy.HalfOpenMaxRequests < 1 {
+		policy.HalfOpenMaxRequests = 1
+	}
+
+	return &breaker{
+		policy: policy,
+		now:    time.Now,
+	}
+}
+
+// allow reports whether a request may be sent. Every allowed request must be
+// followed by a call to done or release with the returned generation, so
+// outcomes of requests started before a state change are ignored.
+func (b *breaker) allow() (uint64, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == StateOpen {
+		if b.now().Sub(b.openedAt) < b.policy.OpenTimeout {
+			return 0, false
+		}
+
+		b.setState(StateHalfOpen)
+	}
+
+	if b.state == StateHalfOpen {
+		if b.inFlight >= b.policy.HalfOpenMaxRequests {
+			return 0, false
+		}
+
+		b.inFlight++
+	}
+
+	return b.generation, true
+}
+
+func (b *breaker) done(generation uint64, failed bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if generation != b.generation {
+		return
+	}
+
+	switch b.state {
+	case StateClosed:
+		if !failed {
+			b.failures = 0
+			return
+		}
+
+		b.failures++
+		if b.failures >= b.policy.FailureThreshold {
+			b.setState(StateOpen)
+		}
+	case StateHalfOpen:
+		b.inFlight--
+
+		if failed {
+			b.setState(StateOpen)
+			return
+		}
+
+		b.successes++
+		if b.successes >= b.policy.HalfOpenMaxRequests {
+			b.setState(StateClosed)
+		}
+	}
+}
+
+// release ends a request without recording an outcome.
+func (b *breaker) release(generation uint64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if generation == b.generation && b.state == StateHalfOpen {
+		b.inFlight--
+	}
+}
+
+func (b *breaker) State() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.state
+}
+
+// setState must be called with mu held.
+func (b *breaker) setState(state State) {
+	if state == StateOpen {
+		b.openedAt = b.now()
+	}
+
+	log.Warn().Str("from", b.state.String()).Str("to", state.String()).Msg("circuit breaker state change")
+
+	b.state = state
+	b.generation++
+	b.failures = 0
+	b.successes = 0
+	b.inFlight = 0
+}
+
+func failed(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+
+	return resp.StatusCode >= http.StatusInternalServerError || resp.StatusCode == http.StatusTooManyRequests
+}
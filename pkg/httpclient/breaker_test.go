@@ -0,0 +1,100 @@
+package httpclient
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestClient_Do_CircuitBreaker(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	mockClient := NewMockHTTPClient(mockCtrl)
+	c := NewClient(mockClient, WithCircuitBreaker(BreakerPolicy{
+		FailureThreshold:    2,
+		OpenTimeout:         30 * time.Second,
+		HalfOpenMaxRequests: 1,
+	}))
+	c.breaker.now = func() time.Time { return now }
+
+	send := func() (*http.Response, error) {
+		req, err := http.NewRequest(http.MethodGet, "https://api.spotify.com/v1/search", nil)
+		assert.NoError(t, err)
+
+		return c.Do(req)
+	}
+
+	// consecutive failures open the circuit
+	mockClient.EXPECT().Do(gomock.Any()).Return(response(http.StatusServiceUnavailable, nil), nil).Times(1)
+	mockClient.EXPECT().Do(gomock.Any()).Return(nil, errors.New("connection refused")).Times(1)
+
+	_, err := send()
+	assert.NoError(t, err)
+	assert.Equal(t, StateClosed, c.BreakerState())
+
+	_, err = send()
+	assert.Error(t, err)
+	assert.Equal(t, StateOpen, c.BreakerState())
+
+	// open rejects without calling upstream
+	_, err = send()
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+
+	// after the timeout a failing trial reopens
+	now = now.Add(31 * time.Second)
+	mockClient.EXPECT().Do(gomock.Any()).Return(response(http.StatusBadGateway, nil), nil).Times(1)
+
+	_, err = send()
+	assert.NoError(t, err)
+	assert.Equal(t, StateOpen, c.BreakerState())
+
+	_, err = send()
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+
+	// a succeeding trial closes it
+	now = now.Add(31 * time.Second)
+	mockClient.EXPECT().Do(gomock.Any()).Return(response(http.StatusOK, nil), nil).Times(1)
+
+	resp, err := send()
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, StateClosed, c.BreakerState())
+}
+
+func Test_breaker_HalfOpenLimit(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	b := newBreaker(BreakerPolicy{FailureThreshold: 1, OpenTimeout: time.Second, HalfOpenMaxRequests: 1})
+	b.now = func() time.Time { return now }
+
+	generation, ok := b.allow()
+	assert.True(t, ok)
+	b.done(generation, true)
+	assert.Equal(t, StateOpen, b.State())
+
+	now = now.Add(2 * time.Second)
+
+	trial, ok := b.allow()
+	assert.True(t, ok)
+	assert.Equal(t, StateHalfOpen, b.State())
+
+	// only one trial at a time
+	_, ok = b.allow()
+	assert.False(t, ok)
+
+	// an abandoned trial frees its slot
+	b.release(trial)
+	_, ok = b.allow()
+	assert.True(t, ok)
+
+	// outcomes from before the state change are ignored
+	b.done(generation, true)
+	assert.Equal(t, StateHalfOpen, b.State())
+}
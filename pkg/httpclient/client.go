@@ -1,22 +1,228 @@
 package httpclient
 
-import "net/http"
+import (
+	"context"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
 
 //go:generate mockgen -source=client.go -destination=client_mock.go -package=httpclient
 type HTTPClient interface {
 	Do(req *http.Request) (*http.Response, error)
 }
 
+// RetryPolicy controls how idempotent requests are retried. Delays grow
+// exponentially from BaseDelay up to MaxDelay with full jitter. A 429 waits
+// for its Retry-After instead, unless that is longer than MaxRetryAfter.
+type RetryPolicy struct {
+	MaxAttempts   int
+	BaseDelay     time.Duration
+	MaxDelay      time.Duration
+	MaxRetryAfter time.Duration
+}
+
+type Option func(*Client)
+
+// WithRetry enables retries. Without it every request is attempted once.
+func WithRetry(policy RetryPolicy) Option {
+	return func(c *Client) {
+		c.retry = policy
+	}
+}
+
 type Client struct {
 	Client HTTPClient
+
+	retry   RetryPolicy
+	breaker *breaker
+	sleep   func(ctx context.Context, d time.Duration) error
+	jitter  func(d time.Duration) time.Duration
 }
 
-func NewClient(client HTTPClient) *Client {
-	return &Client{
+func NewClient(client HTTPClient, opts ...Option) *Client {
+	c := &Client{
 		Client: client,
+		sleep:  sleep,
+		jitter: jitter,
 	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
 }
 
 func (c *Client) Do(req *http.Request) (*http.Response, error) {
-	return c.Client.Do(req)
-}
\ No newline at end of file
+	if c.breaker == nil {
+		return c.do(req)
+	}
+
+	generation, ok := c.breaker.allow()
+	if !ok {
+		return nil, ErrCircuitOpen
+	}
+
+	resp, err := c.do(req)
+
+	// the caller giving up says nothing about the upstream health
+	if req.Context().Err() != nil {
+		c.breaker.release(generation)
+	} else {
+		c.breaker.done(generation, failed(resp, err))
+	}
+
+	return resp, err
+}
+
+// BreakerState reports the circuit breaker state, always closed without one.
+func (c *Client) BreakerState() State {
+	if c.breaker == nil {
+		return StateClosed
+	}
+
+	return c.breaker.State()
+}
+
+// do sends req, retrying it according to the retry policy.
+func (c *Client) do(req *http.Request) (*http.Response, error) {
+	attempts := c.retry.MaxAttempts
+	if attempts < 1 || !isIdempotent(req.Method) {
+		attempts = 1
+	}
+
+	for attempt := 1; ; attempt++ {
+		if attempt > 1 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+
+		start := time.Now()
+		resp, err := c.Client.Do(req)
+
+		event := log.Debug()
+		if retryable(resp, err) {
+			event = log.Warn()
+		}
+		if err != nil {
+			event = event.Err(err)
+		} else {
+			event = event.Int("status", resp.StatusCode)
+		}
+		event.
+			Str("method", req.Method).
+			Str("url", req.URL.Redacted()).
+			Int("attempt", attempt).
+			Dur("elapsed", time.Since(start)).
+			Msg("http request attempt")
+
+		if attempt >= attempts || !retryable(resp, err) || req.Context().Err() != nil {
+			return resp, err
+		}
+
+		delay := c.backoff(attempt)
+		if resp != nil {
+			if resp.StatusCode == http.StatusTooManyRequests {
+				if retryAfter, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+					if c.retry.MaxRetryAfter > 0 && retryAfter > c.retry.MaxRetryAfter {
+						return resp, nil
+					}
+					delay = retryAfter
+				}
+			}
+
+			// drain so the connection can be reused
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		if err := c.sleep(req.Context(), delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (c *Client) backoff(attempt int) time.Duration {
+	delay := c.retry.BaseDelay << (attempt - 1)
+	if delay <= 0 || (c.retry.MaxDelay > 0 && delay > c.retry.MaxDelay) {
+		delay = c.retry.MaxDelay
+	}
+
+	return c.jitter(delay)
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete, http.MethodTrace:
+		return true
+	}
+
+	return false
+}
+
+func retryable(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests,
+		http.StatusInternalServerError,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return true
+	}
+
+	return false
+}
+
+// parseRetryAfter accepts both forms of the header, delay seconds and an
+// HTTP date.
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	at, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+
+	return max(at.Sub(now), 0), true
+}
+
+func jitter(d time.Duration) time.Duration {
+	if d <= 0 {
+		return 0
+	}
+
+	return rand.N(d)
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,186 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func response(status int, header http.Header) *http.Response {
+	if header == nil {
+		header = http.Header{}
+	}
+
+	return &http.Response{
+		StatusCode: status,
+		Header:     header,
+		Body:       io.NopCloser(strings.NewReader("")),
+	}
+}
+
+func TestClient_Do(t *testing.T) {
+	policy := RetryPolicy{
+		MaxAttempts:   3,
+		BaseDelay:     100 * time.Millisecond,
+		MaxDelay:      time.Second,
+		MaxRetryAfter: time.Minute,
+	}
+
+	tests := []struct {
+		name       string
+		method     string
+		responses  []*http.Response
+		errs       []error
+		wantStatus int
+		wantDelays []time.Duration
+	}{
+		{
+			name:       "should not retry a success",
+			method:     http.MethodGet,
+			responses:  []*http.Response{response(http.StatusOK, nil)},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:   "should retry 5xx with exponential backoff",
+			method: http.MethodGet,
+			responses: []*http.Response{
+				response(http.StatusBadGateway, nil),
+				response(http.StatusServiceUnavailable, nil),
+				response(http.StatusOK, nil),
+			},
+			wantStatus: http.StatusOK,
+			wantDelays: []time.Duration{100 * time.Millisecond, 200 * time.Millisecond},
+		},
+		{
+			name:   "should retry network errors",
+			method: http.MethodGet,
+			responses: []*http.Response{
+				nil,
+				response(http.StatusOK, nil),
+			},
+			errs:       []error{errors.New("connection reset"), nil},
+			wantStatus: http.StatusOK,
+			wantDelays: []time.Duration{100 * time.Millisecond},
+		},
+		{
+			name:   "should give up after max attempts",
+			method: http.MethodGet,
+			responses: []*http.Response{
+				response(http.StatusInternalServerError, nil),
+				response(http.StatusInternalServerError, nil),
+				response(http.StatusInternalServerError, nil),
+			},
+			wantStatus: http.StatusInternalServerError,
+			wantDelays: []time.Duration{100 * time.Millisecond, 200 * time.Millisecond},
+		},
+		{
+			name:   "should honour retry-after on 429",
+			method: http.MethodGet,
+			responses: []*http.Response{
+				response(http.StatusTooManyRequests, http.Header{"Retry-After": {"7"}}),
+				response(http.StatusOK, nil),
+			},
+			wantStatus: http.StatusOK,
+			wantDelays: []time.Duration{7 * time.Second},
+		},
+		{
+			name:   "should return 429 when retry-after is too long",
+			method: http.MethodGet,
+			responses: []*http.Response{
+				response(http.StatusTooManyRequests, http.Header{"Retry-After": {"3600"}}),
+			},
+			wantStatus: http.StatusTooManyRequests,
+		},
+		{
+			name:   "should not retry non idempotent requests",
+			method: http.MethodPost,
+			responses: []*http.Response{
+				response(http.StatusServiceUnavailable, nil),
+			},
+			wantStatus: http.StatusServiceUnavailable,
+		},
+		{
+			name:   "should not retry client errors",
+			method: http.MethodGet,
+			responses: []*http.Response{
+				response(http.StatusNotFound, nil),
+			},
+			wantStatus: http.StatusNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockClient := NewMockHTTPClient(mockCtrl)
+			for i, resp := range tt.responses {
+				var err error
+				if i < len(tt.errs) {
+					err = tt.errs[i]
+				}
+				mockClient.EXPECT().Do(gomock.Any()).Return(resp, err).Times(1)
+			}
+
+			var delays []time.Duration
+			c := NewClient(mockClient, WithRetry(policy))
+			c.jitter = func(d time.Duration) time.Duration { return d }
+			c.sleep = func(ctx context.Context, d time.Duration) error {
+				delays = append(delays, d)
+				return nil
+			}
+
+			req, err := http.NewRequest(tt.method, "https://api.spotify.com/v1/search", nil)
+			assert.NoError(t, err)
+
+			resp, err := c.Do(req)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+			assert.Equal(t, tt.wantDelays, delays)
+		})
+	}
+}
+
+func TestClient_Do_ContextDone(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockClient := NewMockHTTPClient(mockCtrl)
+	mockClient.EXPECT().Do(gomock.Any()).Return(response(http.StatusServiceUnavailable, nil), nil).Times(1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	c := NewClient(mockClient, WithRetry(RetryPolicy{MaxAttempts: 5, BaseDelay: time.Hour}))
+	c.sleep = func(ctx context.Context, d time.Duration) error {
+		cancel()
+		return sleep(ctx, d)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://api.spotify.com/v1/search", nil)
+	assert.NoError(t, err)
+
+	_, err = c.Do(req)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func Test_parseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	got, ok := parseRetryAfter("120", now)
+	assert.True(t, ok)
+	assert.Equal(t, 2*time.Minute, got)
+
+	got, ok = parseRetryAfter(now.Add(30*time.Second).Format(http.TimeFormat), now)
+	assert.True(t, ok)
+	assert.Equal(t, 30*time.Second, got)
+
+	_, ok = parseRetryAfter("soon", now)
+	assert.False(t, ok)
+}
@@ -7,7 +7,7 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 )
 
-func CreateToken(UserID uint, username, secretKey string) (string, error) {
+func CreateToken(UserID uint, username, role, secretKey string) (string, error) {
 
 	if secretKey == "" {
 		return "", errors.New("need secret key")
@@ -16,6 +16,7 @@ func CreateToken(UserID uint, username, secretKey string) (string, error) {
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
 		"id": UserID,
 		"username": username,
+		"role": role,
 		"exp": time.Now().Add(10 * time.Minute).Unix(),
 	})
 
@@ -28,7 +29,7 @@ func CreateToken(UserID uint, username, secretKey string) (string, error) {
 	return tokenStr, nil
 }
 
-func ValidateToken(tokenReq string, secretKey string) (uint, string, error) {
+func ValidateToken(tokenReq string, secretKey string) (uint, string, string, error) {
 	key := []byte(secretKey)
 	claims := jwt.MapClaims{}
 
@@ -36,17 +37,20 @@ func ValidateToken(tokenReq string, secretKey string) (uint, string, error) {
 		return key, nil
 	})
 	if err != nil {
-		return 0, "", err
+		return 0, "", "", err
 	}
 
 	if !token.Valid {
-		return 0, "", errors.New("invalid token")
+		return 0, "", "", errors.New("invalid token")
 	}
 
-	return uint(claims["id"].(float64)), claims["username"].(string), nil
+	// tokens issued before roles existed don't carry one
+	role, _ := claims["role"].(string)
+
+	return uint(claims["id"].(float64)), claims["username"].(string), role, nil
 }
 
-func ValidateTokenWithoutExpiry(tokenReq string, secretKey string) (uint, string, error) {
+func ValidateTokenWithoutExpiry(tokenReq string, secretKey string) (uint, string, string, error) {
 	key := []byte(secretKey)
 	claims := jwt.MapClaims{}
 
@@ -54,12 +58,15 @@ func ValidateTokenWithoutExpiry(tokenReq string, secretKey string) (uint, string
 		return key, nil
 	}, jwt.WithoutClaimsValidation())
 	if err != nil {
-		return 0, "", err
+		return 0, "", "", err
 	}
 
 	if !token.Valid {
-		return 0, "", errors.New("invalid token")
+		return 0, "", "", errors.New("invalid token")
 	}
 
-	return uint(claims["id"].(float64)), claims["username"].(string), nil
+	// tokens issued before roles existed don't carry one
+	role, _ := claims["role"].(string)
+
+	return uint(claims["id"].(float64)), claims["username"].(string), role, nil
 }
\ No newline at end of file
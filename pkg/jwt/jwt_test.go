@@ -10,6 +10,7 @@ func TestCreateToken(t *testing.T) {
 	type args struct {
 		UserID    uint
 		username  string
+		role      string
 		secretKey string
 	}
 	tests := []struct {
@@ -23,6 +24,7 @@ func TestCreateToken(t *testing.T) {
 			name: "should generate jwt token",
 			args: args{
 				username:  "developer",
+				role:      "user",
 				secretKey: "secret",
 				UserID:    1,
 			},
@@ -42,7 +44,7 @@ func TestCreateToken(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := CreateToken(tt.args.UserID, tt.args.username, tt.args.secretKey)
+			got, err := CreateToken(tt.args.UserID, tt.args.username, tt.args.role, tt.args.secretKey)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("CreateToken() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -66,6 +68,7 @@ func TestValidateToken(t *testing.T) {
 		args    args
 		want    uint
 		want1   string
+		want2   string
 		wantErr bool
 	}{
 		// TODO: Add test cases.
@@ -75,7 +78,7 @@ func TestValidateToken(t *testing.T) {
 				secretKey := "secret"
 				userID := uint(1)
 				username := "developer"
-				token, err := CreateToken(userID, username, secretKey)
+				token, err := CreateToken(userID, username, "admin", secretKey)
 				if err != nil {
 					t.Fatalf("failed to generate token: %v", err)
 				}
@@ -86,6 +89,7 @@ func TestValidateToken(t *testing.T) {
 			}(),
 			want: 1,
 			want1: "developer",
+			want2: "admin",
 			wantErr: false,
 		},
 		{
@@ -101,7 +105,7 @@ func TestValidateToken(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, got1, err := ValidateToken(tt.args.tokenReq, tt.args.secretKey)
+			got, got1, got2, err := ValidateToken(tt.args.tokenReq, tt.args.secretKey)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("ValidateToken() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -112,6 +116,9 @@ func TestValidateToken(t *testing.T) {
 			if got1 != tt.want1 {
 				t.Errorf("ValidateToken() got1 = %v, want %v", got1, tt.want1)
 			}
+			if got2 != tt.want2 {
+				t.Errorf("ValidateToken() got2 = %v, want %v", got2, tt.want2)
+			}
 		})
 	}
 }
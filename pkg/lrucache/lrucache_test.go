@@ -0,0 +1,41 @@
+package lrucache
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCache(t *testing.T) {
+	c := New[string, int](2)
+
+	c.Add("first", 1)
+	c.Add("second", 2)
+
+	// touching first makes second the least recently used
+	value, ok := c.Get("first")
+	assert.True(t, ok)
+	assert.Equal(t, 1, value)
+
+	c.Add("third", 3)
+	assert.Equal(t, 2, c.Len())
+
+	_, ok = c.Get("second")
+	assert.False(t, ok)
+
+	value, ok = c.Get("third")
+	assert.True(t, ok)
+	assert.Equal(t, 3, value)
+}
+
+func TestCache_AddExisting(t *testing.T) {
+	c := New[string, int](2)
+
+	c.Add("first", 1)
+	c.Add("first", 2)
+
+	value, ok := c.Get("first")
+	assert.True(t, ok)
+	assert.Equal(t, 2, value)
+	assert.Equal(t, 1, c.Len())
+}
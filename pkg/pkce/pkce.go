@@ -0,0 +1,40 @@
+// Package pkce implements the client side of RFC 7636, proof key for code
+// exchange, for the OAuth authorization code flow.
+package pkce
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+)
+
+// Method is the only challenge method that is worth supporting.
+const Method = "S256"
+
+// NewVerifier returns a code verifier of 64 random bytes, 86 characters
+// once encoded, within the 43 to 128 the RFC allows.
+func NewVerifier() (string, error) {
+	return randomString(64)
+}
+
+// Challenge derives the S256 code challenge that is sent with the
+// authorization request.
+func Challenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// NewState returns an unguessable value tying the callback to the request
+// that started the flow.
+func NewState() (string, error) {
+	return randomString(32)
+}
+
+func randomString(size int) (string, error) {
+	buf := make([]byte, size)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
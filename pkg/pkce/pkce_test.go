@@ -0,0 +1,23 @@
+package pkce
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewVerifier(t *testing.T) {
+	first, err := NewVerifier()
+	assert.NoError(t, err)
+
+	second, err := NewVerifier()
+	assert.NoError(t, err)
+
+	assert.Len(t, first, 86)
+	assert.NotEqual(t, first, second)
+}
+
+func TestChallenge(t *testing.T) {
+	// the example of RFC 7636 appendix B
+	assert.Equal(t, "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM", Challenge("dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"))
+}
@@ -0,0 +1,194 @@
+// Package ratelimit is a token bucket rate limiter keyed by client. Every key
+// gets its own bucket holding up to Burst tokens, refilled at Rate tokens a
+// second; a request takes one token.
+package ratelimit
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sgitwhyd/music-catalogue/pkg/lrucache"
+)
+
+// buckets kept per limiter, the least recently used key starts over with a
+// full bucket when it comes back
+const defaultMaxKeys = 100000
+
+var ErrInvalidSpec = errors.New("ratelimit: invalid spec")
+
+type Limit struct {
+	// tokens added per second
+	Rate  float64
+	Burst int
+}
+
+// Every is the limit of requests per period, with bursts of up to burst
+// requests. A burst below one is requests.
+func Every(requests int, period time.Duration, burst int) Limit {
+	if burst < 1 {
+		burst = requests
+	}
+
+	return Limit{
+		Rate:  float64(requests) / period.Seconds(),
+		Burst: burst,
+	}
+}
+
+// Decision is the outcome of a request and the state of its bucket after
+// it, the numbers behind the RateLimit-* headers.
+type Decision struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// until the bucket is full again
+	Reset time.Duration
+	// until the next request is allowed, zero when it was allowed
+	RetryAfter time.Duration
+}
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+type Limiter struct {
+	limit Limit
+	now   func() time.Time
+
+	mu      sync.Mutex
+	buckets *lrucache.Cache[string, *bucket]
+}
+
+func New(limit Limit) *Limiter {
+	return &Limiter{
+		limit:   limit,
+		now:     time.Now,
+		buckets: lrucache.New[string, *bucket](defaultMaxKeys),
+	}
+}
+
+// Allow takes a token from the bucket of key.
+func (l *Limiter) Allow(key string) Decision {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	burst := float64(l.limit.Burst)
+
+	b, ok := l.buckets.Get(key)
+	if !ok {
+		b = &bucket{tokens: burst, updated: now}
+		l.buckets.Add(key, b)
+	}
+
+	if elapsed := now.Sub(b.updated); elapsed > 0 {
+		b.tokens = math.Min(burst, b.tokens+elapsed.Seconds()*l.limit.Rate)
+	}
+	b.updated = now
+
+	decision := Decision{
+		Allowed: b.tokens >= 1,
+		Limit:   l.limit.Burst,
+	}
+	if decision.Allowed {
+		b.tokens--
+	} else {
+		decision.RetryAfter = l.refill(1 - b.tokens)
+	}
+
+	decision.Remaining = int(b.tokens)
+	decision.Reset = l.refill(burst - b.tokens)
+
+	return decision
+}
+
+// refill is how long tokens take to come back.
+func (l *Limiter) refill(tokens float64) time.Duration {
+	if l.limit.Rate <= 0 {
+		return 0
+	}
+
+	return time.Duration(tokens / l.limit.Rate * float64(time.Second))
+}
+
+// ParseLimits reads a comma separated list of name=requests/period limits,
+// with an optional :burst, e.g. "default=300/m,spotify=60/m:20". The period
+// is s, m, h or a duration like 10s.
+func ParseLimits(spec string) (map[string]Limit, error) {
+	limits := make(map[string]Limit)
+
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		name, value, ok := strings.Cut(entry, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("%w: %q is not name=requests/period", ErrInvalidSpec, entry)
+		}
+
+		limit, err := parseLimit(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q: %s", ErrInvalidSpec, entry, err)
+		}
+
+		limits[name] = limit
+	}
+
+	return limits, nil
+}
+
+func parseLimit(value string) (Limit, error) {
+	rate, burstValue, hasBurst := strings.Cut(value, ":")
+
+	requestsValue, periodValue, ok := strings.Cut(rate, "/")
+	if !ok {
+		return Limit{}, errors.New("missing /period")
+	}
+
+	requests, err := strconv.Atoi(requestsValue)
+	if err != nil || requests < 1 {
+		return Limit{}, errors.New("requests must be a positive number")
+	}
+
+	period, err := parsePeriod(periodValue)
+	if err != nil {
+		return Limit{}, err
+	}
+
+	burst := requests
+	if hasBurst {
+		burst, err = strconv.Atoi(burstValue)
+		if err != nil || burst < 1 {
+			return Limit{}, errors.New("burst must be a positive number")
+		}
+	}
+
+	return Every(requests, period, burst), nil
+}
+
+func parsePeriod(value string) (time.Duration, error) {
+	switch value {
+	case "s":
+		return time.Second, nil
+	case "m":
+		return time.Minute, nil
+	case "h":
+		return time.Hour, nil
+	}
+
+	period, err := time.ParseDuration(value)
+	if err != nil || period <= 0 {
+		return 0, errors.New("period must be s, m, h or a positive duration")
+	}
+
+	return period, nil
+}
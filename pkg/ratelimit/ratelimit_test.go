@@ -0,0 +1,103 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLimiter_Allow(t *testing.T) {
+	now := time.Date(2026, 1, 2, 12, 0, 0, 0, time.UTC)
+
+	// one request every 10 seconds, bursts of two
+	l := New(Every(6, time.Minute, 2))
+	l.now = func() time.Time { return now }
+
+	got := l.Allow("user:1")
+	assert.Equal(t, Decision{Allowed: true, Limit: 2, Remaining: 1, Reset: 10 * time.Second}, got)
+
+	got = l.Allow("user:1")
+	assert.Equal(t, Decision{Allowed: true, Limit: 2, Remaining: 0, Reset: 20 * time.Second}, got)
+
+	got = l.Allow("user:1")
+	assert.Equal(t, Decision{Allowed: false, Limit: 2, Remaining: 0, Reset: 20 * time.Second, RetryAfter: 10 * time.Second}, got)
+
+	// other keys have their own bucket
+	got = l.Allow("ip:127.0.0.1")
+	assert.True(t, got.Allowed)
+
+	// a token came back
+	now = now.Add(10 * time.Second)
+
+	got = l.Allow("user:1")
+	assert.Equal(t, Decision{Allowed: true, Limit: 2, Remaining: 0, Reset: 20 * time.Second}, got)
+
+	// a long pause refills no more than the burst
+	now = now.Add(time.Hour)
+
+	got = l.Allow("user:1")
+	assert.Equal(t, Decision{Allowed: true, Limit: 2, Remaining: 1, Reset: 10 * time.Second}, got)
+}
+
+func TestParseLimits(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    string
+		want    map[string]Limit
+		wantErr bool
+	}{
+		{
+			name: "units and bursts",
+			spec: "default=300/m, spotify=60/m:20,auth=10/30s,exports=5/h",
+			want: map[string]Limit{
+				"default": {Rate: 5, Burst: 300},
+				"spotify": {Rate: 1, Burst: 20},
+				"auth":    {Rate: 1.0 / 3, Burst: 10},
+				"exports": {Rate: 5.0 / 3600, Burst: 5},
+			},
+		},
+		{
+			name: "empty",
+			spec: "",
+			want: map[string]Limit{},
+		},
+		{
+			name:    "missing name",
+			spec:    "=10/s",
+			wantErr: true,
+		},
+		{
+			name:    "missing period",
+			spec:    "default=10",
+			wantErr: true,
+		},
+		{
+			name:    "unknown period",
+			spec:    "default=10/week",
+			wantErr: true,
+		},
+		{
+			name:    "zero requests",
+			spec:    "default=0/s",
+			wantErr: true,
+		},
+		{
+			name:    "invalid burst",
+			spec:    "default=10/s:many",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseLimits(tt.spec)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidSpec)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package refreshtoken
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+)
+
+// Generate returns a new opaque refresh token. Only its Hash is stored.
+func Generate() (string, error) {
+	return randomString(32)
+}
+
+// NewFamilyID returns an identifier shared by every token rotated from the
+// same login.
+func NewFamilyID() (string, error) {
+	return randomString(16)
+}
+
+func Hash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func randomString(size int) (string, error) {
+	buf := make([]byte, size)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
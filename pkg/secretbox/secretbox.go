@@ -0,0 +1,75 @@
+// Package secretbox encrypts short secrets, like third party tokens, before
+// they are stored. It uses AES-GCM with a random nonce per secret.
+package secretbox
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+)
+
+// KeySize is the key length in bytes, it selects AES-256.
+const KeySize = 32
+
+var (
+	ErrInvalidKey = errors.New("secretbox: key must be 32 bytes, base64 encoded")
+	// ErrDecrypt covers both tampered secrets and a wrong key, GCM can't tell
+	// them apart.
+	ErrDecrypt = errors.New("secretbox: can't decrypt secret")
+)
+
+type Box struct {
+	aead cipher.AEAD
+}
+
+// New returns a Box for key, which is base64 encoded like it comes from the
+// environment.
+func New(key string) (*Box, error) {
+	raw, err := base64.StdEncoding.DecodeString(key)
+	if err != nil || len(raw) != KeySize {
+		return nil, ErrInvalidKey
+	}
+
+	block, err := aes.NewCipher(raw)
+	if err != nil {
+		return nil, fmt.Errorf("secretbox: %w", err)
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("secretbox: %w", err)
+	}
+
+	return &Box{aead: aead}, nil
+}
+
+// Seal encrypts plaintext and returns it base64 encoded with the nonce in
+// front.
+func (b *Box) Seal(plaintext string) (string, error) {
+	nonce := make([]byte, b.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	sealed := b.aead.Seal(nonce, nonce, []byte(plaintext), nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+func (b *Box) Open(secret string) (string, error) {
+	sealed, err := base64.StdEncoding.DecodeString(secret)
+	if err != nil || len(sealed) < b.aead.NonceSize() {
+		return "", ErrDecrypt
+	}
+
+	nonce, ciphertext := sealed[:b.aead.NonceSize()], sealed[b.aead.NonceSize():]
+
+	plaintext, err := b.aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", ErrDecrypt
+	}
+
+	return string(plaintext), nil
+}
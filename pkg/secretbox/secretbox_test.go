@@ -0,0 +1,69 @@
+package secretbox
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testKey = base64.StdEncoding.EncodeToString([]byte(strings.Repeat("k", KeySize)))
+
+func TestBox_SealOpen(t *testing.T) {
+	box, err := New(testKey)
+	assert.NoError(t, err)
+
+	first, err := box.Seal("access-token")
+	assert.NoError(t, err)
+
+	second, err := box.Seal("access-token")
+	assert.NoError(t, err)
+
+	// a fresh nonce every time
+	assert.NotEqual(t, first, second)
+	assert.NotContains(t, first, "access-token")
+
+	opened, err := box.Open(first)
+	assert.NoError(t, err)
+	assert.Equal(t, "access-token", opened)
+}
+
+func TestBox_Open(t *testing.T) {
+	box, err := New(testKey)
+	assert.NoError(t, err)
+
+	sealed, err := box.Seal("access-token")
+	assert.NoError(t, err)
+
+	otherKey := base64.StdEncoding.EncodeToString([]byte(strings.Repeat("o", KeySize)))
+	other, err := New(otherKey)
+	assert.NoError(t, err)
+
+	raw, _ := base64.StdEncoding.DecodeString(sealed)
+	raw[len(raw)-1] ^= 1
+
+	tests := []struct {
+		name   string
+		box    *Box
+		secret string
+	}{
+		{name: "should fail with another key", box: other, secret: sealed},
+		{name: "should fail when tampered", box: box, secret: base64.StdEncoding.EncodeToString(raw)},
+		{name: "should fail on garbage", box: box, secret: "not base64!"},
+		{name: "should fail when shorter than a nonce", box: box, secret: "AAAA"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.box.Open(tt.secret)
+			assert.ErrorIs(t, err, ErrDecrypt)
+		})
+	}
+}
+
+func TestNew_InvalidKey(t *testing.T) {
+	for _, key := range []string{"", "short", base64.StdEncoding.EncodeToString([]byte("sixteen byte key"))} {
+		_, err := New(key)
+		assert.ErrorIs(t, err, ErrInvalidKey)
+	}
+}
@@ -0,0 +1,168 @@
+package spotifyfake
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const (
+	authorizationCodeTTL = 10 * time.Minute
+
+	// the profile every user token belongs to
+	UserID          = "spotifyfake"
+	UserDisplayName = "Spotify Fake"
+)
+
+type authorizationCode struct {
+	clientID      string
+	redirectURI   string
+	codeChallenge string
+	scope         string
+	expiresAt     time.Time
+}
+
+// handleAuthorize stands in for the consent screen: the user always agrees,
+// so it redirects straight back with a code. Only the PKCE variant of the
+// authorization code flow is supported.
+func (s *Server) handleAuthorize(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	redirectURI, err := url.Parse(query.Get("redirect_uri"))
+	if err != nil || !redirectURI.IsAbs() {
+		writeError(w, http.StatusBadRequest, "Invalid redirect URI")
+		return
+	}
+
+	callback := redirectURI.Query()
+	callback.Set("state", query.Get("state"))
+
+	switch {
+	case s.ClientID != "" && query.Get("client_id") != s.ClientID:
+		callback.Set("error", "invalid_client")
+	case query.Get("response_type") != "code":
+		callback.Set("error", "unsupported_response_type")
+	case query.Get("code_challenge_method") != "S256" || query.Get("code_challenge") == "":
+		callback.Set("error", "invalid_request")
+	default:
+		code, err := randomToken()
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, "Server error")
+			return
+		}
+
+		s.mu.Lock()
+		s.codes[code] = authorizationCode{
+			clientID:      query.Get("client_id"),
+			redirectURI:   query.Get("redirect_uri"),
+			codeChallenge: query.Get("code_challenge"),
+			scope:         query.Get("scope"),
+			expiresAt:     time.Now().Add(authorizationCodeTTL),
+		}
+		s.mu.Unlock()
+
+		callback.Set("code", code)
+	}
+
+	redirectURI.RawQuery = callback.Encode()
+	http.Redirect(w, r, redirectURI.String(), http.StatusFound)
+}
+
+// handleAuthorizationCode redeems a code once, the verifier has to hash to
+// the challenge the code was issued for.
+func (s *Server) handleAuthorizationCode(w http.ResponseWriter, r *http.Request) {
+	code := r.PostForm.Get("code")
+
+	s.mu.Lock()
+	issued, ok := s.codes[code]
+	delete(s.codes, code)
+	s.mu.Unlock()
+
+	if !ok || time.Now().After(issued.expiresAt) || issued.redirectURI != r.PostForm.Get("redirect_uri") {
+		writeOAuthError(w, http.StatusBadRequest, "invalid_grant")
+		return
+	}
+
+	if issued.clientID != r.PostForm.Get("client_id") {
+		writeOAuthError(w, http.StatusBadRequest, "invalid_client")
+		return
+	}
+
+	challenge := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	if base64.RawURLEncoding.EncodeToString(challenge[:]) != issued.codeChallenge {
+		writeOAuthError(w, http.StatusBadRequest, "invalid_grant")
+		return
+	}
+
+	s.writeUserToken(w, issued.scope)
+}
+
+// handleRefreshToken rotates the refresh token, like spotify may do, the
+// old one stops working.
+func (s *Server) handleRefreshToken(w http.ResponseWriter, r *http.Request) {
+	refreshToken := r.PostForm.Get("refresh_token")
+
+	s.mu.Lock()
+	scope, ok := s.refreshTokens[refreshToken]
+	delete(s.refreshTokens, refreshToken)
+	s.mu.Unlock()
+
+	if !ok {
+		writeOAuthError(w, http.StatusBadRequest, "invalid_grant")
+		return
+	}
+
+	s.writeUserToken(w, scope)
+}
+
+func (s *Server) writeUserToken(w http.ResponseWriter, scope string) {
+	token, err := s.issueToken(true, scope)
+	if err != nil {
+		writeOAuthError(w, http.StatusInternalServerError, "server_error")
+		return
+	}
+
+	refreshToken, err := randomToken()
+	if err != nil {
+		writeOAuthError(w, http.StatusInternalServerError, "server_error")
+		return
+	}
+
+	s.mu.Lock()
+	s.refreshTokens[refreshToken] = scope
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"access_token":  token,
+		"token_type":    "Bearer",
+		"expires_in":    int(s.TokenTTL.Seconds()),
+		"refresh_token": refreshToken,
+		"scope":         scope,
+	})
+}
+
+// handleMe only answers user tokens, an app token has no user.
+func (s *Server) handleMe(w http.ResponseWriter, r *http.Request) {
+	if !s.isUserToken(r) {
+		writeError(w, http.StatusForbidden, "This endpoint requires a user token")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"id":           UserID,
+		"display_name": UserDisplayName,
+		"type":         "user",
+		"uri":          "spotify:user:" + UserID,
+	})
+}
+
+func (s *Server) isUserToken(r *http.Request) bool {
+	token := bearerToken(r)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.tokens[token].user
+}
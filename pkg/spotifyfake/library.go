@@ -0,0 +1,155 @@
+package spotifyfake
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SaveTracks puts tracks into the "Liked Songs" of the fake user as if they
+// were saved at addedAt, to set up a library before a test.
+func (s *Server) SaveTracks(addedAt time.Time, ids ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, id := range ids {
+		s.saved[id] = addedAt
+	}
+}
+
+// SavedTrackIDs lists the saved tracks of the fake user, most recently
+// saved first.
+func (s *Server) SavedTrackIDs() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.savedTrackIDs()
+}
+
+func (s *Server) savedTrackIDs() []string {
+	ids := make([]string, 0, len(s.saved))
+	for id := range s.saved {
+		ids = append(ids, id)
+	}
+
+	sort.Slice(ids, func(i, j int) bool {
+		if !s.saved[ids[i]].Equal(s.saved[ids[j]]) {
+			return s.saved[ids[i]].After(s.saved[ids[j]])
+		}
+
+		return ids[i] < ids[j]
+	})
+
+	return ids
+}
+
+func (s *Server) handleSavedTracks(w http.ResponseWriter, r *http.Request) {
+	if !s.isUserToken(r) {
+		writeError(w, http.StatusForbidden, "This endpoint requires a user token")
+		return
+	}
+
+	limit, offset, err := pagination(r.URL.Query())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	ids := s.savedTrackIDs()
+	items := make([]json.RawMessage, 0, len(ids))
+	for _, id := range ids {
+		item, err := json.Marshal(map[string]any{
+			"added_at": s.saved[id].UTC().Format(time.RFC3339),
+			"track":    s.tracksByID[id].raw,
+		})
+		if err != nil {
+			s.mu.Unlock()
+			writeError(w, http.StatusInternalServerError, "Server error")
+			return
+		}
+
+		items = append(items, item)
+	}
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, page(r, items, limit, offset))
+}
+
+// handleSaveTracks saves tracks that aren't saved yet, ids the catalogue
+// doesn't have are ignored.
+func (s *Server) handleSaveTracks(w http.ResponseWriter, r *http.Request) {
+	ids, ok := s.libraryIDs(w, r)
+	if !ok {
+		return
+	}
+
+	now := time.Now()
+
+	s.mu.Lock()
+	for _, id := range ids {
+		if _, known := s.tracksByID[id]; !known {
+			continue
+		}
+
+		if _, saved := s.saved[id]; !saved {
+			s.saved[id] = now
+		}
+	}
+	s.mu.Unlock()
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) handleRemoveSavedTracks(w http.ResponseWriter, r *http.Request) {
+	ids, ok := s.libraryIDs(w, r)
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	for _, id := range ids {
+		delete(s.saved, id)
+	}
+	s.mu.Unlock()
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// libraryIDs reads the ids of a save or remove request, spotify takes them
+// from the JSON body or the ids query parameter.
+func (s *Server) libraryIDs(w http.ResponseWriter, r *http.Request) ([]string, bool) {
+	if !s.isUserToken(r) {
+		writeError(w, http.StatusForbidden, "This endpoint requires a user token")
+		return nil, false
+	}
+
+	var ids []string
+	if value := r.URL.Query().Get("ids"); value != "" {
+		ids = strings.Split(value, ",")
+	} else {
+		var body struct {
+			IDs []string `json:"ids"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			writeError(w, http.StatusBadRequest, "Invalid request body")
+			return nil, false
+		}
+
+		ids = body.IDs
+	}
+
+	if len(ids) == 0 {
+		writeError(w, http.StatusBadRequest, "No ids provided")
+		return nil, false
+	}
+
+	if len(ids) > maxSeveralIDs {
+		writeError(w, http.StatusBadRequest, "Too many ids requested")
+		return nil, false
+	}
+
+	return ids, true
+}
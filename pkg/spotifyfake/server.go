@@ -0,0 +1,990 @@
+// Package spotifyfake is an in-process emulator of the parts of the Spotify
+// accounts service and Web API the catalogue depends on. It serves fixture
+// data so the whole stack can run and be tested without network access.
+package spotifyfake
+
+import (
+	"crypto/rand"
+	_ "embed"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultTokenTTL = time.Hour
+
+	defaultPageSize = 20
+	maxPageSize     = 50
+	maxSeveralIDs   = 50
+	maxTopTracks    = 10
+
+	defaultRecommendations = 20
+	maxRecommendations     = 100
+	maxSeeds               = 5
+)
+
+//go:embed fixtures/catalogue.json
+var defaultCatalogue []byte
+
+// Catalogue holds raw Spotify API objects. They are served exactly as
+// stored, so fixtures can be copied straight from real API responses.
+// Albums and artists are taken from the tracks that reference them.
+type Catalogue struct {
+	Tracks    []json.RawMessage `json:"tracks"`
+	Playlists []json.RawMessage `json:"playlists"`
+}
+
+// DefaultCatalogue returns the fixture catalogue embedded in the package.
+func DefaultCatalogue() (*Catalogue, error) {
+	catalogue := &Catalogue{}
+	if err := json.Unmarshal(defaultCatalogue, catalogue); err != nil {
+		return nil, err
+	}
+
+	return catalogue, nil
+}
+
+// item is a catalogue object along with the fields search matches on.
+// Albums only set album and artists, artists only set artists.
+type item struct {
+	raw     json.RawMessage
+	id      string
+	name    string
+	album   string
+	isrc    string
+	artists []string
+
+	albumID     string
+	albumType   string
+	artistIDs   []string
+	popularity  int
+	discNumber  int
+	trackNumber int
+}
+
+type Server struct {
+	// ClientID and ClientSecret, when set, are required by the token endpoint.
+	ClientID     string
+	ClientSecret string
+	TokenTTL     time.Duration
+
+	tracks      []item
+	tracksByID  map[string]item
+	albumsByID  map[string]item
+	artistsByID map[string]item
+
+	// searchable items by search type
+	searchable map[string][]item
+
+	mux *http.ServeMux
+
+	mu     sync.Mutex
+	tokens map[string]accessGrant
+	// pending authorization codes and live refresh tokens of the user flow
+	codes         map[string]authorizationCode
+	refreshTokens map[string]string
+	// "Liked Songs" of the fake user, by the time they were saved
+	saved map[string]time.Time
+}
+
+// accessGrant is a live access token, user tokens come from the
+// authorization code flow and may call the /me endpoints.
+type accessGrant struct {
+	expiresAt time.Time
+	user      bool
+	scope     string
+}
+
+func New(catalogue *Catalogue) (*Server, error) {
+	s := &Server{
+		TokenTTL:    defaultTokenTTL,
+		tracksByID:  make(map[string]item, len(catalogue.Tracks)),
+		albumsByID:  make(map[string]item),
+		artistsByID: make(map[string]item),
+		searchable:  map[string][]item{"track": nil, "album": nil, "artist": nil, "playlist": nil},
+		tokens:      make(map[string]accessGrant),
+		codes:       make(map[string]authorizationCode),
+
+		refreshTokens: make(map[string]string),
+		saved:         make(map[string]time.Time),
+	}
+
+	for _, raw := range catalogue.Tracks {
+		var decoded struct {
+			ID          string            `json:"id"`
+			Name        string            `json:"name"`
+			Popularity  int               `json:"popularity"`
+			DiscNumber  int               `json:"disc_number"`
+			TrackNumber int               `json:"track_number"`
+			Artists     []json.RawMessage `json:"artists"`
+			Album       json.RawMessage   `json:"album"`
+			ExternalIDs struct {
+				ISRC string `json:"isrc"`
+			} `json:"external_ids"`
+		}
+		if err := json.Unmarshal(raw, &decoded); err != nil {
+			return nil, fmt.Errorf("spotifyfake: decode track fixture: %w", err)
+		}
+
+		if decoded.ID == "" {
+			return nil, errors.New("spotifyfake: track fixture without id")
+		}
+
+		var album struct {
+			ID        string `json:"id"`
+			Name      string `json:"name"`
+			AlbumType string `json:"album_type"`
+			Artists   []struct {
+				ID   string `json:"id"`
+				Name string `json:"name"`
+			} `json:"artists"`
+		}
+		if len(decoded.Album) > 0 {
+			if err := json.Unmarshal(decoded.Album, &album); err != nil {
+				return nil, fmt.Errorf("spotifyfake: decode album of track %s: %w", decoded.ID, err)
+			}
+		}
+
+		t := item{
+			raw:         raw,
+			id:          decoded.ID,
+			name:        decoded.Name,
+			album:       album.Name,
+			isrc:        decoded.ExternalIDs.ISRC,
+			albumID:     album.ID,
+			popularity:  decoded.Popularity,
+			discNumber:  decoded.DiscNumber,
+			trackNumber: decoded.TrackNumber,
+		}
+
+		for _, rawArtist := range decoded.Artists {
+			var artist struct {
+				ID   string `json:"id"`
+				Name string `json:"name"`
+			}
+			if err := json.Unmarshal(rawArtist, &artist); err != nil {
+				return nil, fmt.Errorf("spotifyfake: decode artist of track %s: %w", decoded.ID, err)
+			}
+
+			t.artists = append(t.artists, artist.Name)
+			t.artistIDs = append(t.artistIDs, artist.ID)
+
+			if _, seen := s.artistsByID[artist.ID]; artist.ID != "" && !seen {
+				a := item{
+					raw:     rawArtist,
+					id:      artist.ID,
+					artists: []string{artist.Name},
+				}
+
+				s.artistsByID[a.id] = a
+				s.searchable["artist"] = append(s.searchable["artist"], a)
+			}
+		}
+
+		if _, seen := s.albumsByID[album.ID]; album.ID != "" && !seen {
+			a := item{
+				raw:       decoded.Album,
+				id:        album.ID,
+				album:     album.Name,
+				albumType: album.AlbumType,
+			}
+			for _, artist := range album.Artists {
+				a.artists = append(a.artists, artist.Name)
+				a.artistIDs = append(a.artistIDs, artist.ID)
+			}
+
+			s.albumsByID[a.id] = a
+			s.searchable["album"] = append(s.searchable["album"], a)
+		}
+
+		s.tracks = append(s.tracks, t)
+		s.tracksByID[t.id] = t
+	}
+
+	s.searchable["track"] = s.tracks
+
+	for _, raw := range catalogue.Playlists {
+		var decoded struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+		}
+		if err := json.Unmarshal(raw, &decoded); err != nil {
+			return nil, fmt.Errorf("spotifyfake: decode playlist fixture: %w", err)
+		}
+
+		if decoded.ID == "" {
+			return nil, errors.New("spotifyfake: playlist fixture without id")
+		}
+
+		s.searchable["playlist"] = append(s.searchable["playlist"], item{
+			raw:  raw,
+			id:   decoded.ID,
+			name: decoded.Name,
+		})
+	}
+
+	s.mux = http.NewServeMux()
+	s.mux.HandleFunc("GET /authorize", s.handleAuthorize)
+	s.mux.HandleFunc("POST /api/token", s.handleToken)
+	s.mux.HandleFunc("GET /v1/me", s.authorized(s.handleMe))
+	s.mux.HandleFunc("GET /v1/me/tracks", s.authorized(s.handleSavedTracks))
+	s.mux.HandleFunc("PUT /v1/me/tracks", s.authorized(s.handleSaveTracks))
+	s.mux.HandleFunc("DELETE /v1/me/tracks", s.authorized(s.handleRemoveSavedTracks))
+	s.mux.HandleFunc("GET /v1/search", s.authorized(s.handleSearch))
+	s.mux.HandleFunc("GET /v1/tracks", s.authorized(s.handleSeveralTracks))
+	s.mux.HandleFunc("GET /v1/tracks/{id}", s.authorized(s.handleTrack))
+	s.mux.HandleFunc("GET /v1/artists/{id}", s.authorized(s.handleArtist))
+	s.mux.HandleFunc("GET /v1/artists/{id}/top-tracks", s.authorized(s.handleArtistTopTracks))
+	s.mux.HandleFunc("GET /v1/artists/{id}/albums", s.authorized(s.handleArtistAlbums))
+	s.mux.HandleFunc("GET /v1/albums/{id}", s.authorized(s.handleAlbum))
+	s.mux.HandleFunc("GET /v1/albums/{id}/tracks", s.authorized(s.handleAlbumTracks))
+	s.mux.HandleFunc("GET /v1/recommendations", s.authorized(s.handleRecommendations))
+
+	return s, nil
+}
+
+// NewDefault returns a Server backed by DefaultCatalogue.
+func NewDefault() (*Server, error) {
+	catalogue, err := DefaultCatalogue()
+	if err != nil {
+		return nil, err
+	}
+
+	return New(catalogue)
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// Start serves s on addr in the background and returns its base url.
+// Use "127.0.0.1:0" to pick a free port. The accounts service lives at the
+// base url and the Web API under base url + "/v1".
+func (s *Server) Start(addr string) (string, *http.Server, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return "", nil, err
+	}
+
+	server := &http.Server{
+		Handler:           s,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	go server.Serve(listener)
+
+	return "http://" + listener.Addr().String(), server, nil
+}
+
+func (s *Server) handleToken(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeOAuthError(w, http.StatusBadRequest, "invalid_request")
+		return
+	}
+
+	switch r.PostForm.Get("grant_type") {
+	case "client_credentials":
+	case "authorization_code":
+		s.handleAuthorizationCode(w, r)
+		return
+	case "refresh_token":
+		s.handleRefreshToken(w, r)
+		return
+	default:
+		writeOAuthError(w, http.StatusBadRequest, "unsupported_grant_type")
+		return
+	}
+
+	clientID, clientSecret, ok := r.BasicAuth()
+	if !ok {
+		clientID = r.PostForm.Get("client_id")
+		clientSecret = r.PostForm.Get("client_secret")
+	}
+
+	if s.ClientID != "" && (clientID != s.ClientID || clientSecret != s.ClientSecret) {
+		writeOAuthError(w, http.StatusBadRequest, "invalid_client")
+		return
+	}
+
+	token, err := s.issueToken(false, "")
+	if err != nil {
+		writeOAuthError(w, http.StatusInternalServerError, "server_error")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"access_token": token,
+		"token_type":   "Bearer",
+		"expires_in":   int(s.TokenTTL.Seconds()),
+	})
+}
+
+func (s *Server) issueToken(user bool, scope string) (string, error) {
+	token, err := randomToken()
+	if err != nil {
+		return "", err
+	}
+
+	s.mu.Lock()
+	s.tokens[token] = accessGrant{
+		expiresAt: time.Now().Add(s.TokenTTL),
+		user:      user,
+		scope:     scope,
+	}
+	s.mu.Unlock()
+
+	return token, nil
+}
+
+func randomToken() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(buf), nil
+}
+
+// authorized rejects requests without a live bearer token from handleToken.
+func (s *Server) authorized(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token := bearerToken(r)
+		if token == "" {
+			writeError(w, http.StatusUnauthorized, "No token provided")
+			return
+		}
+
+		s.mu.Lock()
+		grant, known := s.tokens[token]
+		s.mu.Unlock()
+
+		if !known || time.Now().After(grant.expiresAt) {
+			writeError(w, http.StatusUnauthorized, "Invalid access token")
+			return
+		}
+
+		next(w, r)
+	}
+}
+
+func bearerToken(r *http.Request) string {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		return ""
+	}
+
+	return token
+}
+
+func (s *Server) handleSearch(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	q := query.Get("q")
+	if q == "" {
+		writeError(w, http.StatusBadRequest, "No search query")
+		return
+	}
+
+	types := strings.Split(query.Get("type"), ",")
+	for _, searchType := range types {
+		if _, ok := s.searchable[searchType]; !ok {
+			writeError(w, http.StatusBadRequest, "Unsupported type")
+			return
+		}
+	}
+
+	limit, offset, err := pagination(query)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	matcher := parseQuery(q)
+
+	response := make(map[string]any, len(types))
+	for _, searchType := range types {
+		matched := make([]json.RawMessage, 0)
+		for _, candidate := range s.searchable[searchType] {
+			if matcher.match(candidate) {
+				matched = append(matched, candidate.raw)
+			}
+		}
+
+		// the sections are keyed by the plural, tracks, albums and so on
+		response[searchType+"s"] = page(r, matched, limit, offset)
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (s *Server) handleTrack(w http.ResponseWriter, r *http.Request) {
+	t, ok := s.tracksByID[r.PathValue("id")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Resource not found")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, t.raw)
+}
+
+func (s *Server) handleSeveralTracks(w http.ResponseWriter, r *http.Request) {
+	ids := strings.Split(r.URL.Query().Get("ids"), ",")
+	if len(ids) > maxSeveralIDs {
+		writeError(w, http.StatusBadRequest, "Too many ids requested")
+		return
+	}
+
+	tracks := make([]json.RawMessage, len(ids))
+	for i, id := range ids {
+		t, ok := s.tracksByID[id]
+		if !ok {
+			tracks[i] = json.RawMessage("null")
+			continue
+		}
+
+		tracks[i] = t.raw
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"tracks": tracks,
+	})
+}
+
+func (s *Server) handleArtist(w http.ResponseWriter, r *http.Request) {
+	artist, ok := s.artistsByID[r.PathValue("id")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Resource not found")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, artist.raw)
+}
+
+// handleArtistTopTracks answers with the artist's ten most popular tracks.
+// The market is accepted but the catalogue is the same everywhere.
+func (s *Server) handleArtistTopTracks(w http.ResponseWriter, r *http.Request) {
+	artistID := r.PathValue("id")
+	if _, ok := s.artistsByID[artistID]; !ok {
+		writeError(w, http.StatusNotFound, "Resource not found")
+		return
+	}
+
+	matched := make([]item, 0)
+	for _, t := range s.tracks {
+		if contains(t.artistIDs, artistID) {
+			matched = append(matched, t)
+		}
+	}
+
+	sort.SliceStable(matched, func(i, j int) bool {
+		return matched[i].popularity > matched[j].popularity
+	})
+
+	tracks := make([]json.RawMessage, 0, maxTopTracks)
+	for _, t := range matched[:min(len(matched), maxTopTracks)] {
+		tracks = append(tracks, t.raw)
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"tracks": tracks,
+	})
+}
+
+// handleArtistAlbums pages the albums the artist released, grouped by their
+// album type, followed by the albums of other artists they appear on.
+func (s *Server) handleArtistAlbums(w http.ResponseWriter, r *http.Request) {
+	artistID := r.PathValue("id")
+	if _, ok := s.artistsByID[artistID]; !ok {
+		writeError(w, http.StatusNotFound, "Resource not found")
+		return
+	}
+
+	query := r.URL.Query()
+
+	groups := []string{"album", "single", "appears_on", "compilation"}
+	if value := query.Get("include_groups"); value != "" {
+		groups = strings.Split(value, ",")
+	}
+
+	limit, offset, err := pagination(query)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	seen := make(map[string]struct{})
+	grouped := make(map[string][]json.RawMessage)
+	for _, t := range s.tracks {
+		if !contains(t.artistIDs, artistID) {
+			continue
+		}
+
+		album, ok := s.albumsByID[t.albumID]
+		if !ok {
+			continue
+		}
+
+		if _, ok := seen[album.id]; ok {
+			continue
+		}
+		seen[album.id] = struct{}{}
+
+		group := album.albumType
+		if !contains(album.artistIDs, artistID) {
+			group = "appears_on"
+		}
+
+		raw, err := withField(album.raw, "album_group", group)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		grouped[group] = append(grouped[group], raw)
+	}
+
+	matched := make([]json.RawMessage, 0)
+	for _, group := range groups {
+		matched = append(matched, grouped[group]...)
+	}
+
+	writeJSON(w, http.StatusOK, page(r, matched, limit, offset))
+}
+
+// handleAlbum answers with the album and the first page of its tracks,
+// linking to handleAlbumTracks for the rest like spotify does.
+func (s *Server) handleAlbum(w http.ResponseWriter, r *http.Request) {
+	album, ok := s.albumsByID[r.PathValue("id")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Resource not found")
+		return
+	}
+
+	tracks, err := s.albumTracks(album.id)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	tracksRequest := r.Clone(r.Context())
+	tracksRequest.URL.Path += "/tracks"
+	tracksRequest.URL.RawQuery = ""
+
+	raw, err := withField(album.raw, "tracks", page(tracksRequest, tracks, maxPageSize, 0))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, raw)
+}
+
+func (s *Server) handleAlbumTracks(w http.ResponseWriter, r *http.Request) {
+	album, ok := s.albumsByID[r.PathValue("id")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Resource not found")
+		return
+	}
+
+	limit, offset, err := pagination(r.URL.Query())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	tracks, err := s.albumTracks(album.id)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, page(r, tracks, limit, offset))
+}
+
+// albumTracks returns the catalogue tracks of the album in tracklist order,
+// as simplified track objects without their album. Only the tracks in the
+// catalogue are listed, so the total can be lower than the album's
+// total_tracks.
+func (s *Server) albumTracks(albumID string) ([]json.RawMessage, error) {
+	matched := make([]item, 0)
+	for _, t := range s.tracks {
+		if t.albumID == albumID {
+			matched = append(matched, t)
+		}
+	}
+
+	sort.SliceStable(matched, func(i, j int) bool {
+		if matched[i].discNumber != matched[j].discNumber {
+			return matched[i].discNumber < matched[j].discNumber
+		}
+		return matched[i].trackNumber < matched[j].trackNumber
+	})
+
+	tracks := make([]json.RawMessage, len(matched))
+	for i, t := range matched {
+		raw, err := withoutField(t.raw, "album")
+		if err != nil {
+			return nil, err
+		}
+
+		tracks[i] = raw
+	}
+
+	return tracks, nil
+}
+
+// handleRecommendations recommends the tracks sharing an artist with the
+// seed tracks and artists. The catalogue has no genres or audio features, so
+// a genre seed widens the pool to every track and only the popularity
+// attributes are applied; the others are validated and ignored.
+func (s *Server) handleRecommendations(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	split := func(key string) []string {
+		if value := query.Get(key); value != "" {
+			return strings.Split(value, ",")
+		}
+		return nil
+	}
+
+	seedTracks := split("seed_tracks")
+	seedArtists := split("seed_artists")
+	seedGenres := split("seed_genres")
+
+	total := len(seedTracks) + len(seedArtists) + len(seedGenres)
+	if total == 0 || total > maxSeeds {
+		writeError(w, http.StatusBadRequest, "Invalid number of seeds")
+		return
+	}
+
+	limit := defaultRecommendations
+	if value := query.Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > maxRecommendations {
+			writeError(w, http.StatusBadRequest, "Invalid limit")
+			return
+		}
+		limit = parsed
+	}
+
+	attributes := make(map[string]float64)
+	for key := range query {
+		if !strings.HasPrefix(key, "min_") && !strings.HasPrefix(key, "max_") && !strings.HasPrefix(key, "target_") {
+			continue
+		}
+
+		value, err := strconv.ParseFloat(query.Get(key), 64)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "Invalid value for "+key)
+			return
+		}
+		attributes[key] = value
+	}
+
+	seeds := make([]map[string]any, 0, total)
+	artistIDs := append([]string{}, seedArtists...)
+	for _, id := range seedTracks {
+		t, ok := s.tracksByID[id]
+		if !ok {
+			writeError(w, http.StatusBadRequest, "Invalid track id")
+			return
+		}
+
+		artistIDs = append(artistIDs, t.artistIDs...)
+		seeds = append(seeds, seedObject(r, "TRACK", id, "/tracks/"+id))
+	}
+	for _, id := range seedArtists {
+		if _, ok := s.artistsByID[id]; !ok {
+			writeError(w, http.StatusBadRequest, "Invalid artist id")
+			return
+		}
+
+		seeds = append(seeds, seedObject(r, "ARTIST", id, "/artists/"+id))
+	}
+	for _, genre := range seedGenres {
+		seeds = append(seeds, map[string]any{"id": genre, "type": "GENRE", "href": nil})
+	}
+
+	matched := make([]item, 0)
+	for _, t := range s.tracks {
+		if contains(seedTracks, t.id) {
+			continue
+		}
+
+		related := len(seedGenres) > 0
+		for _, artistID := range t.artistIDs {
+			related = related || contains(artistIDs, artistID)
+		}
+		if !related {
+			continue
+		}
+
+		popularity := float64(t.popularity)
+		if value, ok := attributes["min_popularity"]; ok && popularity < value {
+			continue
+		}
+		if value, ok := attributes["max_popularity"]; ok && popularity > value {
+			continue
+		}
+
+		matched = append(matched, t)
+	}
+
+	// closest to the target popularity first, the most popular otherwise
+	target, hasTarget := attributes["target_popularity"]
+	sort.SliceStable(matched, func(i, j int) bool {
+		if hasTarget {
+			return math.Abs(float64(matched[i].popularity)-target) < math.Abs(float64(matched[j].popularity)-target)
+		}
+		return matched[i].popularity > matched[j].popularity
+	})
+
+	tracks := make([]json.RawMessage, 0, limit)
+	for _, t := range matched[:min(len(matched), limit)] {
+		tracks = append(tracks, t.raw)
+	}
+
+	for _, seed := range seeds {
+		seed["initialPoolSize"] = len(matched)
+		seed["afterFilteringSize"] = len(matched)
+		seed["afterRelinkingSize"] = len(matched)
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"seeds":  seeds,
+		"tracks": tracks,
+	})
+}
+
+// seedObject is a recommendations seed object for the catalogue object at path.
+func seedObject(r *http.Request, seedType, id, path string) map[string]any {
+	return map[string]any{
+		"id":   id,
+		"type": seedType,
+		"href": fmt.Sprintf("http://%s/v1%s", r.Host, path),
+	}
+}
+
+// withField returns a copy of the raw JSON object with key set to value.
+func withField(raw json.RawMessage, key string, value any) (json.RawMessage, error) {
+	object := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(raw, &object); err != nil {
+		return nil, err
+	}
+
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	object[key] = encoded
+
+	return json.Marshal(object)
+}
+
+// withoutField returns a copy of the raw JSON object without key.
+func withoutField(raw json.RawMessage, key string) (json.RawMessage, error) {
+	object := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(raw, &object); err != nil {
+		return nil, err
+	}
+	delete(object, key)
+
+	return json.Marshal(object)
+}
+
+// queryMatcher implements the subset of the search query syntax we rely on:
+// free-text terms plus the track:, artist:, album: and isrc: field filters.
+type queryMatcher struct {
+	terms   []string
+	filters map[string]string
+}
+
+func parseQuery(q string) queryMatcher {
+	matcher := queryMatcher{
+		filters: make(map[string]string),
+	}
+
+	for _, token := range tokenize(q) {
+		field, value, ok := strings.Cut(token, ":")
+		if ok {
+			switch field {
+			case "track", "artist", "album", "isrc":
+				matcher.filters[field] = strings.ToLower(value)
+				continue
+			}
+		}
+
+		matcher.terms = append(matcher.terms, strings.ToLower(token))
+	}
+
+	return matcher
+}
+
+func (m queryMatcher) match(t item) bool {
+	artists := strings.ToLower(strings.Join(t.artists, " "))
+	name := strings.ToLower(t.name)
+	album := strings.ToLower(t.album)
+
+	for field, value := range m.filters {
+		switch field {
+		case "track":
+			if !strings.Contains(name, value) {
+				return false
+			}
+		case "artist":
+			if !strings.Contains(artists, value) {
+				return false
+			}
+		case "album":
+			if !strings.Contains(album, value) {
+				return false
+			}
+		case "isrc":
+			if strings.ToLower(t.isrc) != value {
+				return false
+			}
+		}
+	}
+
+	haystack := strings.Join([]string{name, artists, album}, " ")
+	for _, term := range m.terms {
+		if !strings.Contains(haystack, term) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// tokenize splits on whitespace, keeping double-quoted phrases together.
+func tokenize(q string) []string {
+	var (
+		tokens  []string
+		current strings.Builder
+		quoted  bool
+	)
+
+	flush := func() {
+		if current.Len() > 0 {
+			tokens = append(tokens, current.String())
+			current.Reset()
+		}
+	}
+
+	for _, r := range q {
+		switch {
+		case r == '"':
+			quoted = !quoted
+		case (r == ' ' || r == '\t') && !quoted:
+			flush()
+		default:
+			current.WriteRune(r)
+		}
+	}
+	flush()
+
+	return tokens
+}
+
+func pagination(query url.Values) (int, int, error) {
+	limit := defaultPageSize
+	if value := query.Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > maxPageSize {
+			return 0, 0, errors.New("Invalid limit")
+		}
+		limit = parsed
+	}
+
+	offset := 0
+	if value := query.Get("offset"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 0 {
+			return 0, 0, errors.New("Invalid offset")
+		}
+		offset = parsed
+	}
+
+	return limit, offset, nil
+}
+
+// page slices items the way spotify paging objects do, including the
+// href/next/previous links.
+func page(r *http.Request, items []json.RawMessage, limit, offset int) map[string]any {
+	total := len(items)
+
+	start := min(offset, total)
+	end := min(offset+limit, total)
+
+	link := func(offset int) string {
+		query := r.URL.Query()
+		query.Set("offset", strconv.Itoa(offset))
+		query.Set("limit", strconv.Itoa(limit))
+
+		return fmt.Sprintf("http://%s%s?%s", r.Host, r.URL.Path, query.Encode())
+	}
+
+	var next, previous *string
+	if end < total {
+		value := link(end)
+		next = &value
+	}
+	if offset > 0 {
+		value := link(max(offset-limit, 0))
+		previous = &value
+	}
+
+	return map[string]any{
+		"href":     link(offset),
+		"items":    items[start:end],
+		"limit":    limit,
+		"next":     next,
+		"offset":   offset,
+		"previous": previous,
+		"total":    total,
+	}
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+// writeError mirrors the Web API error object.
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]any{
+		"error": map[string]any{
+			"status":  status,
+			"message": message,
+		},
+	})
+}
+
+// writeOAuthError mirrors the accounts service error object.
+func writeOAuthError(w http.ResponseWriter, status int, code string) {
+	writeJSON(w, status, map[string]any{
+		"error": code,
+	})
+}
@@ -0,0 +1,379 @@
+package spotifyfake
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestServer(t *testing.T) (*Server, *httptest.Server, string) {
+	fake, err := NewDefault()
+	assert.NoError(t, err)
+
+	fake.ClientID = "client"
+	fake.ClientSecret = "secret"
+
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	form := url.Values{}
+	form.Set("grant_type", "client_credentials")
+	form.Set("client_id", "client")
+	form.Set("client_secret", "secret")
+
+	resp, err := http.Post(server.URL+"/api/token", "application/x-www-form-urlencoded", strings.NewReader(form.Encode()))
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var token struct {
+		AccessToken string `json:"access_token"`
+		TokenType   string `json:"token_type"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&token))
+	assert.Equal(t, "Bearer", token.TokenType)
+	assert.Equal(t, 3600, token.ExpiresIn)
+
+	return fake, server, token.AccessToken
+}
+
+func get(t *testing.T, endpoint, token string, response any) int {
+	req, err := http.NewRequest(http.MethodGet, endpoint, nil)
+	assert.NoError(t, err)
+
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+
+	if response != nil {
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(response))
+	}
+
+	return resp.StatusCode
+}
+
+func TestServer_Token(t *testing.T) {
+	_, server, _ := newTestServer(t)
+
+	tests := []struct {
+		name         string
+		form         url.Values
+		expectedCode int
+	}{
+		{
+			name: "should reject wrong client secret",
+			form: url.Values{
+				"grant_type":    {"client_credentials"},
+				"client_id":     {"client"},
+				"client_secret": {"wrong"},
+			},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name: "should reject unsupported grant",
+			form: url.Values{
+				"grant_type": {"password"},
+			},
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := http.Post(server.URL+"/api/token", "application/x-www-form-urlencoded", strings.NewReader(tt.form.Encode()))
+			assert.NoError(t, err)
+			resp.Body.Close()
+
+			assert.Equal(t, tt.expectedCode, resp.StatusCode)
+		})
+	}
+}
+
+func TestServer_Search(t *testing.T) {
+	_, server, token := newTestServer(t)
+
+	type searchResponse struct {
+		Tracks struct {
+			Total int     `json:"total"`
+			Next  *string `json:"next"`
+			Items []struct {
+				ID string `json:"id"`
+			} `json:"items"`
+		} `json:"tracks"`
+	}
+
+	tests := []struct {
+		name         string
+		query        url.Values
+		token        string
+		expectedCode int
+		wantIDs      []string
+		wantTotal    int
+		wantNext     bool
+	}{
+		{
+			name:         "should require a token",
+			query:        url.Values{"q": {"queen"}, "type": {"track"}},
+			token:        "",
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name:         "should match free text across name, artist and album",
+			query:        url.Values{"q": {"bohemian rhapsody"}, "type": {"track"}},
+			token:        token,
+			expectedCode: http.StatusOK,
+			wantIDs:      []string{"3z8h0TU7ReDPLIbEnYhWZb", "4u7EnebtmKWzUH433cf5Qv"},
+			wantTotal:    2,
+		},
+		{
+			name:         "should page results",
+			query:        url.Values{"q": {"queen"}, "type": {"track"}, "limit": {"1"}, "offset": {"1"}},
+			token:        token,
+			expectedCode: http.StatusOK,
+			wantIDs:      []string{"4u7EnebtmKWzUH433cf5Qv"},
+			wantTotal:    4,
+			wantNext:     true,
+		},
+		{
+			name:         "should filter by field",
+			query:        url.Values{"q": {`artist:"daft punk" track:lucky`}, "type": {"track"}},
+			token:        token,
+			expectedCode: http.StatusOK,
+			wantIDs:      []string{"69kOkLUCkxIZYexIgSG8rq"},
+			wantTotal:    1,
+		},
+		{
+			name:         "should filter by isrc",
+			query:        url.Values{"q": {"isrc:GBAYE9200070"}, "type": {"track"}},
+			token:        token,
+			expectedCode: http.StatusOK,
+			wantIDs:      []string{"70LcF31zb1H0PyJoS1Sx1r"},
+			wantTotal:    1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := searchResponse{}
+			code := get(t, server.URL+"/v1/search?"+tt.query.Encode(), tt.token, &response)
+
+			assert.Equal(t, tt.expectedCode, code)
+			if tt.expectedCode != http.StatusOK {
+				return
+			}
+
+			ids := make([]string, 0, len(response.Tracks.Items))
+			for _, item := range response.Tracks.Items {
+				ids = append(ids, item.ID)
+			}
+
+			assert.Equal(t, tt.wantIDs, ids)
+			assert.Equal(t, tt.wantTotal, response.Tracks.Total)
+			assert.Equal(t, tt.wantNext, response.Tracks.Next != nil)
+		})
+	}
+}
+
+func TestServer_SearchTypes(t *testing.T) {
+	_, server, token := newTestServer(t)
+
+	type section struct {
+		Total int `json:"total"`
+		Items []struct {
+			ID string `json:"id"`
+		} `json:"items"`
+	}
+
+	response := map[string]section{}
+	query := url.Values{"q": {"queen"}, "type": {"album,artist,playlist"}}
+	code := get(t, server.URL+"/v1/search?"+query.Encode(), token, &response)
+	assert.Equal(t, http.StatusOK, code)
+
+	assert.NotContains(t, response, "tracks")
+	assert.Equal(t, 4, response["albums"].Total)
+	assert.Equal(t, 1, response["artists"].Total)
+	assert.Equal(t, "1dfeR4HaWDbWqFHLkxsg1d", response["artists"].Items[0].ID)
+	assert.Equal(t, 1, response["playlists"].Total)
+	assert.Equal(t, "37i9dQZF1DWSFMCJumGzJk", response["playlists"].Items[0].ID)
+
+	query = url.Values{"q": {"queen"}, "type": {"track,show"}}
+	code = get(t, server.URL+"/v1/search?"+query.Encode(), token, nil)
+	assert.Equal(t, http.StatusBadRequest, code)
+}
+
+func TestServer_Artists(t *testing.T) {
+	_, server, token := newTestServer(t)
+
+	artist := struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	}{}
+	code := get(t, server.URL+"/v1/artists/0oSGxfWSnnOXhD2fKuz2Gy", token, &artist)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "David Bowie", artist.Name)
+
+	code = get(t, server.URL+"/v1/artists/unknown/top-tracks", token, nil)
+	assert.Equal(t, http.StatusNotFound, code)
+
+	topTracks := struct {
+		Tracks []struct {
+			ID         string `json:"id"`
+			Popularity int    `json:"popularity"`
+		} `json:"tracks"`
+	}{}
+	code = get(t, server.URL+"/v1/artists/1dfeR4HaWDbWqFHLkxsg1d/top-tracks?market=ID", token, &topTracks)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Len(t, topTracks.Tracks, 4)
+	for i := 1; i < len(topTracks.Tracks); i++ {
+		assert.GreaterOrEqual(t, topTracks.Tracks[i-1].Popularity, topTracks.Tracks[i].Popularity)
+	}
+
+	type albums struct {
+		Total int `json:"total"`
+		Items []struct {
+			ID         string `json:"id"`
+			AlbumGroup string `json:"album_group"`
+		} `json:"items"`
+	}
+
+	pharrell := albums{}
+	code = get(t, server.URL+"/v1/artists/2RdwBSPQiwcmiDo9kixcl8/albums", token, &pharrell)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, 1, pharrell.Total)
+	assert.Equal(t, "appears_on", pharrell.Items[0].AlbumGroup)
+
+	pharrell = albums{}
+	code = get(t, server.URL+"/v1/artists/2RdwBSPQiwcmiDo9kixcl8/albums?include_groups=album,single", token, &pharrell)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, 0, pharrell.Total)
+
+	queen := albums{}
+	code = get(t, server.URL+"/v1/artists/1dfeR4HaWDbWqFHLkxsg1d/albums?limit=2", token, &queen)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, 4, queen.Total)
+	assert.Len(t, queen.Items, 2)
+	assert.Equal(t, "album", queen.Items[0].AlbumGroup)
+}
+
+func TestServer_Albums(t *testing.T) {
+	_, server, token := newTestServer(t)
+
+	type tracks struct {
+		Href  string  `json:"href"`
+		Next  *string `json:"next"`
+		Total int     `json:"total"`
+		Items []struct {
+			ID          string          `json:"id"`
+			TrackNumber int             `json:"track_number"`
+			Album       json.RawMessage `json:"album"`
+		} `json:"items"`
+	}
+
+	album := struct {
+		ID                   string `json:"id"`
+		ReleaseDatePrecision string `json:"release_date_precision"`
+		Tracks               tracks `json:"tracks"`
+	}{}
+	code := get(t, server.URL+"/v1/albums/2noRn2Aes5aoNVsU6iWThc", token, &album)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "day", album.ReleaseDatePrecision)
+	assert.Equal(t, 2, album.Tracks.Total)
+	assert.Contains(t, album.Tracks.Href, "/v1/albums/2noRn2Aes5aoNVsU6iWThc/tracks")
+	assert.Equal(t, 1, album.Tracks.Items[0].TrackNumber)
+	assert.Equal(t, 4, album.Tracks.Items[1].TrackNumber)
+	assert.Nil(t, album.Tracks.Items[0].Album)
+
+	code = get(t, server.URL+"/v1/albums/unknown", token, nil)
+	assert.Equal(t, http.StatusNotFound, code)
+
+	page := tracks{}
+	code = get(t, server.URL+"/v1/albums/2noRn2Aes5aoNVsU6iWThc/tracks?limit=1&offset=1", token, &page)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, 2, page.Total)
+	assert.Nil(t, page.Next)
+	assert.Len(t, page.Items, 1)
+	assert.Equal(t, "2VxeLyX666F8uXCJ0dZF8B", page.Items[0].ID)
+}
+
+func TestServer_Recommendations(t *testing.T) {
+	_, server, token := newTestServer(t)
+
+	type recommendations struct {
+		Seeds []struct {
+			ID   string `json:"id"`
+			Type string `json:"type"`
+		} `json:"seeds"`
+		Tracks []struct {
+			ID string `json:"id"`
+		} `json:"tracks"`
+	}
+
+	trackIDs := func(response recommendations) []string {
+		ids := make([]string, len(response.Tracks))
+		for i, track := range response.Tracks {
+			ids[i] = track.ID
+		}
+		return ids
+	}
+
+	queen := recommendations{}
+	code := get(t, server.URL+"/v1/recommendations?seed_tracks=3z8h0TU7ReDPLIbEnYhWZb", token, &queen)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "TRACK", queen.Seeds[0].Type)
+	// most popular first, without the seed itself
+	assert.Equal(t, []string{"7hQJA50XrCWABAu5v6QZ4i", "4u7EnebtmKWzUH433cf5Qv", "11IzgLRXV7Cgek3tEgGgjw"}, trackIDs(queen))
+
+	queen = recommendations{}
+	code = get(t, server.URL+"/v1/recommendations?seed_tracks=3z8h0TU7ReDPLIbEnYhWZb&target_popularity=77&max_popularity=80&target_energy=0.5", token, &queen)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, []string{"11IzgLRXV7Cgek3tEgGgjw", "4u7EnebtmKWzUH433cf5Qv"}, trackIDs(queen))
+
+	bowie := recommendations{}
+	code = get(t, server.URL+"/v1/recommendations?seed_artists=0oSGxfWSnnOXhD2fKuz2Gy&limit=1", token, &bowie)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, []string{"11IzgLRXV7Cgek3tEgGgjw"}, trackIDs(bowie))
+
+	code = get(t, server.URL+"/v1/recommendations", token, nil)
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	code = get(t, server.URL+"/v1/recommendations?seed_genres=a,b,c,d,e,f", token, nil)
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	code = get(t, server.URL+"/v1/recommendations?seed_tracks=unknown", token, nil)
+	assert.Equal(t, http.StatusBadRequest, code)
+}
+
+func TestServer_Tracks(t *testing.T) {
+	_, server, token := newTestServer(t)
+
+	track := struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	}{}
+	code := get(t, server.URL+"/v1/tracks/70LcF31zb1H0PyJoS1Sx1r", token, &track)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "Creep", track.Name)
+
+	code = get(t, server.URL+"/v1/tracks/unknown", token, nil)
+	assert.Equal(t, http.StatusNotFound, code)
+
+	several := struct {
+		Tracks []*struct {
+			ID string `json:"id"`
+		} `json:"tracks"`
+	}{}
+	code = get(t, server.URL+"/v1/tracks?ids=70LcF31zb1H0PyJoS1Sx1r,unknown", token, &several)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Len(t, several.Tracks, 2)
+	assert.Equal(t, "70LcF31zb1H0PyJoS1Sx1r", several.Tracks[0].ID)
+	assert.Nil(t, several.Tracks[1])
+}
@@ -0,0 +1,289 @@
+package trackio
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// FormatM3U is only read, m3u and m3u8 files differ in encoding alone and
+// are decoded the same way.
+const FormatM3U = "m3u"
+
+var ErrMalformed = errors.New("trackio: malformed listing")
+
+// Listing is a decoded file, Title is empty when the format has none.
+type Listing struct {
+	Title  string
+	Tracks []Track
+}
+
+// FormatFromFilename guesses the format from the file extension.
+func FormatFromFilename(filename string) (string, bool) {
+	format := strings.ToLower(strings.TrimPrefix(path.Ext(filename), "."))
+	switch format {
+	case FormatCSV, FormatJSON, FormatM3U, FormatM3U8, FormatXSPF:
+		return format, true
+	}
+
+	return "", false
+}
+
+// Decode reads a listing in format. Errors about the content wrap
+// ErrMalformed.
+func Decode(format string, r io.Reader) (*Listing, error) {
+	var (
+		listing *Listing
+		err     error
+	)
+
+	switch format {
+	case FormatCSV:
+		listing, err = decodeCSV(r)
+	case FormatJSON:
+		listing, err = decodeJSON(r)
+	case FormatM3U, FormatM3U8:
+		listing, err = decodeM3U(r)
+	case FormatXSPF:
+		listing, err = decodeXSPF(r)
+	default:
+		return nil, ErrUnknownFormat
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrMalformed, format, err)
+	}
+
+	return listing, nil
+}
+
+// csvColumns maps the header names other exporters use onto track fields.
+var csvColumns = map[string]string{
+	"name":         "name",
+	"title":        "name",
+	"track":        "name",
+	"track name":   "name",
+	"artists":      "artists",
+	"artist":       "artists",
+	"artist name":  "artists",
+	"artist names": "artists",
+	"album":        "album",
+	"album name":   "album",
+	"duration_ms":  "duration_ms",
+	"duration":     "duration_ms",
+	"isrc":         "isrc",
+	"uri":          "uri",
+	"track uri":    "uri",
+}
+
+func decodeCSV(r io.Reader) (*Listing, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, err
+	}
+
+	columns := make(map[string]int, len(header))
+	for idx, name := range header {
+		field, ok := csvColumns[strings.ToLower(strings.TrimSpace(name))]
+		if _, taken := columns[field]; ok && !taken {
+			columns[field] = idx
+		}
+	}
+
+	if _, ok := columns["name"]; !ok {
+		return nil, errors.New("no name or title column")
+	}
+
+	value := func(record []string, field string) string {
+		idx, ok := columns[field]
+		if !ok || idx >= len(record) {
+			return ""
+		}
+
+		return strings.TrimSpace(record[idx])
+	}
+
+	listing := &Listing{}
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		track := Track{
+			Name:    value(record, "name"),
+			Artists: splitArtists(value(record, "artists"), ";"),
+			Album:   value(record, "album"),
+			ISRC:    value(record, "isrc"),
+			URI:     value(record, "uri"),
+		}
+		track.DurationMs, _ = strconv.Atoi(value(record, "duration_ms"))
+
+		if track.Name == "" && track.ISRC == "" {
+			continue
+		}
+
+		listing.Tracks = append(listing.Tracks, track)
+	}
+
+	return listing, nil
+}
+
+// decodeJSON reads what the json encoder writes, or just its tracks array.
+func decodeJSON(r io.Reader) (*Listing, error) {
+	var raw json.RawMessage
+	err := json.NewDecoder(r).Decode(&raw)
+	if err != nil {
+		return nil, err
+	}
+
+	var document struct {
+		Title  string      `json:"title"`
+		Tracks []jsonTrack `json:"tracks"`
+	}
+
+	raw = json.RawMessage(strings.TrimSpace(string(raw)))
+	if len(raw) > 0 && raw[0] == '[' {
+		err = json.Unmarshal(raw, &document.Tracks)
+	} else {
+		err = json.Unmarshal(raw, &document)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	listing := &Listing{
+		Title:  document.Title,
+		Tracks: make([]Track, 0, len(document.Tracks)),
+	}
+	for _, track := range document.Tracks {
+		listing.Tracks = append(listing.Tracks, Track(track))
+	}
+
+	return listing, nil
+}
+
+// decodeM3U reads plain and extended m3u. Without an #EXTINF line the file
+// name of the location stands in for "artist - title".
+func decodeM3U(r io.Reader) (*Listing, error) {
+	scanner := bufio.NewScanner(r)
+
+	listing := &Listing{}
+	var (
+		label      string
+		durationMs int
+	)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "":
+			continue
+		case strings.HasPrefix(line, "#PLAYLIST:"):
+			listing.Title = strings.TrimSpace(strings.TrimPrefix(line, "#PLAYLIST:"))
+		case strings.HasPrefix(line, "#EXTINF:"):
+			info, title, _ := strings.Cut(strings.TrimPrefix(line, "#EXTINF:"), ",")
+			label = strings.TrimSpace(title)
+
+			// the length may be followed by attributes
+			seconds, _ := strconv.Atoi(strings.Fields(info + " ")[0])
+			durationMs = max(seconds, 0) * 1000
+		case strings.HasPrefix(line, "#"):
+			continue
+		default:
+			if label == "" {
+				base := path.Base(strings.ReplaceAll(line, `\`, "/"))
+				label = strings.TrimSuffix(base, path.Ext(base))
+			}
+
+			track := Track{
+				Name:       label,
+				DurationMs: durationMs,
+				URI:        line,
+			}
+			if artists, name, ok := strings.Cut(label, " - "); ok {
+				track.Artists = splitArtists(artists, ",")
+				track.Name = strings.TrimSpace(name)
+			}
+
+			listing.Tracks = append(listing.Tracks, track)
+			label, durationMs = "", 0
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return listing, nil
+}
+
+func decodeXSPF(r io.Reader) (*Listing, error) {
+	var document struct {
+		Title  string `xml:"title"`
+		Tracks []struct {
+			Location    []string `xml:"location"`
+			Identifiers []string `xml:"identifier"`
+			Title       string   `xml:"title"`
+			Creator     string   `xml:"creator"`
+			Album       string   `xml:"album"`
+			Duration    int      `xml:"duration"`
+		} `xml:"trackList>track"`
+	}
+
+	err := xml.NewDecoder(r).Decode(&document)
+	if err != nil {
+		return nil, err
+	}
+
+	listing := &Listing{
+		Title:  strings.TrimSpace(document.Title),
+		Tracks: make([]Track, 0, len(document.Tracks)),
+	}
+	for _, item := range document.Tracks {
+		track := Track{
+			Name:       strings.TrimSpace(item.Title),
+			Artists:    splitArtists(item.Creator, ","),
+			Album:      strings.TrimSpace(item.Album),
+			DurationMs: item.Duration,
+		}
+		if len(item.Location) > 0 {
+			track.URI = strings.TrimSpace(item.Location[0])
+		}
+
+		for _, identifier := range item.Identifiers {
+			identifier = strings.TrimPrefix(strings.TrimSpace(identifier), "urn:")
+			if isrc, ok := strings.CutPrefix(identifier, "isrc:"); ok {
+				track.ISRC = isrc
+				break
+			}
+		}
+
+		listing.Tracks = append(listing.Tracks, track)
+	}
+
+	return listing, nil
+}
+
+func splitArtists(value, separator string) []string {
+	var artists []string
+	for _, artist := range strings.Split(value, separator) {
+		artist = strings.TrimSpace(artist)
+		if artist != "" {
+			artists = append(artists, artist)
+		}
+	}
+
+	return artists
+}
@@ -0,0 +1,130 @@
+package trackio
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecode_RoundTrip(t *testing.T) {
+	for _, format := range []string{FormatCSV, FormatXSPF} {
+		t.Run(format, func(t *testing.T) {
+			var out bytes.Buffer
+
+			encoder, err := NewEncoder(format, &out)
+			assert.NoError(t, err)
+
+			assert.NoError(t, encoder.Begin("Liked & Loved"))
+			for _, track := range tracks {
+				assert.NoError(t, encoder.Encode(track))
+			}
+			assert.NoError(t, encoder.End())
+
+			listing, err := Decode(format, &out)
+			assert.NoError(t, err)
+			assert.Equal(t, tracks, listing.Tracks)
+		})
+	}
+}
+
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  string
+		input   string
+		want    *Listing
+		wantErr error
+	}{
+		{
+			name:   "csv with the headers of another player",
+			format: FormatCSV,
+			input: "Track Name,Artist Name,Album Name,ISRC\n" +
+				"Bohemian Rhapsody,Queen,A Night At The Opera,GBUM71029604\n" +
+				",,,\n" +
+				"Under Pressure,Queen; David Bowie,Hot Space,\n",
+			want: &Listing{Tracks: []Track{
+				{Name: "Bohemian Rhapsody", Artists: []string{"Queen"}, Album: "A Night At The Opera", ISRC: "GBUM71029604"},
+				{Name: "Under Pressure", Artists: []string{"Queen", "David Bowie"}, Album: "Hot Space"},
+			}},
+		},
+		{
+			name:    "csv without a title column",
+			format:  FormatCSV,
+			input:   "artist,album\nQueen,Hot Space\n",
+			wantErr: ErrMalformed,
+		},
+		{
+			name:   "json tracks array",
+			format: FormatJSON,
+			input:  `[{"name":"Bohemian Rhapsody","artists":["Queen"],"duration_ms":354947}]`,
+			want: &Listing{Tracks: []Track{
+				{Name: "Bohemian Rhapsody", Artists: []string{"Queen"}, DurationMs: 354947},
+			}},
+		},
+		{
+			name:    "broken json",
+			format:  FormatJSON,
+			input:   `{"tracks":[`,
+			wantErr: ErrMalformed,
+		},
+		{
+			name:   "extended m3u",
+			format: FormatM3U,
+			input: "#EXTM3U\n#PLAYLIST:Road Trip\n" +
+				"#EXTINF:355,Queen - Bohemian Rhapsody\n/music/01.mp3\n\n" +
+				"#EXTINF:-1 tvg-id=\"x\",Queen, David Bowie - Under Pressure\nhttp://example.com/02.mp3\n",
+			want: &Listing{Title: "Road Trip", Tracks: []Track{
+				{Name: "Bohemian Rhapsody", Artists: []string{"Queen"}, DurationMs: 355000, URI: "/music/01.mp3"},
+				{Name: "Under Pressure", Artists: []string{"Queen", "David Bowie"}, URI: "http://example.com/02.mp3"},
+			}},
+		},
+		{
+			name:   "plain m3u falls back to the file name",
+			format: FormatM3U,
+			input:  "C:\\Music\\Queen - Bohemian Rhapsody.flac\nintro.mp3\n",
+			want: &Listing{Tracks: []Track{
+				{Name: "Bohemian Rhapsody", Artists: []string{"Queen"}, URI: "C:\\Music\\Queen - Bohemian Rhapsody.flac"},
+				{Name: "intro", URI: "intro.mp3"},
+			}},
+		},
+		{
+			name:   "xspf with an isrc urn",
+			format: FormatXSPF,
+			input: `<playlist version="1" xmlns="http://xspf.org/ns/0/"><title>Road Trip</title><trackList>` +
+				`<track><identifier>https://example.com/1</identifier><identifier>urn:isrc:GBUM71029604</identifier>` +
+				`<title>Bohemian Rhapsody</title><creator>Queen</creator></track>` +
+				`</trackList></playlist>`,
+			want: &Listing{Title: "Road Trip", Tracks: []Track{
+				{Name: "Bohemian Rhapsody", Artists: []string{"Queen"}, ISRC: "GBUM71029604"},
+			}},
+		},
+		{
+			name:    "unknown format",
+			format:  "wav",
+			wantErr: ErrUnknownFormat,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			listing, err := Decode(tt.format, strings.NewReader(tt.input))
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, listing)
+		})
+	}
+}
+
+func TestFormatFromFilename(t *testing.T) {
+	format, ok := FormatFromFilename("Road Trip.M3U")
+	assert.True(t, ok)
+	assert.Equal(t, FormatM3U, format)
+
+	_, ok = FormatFromFilename("library.txt")
+	assert.False(t, ok)
+}
@@ -0,0 +1,242 @@
+package trackio
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+var csvHeader = []string{"name", "artists", "album", "duration_ms", "isrc", "uri"}
+
+type csvEncoder struct {
+	out *flushWriter
+	w   *csv.Writer
+}
+
+func newCSVEncoder(out *flushWriter) *csvEncoder {
+	return &csvEncoder{out: out, w: csv.NewWriter(out)}
+}
+
+// Begin writes the header row, csv has no place for the title.
+func (e *csvEncoder) Begin(title string) error {
+	return e.w.Write(csvHeader)
+}
+
+func (e *csvEncoder) Encode(track Track) error {
+	return e.w.Write([]string{
+		track.Name,
+		strings.Join(track.Artists, "; "),
+		track.Album,
+		strconv.Itoa(track.DurationMs),
+		track.ISRC,
+		track.URI,
+	})
+}
+
+func (e *csvEncoder) End() error {
+	return e.Flush()
+}
+
+func (e *csvEncoder) Flush() error {
+	e.w.Flush()
+	if err := e.w.Error(); err != nil {
+		return err
+	}
+
+	return e.out.Flush()
+}
+
+type jsonTrack struct {
+	Name       string   `json:"name"`
+	Artists    []string `json:"artists"`
+	Album      string   `json:"album"`
+	DurationMs int      `json:"duration_ms"`
+	ISRC       string   `json:"isrc"`
+	URI        string   `json:"uri"`
+}
+
+// jsonEncoder writes {"title": ..., "tracks": [...]} one track at a time.
+type jsonEncoder struct {
+	out   *flushWriter
+	count int
+}
+
+func newJSONEncoder(out *flushWriter) *jsonEncoder {
+	return &jsonEncoder{out: out}
+}
+
+func (e *jsonEncoder) Begin(title string) error {
+	encoded, err := marshalJSON(title)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(e.out, `{"title":%s,"tracks":[`, encoded)
+	return err
+}
+
+func (e *jsonEncoder) Encode(track Track) error {
+	artists := track.Artists
+	if artists == nil {
+		artists = []string{}
+	}
+
+	encoded, err := marshalJSON(jsonTrack{
+		Name:       track.Name,
+		Artists:    artists,
+		Album:      track.Album,
+		DurationMs: track.DurationMs,
+		ISRC:       track.ISRC,
+		URI:        track.URI,
+	})
+	if err != nil {
+		return err
+	}
+
+	if e.count > 0 {
+		if _, err = io.WriteString(e.out, ","); err != nil {
+			return err
+		}
+	}
+	e.count++
+
+	_, err = e.out.Write(encoded)
+	return err
+}
+
+func (e *jsonEncoder) End() error {
+	if _, err := io.WriteString(e.out, "]}\n"); err != nil {
+		return err
+	}
+
+	return e.Flush()
+}
+
+func (e *jsonEncoder) Flush() error {
+	return e.out.Flush()
+}
+
+// marshalJSON is json.Marshal without escaping &, < and >, the export is a
+// file and not embedded in html.
+func marshalJSON(value any) ([]byte, error) {
+	var b bytes.Buffer
+
+	encoder := json.NewEncoder(&b)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil {
+		return nil, err
+	}
+
+	return bytes.TrimSuffix(b.Bytes(), []byte("\n")), nil
+}
+
+// m3u8Encoder writes an extended m3u playlist with spotify uris as
+// locations.
+type m3u8Encoder struct {
+	out *flushWriter
+}
+
+func newM3U8Encoder(out *flushWriter) *m3u8Encoder {
+	return &m3u8Encoder{out: out}
+}
+
+func (e *m3u8Encoder) Begin(title string) error {
+	_, err := fmt.Fprintf(e.out, "#EXTM3U\n#PLAYLIST:%s\n", oneLine(title))
+	return err
+}
+
+func (e *m3u8Encoder) Encode(track Track) error {
+	// -1 is the m3u convention for an unknown length
+	seconds := -1
+	if track.DurationMs > 0 {
+		seconds = (track.DurationMs + 500) / 1000
+	}
+
+	label := track.Name
+	if len(track.Artists) > 0 {
+		label = strings.Join(track.Artists, ", ") + " - " + track.Name
+	}
+
+	_, err := fmt.Fprintf(e.out, "#EXTINF:%d,%s\n%s\n", seconds, oneLine(label), track.URI)
+	return err
+}
+
+func (e *m3u8Encoder) End() error {
+	return e.Flush()
+}
+
+func (e *m3u8Encoder) Flush() error {
+	return e.out.Flush()
+}
+
+// oneLine keeps a value from breaking the line based m3u format.
+func oneLine(value string) string {
+	return strings.NewReplacer("\r\n", " ", "\n", " ", "\r", " ").Replace(value)
+}
+
+const (
+	xspfHeader = `<?xml version="1.0" encoding="UTF-8"?>` + "\n" +
+		`<playlist version="1" xmlns="http://xspf.org/ns/0/">` + "\n"
+	xspfFooter = "  </trackList>\n</playlist>\n"
+)
+
+type xspfEncoder struct {
+	out *flushWriter
+}
+
+func newXSPFEncoder(out *flushWriter) *xspfEncoder {
+	return &xspfEncoder{out: out}
+}
+
+func (e *xspfEncoder) Begin(title string) error {
+	_, err := fmt.Fprintf(e.out, "%s  <title>%s</title>\n  <trackList>\n", xspfHeader, escapeXML(title))
+	return err
+}
+
+func (e *xspfEncoder) Encode(track Track) error {
+	var b strings.Builder
+
+	b.WriteString("    <track>\n")
+	fmt.Fprintf(&b, "      <location>%s</location>\n", escapeXML(track.URI))
+	if track.ISRC != "" {
+		fmt.Fprintf(&b, "      <identifier>isrc:%s</identifier>\n", escapeXML(track.ISRC))
+	}
+	fmt.Fprintf(&b, "      <title>%s</title>\n", escapeXML(track.Name))
+	if len(track.Artists) > 0 {
+		fmt.Fprintf(&b, "      <creator>%s</creator>\n", escapeXML(strings.Join(track.Artists, ", ")))
+	}
+	if track.Album != "" {
+		fmt.Fprintf(&b, "      <album>%s</album>\n", escapeXML(track.Album))
+	}
+	if track.DurationMs > 0 {
+		fmt.Fprintf(&b, "      <duration>%d</duration>\n", track.DurationMs)
+	}
+	b.WriteString("    </track>\n")
+
+	_, err := io.WriteString(e.out, b.String())
+	return err
+}
+
+func (e *xspfEncoder) End() error {
+	if _, err := io.WriteString(e.out, xspfFooter); err != nil {
+		return err
+	}
+
+	return e.Flush()
+}
+
+func (e *xspfEncoder) Flush() error {
+	return e.out.Flush()
+}
+
+func escapeXML(value string) string {
+	var b strings.Builder
+	// writing to a strings.Builder can't fail
+	_ = xml.EscapeText(&b, []byte(value))
+	return b.String()
+}
@@ -0,0 +1,95 @@
+// Package trackio reads and writes track listings in the formats other
+// players and services use. Encoders stream: every track is written as it
+// comes so a listing never has to be held in memory.
+package trackio
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"net/http"
+)
+
+const (
+	FormatCSV  = "csv"
+	FormatJSON = "json"
+	FormatM3U8 = "m3u8"
+	FormatXSPF = "xspf"
+)
+
+var ErrUnknownFormat = errors.New("trackio: unknown format")
+
+// Track is one entry of a listing.
+type Track struct {
+	Name       string
+	Artists    []string
+	Album      string
+	DurationMs int
+	ISRC       string
+	// spotify:track:<id>
+	URI string
+}
+
+// Encoder writes a listing. Begin is called once before the first track and
+// End once after the last, Flush pushes what was written so far to the
+// underlying writer.
+type Encoder interface {
+	Begin(title string) error
+	Encode(track Track) error
+	End() error
+	Flush() error
+}
+
+// NewEncoder returns the encoder for format writing to w.
+func NewEncoder(format string, w io.Writer) (Encoder, error) {
+	buffered := &flushWriter{Writer: bufio.NewWriter(w), target: w}
+
+	switch format {
+	case FormatCSV:
+		return newCSVEncoder(buffered), nil
+	case FormatJSON:
+		return newJSONEncoder(buffered), nil
+	case FormatM3U8:
+		return newM3U8Encoder(buffered), nil
+	case FormatXSPF:
+		return newXSPFEncoder(buffered), nil
+	default:
+		return nil, ErrUnknownFormat
+	}
+}
+
+// ContentType is the media type to serve a listing in format with.
+func ContentType(format string) string {
+	switch format {
+	case FormatCSV:
+		return "text/csv; charset=utf-8"
+	case FormatJSON:
+		return "application/json; charset=utf-8"
+	case FormatM3U8:
+		return "audio/x-mpegurl; charset=utf-8"
+	case FormatXSPF:
+		return "application/xspf+xml; charset=utf-8"
+	default:
+		return "application/octet-stream"
+	}
+}
+
+// flushWriter buffers writes to target and, when flushed, flushes target as
+// well if it can be, so an http response actually goes out.
+type flushWriter struct {
+	*bufio.Writer
+	target io.Writer
+}
+
+func (w *flushWriter) Flush() error {
+	err := w.Writer.Flush()
+	if err != nil {
+		return err
+	}
+
+	if flusher, ok := w.target.(http.Flusher); ok {
+		flusher.Flush()
+	}
+
+	return nil
+}
@@ -0,0 +1,111 @@
+package trackio
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var tracks = []Track{
+	{
+		Name:       "Bohemian Rhapsody",
+		Artists:    []string{"Queen"},
+		Album:      "A Night At The Opera",
+		DurationMs: 354947,
+		ISRC:       "GBUM71029604",
+		URI:        "spotify:track:3z8h0TU7ReDPLIbEnYhWZb",
+	},
+	{
+		Name: "Under Pressure, \"Live\"",
+		URI:  "spotify:track:gone",
+	},
+}
+
+func TestEncoders(t *testing.T) {
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{
+			format: FormatCSV,
+			want: "name,artists,album,duration_ms,isrc,uri\n" +
+				"Bohemian Rhapsody,Queen,A Night At The Opera,354947,GBUM71029604,spotify:track:3z8h0TU7ReDPLIbEnYhWZb\n" +
+				"\"Under Pressure, \"\"Live\"\"\",,,0,,spotify:track:gone\n",
+		},
+		{
+			format: FormatJSON,
+			want: `{"title":"Liked & Loved","tracks":[` +
+				`{"name":"Bohemian Rhapsody","artists":["Queen"],"album":"A Night At The Opera","duration_ms":354947,"isrc":"GBUM71029604","uri":"spotify:track:3z8h0TU7ReDPLIbEnYhWZb"},` +
+				`{"name":"Under Pressure, \"Live\"","artists":[],"album":"","duration_ms":0,"isrc":"","uri":"spotify:track:gone"}` +
+				"]}\n",
+		},
+		{
+			format: FormatM3U8,
+			want: "#EXTM3U\n#PLAYLIST:Liked & Loved\n" +
+				"#EXTINF:355,Queen - Bohemian Rhapsody\nspotify:track:3z8h0TU7ReDPLIbEnYhWZb\n" +
+				"#EXTINF:-1,Under Pressure, \"Live\"\nspotify:track:gone\n",
+		},
+		{
+			format: FormatXSPF,
+			want: `<?xml version="1.0" encoding="UTF-8"?>` + "\n" +
+				`<playlist version="1" xmlns="http://xspf.org/ns/0/">` + "\n" +
+				"  <title>Liked &amp; Loved</title>\n" +
+				"  <trackList>\n" +
+				"    <track>\n" +
+				"      <location>spotify:track:3z8h0TU7ReDPLIbEnYhWZb</location>\n" +
+				"      <identifier>isrc:GBUM71029604</identifier>\n" +
+				"      <title>Bohemian Rhapsody</title>\n" +
+				"      <creator>Queen</creator>\n" +
+				"      <album>A Night At The Opera</album>\n" +
+				"      <duration>354947</duration>\n" +
+				"    </track>\n" +
+				"    <track>\n" +
+				"      <location>spotify:track:gone</location>\n" +
+				"      <title>Under Pressure, &#34;Live&#34;</title>\n" +
+				"    </track>\n" +
+				"  </trackList>\n" +
+				"</playlist>\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			var out bytes.Buffer
+
+			encoder, err := NewEncoder(tt.format, &out)
+			assert.NoError(t, err)
+
+			assert.NoError(t, encoder.Begin("Liked & Loved"))
+			for _, track := range tracks {
+				assert.NoError(t, encoder.Encode(track))
+			}
+			assert.NoError(t, encoder.End())
+
+			assert.Equal(t, tt.want, out.String())
+		})
+	}
+}
+
+func TestEncoder_Flush(t *testing.T) {
+	w := httptest.NewRecorder()
+
+	encoder, err := NewEncoder(FormatM3U8, w)
+	assert.NoError(t, err)
+
+	assert.NoError(t, encoder.Begin("Liked"))
+	assert.NoError(t, encoder.Encode(tracks[0]))
+
+	// nothing reaches the response until the buffer is flushed
+	assert.Zero(t, w.Body.Len())
+	assert.False(t, w.Flushed)
+
+	assert.NoError(t, encoder.Flush())
+	assert.Contains(t, w.Body.String(), "Bohemian Rhapsody")
+	assert.True(t, w.Flushed)
+}
+
+func TestNewEncoder_UnknownFormat(t *testing.T) {
+	_, err := NewEncoder("wav", &bytes.Buffer{})
+	assert.ErrorIs(t, err, ErrUnknownFormat)
+}
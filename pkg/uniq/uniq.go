@@ -0,0 +1,18 @@
+// Package uniq drops repeated values from slices.
+package uniq
+
+// Values returns values without repeats, keeping the first-seen order.
+func Values[T comparable](values []T) []T {
+	seen := make(map[T]struct{}, len(values))
+	result := make([]T, 0, len(values))
+	for _, value := range values {
+		if _, ok := seen[value]; ok {
+			continue
+		}
+
+		seen[value] = struct{}{}
+		result = append(result, value)
+	}
+
+	return result
+}
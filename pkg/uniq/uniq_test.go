@@ -0,0 +1,12 @@
+package uniq
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValues(t *testing.T) {
+	assert.Equal(t, []string{"b", "a", "c"}, Values([]string{"b", "a", "b", "c", "a"}))
+	assert.Equal(t, []string{}, Values[string](nil))
+}